@@ -30,6 +30,9 @@ func main() {
 	// Setup HTTP server
 	server := bootstrap.SetupServer(app, app.AnimalController)
 
+	// Start publishing stored events
+	app.Start()
+
 	// Start the server in a goroutine
 	go func() {
 		bootstrap.LogServerInfo(logger, cfg.Server.Port, cfg.IsDevelopment(), cfg)
@@ -38,6 +41,17 @@ func main() {
 		}
 	}()
 
+	// Reload the log level on SIGHUP so debug logging can be enabled without a restart
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := app.ReloadLogLevel(); err != nil {
+				logger.Error("Failed to reload log level", zap.Error(err))
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -55,5 +69,17 @@ func main() {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Stop publishing stored events; those not yet published wait in the outbox for the next dispatcher
+	if app.OutboxDispatcher != nil {
+		if err := app.OutboxDispatcher.Stop(ctx); err != nil {
+			logger.Error("Failed to stop outbox dispatcher", zap.Error(err))
+		}
+	}
+
+	// Flush spans recorded while draining requests
+	if err := app.Telemetry(ctx); err != nil {
+		logger.Error("Failed to flush traces", zap.Error(err))
+	}
+
 	logger.Info("Server exiting")
 }
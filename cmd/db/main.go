@@ -1,14 +1,17 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/linkeunid/go-api/internal/bootstrap"
 	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/config"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -19,24 +22,37 @@ var (
 	truncateAll   bool
 	help          bool
 	verbose       bool
+	confirm       bool
+	dryRun        bool
 )
 
 // Register command line flags
 func init() {
 	flag.StringVar(&truncateModel, "truncate", "", "Truncate a specific table based on model name")
-	flag.BoolVar(&truncateAll, "truncate-all", false, "Truncate all tables")
+	flag.BoolVar(&truncateAll, "truncate-all", false, "Truncate all tables except users and outbox")
 	flag.BoolVar(&help, "help", false, "Show help")
 	flag.BoolVar(&help, "h", false, "Show help (shorthand)")
 	flag.BoolVar(&verbose, "v", false, "Verbose output")
+	flag.BoolVar(&confirm, "confirm", false, "Confirm destroying data without an interactive prompt")
+	flag.BoolVar(&dryRun, "dry-run", false, "Show the SQL that would run and affected row counts without executing it")
 }
 
 // Model map to get the table name for a model
 var modelMap = map[string]interface{}{
 	"animal": &model.Animal{},
 	"flower": &model.Flower{},
+	"outbox": &model.OutboxEvent{},
+	"user":   &model.User{},
 	// Add more models here as they are implemented
 }
 
+// keepOnTruncateAll names the models whose tables -truncate-all leaves alone: accounts, and
+// events not yet delivered. They can still be truncated one at a time with -truncate
+var keepOnTruncateAll = map[string]bool{
+	"outbox": true,
+	"user":   true,
+}
+
 func main() {
 	flag.Parse()
 
@@ -53,6 +69,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Confirm with the user before destroying data (if not confirmed by flag or a dry run)
+	if !dryRun && !confirm && !confirmAction() {
+		fmt.Println("❌ Operation cancelled.")
+		os.Exit(0)
+	}
+
 	// Initialize the app
 	app, err := bootstrap.InitializeApp()
 	if err != nil {
@@ -67,17 +89,126 @@ func main() {
 	// Get dependencies
 	logger := app.Logger
 	db := app.DB.GetDB()
+	driver := app.Config.Database.Driver
 
 	// Process command
-	if truncateModel != "" {
-		truncateModelTable(logger, db, truncateModel)
+	if dryRun {
+		previewTruncation(db, driver)
+	} else if truncateModel != "" {
+		truncateModelTable(logger, db, driver, truncateModel)
 	} else if truncateAll {
-		truncateAllTables(logger, db)
+		truncateAllTables(logger, db, driver)
+	}
+}
+
+// previewTruncation prints the statements a truncation would execute and how many
+// rows each affected table currently holds, without changing anything
+func previewTruncation(db *gorm.DB, driver string) {
+	var tables, statements, followUp []string
+
+	if truncateAll {
+		tables = truncateAllTableNames()
+		statements = truncateAllStatements(driver, tables)
+		followUp = resetIdentityStatements(driver, tables)
+	} else {
+		modelName := strings.ToLower(truncateModel)
+		model, exists := modelMap[modelName]
+		if !exists {
+			fmt.Printf("❌ Model '%s' not found\n", modelName)
+			showAvailableModels()
+			os.Exit(1)
+		}
+		tableName := tableNameFor(modelName, model)
+		tables = []string{tableName}
+		statements = []string{truncateStatement(driver, tableName)}
+	}
+
+	fmt.Println("🔍 Dry run: no data will be changed")
+	fmt.Println("")
+	fmt.Println("Tables affected:")
+	var total int64
+	for _, tableName := range tables {
+		var count int64
+		if err := db.Table(tableName).Count(&count).Error; err != nil {
+			fmt.Printf("  - %s (row count unavailable: %v)\n", tableName, err)
+			continue
+		}
+		total += count
+		fmt.Printf("  - %s: %d rows\n", tableName, count)
+	}
+	fmt.Printf("Total rows that would be deleted: %d\n", total)
+
+	fmt.Println("")
+	if truncateAll {
+		fmt.Println("SQL that would run in one transaction:")
+	} else {
+		fmt.Println("SQL that would run:")
+	}
+	for _, stmt := range statements {
+		fmt.Printf("  %s;\n", stmt)
+	}
+
+	if len(followUp) > 0 {
+		fmt.Println("SQL that would run after the transaction commits:")
+		for _, stmt := range followUp {
+			fmt.Printf("  %s;\n", stmt)
+		}
+	}
+}
+
+// confirmAction asks the user to confirm the truncation
+func confirmAction() bool {
+	fmt.Println("⚠️ WARNING: This operation will permanently delete data from:")
+	if truncateAll {
+		for _, tableName := range truncateAllTableNames() {
+			fmt.Printf("  - %s\n", tableName)
+		}
+	} else {
+		fmt.Printf("  - the table for model '%s'\n", strings.ToLower(truncateModel))
+	}
+
+	fmt.Println("\nThis operation cannot be undone. Do you want to continue? (y/n)")
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	return response == "y" || response == "yes"
+}
+
+// sortedModelNames returns the registered model names in a deterministic order
+func sortedModelNames() []string {
+	names := make([]string, 0, len(modelMap))
+	for modelName := range modelMap {
+		names = append(names, modelName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// truncateAllTableNames returns the tables -truncate-all empties, in a deterministic order
+func truncateAllTableNames() []string {
+	tables := make([]string, 0, len(modelMap))
+	for _, modelName := range sortedModelNames() {
+		if !keepOnTruncateAll[modelName] {
+			tables = append(tables, tableNameFor(modelName, modelMap[modelName]))
+		}
 	}
+	return tables
+}
+
+// tableNameFor returns the table name of a model
+func tableNameFor(modelName string, model interface{}) string {
+	// Check if the model implements TableName() method
+	if tableNamer, ok := model.(interface{ TableName() string }); ok {
+		return tableNamer.TableName()
+	}
+	// Default table name (lowercase model name with 's' appended)
+	return modelName + "s"
 }
 
 // truncateModelTable truncates a single table based on the model name
-func truncateModelTable(logger *zap.Logger, db *gorm.DB, modelName string) {
+func truncateModelTable(logger *zap.Logger, db *gorm.DB, driver, modelName string) {
 	modelName = strings.ToLower(modelName)
 
 	// Check if model exists
@@ -89,20 +220,11 @@ func truncateModelTable(logger *zap.Logger, db *gorm.DB, modelName string) {
 		os.Exit(1)
 	}
 
-	// Get the table name using GORM's TableName method if available
-	tableName := ""
-
-	// Check if the model implements TableName() method
-	if tableNamer, ok := model.(interface{ TableName() string }); ok {
-		tableName = tableNamer.TableName()
-	} else {
-		// Default table name (lowercase model name with 's' appended)
-		tableName = modelName + "s"
-	}
+	tableName := tableNameFor(modelName, model)
 
 	// Execute TRUNCATE statement
 	logger.Info("Truncating table", zap.String("table", tableName))
-	result := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s", tableName))
+	result := db.Exec(truncateStatement(driver, tableName))
 
 	// Check for errors
 	if result.Error != nil {
@@ -118,51 +240,97 @@ func truncateModelTable(logger *zap.Logger, db *gorm.DB, modelName string) {
 	logger.Info("Table truncated successfully", zap.String("table", tableName))
 }
 
-// truncateAllTables truncates all tables in the database
-func truncateAllTables(logger *zap.Logger, db *gorm.DB) {
+// truncateAllTables empties all tables in one transaction, so a failure on any
+// table leaves every table untouched, and exits with a non-zero code on failure
+func truncateAllTables(logger *zap.Logger, db *gorm.DB, driver string) {
 	logger.Info("Truncating all tables")
 
-	// Execute SET FOREIGN_KEY_CHECKS=0 to temporarily disable foreign key constraints
-	db.Exec("SET FOREIGN_KEY_CHECKS=0")
-
-	// Truncate each table
-	for modelName, model := range modelMap {
-		// Get table name
-		tableName := ""
-		if tableNamer, ok := model.(interface{ TableName() string }); ok {
-			tableName = tableNamer.TableName()
-		} else {
-			tableName = modelName + "s"
-		}
+	tables := truncateAllTableNames()
 
-		// Execute TRUNCATE statement
-		if verbose {
-			logger.Info("Truncating table", zap.String("table", tableName))
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if driver == config.DBDriverMySQL {
+			// Session variables survive a rollback, so restore the check before the
+			// connection goes back to the pool even if a statement fails
+			defer tx.Exec("SET FOREIGN_KEY_CHECKS=1")
 		}
 
-		result := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s", tableName))
-
-		// Check for errors but continue with other tables
-		if result.Error != nil {
-			logger.Error("Failed to truncate table",
-				zap.String("table", tableName),
-				zap.Error(result.Error),
-			)
+		for _, stmt := range truncateAllStatements(driver, tables) {
 			if verbose {
-				fmt.Printf("❌ Failed to truncate table '%s': %v\n",
-					tableName, result.Error)
+				logger.Info("Executing statement", zap.String("sql", stmt))
 			}
-		} else if verbose {
-			fmt.Printf("✅ Truncated table '%s'\n", tableName)
+
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("%s: %w", stmt, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to truncate tables, no table was changed", zap.Error(err))
+		fmt.Printf("❌ Failed to truncate tables, no table was changed: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Restart IDs like TRUNCATE would; MySQL can't do this inside the transaction
+	for _, stmt := range resetIdentityStatements(driver, tables) {
+		if err := db.Exec(stmt).Error; err != nil {
+			logger.Warn("Failed to reset auto increment", zap.String("sql", stmt), zap.Error(err))
 		}
 	}
 
-	// Execute SET FOREIGN_KEY_CHECKS=1 to re-enable foreign key constraints
-	db.Exec("SET FOREIGN_KEY_CHECKS=1")
+	if verbose {
+		for _, tableName := range tables {
+			fmt.Printf("✅ Truncated table '%s'\n", tableName)
+		}
+	}
 
 	logger.Info("All tables truncated successfully")
 }
 
+// truncateStatement returns the dialect-specific statement that empties a table.
+// On PostgreSQL, CASCADE also empties tables referencing it and identity sequences are reset
+// to match MySQL's TRUNCATE resetting AUTO_INCREMENT
+func truncateStatement(driver, tableName string) string {
+	if driver == config.DBDriverPostgres {
+		return fmt.Sprintf(`TRUNCATE TABLE "%s" RESTART IDENTITY CASCADE`, tableName)
+	}
+	return fmt.Sprintf("TRUNCATE TABLE `%s`", tableName)
+}
+
+// truncateAllStatements returns the statements that empty all tables inside one transaction.
+// PostgreSQL truncates them together in a single statement, which is transactional and
+// handles foreign keys between them. MySQL's TRUNCATE commits implicitly, so rows are
+// deleted instead with foreign key checks disabled on the transaction's connection
+func truncateAllStatements(driver string, tables []string) []string {
+	if driver == config.DBDriverPostgres {
+		quoted := make([]string, len(tables))
+		for i, tableName := range tables {
+			quoted[i] = fmt.Sprintf(`"%s"`, tableName)
+		}
+		return []string{fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", strings.Join(quoted, ", "))}
+	}
+
+	statements := []string{"SET FOREIGN_KEY_CHECKS=0"}
+	for _, tableName := range tables {
+		statements = append(statements, fmt.Sprintf("DELETE FROM `%s`", tableName))
+	}
+	return append(statements, "SET FOREIGN_KEY_CHECKS=1")
+}
+
+// resetIdentityStatements returns the statements that restart ID generation after
+// truncateAllStatements has committed; PostgreSQL already did so with RESTART IDENTITY
+func resetIdentityStatements(driver string, tables []string) []string {
+	if driver == config.DBDriverPostgres {
+		return nil
+	}
+
+	statements := make([]string, 0, len(tables))
+	for _, tableName := range tables {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE `%s` AUTO_INCREMENT = 1", tableName))
+	}
+	return statements
+}
+
 // showHelp displays help information
 func showHelp() {
 	fmt.Println("🗄️ Database Operations Tool")
@@ -172,13 +340,16 @@ func showHelp() {
 	fmt.Println("")
 	fmt.Println("Options:")
 	fmt.Println("  -truncate MODEL  Truncate a specific table based on model name")
-	fmt.Println("  -truncate-all    Truncate all tables")
+	fmt.Println("  -truncate-all    Truncate all tables except users and outbox")
 	fmt.Println("  -v               Verbose output")
+	fmt.Println("  -confirm         Skip the confirmation prompt (for scripts and CI)")
+	fmt.Println("  -dry-run         Show the SQL and affected row counts without executing anything")
 	fmt.Println("  -help, -h        Show this help message")
 	fmt.Println("")
 	fmt.Println("Examples:")
-	fmt.Println("  go run ./cmd/db -truncate animal    # Truncate only the animals table")
-	fmt.Println("  go run ./cmd/db -truncate-all       # Truncate all tables")
+	fmt.Println("  go run ./cmd/db -truncate animal         # Truncate only the animals table")
+	fmt.Println("  go run ./cmd/db -truncate-all            # Truncate all tables")
+	fmt.Println("  go run ./cmd/db -truncate-all -dry-run   # Preview truncating all tables")
 	fmt.Println("")
 	showAvailableModels()
 	fmt.Println("")
@@ -190,7 +361,7 @@ func showHelp() {
 // showAvailableModels displays a list of available models
 func showAvailableModels() {
 	fmt.Println("Available models:")
-	for modelName := range modelMap {
+	for _, modelName := range sortedModelNames() {
 		fmt.Printf("  - %s\n", modelName)
 	}
 }
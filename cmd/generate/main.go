@@ -0,0 +1,631 @@
+// Package main provides a command line tool for scaffolding a new resource: its model,
+// repository, service, controller, seeder and service tests, wired into the application
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/linkeunid/go-api/internal/codegen"
+	"github.com/linkeunid/go-api/pkg/config"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// Command line flags
+var (
+	resourceName string
+	fieldsSpec   string
+	idType       string
+	dryRun       bool
+	force        bool
+)
+
+func init() {
+	flag.StringVar(&resourceName, "resource", "", "Resource name in PascalCase (e.g., Plant)")
+	flag.StringVar(&fieldsSpec, "fields", "", "Comma-separated name:type fields (e.g., \"name:string,height:int\")")
+	flag.StringVar(&idType, "id", "", "Primary key type: int or ulid (default DB_ID_TYPE)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the generated files instead of writing them")
+	flag.BoolVar(&force, "force", false, "Overwrite files that already exist")
+}
+
+// fieldType describes how fields of a type are declared, stored, validated and faked
+type fieldType struct {
+	GoType      string
+	Gorm        string
+	Validate    string
+	Example     string
+	Fake        string // Expression the seeder uses to generate a value
+	Sample      string // Literal value used in the generated tests
+	SwaggerType string
+	Filterable  bool
+	FilterKind  string // repository.FilterKind constant filter values are checked against
+	Like        bool   // Whether the field supports the _like filter
+	Required    bool   // Whether the service rejects an empty value
+	UsesFaker   bool
+	UsesTime    bool
+}
+
+// fieldTypes lists the supported field types, keyed by the name used in -fields
+var fieldTypes = map[string]fieldType{
+	"string": {
+		GoType: "string", Gorm: "type:varchar(255);not null", Validate: "required,max=255",
+		Example: "Example", Fake: "faker.Word()", Sample: `"Example"`, SwaggerType: "string",
+		Filterable: true, FilterKind: "FilterString", Like: true, Required: true, UsesFaker: true,
+	},
+	"text": {
+		GoType: "string", Gorm: "type:text", Validate: "omitempty,max=5000",
+		Example: "A longer description", Fake: "faker.Sentence()", Sample: `"A longer description"`, SwaggerType: "string",
+		UsesFaker: true,
+	},
+	"int": {
+		GoType: "int", Gorm: "type:int;not null;default:0",
+		Example: "1", Fake: "rng.Intn(100) + 1", Sample: "1", SwaggerType: "int",
+		Filterable: true, FilterKind: "FilterInt",
+	},
+	"int64": {
+		GoType: "int64", Gorm: "type:bigint;not null;default:0",
+		Example: "1", Fake: "rng.Int63n(1000000) + 1", Sample: "1", SwaggerType: "int",
+		Filterable: true, FilterKind: "FilterInt",
+	},
+	"float": {
+		GoType: "float64", Gorm: "type:double precision;not null;default:0",
+		Example: "1.5", Fake: "rng.Float64() * 100", Sample: "1.5", SwaggerType: "number",
+		Filterable: true, FilterKind: "FilterFloat",
+	},
+	"bool": {
+		GoType: "bool", Gorm: "type:boolean;default:false",
+		Example: "true", Fake: "rng.Intn(2) == 1", Sample: "true", SwaggerType: "bool",
+		Filterable: true, FilterKind: "FilterBool",
+	},
+	"time": {
+		GoType: "time.Time", Gorm: "not null", Validate: "required",
+		Example: "2025-01-01T00:00:00Z", Fake: "now().Add(-time.Duration(rng.Intn(365)) * 24 * time.Hour)",
+		Sample: "time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)", SwaggerType: "string",
+		Filterable: true, FilterKind: "FilterTime", UsesTime: true,
+	},
+}
+
+// fieldTypeAliases maps alternative type names to the supported ones
+var fieldTypeAliases = map[string]string{
+	"float64":  "float",
+	"boolean":  "bool",
+	"datetime": "time",
+}
+
+// reservedColumns are generated for every resource and can't be declared as fields
+var reservedColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true}
+
+// initialisms are the words Go spells in all capitals within identifiers
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// Field is a resource field as the templates see it
+type Field struct {
+	fieldType
+	Name   string // Go field name, e.g. "PlantHeight"
+	Column string // Column and JSON name, e.g. "plant_height"
+	Human  string // Name used in documentation, e.g. "plant height"
+}
+
+// Gorm returns the field's gorm tag, indexing the columns that may be filtered on
+func (f Field) Gorm(resourceKey string) string {
+	if f.Filterable {
+		return f.fieldType.Gorm + ";index:idx_" + resourceKey + "_" + f.Column
+	}
+	return f.fieldType.Gorm
+}
+
+// Resource holds every spelling of a resource name the templates need
+type Resource struct {
+	Module           string  // Module path of the project, e.g. "github.com/linkeunid/go-api"
+	Name             string  // Go type name, e.g. "PlantPot"
+	Plural           string  // Plural Go name, e.g. "PlantPots"
+	Var              string  // Variable name, e.g. "plantPot"
+	PluralVar        string  // Plural variable name, e.g. "plantPots"
+	Key              string  // snake_case name used for files and keys, e.g. "plant_pot"
+	Table            string  // Table name, e.g. "plant_pots"
+	Route            string  // Route path segment and Swagger tag, e.g. "plant-pots"
+	IDParam          string  // URL parameter holding the ID, e.g. "plantPotID"
+	Human            string  // Name used in messages, e.g. "plant pot"
+	HumanPlural      string  // Plural name used in messages, e.g. "plant pots"
+	HumanPluralTitle string  // Plural name starting a sentence, e.g. "Plant pots"
+	SeederName       string  // Name the seeder registers under, e.g. "plantpot"
+	Fields           []Field // Declared fields, in order
+	ULID             bool    // Whether the primary key is a ULID rather than an auto-increment integer
+}
+
+// IDType returns the Go type of the primary key
+func (r Resource) IDType() string {
+	if r.ULID {
+		return "string"
+	}
+	return "uint64"
+}
+
+// IDZero returns the zero value of the primary key, which no stored record has
+func (r Resource) IDZero() string {
+	if r.ULID {
+		return `""`
+	}
+	return "0"
+}
+
+// IDLog returns the zap field constructor for logging the primary key
+func (r Resource) IDLog() string {
+	if r.ULID {
+		return "zap.String"
+	}
+	return "zap.Uint64"
+}
+
+// IDVar returns the name of the variable holding an ID parsed from a request
+func (r Resource) IDVar() string {
+	if r.ULID {
+		return "ulid"
+	}
+	return "numericID"
+}
+
+// PathID returns the n-th sample ID as it appears in a request path
+func (r Resource) PathID(n int) string {
+	if r.ULID {
+		return fmt.Sprintf("%026d", n)
+	}
+	return fmt.Sprint(n)
+}
+
+// KeyID returns a Go expression for the n-th sample ID as the repository receives it
+func (r Resource) KeyID(n int) string {
+	if r.ULID {
+		return fmt.Sprintf("%q", r.PathID(n))
+	}
+	return fmt.Sprintf("uint64(%d)", n)
+}
+
+// IDField returns the keyed composite literal element setting a model's ID to the n-th sample ID
+func (r Resource) IDField(n int) string {
+	if r.ULID {
+		return fmt.Sprintf("WithULID: model.WithULID{ID: %s}", r.KeyID(n))
+	}
+	return fmt.Sprintf("ID: %d", n)
+}
+
+// ColumnList lists every column for the Swagger fields parameter
+func (r Resource) ColumnList() string {
+	columns := []string{"id"}
+	for _, f := range r.Fields {
+		columns = append(columns, f.Column)
+	}
+	return strings.Join(append(columns, "created_at", "updated_at"), ", ")
+}
+
+// SortableList lists the columns results may be sorted by for the Swagger sort parameter
+func (r Resource) SortableList() string {
+	columns := []string{"id"}
+	for _, f := range r.Fields {
+		if f.Filterable {
+			columns = append(columns, f.Column)
+		}
+	}
+	return strings.Join(append(columns, "created_at", "updated_at"), ", ")
+}
+
+// UsesFaker reports whether the seeder needs the faker package
+func (r Resource) UsesFaker() bool {
+	for _, f := range r.Fields {
+		if f.UsesFaker {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesTime reports whether the seeder needs the time package
+func (r Resource) UsesTime() bool {
+	for _, f := range r.Fields {
+		if f.UsesTime {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedFile is a file rendered from a template
+type generatedFile struct {
+	Path    string
+	Content []byte
+}
+
+// fileTemplates maps each template to the path of the file it generates; %s is the resource key
+var fileTemplates = []struct {
+	template string
+	path     string
+}{
+	{"model.go.tmpl", "internal/model/%s.go"},
+	{"repository.go.tmpl", "internal/repository/%s_repository.go"},
+	{"service.go.tmpl", "internal/service/%s_service.go"},
+	{"service_test.go.tmpl", "internal/service/%s_service_test.go"},
+	{"controller.go.tmpl", "internal/controller/%s_controller.go"},
+	{"seeder.go.tmpl", "pkg/seeder/%s_seeder.go"},
+}
+
+func main() {
+	flag.Parse()
+
+	if resourceName == "" || fieldsSpec == "" {
+		fmt.Println("❌ Error: -resource and -fields are required.")
+		fmt.Println("Example: go run ./cmd/generate -resource Plant -fields \"name:string,height:int\"")
+		os.Exit(1)
+	}
+
+	module, err := readModulePath("go.mod")
+	if err != nil {
+		fmt.Printf("❌ Error reading go.mod: %v\n", err)
+		os.Exit(1)
+	}
+
+	fields, err := parseFields(fieldsSpec)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	resource, err := newResource(module, resourceName, fields)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if idType == "" {
+		idType = config.LoadConfig().Database.IDType
+	}
+	switch strings.ToLower(idType) {
+	case config.IDTypeInt:
+	case config.IDTypeULID:
+		resource.ULID = true
+	default:
+		fmt.Printf("❌ Error: unsupported ID type %q (supported: int, ulid)\n", idType)
+		os.Exit(1)
+	}
+
+	files, err := renderFiles(resource)
+	if err != nil {
+		fmt.Printf("❌ Error rendering templates: %v\n", err)
+		os.Exit(1)
+	}
+
+	wiring, err := wireResource(resource)
+	if err != nil {
+		fmt.Printf("❌ Error wiring %s into bootstrap: %v\n", resource.Name, err)
+		os.Exit(1)
+	}
+
+	if dryRun {
+		for _, file := range append(files, wiring...) {
+			fmt.Printf("==> %s <==\n%s\n", file.Path, file.Content)
+		}
+		fmt.Println("ℹ️ Dry run: no files were written")
+		return
+	}
+
+	if !force {
+		for _, file := range files {
+			if _, err := os.Stat(file.Path); err == nil {
+				fmt.Printf("❌ Error: %s already exists (use -force to overwrite)\n", file.Path)
+				os.Exit(1)
+			}
+		}
+	}
+
+	fmt.Printf("🏗️ Generating %s resource...\n", resource.Name)
+	for _, file := range append(files, wiring...) {
+		if err := os.WriteFile(file.Path, file.Content, 0644); err != nil {
+			fmt.Printf("❌ Error writing %s: %v\n", file.Path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("  ✓ %s\n", file.Path)
+	}
+
+	// Register the new model and seeder with the database tools
+	for _, tool := range []string{"./cmd/model-mapper", "./cmd/seeder-mapper"} {
+		if err := runTool(tool); err != nil {
+			fmt.Printf("❌ Error running %s: %v\n", tool, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("✅ %s resource generated successfully!\n", resource.Name)
+	fmt.Println("")
+	fmt.Println("Next steps:")
+	fmt.Printf("1. Create its table: make migrate-from-model model=%s && make migrate\n", resource.Key)
+	fmt.Println("2. Regenerate the API documentation: make swagger")
+	fmt.Println("3. Review the generated files and run the tests: make test")
+}
+
+// readModulePath returns the module path declared in a go.mod file
+func readModulePath(goModPath string) (string, error) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no module directive in %s", goModPath)
+}
+
+// fieldNamePattern matches a snake_case field name
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// parseFields parses a comma-separated list of name:type pairs, e.g. "name:string,height:int".
+// Names may be given in snake_case or camelCase
+func parseFields(spec string) ([]Field, error) {
+	var fields []Field
+	seen := make(map[string]bool)
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, typeName, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("field %q must be written as name:type", pair)
+		}
+
+		column := codegen.SnakeCase(strings.TrimSpace(name))
+		if !fieldNamePattern.MatchString(column) {
+			return nil, fmt.Errorf("invalid field name %q", name)
+		}
+		if reservedColumns[column] {
+			return nil, fmt.Errorf("field %q is generated for every resource", column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate field %q", column)
+		}
+		seen[column] = true
+
+		typeName = strings.ToLower(strings.TrimSpace(typeName))
+		if alias, ok := fieldTypeAliases[typeName]; ok {
+			typeName = alias
+		}
+		ft, ok := fieldTypes[typeName]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %q for field %q (supported: string, text, int, int64, float, bool, time)", typeName, column)
+		}
+
+		words := strings.Split(column, "_")
+		fields = append(fields, Field{
+			fieldType: ft,
+			Name:      pascalCase(words),
+			Column:    column,
+			Human:     strings.Join(words, " "),
+		})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+	return fields, nil
+}
+
+// resourceNamePattern matches a PascalCase resource name
+var resourceNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// newResource derives every spelling of the resource name
+func newResource(module, name string, fields []Field) (Resource, error) {
+	if !resourceNamePattern.MatchString(name) {
+		return Resource{}, fmt.Errorf("resource name %q must be PascalCase, e.g. Plant", name)
+	}
+
+	key := codegen.SnakeCase(name)
+	words := strings.Split(key, "_")
+	varName := camelCase(words)
+	if token.IsKeyword(varName) {
+		return Resource{}, fmt.Errorf("resource name %q is a Go keyword when lowercased", name)
+	}
+
+	human := strings.Join(words, " ")
+	humanPlural := pluralize(human)
+
+	return Resource{
+		Module:           module,
+		Name:             name,
+		Plural:           pluralize(name),
+		Var:              varName,
+		PluralVar:        pluralize(varName),
+		Key:              key,
+		Table:            pluralize(key),
+		Route:            strings.ReplaceAll(pluralize(key), "_", "-"),
+		IDParam:          varName + "ID",
+		Human:            human,
+		HumanPlural:      humanPlural,
+		HumanPluralTitle: strings.ToUpper(humanPlural[:1]) + humanPlural[1:],
+		SeederName:       strings.ToLower(name),
+		Fields:           fields,
+	}, nil
+}
+
+// pascalCase joins snake_case words into a Go exported identifier, e.g. ["user", "id"] -> "UserID"
+func pascalCase(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if initialisms[word] {
+			b.WriteString(strings.ToUpper(word))
+		} else if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// camelCase joins snake_case words into a Go unexported identifier, e.g. ["plant", "pot"] -> "plantPot"
+func camelCase(words []string) string {
+	return words[0] + pascalCase(words[1:])
+}
+
+// pluralize returns the English plural of a name by its last word, e.g. "Category" -> "Categories"
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
+
+// renderFiles renders every template for the resource, formatted with go/format
+func renderFiles(resource Resource) ([]generatedFile, error) {
+	tmpl, err := template.New("").ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]generatedFile, 0, len(fileTemplates))
+	for _, ft := range fileTemplates {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, ft.template, resource); err != nil {
+			return nil, fmt.Errorf("%s: %w", ft.template, err)
+		}
+
+		content, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: generated invalid code: %w", ft.template, err)
+		}
+
+		files = append(files, generatedFile{
+			Path:    filepath.FromSlash(fmt.Sprintf(ft.path, resource.Key)),
+			Content: content,
+		})
+	}
+	return files, nil
+}
+
+// bootstrapFiles lists the files the resource is wired into, with the code inserted above each
+// scaffold marker comment. %[1]s is the resource name and %[2]s its variable name
+var bootstrapFiles = []struct {
+	path     string
+	snippets map[string]string
+}{
+	{
+		path: "internal/bootstrap/app.go",
+		snippets: map[string]string{
+			"app-fields":    "%[1]sController *controller.%[1]s",
+			"services":      "%[2]sRepo := repository.New%[1]sRepository(dbWrapper, logger)\n%[2]sService := service.New%[1]sService(cfg, logger, %[2]sRepo)",
+			"cache-warmers": "cacheWarmer.Register(\"%[2]s\", warmFirstPage(%[2]sRepo.FindAllPaginated))",
+			"controllers":   "%[2]sController := controller.New%[1]s(%[2]sService)",
+			"app-values":    "%[1]sController: %[2]sController,",
+		},
+	},
+	{
+		path: "internal/bootstrap/server.go",
+		snippets: map[string]string{
+			"routes": "// %[1]s routes\napp.%[1]sController.RegisterRoutes(r.With(maintenance))\n",
+		},
+	},
+	{
+		path: "internal/bootstrap/server_test.go",
+		snippets: map[string]string{
+			"test-app-values": "%[1]sController: controller.New%[1]s(nil),",
+		},
+	},
+}
+
+// wireResource returns the bootstrap files with the resource's repository, service,
+// controller and routes added
+func wireResource(resource Resource) ([]generatedFile, error) {
+	var files []generatedFile
+	for _, bf := range bootstrapFiles {
+		src, err := os.ReadFile(bf.path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Wiring the same resource twice would not compile
+		if bytes.Contains(src, []byte(resource.Name+"Controller")) {
+			continue
+		}
+
+		snippets := make(map[string]string, len(bf.snippets))
+		for marker, snippet := range bf.snippets {
+			snippets[marker] = fmt.Sprintf(snippet, resource.Name, resource.Var)
+		}
+
+		content, err := insertAtMarkers(src, snippets)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", bf.path, err)
+		}
+		files = append(files, generatedFile{Path: bf.path, Content: content})
+	}
+	return files, nil
+}
+
+// insertAtMarkers inserts each snippet above its "// scaffold:<marker>" comment, indented
+// like the comment, and formats the result
+func insertAtMarkers(src []byte, snippets map[string]string) ([]byte, error) {
+	lines := strings.Split(string(src), "\n")
+	found := make(map[string]bool, len(snippets))
+
+	var out []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if marker, ok := strings.CutPrefix(trimmed, "// scaffold:"); ok {
+			if snippet, ok := snippets[marker]; ok {
+				indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+				for _, snippetLine := range strings.Split(snippet, "\n") {
+					if snippetLine == "" {
+						out = append(out, "")
+						continue
+					}
+					out = append(out, indent+snippetLine)
+				}
+				found[marker] = true
+			}
+		}
+		out = append(out, line)
+	}
+
+	for marker := range snippets {
+		if !found[marker] {
+			return nil, fmt.Errorf("missing // scaffold:%s marker", marker)
+		}
+	}
+
+	return format.Source([]byte(strings.Join(out, "\n")))
+}
+
+// runTool runs one of the project's command line tools with go run
+func runTool(path string) error {
+	cmd := exec.Command("go", "run", path)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := parseFields("name:string, heightCm:int,image_url:STRING,notes:text,bloomed_at:datetime")
+	require.NoError(t, err)
+	require.Len(t, fields, 5)
+
+	assert.Equal(t, "Name", fields[0].Name)
+	assert.Equal(t, "HeightCm", fields[1].Name)
+	assert.Equal(t, "height_cm", fields[1].Column)
+	assert.Equal(t, "height cm", fields[1].Human)
+	assert.Equal(t, "ImageURL", fields[2].Name)
+	assert.Equal(t, "image_url", fields[2].Column)
+	assert.Equal(t, "string", fields[3].GoType)
+	assert.False(t, fields[3].Filterable)
+	assert.Equal(t, "time.Time", fields[4].GoType)
+
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "Empty", spec: " , "},
+		{name: "MissingType", spec: "name"},
+		{name: "UnknownType", spec: "name:decimal"},
+		{name: "InvalidName", spec: "1name:string"},
+		{name: "Reserved", spec: "id:int"},
+		{name: "Duplicate", spec: "name:string,Name:text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFields(tt.spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Resource
+	}{
+		{
+			name: "Plant",
+			expected: Resource{
+				Name: "Plant", Plural: "Plants", Var: "plant", PluralVar: "plants", Key: "plant", Table: "plants",
+				Route: "plants", IDParam: "plantID", Human: "plant", HumanPlural: "plants", HumanPluralTitle: "Plants",
+				SeederName: "plant",
+			},
+		},
+		{
+			name: "PlantCategory",
+			expected: Resource{
+				Name: "PlantCategory", Plural: "PlantCategories", Var: "plantCategory", PluralVar: "plantCategories",
+				Key: "plant_category", Table: "plant_categories", Route: "plant-categories", IDParam: "plantCategoryID",
+				Human: "plant category", HumanPlural: "plant categories", HumanPluralTitle: "Plant categories",
+				SeederName: "plantcategory",
+			},
+		},
+		{
+			name: "HTTPProbe",
+			expected: Resource{
+				Name: "HTTPProbe", Plural: "HTTPProbes", Var: "httpProbe", PluralVar: "httpProbes", Key: "http_probe",
+				Table: "http_probes", Route: "http-probes", IDParam: "httpProbeID", Human: "http probe",
+				HumanPlural: "http probes", HumanPluralTitle: "Http probes", SeederName: "httpprobe",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, err := newResource("example.com/app", tt.name, nil)
+			require.NoError(t, err)
+
+			tt.expected.Module = "example.com/app"
+			assert.Equal(t, tt.expected, resource)
+		})
+	}
+
+	for _, name := range []string{"plant", "Plant_Pot", "Type", ""} {
+		t.Run("Invalid"+name, func(t *testing.T) {
+			_, err := newResource("example.com/app", name, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "plant", expected: "plants"},
+		{name: "Category", expected: "Categories"},
+		{name: "day", expected: "days"},
+		{name: "box", expected: "boxes"},
+		{name: "Branch", expected: "Branches"},
+		{name: "bus", expected: "buses"},
+		{name: "API", expected: "APIs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pluralize(tt.name))
+		})
+	}
+}
+
+func TestRenderFiles(t *testing.T) {
+	fields, err := parseFields("name:string,height:int,notes:text,blooming:bool,planted_at:time,price:float")
+	require.NoError(t, err)
+
+	resource, err := newResource("github.com/linkeunid/go-api", "PlantPot", fields)
+	require.NoError(t, err)
+
+	// renderFiles fails if any template produces code go/format can't parse
+	files, err := renderFiles(resource)
+	require.NoError(t, err)
+	require.Len(t, files, len(fileTemplates))
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+		assert.NotContains(t, string(file.Content), "<no value>", file.Path)
+	}
+	assert.Equal(t, []string{
+		"internal/model/plant_pot.go",
+		"internal/repository/plant_pot_repository.go",
+		"internal/service/plant_pot_service.go",
+		"internal/service/plant_pot_service_test.go",
+		"internal/controller/plant_pot_controller.go",
+		"pkg/seeder/plant_pot_seeder.go",
+	}, paths)
+
+	model := string(files[0].Content)
+	assert.Contains(t, model, "type PlantPot struct")
+	assert.Contains(t, model, `gorm:"type:varchar(255);not null;index:idx_plant_pot_name"`)
+	assert.Contains(t, model, `gorm:"type:text" example:`)
+	assert.Contains(t, model, `return "plant_pots"`)
+}
+
+func TestRenderFiles_ULID(t *testing.T) {
+	fields, err := parseFields("name:string")
+	require.NoError(t, err)
+
+	resource, err := newResource("github.com/linkeunid/go-api", "Plant", fields)
+	require.NoError(t, err)
+	resource.ULID = true
+
+	files, err := renderFiles(resource)
+	require.NoError(t, err)
+
+	model := string(files[0].Content)
+	assert.Contains(t, model, "\tWithULID\n")
+	assert.NotContains(t, model, "autoIncrement")
+
+	repository := string(files[1].Content)
+	assert.Contains(t, repository, "FindByID(ctx context.Context, id string) (PlantResult, error)")
+	assert.Contains(t, repository, "withTxWrites[string](ctx)")
+	assert.NotContains(t, repository, "uint64")
+
+	service := string(files[2].Content)
+	assert.Contains(t, service, "ulid, err := util.ParseULID(id)")
+	assert.NotContains(t, service, "strconv")
+
+	tests := string(files[3].Content)
+	assert.Contains(t, tests, `WithULID: model.WithULID{ID: "00000000000000000000000001"}`)
+	assert.Contains(t, tests, `mockRepo.On("FindByID", mock.Anything, "00000000000000000000000999")`)
+}
+
+func TestInsertAtMarkers(t *testing.T) {
+	src := `package bootstrap
+
+type App struct {
+	FlowerController *controller.Flower
+	// scaffold:app-fields
+}
+
+func routes(app *App) {
+	r.Group(func(r chi.Router) {
+		// Flower routes
+		app.FlowerController.RegisterRoutes(r)
+
+		// scaffold:routes
+	})
+}
+`
+
+	result, err := insertAtMarkers([]byte(src), map[string]string{
+		"app-fields": "PlantController *controller.Plant",
+		"routes":     "// Plant routes\napp.PlantController.RegisterRoutes(r)\n",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `package bootstrap
+
+type App struct {
+	FlowerController *controller.Flower
+	PlantController  *controller.Plant
+	// scaffold:app-fields
+}
+
+func routes(app *App) {
+	r.Group(func(r chi.Router) {
+		// Flower routes
+		app.FlowerController.RegisterRoutes(r)
+
+		// Plant routes
+		app.PlantController.RegisterRoutes(r)
+
+		// scaffold:routes
+	})
+}
+`, string(result))
+
+	t.Run("MissingMarker", func(t *testing.T) {
+		_, err := insertAtMarkers([]byte(src), map[string]string{"services": "x := 1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "scaffold:services")
+	})
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -11,133 +13,70 @@ import (
 	"strings"
 	"time"
 
-	"github.com/go-sql-driver/mysql"
-	"github.com/golang-migrate/migrate/v4"
-	mysqldriver "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/migrations"
 	"github.com/linkeunid/go-api/pkg/config"
-	gormMysql "gorm.io/driver/mysql"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/migration"
 	"gorm.io/gorm"
 )
 
-const migrationsPath = "migrations"
+// migrationsDir returns the directory holding migrations for the configured driver
+func migrationsDir() string {
+	return filepath.FromSlash(migration.Dir(config.LoadConfig().Database.Driver))
+}
 
 // ModelRegistry contains all models that can be used for migrations
 var ModelRegistry = map[string]interface{}{
 	"animal": &model.Animal{},
 	"flower": &model.Flower{},
+	"outbox": &model.OutboxEvent{},
+	"user":   &model.User{},
 }
 
-// MigrationGenerator handles the generation of migrations using GORM
-type MigrationGenerator struct {
-	db *gorm.DB
-}
-
-// NewMigrationGenerator creates a new migration generator instance
-func NewMigrationGenerator() (*MigrationGenerator, error) {
+// newManager connects to the configured database and returns a migration manager for it
+// If embedded is true, migrations are read from the files compiled into the binary
+func newManager(embedded bool) *migration.Manager {
 	cfg := config.LoadConfig()
 
-	db, err := gorm.Open(gormMysql.Open(cfg.Database.DSN), &gorm.Config{
-		DisableForeignKeyConstraintWhenMigrating: true,
-		DryRun:                                   true, // Enable dry run mode for DDL generation
-	})
+	dsn := cfg.Database.DSN
+	if cfg.Database.Driver == config.DBDriverMySQL {
+		dsn = prepareDSNForMigration(dsn)
+	}
+
+	// Retry while the database starts up, e.g. when migrations run next to a fresh container
+	var db *gorm.DB
+	err := database.ConnectWithRetry(context.Background(), cfg.Database.ConnectRetries, cfg.Database.ConnectBackoff,
+		func(attempt int, wait time.Duration, err error) {
+			log.Printf("Connection attempt %d failed, retrying in %s: %v", attempt, wait, err)
+		},
+		func() error {
+			var err error
+			db, err = database.Open(database.Dialector(cfg.Database.Driver, dsn), &gorm.Config{
+				DisableForeignKeyConstraintWhenMigrating: true,
+			})
+			return err
+		})
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	return &MigrationGenerator{db: db}, nil
-}
-
-// GenerateModelMigration generates SQL for creating and dropping a table based on a GORM model
-func (mg *MigrationGenerator) GenerateModelMigration(model interface{}) (upSQL, downSQL string, err error) {
-	// Parse the model to get table information
-	stmt := &gorm.Statement{DB: mg.db}
-	if err := stmt.Parse(model); err != nil {
-		return "", "", fmt.Errorf("failed to parse model: %w", err)
-	}
-
-	tableName := stmt.Schema.Table
-
-	// Create a temporary database connection for actual DDL operations
-	cfg := config.LoadConfig()
-	actualDB, err := gorm.Open(gormMysql.Open(cfg.Database.DSN), &gorm.Config{
-		DisableForeignKeyConstraintWhenMigrating: true,
-	})
-	if err != nil {
-		return "", "", fmt.Errorf("failed to connect to database for DDL: %w", err)
-	}
-
-	actualMigrator := actualDB.Migrator()
-
-	// Create the table temporarily to get the CREATE TABLE statement
-	if err := actualMigrator.CreateTable(model); err != nil {
-		return "", "", fmt.Errorf("failed to create table for DDL generation: %w", err)
+		log.Fatalf("Could not connect to database: %v", err)
 	}
 
-	// Get the underlying SQL database connection
-	sqlDB, err := actualDB.DB()
+	fsys, dir := migrationsSource(embedded)
+	manager, err := migration.NewManager(db, fsys, dir)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get SQL database connection: %w", err)
-	}
-
-	// Get the CREATE TABLE statement
-	var showTableName, createTableSQL string
-	query := fmt.Sprintf("SHOW CREATE TABLE `%s`", tableName)
-	if err := sqlDB.QueryRow(query).Scan(&showTableName, &createTableSQL); err != nil {
-		return "", "", fmt.Errorf("failed to get CREATE TABLE SQL: %w", err)
-	}
-
-	// Clean up the temporary table
-	if err := actualMigrator.DropTable(model); err != nil {
-		log.Printf("Warning: Failed to clean up temporary table %s: %v", tableName, err)
+		log.Fatalf("Failed to initialize migration manager: %v", err)
 	}
-
-	// Format the migration SQL
-	upSQL = mg.formatUpSQL(createTableSQL)
-	downSQL = mg.formatDownSQL(tableName)
-
-	return upSQL, downSQL, nil
-}
-
-// formatUpSQL formats the CREATE TABLE statement for the up migration
-func (mg *MigrationGenerator) formatUpSQL(createTableSQL string) string {
-	// Replace CREATE TABLE with CREATE TABLE IF NOT EXISTS
-	createTableSQL = strings.Replace(createTableSQL, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1)
-
-	return fmt.Sprintf(`-- Migration Up
--- SQL in section 'Up' is executed when this migration is applied
-
-%s;`, createTableSQL)
-}
-
-// formatDownSQL creates the DROP TABLE statement for the down migration
-func (mg *MigrationGenerator) formatDownSQL(tableName string) string {
-	return fmt.Sprintf(`-- Migration Down
--- SQL in section 'Down' is executed when this migration is rolled back
-
-DROP TABLE IF EXISTS `+"`%s`"+`;`, tableName)
-}
-
-// MigrationManager handles all migration operations
-type MigrationManager struct {
-	migrator  *migrate.Migrate
-	generator *MigrationGenerator
+	return manager
 }
 
-// NewMigrationManager creates a new migration manager
-func NewMigrationManager() (*MigrationManager, error) {
-	generator, err := NewMigrationGenerator()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create migration generator: %w", err)
+// migrationsSource returns the file system and directory holding the configured driver's
+// migrations, either compiled into the binary or in the migrations directory
+func migrationsSource(embedded bool) (fs.FS, string) {
+	driver := config.LoadConfig().Database.Driver
+	if embedded {
+		return migrations.FS, migration.EmbeddedDir(driver)
 	}
-
-	migrator := getMigrator()
-
-	return &MigrationManager{
-		migrator:  migrator,
-		generator: generator,
-	}, nil
+	return os.DirFS("."), migration.Dir(driver)
 }
 
 func main() {
@@ -150,12 +89,18 @@ func main() {
 		forceTo    = flag.Int("force", -1, "Force migration to a specific version")
 		steps      = flag.Int("steps", 0, "Number of migrations to apply (use with -up or -down)")
 		dryRun     = flag.Bool("dry-run", false, "Show what would be done without actually running migrations")
-		fromModel  = flag.String("from-model", "", "Create migration from a model (e.g., animal)")
+		fromModel  = flag.String("from-model", "", "Create migration from a model or a comma-separated list of models (e.g., animal or animal,flower)")
+		tableName  = flag.String("table", "", "Override the table name of a single -from-model model (e.g., for a renamed or join table)")
+		fromDiff   = flag.String("from-model-diff", "", "Create an ALTER migration from the difference between a model and its live table")
+		destroy    = flag.Bool("allow-destructive", false, "Allow -from-model-diff to drop columns that are not in the model")
 		listModels = flag.Bool("list-models", false, "List available models for migrations")
 		allModels  = flag.Bool("all-models", false, "Create migrations from all available models (skip existing)")
+		embedded   = flag.Bool("embedded", false, "Apply migrations compiled into the binary instead of the migrations directory (default in production)")
 	)
 	flag.Parse()
 
+	useEmbedded := resolveEmbedded(*embedded)
+
 	// Get migration name from remaining arguments
 	var migrationName string
 	if flag.NArg() > 0 {
@@ -169,56 +114,75 @@ func main() {
 	case *allModels:
 		handleAllModelsCommand()
 	case *createCmd:
-		handleCreateCommand(*fromModel, migrationName)
+		handleCreateCommand(*fromModel, *fromDiff, *tableName, *destroy, migrationName)
 	case *upCmd:
-		handleMigrationCommand("up", *steps, *dryRun)
+		handleMigrationCommand("up", *steps, *dryRun, useEmbedded)
 	case *downCmd:
-		handleMigrationCommand("down", *steps, *dryRun)
+		handleMigrationCommand("down", *steps, *dryRun, useEmbedded)
 	case *versionCmd:
-		showVersion()
+		showVersion(useEmbedded)
 	case *forceTo >= 0:
-		forceMigration(*forceTo)
+		forceMigration(*forceTo, useEmbedded)
 	default:
 		showHelp()
 	}
 }
 
-// handleCreateCommand handles the create migration command
-func handleCreateCommand(fromModel, migrationName string) {
-	manager, err := NewMigrationManager()
-	if err != nil {
-		log.Fatalf("Failed to initialize migration manager: %v", err)
+// resolveEmbedded decides whether to use the embedded migrations: the -embedded flag
+// wins when given, otherwise they are used in production where the SQL directory isn't shipped
+func resolveEmbedded(embedded bool) bool {
+	explicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "embedded" {
+			explicit = true
+		}
+	})
+
+	if explicit {
+		return embedded
 	}
+	return config.LoadConfig().IsProduction()
+}
 
-	if fromModel != "" {
-		if migrationName == "" {
-			migrationName = fmt.Sprintf("create_%s_table", fromModel)
-		}
-		createModelMigration(manager, fromModel, migrationName)
-	} else {
+// handleCreateCommand handles the create migration command
+// New files are always written to the migrations directory, so this uses the filesystem source
+func handleCreateCommand(fromModel, fromDiff, tableName string, allowDestructive bool, migrationName string) {
+	if tableName != "" && fromModel == "" {
+		log.Fatal("-table can only be used with -from-model")
+	}
+	if fromDiff == "" && fromModel == "" {
 		if migrationName == "" {
 			log.Fatal("Migration name is required for create command")
 		}
 		createEmptyMigration(migrationName)
+		return
+	}
+
+	manager := newManager(false)
+	defer manager.Close()
+
+	if fromDiff != "" {
+		if migrationName == "" {
+			migrationName = fmt.Sprintf("alter_%s_table", fromDiff)
+		}
+		createModelDiffMigration(manager, fromDiff, migrationName, allowDestructive)
+	} else {
+		createModelMigration(manager, splitModels(fromModel), tableName, migrationName)
 	}
 }
 
 // handleMigrationCommand handles up/down migration commands
-func handleMigrationCommand(direction string, steps int, dryRun bool) {
-	manager, err := NewMigrationManager()
-	if err != nil {
-		log.Fatalf("Failed to initialize migration manager: %v", err)
-	}
+func handleMigrationCommand(direction string, steps int, dryRun, embedded bool) {
+	manager := newManager(embedded)
+	defer manager.Close()
 
-	runMigrations(manager.migrator, direction, steps, dryRun)
+	runMigrations(manager, direction, steps, dryRun)
 }
 
 // handleAllModelsCommand handles the creation of migrations from all available models
 func handleAllModelsCommand() {
-	manager, err := NewMigrationManager()
-	if err != nil {
-		log.Fatalf("Failed to initialize migration manager: %v", err)
-	}
+	manager := newManager(false)
+	defer manager.Close()
 
 	fmt.Println("🗃️ Creating migrations from all available models...")
 	fmt.Println("📋 Getting list of available models...")
@@ -235,12 +199,12 @@ func handleAllModelsCommand() {
 
 	fmt.Printf("📊 Found %d model(s) to process\n\n", totalCount)
 
-	for modelName := range ModelRegistry {
+	for modelName, model := range ModelRegistry {
 		processedCount++
 		fmt.Printf("\033[34m[%d/%d]\033[0m Processing model: \033[1m%s\033[0m", processedCount, totalCount, modelName)
 
 		// Check if table already exists
-		if tableExists(manager, modelName) {
+		if manager.HasTable(model) {
 			fmt.Printf(" - \033[33m⏭️ SKIPPED (table exists)\033[0m\n")
 			skippedCount++
 			continue
@@ -248,7 +212,10 @@ func handleAllModelsCommand() {
 
 		// Create migration for this model
 		migrationName := fmt.Sprintf("create_%s_table", modelName)
-		err := createModelMigrationSafe(manager, modelName, migrationName)
+		upSQL, downSQL, err := manager.GenerateFromModel(model, "")
+		if err == nil {
+			_, _, err = writeMigration(strconv.FormatInt(time.Now().Unix(), 10), migrationName, upSQL, downSQL)
+		}
 		if err != nil {
 			fmt.Printf(" - \033[31m❌ ERROR\033[0m\n")
 			fmt.Printf("   Error details: %v\n", err)
@@ -276,155 +243,143 @@ func handleAllModelsCommand() {
 	fmt.Printf("\033[1;32m✅ All model migrations processing completed\033[0m\n")
 }
 
-// tableExists checks if a table for the given model already exists
-func tableExists(manager *MigrationManager, modelName string) bool {
+// lookupModel returns the registered model with the given name, exiting with the list of
+// models if there is none
+func lookupModel(modelName string) interface{} {
 	model, exists := ModelRegistry[strings.ToLower(modelName)]
 	if !exists {
-		return false
+		fmt.Printf("Error: Model '%s' not found. Available models:\n", modelName)
+		listAvailableModels()
+		os.Exit(1)
 	}
+	return model
+}
 
-	// Parse the model to get table information
-	stmt := &gorm.Statement{DB: manager.generator.db}
-	if err := stmt.Parse(model); err != nil {
-		return false
+// listAvailableModels lists all available models for migrations
+func listAvailableModels() {
+	fmt.Println("Available models for migration:")
+	for name := range ModelRegistry {
+		fmt.Printf("  - %s\n", name)
 	}
+}
 
-	tableName := stmt.Schema.Table
-
-	// Check if table exists in database
-	cfg := config.LoadConfig()
-	db, err := gorm.Open(gormMysql.Open(cfg.Database.DSN), &gorm.Config{})
-	if err != nil {
-		return false
+// splitModels returns the model names of a comma-separated -from-model value
+func splitModels(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
 	}
-
-	return db.Migrator().HasTable(tableName)
+	return names
 }
 
-// createModelMigrationSafe is a safer version of createModelMigration that doesn't exit on error
-func createModelMigrationSafe(manager *MigrationManager, modelName, migrationName string) error {
-	model, exists := ModelRegistry[strings.ToLower(modelName)]
-	if !exists {
-		return fmt.Errorf("model '%s' not found", modelName)
+// createModelMigration creates a migration based on one or more GORM models. A single model's
+// table can be renamed with tableName; several models share one migration file
+func createModelMigration(manager *migration.Manager, modelNames []string, tableName, migrationName string) {
+	if len(modelNames) == 0 {
+		log.Fatal("At least one model name is required for -from-model")
 	}
-
-	// Generate migration files
-	timestamp := time.Now().Unix()
-	version := strconv.FormatInt(timestamp, 10)
-	safeVersion := fmt.Sprintf("%s_%s", version, migrationName)
-
-	// Ensure migrations directory exists
-	if err := os.MkdirAll(migrationsPath, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create migrations directory: %w", err)
+	if tableName != "" && len(modelNames) > 1 {
+		log.Fatal("-table can only be used with a single model")
 	}
 
-	// Generate SQL using GORM migrator
-	upSQL, downSQL, err := manager.generator.GenerateModelMigration(model)
-	if err != nil {
-		return fmt.Errorf("failed to generate migration SQL: %w", err)
+	models := make([]interface{}, 0, len(modelNames))
+	for _, name := range modelNames {
+		models = append(models, lookupModel(name))
 	}
 
-	// Create migration files
-	upFile := filepath.Join(migrationsPath, fmt.Sprintf("%s.up.sql", safeVersion))
-	downFile := filepath.Join(migrationsPath, fmt.Sprintf("%s.down.sql", safeVersion))
-
-	if err := os.WriteFile(upFile, []byte(upSQL), 0644); err != nil {
-		return fmt.Errorf("failed to create up migration file: %w", err)
+	var upSQL, downSQL string
+	var err error
+	if len(models) == 1 {
+		upSQL, downSQL, err = manager.GenerateFromModel(models[0], tableName)
+	} else {
+		upSQL, downSQL, err = manager.GenerateFromModels(models...)
 	}
-
-	if err := os.WriteFile(downFile, []byte(downSQL), 0644); err != nil {
-		return fmt.Errorf("failed to create down migration file: %w", err)
+	if err != nil {
+		log.Fatalf("Failed to generate migration SQL: %v", err)
 	}
 
-	return nil
-}
-
-// listAvailableModels lists all available models for migrations
-func listAvailableModels() {
-	fmt.Println("Available models for migration:")
-	for name := range ModelRegistry {
-		fmt.Printf("  - %s\n", name)
+	if migrationName == "" {
+		switch {
+		case tableName != "":
+			migrationName = fmt.Sprintf("create_%s_table", tableName)
+		case len(modelNames) > 1:
+			migrationName = fmt.Sprintf("create_%s_tables", strings.Join(modelNames, "_"))
+		default:
+			migrationName = fmt.Sprintf("create_%s_table", modelNames[0])
+		}
 	}
-}
 
-// createModelMigration creates a migration based on a GORM model
-func createModelMigration(manager *MigrationManager, modelName, migrationName string) {
-	model, exists := ModelRegistry[strings.ToLower(modelName)]
-	if !exists {
-		fmt.Printf("Error: Model '%s' not found. Available models:\n", modelName)
-		listAvailableModels()
-		os.Exit(1)
+	upFile, downFile, err := writeMigration(strconv.FormatInt(time.Now().Unix(), 10), migrationName, upSQL, downSQL)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Generate migration files
-	timestamp := time.Now().Unix()
-	version := strconv.FormatInt(timestamp, 10)
-	safeVersion := fmt.Sprintf("%s_%s", version, migrationName)
-
-	// Ensure migrations directory exists
-	if err := os.MkdirAll(migrationsPath, os.ModePerm); err != nil {
-		log.Fatalf("Failed to create migrations directory: %v", err)
-	}
+	fmt.Printf("Created model-based migration files:\n  %s\n  %s\n", upFile, downFile)
+}
 
-	// Generate SQL using GORM migrator
-	upSQL, downSQL, err := manager.generator.GenerateModelMigration(model)
+// createModelDiffMigration creates an ALTER migration for the difference between a model and its table
+func createModelDiffMigration(manager *migration.Manager, modelName, migrationName string, allowDestructive bool) {
+	upSQL, downSQL, skipped, err := manager.GenerateDiffFromModel(lookupModel(modelName), allowDestructive)
 	if err != nil {
 		log.Fatalf("Failed to generate migration SQL: %v", err)
 	}
 
-	// Create migration files
-	upFile := filepath.Join(migrationsPath, fmt.Sprintf("%s.up.sql", safeVersion))
-	downFile := filepath.Join(migrationsPath, fmt.Sprintf("%s.down.sql", safeVersion))
+	if len(skipped) > 0 {
+		fmt.Printf("Warning: not dropping columns missing from the model: %s (use -allow-destructive to drop them)\n", strings.Join(skipped, ", "))
+	}
 
-	if err := os.WriteFile(upFile, []byte(upSQL), 0644); err != nil {
-		log.Fatalf("Failed to create up migration file: %v", err)
+	if upSQL == "" {
+		fmt.Printf("No schema changes for model '%s'\n", modelName)
+		return
 	}
 
-	if err := os.WriteFile(downFile, []byte(downSQL), 0644); err != nil {
-		log.Fatalf("Failed to create down migration file: %v", err)
+	upFile, downFile, err := writeMigration(strconv.FormatInt(time.Now().Unix(), 10), migrationName, upSQL, downSQL)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	fmt.Printf("Created model-based migration files:\n  %s\n  %s\n", upFile, downFile)
+	fmt.Printf("Created schema diff migration files:\n  %s\n  %s\n", upFile, downFile)
 }
 
 // createEmptyMigration creates empty migration files
 func createEmptyMigration(name string) {
-	timestamp := time.Now().Format("20060102150405")
-	filename := fmt.Sprintf("%s_%s", timestamp, name)
-
-	upFile := filepath.Join(migrationsPath, fmt.Sprintf("%s.up.sql", filename))
-	downFile := filepath.Join(migrationsPath, fmt.Sprintf("%s.down.sql", filename))
-
-	// Create migrations directory if it doesn't exist
-	if err := os.MkdirAll(migrationsPath, 0755); err != nil {
-		log.Fatalf("Failed to create migrations directory: %v", err)
+	upFile, downFile, err := writeMigration(time.Now().Format("20060102150405"), name, migration.UpHeader, migration.DownHeader)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	upTemplate := `-- Migration Up
--- SQL in section 'Up' is executed when this migration is applied
-
-`
+	fmt.Printf("Created migration files:\n  %s\n  %s\n", upFile, downFile)
+}
 
-	downTemplate := `-- Migration Down
--- SQL in section 'Down' is executed when this migration is rolled back
+// writeMigration writes the up and down files of a migration to the migrations directory
+func writeMigration(version, name, upSQL, downSQL string) (upFile, downFile string, err error) {
+	dir := migrationsDir()
+	filename := fmt.Sprintf("%s_%s", version, name)
+	upFile = filepath.Join(dir, fmt.Sprintf("%s.up.sql", filename))
+	downFile = filepath.Join(dir, fmt.Sprintf("%s.down.sql", filename))
 
-`
+	// Create migrations directory if it doesn't exist
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create migrations directory: %w", err)
+	}
 
-	if err := os.WriteFile(upFile, []byte(upTemplate), 0644); err != nil {
-		log.Fatalf("Failed to create up migration file: %v", err)
+	if err := os.WriteFile(upFile, []byte(upSQL), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to create up migration file: %w", err)
 	}
 
-	if err := os.WriteFile(downFile, []byte(downTemplate), 0644); err != nil {
-		log.Fatalf("Failed to create down migration file: %v", err)
+	if err := os.WriteFile(downFile, []byte(downSQL), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to create down migration file: %w", err)
 	}
 
-	fmt.Printf("Created migration files:\n  %s\n  %s\n", upFile, downFile)
+	return upFile, downFile, nil
 }
 
 // runMigrations executes migration operations
-func runMigrations(m *migrate.Migrate, direction string, steps int, dryRun bool) {
+func runMigrations(manager *migration.Manager, direction string, steps int, dryRun bool) {
 	if dryRun {
-		showDryRunInfo(m, direction, steps)
+		showDryRunInfo(manager, direction, steps)
 		return
 	}
 
@@ -433,25 +388,25 @@ func runMigrations(m *migrate.Migrate, direction string, steps int, dryRun bool)
 	case steps > 0:
 		fmt.Printf("Running %d %s migrations...\n", steps, direction)
 		if direction == "up" {
-			err = m.Steps(steps)
+			err = manager.Steps(steps)
 		} else {
-			err = m.Steps(-steps)
+			err = manager.Steps(-steps)
 		}
 	case direction == "up":
 		fmt.Println("Running all pending migrations...")
-		err = m.Up()
+		err = manager.Up()
 	default:
 		fmt.Println("Rolling back one migration...")
-		err = m.Steps(-1)
+		err = manager.Steps(-1)
 	}
 
 	handleMigrationResult(err)
 }
 
 // showDryRunInfo shows what migrations would be executed
-func showDryRunInfo(m *migrate.Migrate, direction string, steps int) {
-	version, dirty, err := m.Version()
-	if err != nil && err != migrate.ErrNilVersion {
+func showDryRunInfo(manager *migration.Manager, direction string, steps int) {
+	version, dirty, err := manager.Version()
+	if err != nil {
 		log.Fatalf("Failed to get migration version: %v", err)
 	}
 
@@ -478,7 +433,7 @@ func getActionWord(direction string) string {
 // handleMigrationResult handles the result of migration operations
 func handleMigrationResult(err error) {
 	if err != nil {
-		if err == migrate.ErrNoChange {
+		if errors.Is(err, migration.ErrNoChange) {
 			fmt.Println("No migration changes to apply")
 		} else {
 			log.Fatalf("Migration failed: %v", err)
@@ -488,63 +443,43 @@ func handleMigrationResult(err error) {
 	}
 }
 
-// showVersion displays the current migration version
-func showVersion() {
-	m := getMigrator()
-	version, dirty, err := m.Version()
-	if err != nil {
-		if err == migrate.ErrNilVersion {
-			fmt.Println("No migrations have been applied yet")
-		} else {
-			log.Fatalf("Failed to get migration version: %v", err)
-		}
-		return
-	}
+// showVersion displays the current migration version and whether newer migrations are pending
+func showVersion(embedded bool) {
+	manager := newManager(embedded)
+	defer manager.Close()
 
-	fmt.Printf("Current migration version: %d\n", version)
+	version, dirty, err := manager.Version()
+	switch {
+	case err != nil:
+		log.Fatalf("Failed to get migration version: %v", err)
+	case version == 0:
+		fmt.Println("No migrations have been applied yet")
+	default:
+		fmt.Printf("Current migration version: %d\n", version)
+	}
 	if dirty {
 		fmt.Println("Warning: Database is in a dirty state, last migration failed")
 	}
-}
 
-// forceMigration forces a migration to a specific version
-func forceMigration(version int) {
-	m := getMigrator()
-	if err := m.Force(version); err != nil {
-		log.Fatalf("Failed to force migration: %v", err)
-	}
-	fmt.Printf("Successfully forced migration to version %d\n", version)
-}
-
-// getMigrator creates and returns a migrator instance
-func getMigrator() *migrate.Migrate {
-	cfg := config.LoadConfig()
-	dsn := prepareDSNForMigration(cfg.Database.DSN)
-
-	db, err := sql.Open("mysql", dsn)
+	latest, err := manager.Latest()
 	if err != nil {
-		log.Fatalf("Failed to open database connection: %v", err)
+		log.Fatalf("Failed to get latest migration version: %v", err)
 	}
-
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Could not connect to database: %v", err)
+	fmt.Printf("Latest available version: %d\n", latest)
+	if version < latest {
+		fmt.Println("Migrations are pending, run migrate -up to apply them")
 	}
+}
 
-	driver, err := mysqldriver.WithInstance(db, &mysqldriver.Config{
-		MigrationsTable: "schema_migrations",
-		DatabaseName:    extractDatabaseName(dsn),
-	})
-	if err != nil {
-		log.Fatalf("Failed to create migration driver: %v", err)
-	}
+// forceMigration forces a migration to a specific version
+func forceMigration(version int, embedded bool) {
+	manager := newManager(embedded)
+	defer manager.Close()
 
-	sourceURL := fmt.Sprintf("file://%s", migrationsPath)
-	m, err := migrate.NewWithDatabaseInstance(sourceURL, "mysql", driver)
-	if err != nil {
-		log.Fatalf("Failed to create migrator: %v", err)
+	if err := manager.Force(version); err != nil {
+		log.Fatalf("Failed to force migration: %v", err)
 	}
-
-	return m
+	fmt.Printf("Successfully forced migration to version %d\n", version)
 }
 
 // prepareDSNForMigration prepares the DSN for migration use
@@ -556,27 +491,20 @@ func prepareDSNForMigration(dsn string) string {
 	return dsn
 }
 
-// extractDatabaseName extracts the database name from a DSN
-func extractDatabaseName(dsn string) string {
-	cfg, err := mysql.ParseDSN(dsn)
-	if err != nil {
-		// Fallback for simple DSNs
-		parts := strings.Split(dsn, "/")
-		if len(parts) > 1 {
-			dbPart := parts[len(parts)-1]
-			return strings.Split(dbPart, "?")[0]
-		}
-		return ""
-	}
-	return cfg.DBName
-}
-
 // showHelp displays the help information
 func showHelp() {
 	fmt.Println("Migration tool for LinkeunID Go API")
 	fmt.Println("\nUsage:")
 	fmt.Println("  migrate -create NAME                Create a new empty migration")
 	fmt.Println("  migrate -create -from-model MODEL   Create a migration from a model")
+	fmt.Println("  migrate -create -from-model MODEL -table TABLE")
+	fmt.Println("                                      Create a migration from a model under another table name")
+	fmt.Println("  migrate -create -from-model MODEL,MODEL")
+	fmt.Println("                                      Create one migration for the tables of several models")
+	fmt.Println("  migrate -create -from-model-diff MODEL")
+	fmt.Println("                                      Create an ALTER migration for model changes not yet in the table")
+	fmt.Println("  migrate -create -from-model-diff MODEL -allow-destructive")
+	fmt.Println("                                      Also drop table columns that are no longer in the model")
 	fmt.Println("  migrate -all-models                 Create migrations from all available models (skip existing)")
 	fmt.Println("  migrate -up                         Run all pending migrations")
 	fmt.Println("  migrate -up -steps N                Run N up migrations")
@@ -586,11 +514,16 @@ func showHelp() {
 	fmt.Println("  migrate -force VERSION              Force migration to a specific version")
 	fmt.Println("  migrate -dry-run -up|-down          Show migrations that would be applied")
 	fmt.Println("  migrate -list-models                List available models for migrations")
+	fmt.Println("  migrate -embedded -up|-down|...     Use migrations compiled into the binary (default in production)")
 	fmt.Println("\nExamples:")
 	fmt.Println("  migrate -create add_users_table")
 	fmt.Println("  migrate -create -from-model animal")
+	fmt.Println("  migrate -create -from-model animal -table pets")
+	fmt.Println("  migrate -create -from-model animal,flower create_garden_tables")
+	fmt.Println("  migrate -create -from-model-diff animal add_animal_version")
 	fmt.Println("  migrate -all-models")
 	fmt.Println("  migrate -up")
 	fmt.Println("  migrate -down -steps 1")
+	fmt.Println("  migrate -embedded=false -up    (Use the migrations directory in production)")
 	fmt.Println("  migrate -force 0               (Reset all migrations)")
 }
@@ -3,7 +3,6 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
 	"go/ast"
@@ -13,6 +12,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/linkeunid/go-api/internal/codegen"
 )
 
 // Command line flags
@@ -26,10 +27,11 @@ func init() {
 	flag.BoolVar(&cleanOnly, "clean-only", false, "Only remove models that no longer exist without adding new ones")
 	flag.BoolVar(&syncMode, "sync", false, "Both add new models and remove models that no longer exist")
 	flag.BoolVar(&verbose, "v", false, "Enable verbose output")
-	flag.Parse()
 }
 
 func main() {
+	flag.Parse()
+
 	// Scan for models in the filesystem
 	fsModels, err := scanModels("internal/model")
 	if err != nil {
@@ -238,86 +240,55 @@ func hasTableNameMethod(filePath, structName string) bool {
 	return matched
 }
 
-// toSnakeCase converts a string from PascalCase to snake_case
-func toSnakeCase(s string) string {
-	var result string
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			result += "_"
-		}
-		if r >= 'A' && r <= 'Z' {
-			result += string(r - 'A' + 'a')
-		} else {
-			result += string(r)
-		}
-	}
-	return result
-}
-
 // updateModelMap updates the modelMap in the specified Go file
 func updateModelMap(filePath string, models []string) error {
+	return updateModelVar(filePath, "modelMap", models)
+}
+
+// updateModelRegistry updates the ModelRegistry in the specified Go file
+func updateModelRegistry(filePath string, models []string) error {
+	return updateModelVar(filePath, "ModelRegistry", models)
+}
+
+// updateModelVar rewrites the map assigned to varName in the specified Go file
+func updateModelVar(filePath, varName string, models []string) error {
 	// Read the file content
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Define a more precise pattern to match the model map declaration
-	// Match from "var modelMap" up to the final closing brace
-	pattern := regexp.MustCompile(`var\s+modelMap\s*=\s*map\[string\]interface\{\}\s*\{[\s\S]*?\n\}`)
-
-	// Generate the new model map with proper formatting
-	var newMap bytes.Buffer
-	newMap.WriteString("var modelMap = map[string]interface{}{\n")
-	for _, model := range models {
-		// Convert PascalCase to snake_case for the key
-		modelKey := toSnakeCase(model)
-		// Use the original PascalCase struct name
-		newMap.WriteString(fmt.Sprintf("\t\"%s\": &model.%s{},\n", modelKey, model))
+	newContent, err := rewriteModelVar(content, varName, models)
+	if err != nil {
+		return err
 	}
-	newMap.WriteString("\t// Add more models here as they are implemented\n}")
-
-	// Replace the model map declaration
-	newContent := pattern.ReplaceAllString(string(content), newMap.String())
 
 	// Write the updated content back to the file
-	if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+	if err := os.WriteFile(filePath, newContent, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
 	return nil
 }
 
-// updateModelRegistry updates the ModelRegistry in the specified Go file
-func updateModelRegistry(filePath string, models []string) error {
-	// Read the file content
-	content, err := os.ReadFile(filePath)
+// rewriteModelVar replaces the entries of the map assigned to varName with one per model,
+// keeping the comments that stand on their own line inside the map
+func rewriteModelVar(src []byte, varName string, models []string) ([]byte, error) {
+	file, err := codegen.Parse(src)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("failed to parse file: %w", err)
 	}
 
-	// Define a pattern to match the ModelRegistry declaration
-	// Match from "var ModelRegistry" up to the final closing brace
-	pattern := regexp.MustCompile(`var\s+ModelRegistry\s*=\s*map\[string\]interface\{\}\s*\{[\s\S]*?\n\}`)
-
-	// Generate the new ModelRegistry with proper formatting
-	var newRegistry bytes.Buffer
-	newRegistry.WriteString("var ModelRegistry = map[string]interface{}{\n")
-	for _, model := range models {
-		// Convert PascalCase to snake_case for the key
-		modelKey := toSnakeCase(model)
-		// Use the original PascalCase struct name
-		newRegistry.WriteString(fmt.Sprintf("\t\"%s\": &model.%s{},\n", modelKey, model))
+	lit, err := file.VarCompositeLit(varName)
+	if err != nil {
+		return nil, err
 	}
-	newRegistry.WriteString("}")
-
-	// Replace the ModelRegistry declaration
-	newContent := pattern.ReplaceAllString(string(content), newRegistry.String())
 
-	// Write the updated content back to the file
-	if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	entries := make([]string, 0, len(models))
+	for _, model := range models {
+		// Key by the snake_case name, pointing at the original PascalCase struct
+		entries = append(entries, fmt.Sprintf("%q: &model.%s{}", codegen.SnakeCase(model), model))
 	}
 
-	return nil
+	return file.ReplaceElements(lit, entries)
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteModelVar(t *testing.T) {
+	src := `package main
+
+import "github.com/linkeunid/go-api/internal/model"
+
+var modelMap = map[string]interface{}{
+	"animal": &model.Animal{},
+	"flower":   &model.Flower{},
+	// Add more models here as they are implemented
+}
+`
+
+	result, err := rewriteModelVar([]byte(src), "modelMap", []string{"Animal", "UserID", "HTTPServer"})
+	require.NoError(t, err)
+	assert.Equal(t, `package main
+
+import "github.com/linkeunid/go-api/internal/model"
+
+var modelMap = map[string]interface{}{
+	"animal":      &model.Animal{},
+	"user_id":     &model.UserID{},
+	"http_server": &model.HTTPServer{},
+	// Add more models here as they are implemented
+}
+`, string(result))
+
+	_, err = rewriteModelVar([]byte(src), "ModelRegistry", []string{"Animal"})
+	assert.Error(t, err)
+}
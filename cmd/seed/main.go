@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,10 +17,14 @@ import (
 
 // Command line flags
 var (
-	all        bool
-	seederName string
-	count      int
-	help       bool
+	all           bool
+	seederName    string
+	count         int
+	batchSize     int
+	progressEvery int
+	force         bool
+	randomSeed    int64
+	help          bool
 )
 
 // Register command line flags
@@ -27,6 +32,10 @@ func init() {
 	flag.BoolVar(&all, "all", false, "Run all seeders")
 	flag.StringVar(&seederName, "seeder", "", "Run a specific seeder by name")
 	flag.IntVar(&count, "count", 100, "Number of records to generate")
+	flag.IntVar(&batchSize, "batch-size", 100, "Number of records inserted per batch")
+	flag.IntVar(&progressEvery, "progress-every", 10, "Report progress every N batches")
+	flag.Int64Var(&randomSeed, "seed", 0, "Random seed for reproducible data (default: time-based)")
+	flag.BoolVar(&force, "force", false, "Truncate tables before seeding instead of skipping seeders whose table has data")
 	flag.BoolVar(&help, "help", false, "Show help")
 	flag.BoolVar(&help, "h", false, "Show help (shorthand)")
 }
@@ -37,8 +46,46 @@ type Seeder interface {
 	GetName() string
 }
 
+// DependentSeeder is implemented by seeders that need other seeders to run first,
+// e.g. a join table seeder that references seeded rows. Dependencies are seeder names
+type DependentSeeder interface {
+	Dependencies() []string
+}
+
+// Truncater is implemented by seeders that can empty their table for -force
+type Truncater interface {
+	Truncate(ctx context.Context) error
+}
+
+// BatchConfigurer is implemented by seeders that insert rows in batches and can
+// report their progress while doing so
+type BatchConfigurer interface {
+	SetBatchSize(n int)
+	SetProgress(everyBatches int, fn seeder.ProgressFunc)
+}
+
+// seedCounts resolves the number of records each seeder generates
+type seedCounts struct {
+	defaultCount int
+	overrides    map[string]int
+}
+
+// get returns the count for the named seeder, honoring a -count-<name> override
+func (c seedCounts) get(name string) int {
+	if n, ok := c.overrides[strings.ToLower(name)]; ok {
+		return n
+	}
+	return c.defaultCount
+}
+
 func main() {
-	flag.Parse()
+	// Per-seeder -count-<name>=N flags are dynamic, so take them out before parsing the rest
+	overrides, args, err := extractCountOverrides(os.Args[1:])
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	_ = flag.CommandLine.Parse(args)
 
 	// Show help
 	if help {
@@ -59,6 +106,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Validate batching
+	if batchSize <= 0 || progressEvery <= 0 {
+		fmt.Println("❌ Error: -batch-size and -progress-every must be greater than 0")
+		os.Exit(1)
+	}
+
+	// Make generated data reproducible when a seed is given
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seeder.SetRandomSeed(randomSeed)
+		}
+	})
+
 	// Initialize the app
 	app, err := bootstrap.InitializeApp()
 	if err != nil {
@@ -75,7 +135,7 @@ func main() {
 	defer cancel()
 
 	// Create seeders registry
-	seeders := registerSeeders(db, logger, count)
+	seeders := registerSeeders(db, logger, seedCounts{defaultCount: count, overrides: overrides})
 
 	// Run seeders
 	if all {
@@ -85,34 +145,158 @@ func main() {
 	}
 }
 
+// extractCountOverrides removes -count-<name>=N (or --count-<name> N) arguments from args
+// and returns the per-seeder counts they set along with the remaining arguments
+func extractCountOverrides(args []string) (map[string]int, []string, error) {
+	overrides := make(map[string]int)
+	rest := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		trimmed := strings.TrimLeft(arg, "-")
+		if !strings.HasPrefix(arg, "-") || !strings.HasPrefix(trimmed, "count-") {
+			rest = append(rest, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(trimmed, "count-"), "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			value = args[i]
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, nil, fmt.Errorf("count for seeder '%s' must be a number greater than 0", name)
+		}
+		overrides[strings.ToLower(name)] = n
+	}
+
+	return overrides, rest, nil
+}
+
 // Register all available seeders
-func registerSeeders(db database.Database, logger *zap.Logger, count int) []Seeder {
+func registerSeeders(db database.Database, logger *zap.Logger, counts seedCounts) []Seeder {
 	return []Seeder{
-		seeder.NewAnimalSeeder(db, logger, count),
-		seeder.NewFlowerSeeder(db, logger, count),
+		seeder.NewAnimalSeeder(db, logger, counts.get("animal")),
+		seeder.NewFlowerSeeder(db, logger, counts.get("flower")),
 		// Add more seeders here as they are implemented
 	}
 }
 
-// Run all registered seeders
-func runAllSeeders(ctx context.Context, seeders []Seeder, logger *zap.Logger) {
-	logger.Info("Running all seeders", zap.Int("count", len(seeders)))
+// orderSeeders returns seeders sorted so each runs after its dependencies, keeping
+// registration order otherwise. It fails on unknown dependencies and dependency cycles
+func orderSeeders(seeders []Seeder) ([]Seeder, error) {
+	byName := make(map[string]Seeder, len(seeders))
+	for _, s := range seeders {
+		byName[strings.ToLower(s.GetName())] = s
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(seeders))
+	ordered := make([]Seeder, 0, len(seeders))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("seeder dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		state[name] = visiting
+		s := byName[name]
+		if dependent, ok := s.(DependentSeeder); ok {
+			for _, dep := range dependent.Dependencies() {
+				dep = strings.ToLower(dep)
+				if _, exists := byName[dep]; !exists {
+					return fmt.Errorf("seeder '%s' depends on unknown seeder '%s'", name, dep)
+				}
+				if err := visit(dep, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		ordered = append(ordered, s)
+		return nil
+	}
 
 	for _, s := range seeders {
-		runSeeder(ctx, s, logger)
+		if err := visit(strings.ToLower(s.GetName()), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// withDependencies returns the named seeder preceded by everything it depends on
+func withDependencies(seeders []Seeder, name string) ([]Seeder, error) {
+	ordered, err := orderSeeders(seeders)
+	if err != nil {
+		return nil, err
 	}
 
+	needed := map[string]bool{name: true}
+	// Walk backwards so each seeder marks its dependencies before they are reached
+	for i := len(ordered) - 1; i >= 0; i-- {
+		s := ordered[i]
+		if !needed[strings.ToLower(s.GetName())] {
+			continue
+		}
+		if dependent, ok := s.(DependentSeeder); ok {
+			for _, dep := range dependent.Dependencies() {
+				needed[strings.ToLower(dep)] = true
+			}
+		}
+	}
+
+	selected := make([]Seeder, 0, len(needed))
+	for _, s := range ordered {
+		if needed[strings.ToLower(s.GetName())] {
+			selected = append(selected, s)
+		}
+	}
+	return selected, nil
+}
+
+// Run all registered seeders
+func runAllSeeders(ctx context.Context, seeders []Seeder, logger *zap.Logger) {
+	ordered, err := orderSeeders(seeders)
+	if err != nil {
+		logger.Error("Failed to order seeders", zap.Error(err))
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Running all seeders", zap.Int("count", len(ordered)))
+	runSeeders(ctx, ordered, logger)
 	logger.Info("All seeders completed successfully")
 }
 
-// Run a specific seeder by name
+// Run a specific seeder by name, after the seeders it depends on
 func runNamedSeeder(ctx context.Context, seeders []Seeder, name string, logger *zap.Logger) {
 	name = strings.ToLower(name)
 	logger.Info("Looking for seeder", zap.String("name", name))
 
 	for _, s := range seeders {
 		if strings.ToLower(s.GetName()) == name {
-			runSeeder(ctx, s, logger)
+			selected, err := withDependencies(seeders, name)
+			if err != nil {
+				logger.Error("Failed to order seeders", zap.Error(err))
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			runSeeders(ctx, selected, logger)
 			return
 		}
 	}
@@ -123,6 +307,38 @@ func runNamedSeeder(ctx context.Context, seeders []Seeder, name string, logger *
 	os.Exit(1)
 }
 
+// runSeeders runs seeders in the given order. With -force their tables are
+// truncated first, in reverse order so dependents are emptied before what they reference
+func runSeeders(ctx context.Context, seeders []Seeder, logger *zap.Logger) {
+	if force {
+		for i := len(seeders) - 1; i >= 0; i-- {
+			truncateSeeder(ctx, seeders[i], logger)
+		}
+	}
+
+	for _, s := range seeders {
+		runSeeder(ctx, s, logger)
+	}
+}
+
+// truncateSeeder empties the table of a seeder that supports it
+func truncateSeeder(ctx context.Context, s Seeder, logger *zap.Logger) {
+	name := s.GetName()
+
+	truncater, ok := s.(Truncater)
+	if !ok {
+		logger.Warn("Seeder does not support truncation, existing data is kept", zap.String("name", name))
+		return
+	}
+
+	fmt.Printf("🧹 Truncating data for seeder: %s\n", name)
+	if err := truncater.Truncate(ctx); err != nil {
+		logger.Error("Failed to truncate seeder data", zap.String("name", name), zap.Error(err))
+		fmt.Printf("❌ Failed to truncate data for seeder '%s': %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
 // Run a single seeder
 func runSeeder(ctx context.Context, s Seeder, logger *zap.Logger) {
 	name := s.GetName()
@@ -131,6 +347,14 @@ func runSeeder(ctx context.Context, s Seeder, logger *zap.Logger) {
 	fmt.Printf("🌱 Running seeder: %s\n", name)
 	startTime := time.Now()
 
+	if bc, ok := s.(BatchConfigurer); ok {
+		bc.SetBatchSize(batchSize)
+		bc.SetProgress(progressEvery, func(done, total int) {
+			elapsed := time.Since(startTime)
+			fmt.Printf("   ⏳ %s: seeded %d/%d (ETA %v)\n", name, done, total, estimateRemaining(elapsed, done, total))
+		})
+	}
+
 	if err := s.Seed(ctx); err != nil {
 		logger.Error("Failed to run seeder",
 			zap.String("name", name),
@@ -148,6 +372,16 @@ func runSeeder(ctx context.Context, s Seeder, logger *zap.Logger) {
 	fmt.Printf("✅ Seeder '%s' completed in %v\n", name, duration)
 }
 
+// estimateRemaining extrapolates the time left to insert total rows from the
+// time it took to insert the first done rows
+func estimateRemaining(elapsed time.Duration, done, total int) time.Duration {
+	if done <= 0 || done >= total {
+		return 0
+	}
+	perRow := elapsed / time.Duration(done)
+	return (perRow * time.Duration(total-done)).Round(time.Second)
+}
+
 // Show help message
 func showHelp() {
 	fmt.Println("🌱 Database Seeder Tool")
@@ -159,6 +393,11 @@ func showHelp() {
 	fmt.Println("  -all           Run all seeders")
 	fmt.Println("  -seeder=NAME   Run a specific seeder by name")
 	fmt.Println("  -count=N       Number of records to generate (default: 100)")
+	fmt.Println("  -count-NAME=N  Number of records for the NAME seeder, overriding -count")
+	fmt.Println("  -batch-size=N  Number of records inserted per batch (default: 100)")
+	fmt.Println("  -progress-every=N  Report progress every N batches (default: 10)")
+	fmt.Println("  -seed=N        Random seed; the same seed generates the same data (default: time-based)")
+	fmt.Println("  -force         Truncate tables before seeding instead of skipping tables with data")
 	fmt.Println("  -help, -h      Show this help message")
 	fmt.Println("")
 	fmt.Println("Examples:")
@@ -166,6 +405,10 @@ func showHelp() {
 	fmt.Println("  go run ./cmd/seed -all -count=500     # Run all seeders with 500 records each")
 	fmt.Println("  go run ./cmd/seed -seeder=animal      # Run only the animal seeder")
 	fmt.Println("  go run ./cmd/seed -seeder=animal -count=50  # Run animal seeder with 50 records")
+	fmt.Println("  go run ./cmd/seed -all -count-animal=20    # Run all seeders, with 20 animals")
+	fmt.Println("  go run ./cmd/seed -all -force              # Replace existing data with fresh seeds")
+	fmt.Println("  go run ./cmd/seed -all -force -seed=42     # Reproduce the same data on every run")
+	fmt.Println("  go run ./cmd/seed -all -count=100000 -batch-size=1000  # Seed a large dataset in bigger batches")
 }
 
 // Show available seeders
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSeeder is a seeder with a name and optional dependencies
+type stubSeeder struct {
+	name string
+	deps []string
+}
+
+func (s stubSeeder) Seed(ctx context.Context) error { return nil }
+func (s stubSeeder) GetName() string                { return s.name }
+func (s stubSeeder) Dependencies() []string         { return s.deps }
+
+func names(seeders []Seeder) []string {
+	result := make([]string, len(seeders))
+	for i, s := range seeders {
+		result[i] = s.GetName()
+	}
+	return result
+}
+
+func TestOrderSeeders(t *testing.T) {
+	seeders := []Seeder{
+		stubSeeder{name: "adoption", deps: []string{"animal", "owner"}},
+		stubSeeder{name: "animal"},
+		stubSeeder{name: "flower"},
+		stubSeeder{name: "owner"},
+	}
+
+	ordered, err := orderSeeders(seeders)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"animal", "owner", "adoption", "flower"}, names(ordered))
+
+	// Running one seeder also runs what it depends on, and nothing else
+	selected, err := withDependencies(seeders, "adoption")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"animal", "owner", "adoption"}, names(selected))
+}
+
+func TestOrderSeeders_Errors(t *testing.T) {
+	_, err := orderSeeders([]Seeder{stubSeeder{name: "adoption", deps: []string{"owner"}}})
+	assert.ErrorContains(t, err, "unknown seeder 'owner'")
+
+	_, err = orderSeeders([]Seeder{
+		stubSeeder{name: "a", deps: []string{"b"}},
+		stubSeeder{name: "b", deps: []string{"a"}},
+	})
+	assert.ErrorContains(t, err, "cycle: a -> b -> a")
+}
+
+func TestExtractCountOverrides(t *testing.T) {
+	overrides, rest, err := extractCountOverrides([]string{"-all", "--count-animal=20", "-count-Flower", "5", "-count=50"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"animal": 20, "flower": 5}, overrides)
+	assert.Equal(t, []string{"-all", "-count=50"}, rest)
+
+	counts := seedCounts{defaultCount: 50, overrides: overrides}
+	assert.Equal(t, 20, counts.get("animal"))
+	assert.Equal(t, 50, counts.get("owner"))
+
+	_, _, err = extractCountOverrides([]string{"-count-animal=zero"})
+	assert.Error(t, err)
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	assert.Equal(t, 30*time.Second, estimateRemaining(10*time.Second, 2500, 10000))
+	assert.Equal(t, time.Duration(0), estimateRemaining(10*time.Second, 10000, 10000))
+	assert.Equal(t, time.Duration(0), estimateRemaining(0, 0, 10000))
+}
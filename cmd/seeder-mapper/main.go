@@ -2,7 +2,6 @@
 package main
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
 	"go/ast"
@@ -12,6 +11,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/linkeunid/go-api/internal/codegen"
 )
 
 // Command line flags
@@ -25,10 +26,11 @@ func init() {
 	flag.BoolVar(&cleanOnly, "clean-only", false, "Only remove seeders that no longer exist without adding new ones")
 	flag.BoolVar(&syncMode, "sync", false, "Both add new seeders and remove seeders that no longer exist")
 	flag.BoolVar(&verbose, "v", false, "Enable verbose output")
-	flag.Parse()
 }
 
 func main() {
+	flag.Parse()
+
 	// Scan for seeders in the filesystem
 	fsSeeders, err := scanSeeders("pkg/seeder")
 	if err != nil {
@@ -250,29 +252,37 @@ func updateSeederRegistry(filePath string, seeders []string) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Define a pattern to match the registerSeeders function
-	// Match from "func registerSeeders" up to the final closing brace
-	pattern := regexp.MustCompile(`func\s+registerSeeders\([^)]*\)\s+\[\]Seeder\s+\{[\s\S]*?\n\}`)
-
-	// Generate the new registerSeeders function with proper formatting
-	var newRegistry bytes.Buffer
-	newRegistry.WriteString("func registerSeeders(db database.Database, logger *zap.Logger, count int) []Seeder {\n")
-	newRegistry.WriteString("\treturn []Seeder{\n")
-
-	for _, seeder := range seeders {
-		newRegistry.WriteString(fmt.Sprintf("\t\tseeder.New%sSeeder(db, logger, count),\n", seeder))
+	newContent, err := rewriteSeederRegistry(content, seeders)
+	if err != nil {
+		return err
 	}
 
-	newRegistry.WriteString("\t\t// Add more seeders here as they are implemented\n")
-	newRegistry.WriteString("\t}\n}")
-
-	// Replace the registerSeeders function
-	newContent := pattern.ReplaceAllString(string(content), newRegistry.String())
-
 	// Write the updated content back to the file
-	if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+	if err := os.WriteFile(filePath, newContent, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
 	return nil
 }
+
+// rewriteSeederRegistry replaces the seeders returned by registerSeeders with one per seeder,
+// keeping the comments that stand on their own line inside the returned slice
+func rewriteSeederRegistry(src []byte, seeders []string) ([]byte, error) {
+	file, err := codegen.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	lit, err := file.ReturnedCompositeLit("registerSeeders")
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]string, 0, len(seeders))
+	for _, seeder := range seeders {
+		// Counts are looked up by seeder name, which by convention is the lowercased type name
+		calls = append(calls, fmt.Sprintf("seeder.New%sSeeder(db, logger, counts.get(%q))", seeder, strings.ToLower(seeder)))
+	}
+
+	return file.ReplaceElements(lit, calls)
+}
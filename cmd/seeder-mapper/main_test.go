@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteSeederRegistry(t *testing.T) {
+	src := `package main
+
+// registerSeeders creates every seeder
+func registerSeeders(db database.Database, logger *zap.Logger, counts seedCounts) []Seeder {
+	return []Seeder{
+		seeder.NewAnimalSeeder(db, logger, counts.get("animal")),
+		// Add more seeders here as they are implemented
+	}
+}
+`
+
+	result, err := rewriteSeederRegistry([]byte(src), []string{"Animal", "Flower"})
+	require.NoError(t, err)
+	assert.Equal(t, `package main
+
+// registerSeeders creates every seeder
+func registerSeeders(db database.Database, logger *zap.Logger, counts seedCounts) []Seeder {
+	return []Seeder{
+		seeder.NewAnimalSeeder(db, logger, counts.get("animal")),
+		seeder.NewFlowerSeeder(db, logger, counts.get("flower")),
+		// Add more seeders here as they are implemented
+	}
+}
+`, string(result))
+
+	_, err = rewriteSeederRegistry([]byte("package main\n"), []string{"Animal"})
+	assert.Error(t, err)
+}
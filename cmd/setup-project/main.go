@@ -6,21 +6,41 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"go/parser"
+	"go/token"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // Constants
 const (
 	currentModuleName = "github.com/linkeunid/go-api"
+	currentTitle      = "Linkeun Go API"
 )
 
+// titleFiles lists the files that hardcode the project title outside the Swagger annotations
+var titleFiles = []string{
+	"cmd/api/main.go",
+	"cmd/migrate/main.go",
+	"internal/bootstrap/server.go",
+	"internal/docs/swaggerdocs/docs.go",
+	"internal/docs/swaggerdocs/swagger.json",
+	"internal/docs/swaggerdocs/swagger.yaml",
+}
+
+// titleVariants lists the spellings of the current title found in titleFiles
+var titleVariants = []string{currentTitle, "LinkeunID Go API"}
+
 // Command line flags
 var (
 	newModuleName string
+	projectTitle  string
+	swaggerHost   string
 	gitRemoteURL  string
 	resetGit      bool
 	verbose       bool
@@ -29,14 +49,17 @@ var (
 
 func init() {
 	flag.StringVar(&newModuleName, "module", "", "New module name (e.g., github.com/yourusername/your-project)")
+	flag.StringVar(&projectTitle, "title", "", "Project title for Swagger and tool output (default: derived from the module name)")
+	flag.StringVar(&swaggerHost, "host", "", "Swagger @host annotation, e.g. api.example.com (default: unchanged)")
 	flag.StringVar(&gitRemoteURL, "remote", "", "Git remote URL (e.g., git@github.com:yourusername/your-project.git)")
 	flag.BoolVar(&resetGit, "reset-git", false, "Reset Git repository (remove .git folder and initialize a new one)")
 	flag.BoolVar(&verbose, "v", false, "Enable verbose output")
 	flag.BoolVar(&skipConfirm, "y", false, "Skip confirmation prompt (use with caution)")
-	flag.Parse()
 }
 
 func main() {
+	flag.Parse()
+
 	// Validate flags
 	if newModuleName == "" {
 		fmt.Println("❌ Error: New module name is required. Use -module flag.")
@@ -53,6 +76,10 @@ func main() {
 		}
 	}
 
+	if projectTitle == "" {
+		projectTitle = titleFromProjectName(extractProjectName(newModuleName))
+	}
+
 	// Start the rename process
 	fmt.Printf("🔄 Setting up project with new module name: %s\n", newModuleName)
 
@@ -62,12 +89,18 @@ func main() {
 	// Update import paths in all Go files
 	updateImportPaths()
 
+	// Update module path references in SQL, YAML and other text files
+	updateModuleReferences()
+
 	// Update docker-compose.yml with new service and container names
 	updateDockerCompose()
 
 	// Update Makefile with new service names
 	updateMakefile()
 
+	// Update Swagger annotations and the project title
+	updateSwaggerDocs()
+
 	// Handle Git repository
 	handleGitRepository()
 
@@ -78,6 +111,24 @@ func main() {
 	fmt.Println("2. Run 'go mod tidy' to update dependencies")
 	fmt.Println("3. Build and test your project to verify everything works")
 	fmt.Println("4. Update your .env file if needed to match the new service names")
+	fmt.Println("5. Run 'make swagger' to regenerate the API documentation")
+}
+
+// titleFromProjectName derives a human readable title from the project name
+// e.g., "awesome-api" -> "Awesome API"
+func titleFromProjectName(projectName string) string {
+	words := strings.FieldsFunc(projectName, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	for i, word := range words {
+		switch strings.ToLower(word) {
+		case "api", "cli", "grpc", "http", "id", "rest", "sql", "ui":
+			words[i] = strings.ToUpper(word)
+		default:
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
 }
 
 // extractProjectName extracts the project name from the module path
@@ -97,9 +148,11 @@ func confirmAction() bool {
 	fmt.Println("⚠️ WARNING: This operation will:")
 	fmt.Printf("  - Rename module from %s to %s\n", currentModuleName, newModuleName)
 	fmt.Println("  - Update all import paths in Go files")
+	fmt.Println("  - Update module path references in SQL, YAML, Markdown, Makefile and Dockerfile")
 	fmt.Printf("  - Update docker-compose.yml service names (api -> %s, mysql -> %s-mysql, redis -> %s-redis)\n", projectName, projectName, projectName)
 	fmt.Printf("  - Update docker-compose.yml container names accordingly\n")
 	fmt.Printf("  - Update Makefile service references to use new project names\n")
+	fmt.Printf("  - Update Swagger annotations and the project title (%s -> %s)\n", currentTitle, projectTitle)
 
 	if resetGit {
 		fmt.Println("  - Reset Git repository (remove .git folder and initialize a new one)")
@@ -191,9 +244,11 @@ func updateImportsInFile(filePath string) {
 	}
 
 	// Replace import paths
-	oldImportPattern := regexp.QuoteMeta(currentModuleName)
-	newContent := regexp.MustCompile(`"`+oldImportPattern+`(/[^"]*)?"`).
-		ReplaceAll(content, []byte(`"`+newModuleName+`$1"`))
+	newContent, err := rewriteGoImports(content, currentModuleName, newModuleName)
+	if err != nil {
+		fmt.Printf("❌ Error parsing %s: %v\n", filePath, err)
+		return
+	}
 
 	// If content hasn't changed, skip writing
 	if bytes.Equal(content, newContent) {
@@ -215,6 +270,197 @@ func updateImportsInFile(filePath string) {
 	}
 }
 
+// rewriteGoImports rewrites the import paths in Go source that refer to oldModule or one of its
+// packages, along with the //go:generate directives that mention them. Other string literals,
+// struct tags and comments are left alone, and the file is edited in place to keep its formatting
+func rewriteGoImports(src []byte, oldModule, newModule string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if rewritten, ok := rewriteImportPath(path, oldModule, newModule); ok {
+			edits = append(edits, edit{
+				start: fset.Position(spec.Path.Pos()).Offset,
+				end:   fset.Position(spec.Path.End()).Offset,
+				text:  strconv.Quote(rewritten),
+			})
+		}
+	}
+
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, "//go:generate ") {
+				continue
+			}
+			rewritten := replaceModuleReferences([]byte(comment.Text), oldModule, newModule)
+			if string(rewritten) != comment.Text {
+				edits = append(edits, edit{
+					start: fset.Position(comment.Pos()).Offset,
+					end:   fset.Position(comment.End()).Offset,
+					text:  string(rewritten),
+				})
+			}
+		}
+	}
+
+	if len(edits) == 0 {
+		return src, nil
+	}
+
+	// Apply edits from the end of the file so earlier offsets stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	result := append([]byte(nil), src...)
+	for _, e := range edits {
+		result = append(result[:e.start], append([]byte(e.text), result[e.end:]...)...)
+	}
+	return result, nil
+}
+
+// rewriteImportPath returns path moved from oldModule to newModule when it is the module
+// itself or one of its packages. Paths that merely start with the same characters, such as
+// oldModule + "-extra", are not part of the module and are left unchanged
+func rewriteImportPath(path, oldModule, newModule string) (string, bool) {
+	if path == oldModule {
+		return newModule, true
+	}
+	if strings.HasPrefix(path, oldModule+"/") {
+		return newModule + strings.TrimPrefix(path, oldModule), true
+	}
+	return path, false
+}
+
+// moduleReferenceExtensions lists the extensions of non-Go files that may mention the module path
+var moduleReferenceExtensions = map[string]bool{
+	".sql":  true,
+	".yml":  true,
+	".yaml": true,
+	".toml": true,
+	".md":   true,
+}
+
+// moduleReferenceFiles lists non-Go files without a matching extension that may mention the module path
+var moduleReferenceFiles = map[string]bool{
+	"Makefile":   true,
+	"Dockerfile": true,
+}
+
+// isModuleReferenceFile reports whether a non-Go file should be searched for the module path
+func isModuleReferenceFile(name string) bool {
+	return moduleReferenceFiles[name] || moduleReferenceExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// updateModuleReferences updates the module path in SQL, YAML and other non-Go files
+func updateModuleReferences() {
+	fmt.Println("📝 Updating module path references in other files...")
+
+	var files []string
+	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip vendor directory and .git directory
+		if info.IsDir() && (info.Name() == "vendor" || info.Name() == ".git") {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() && isModuleReferenceFile(info.Name()) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		fmt.Printf("❌ Error scanning files: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, filePath := range files {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			fmt.Printf("❌ Error reading %s: %v\n", filePath, err)
+			continue
+		}
+
+		newContent := replaceModuleReferences(content, currentModuleName, newModuleName)
+		if bytes.Equal(content, newContent) {
+			continue
+		}
+
+		if err := os.WriteFile(filePath, newContent, 0644); err != nil {
+			fmt.Printf("❌ Error writing %s: %v\n", filePath, err)
+			continue
+		}
+
+		if verbose {
+			fmt.Printf("  ✓ Updated %s\n", filePath)
+		}
+	}
+}
+
+// replaceModuleReferences replaces each occurrence of oldModule in content that stands on its
+// own as a module or package path. An occurrence that is part of a longer path, such as
+// oldModule + "-extra" or a clone URL ending in oldModule + ".git", is left unchanged
+func replaceModuleReferences(content []byte, oldModule, newModule string) []byte {
+	old := []byte(oldModule)
+	var result []byte
+	last := 0
+
+	for start := 0; ; {
+		i := bytes.Index(content[start:], old)
+		if i < 0 {
+			break
+		}
+		i += start
+		end := i + len(old)
+
+		if (i == 0 || !isPathByte(content[i-1])) && endsModulePath(content[end:]) {
+			result = append(result, content[last:i]...)
+			result = append(result, newModule...)
+			last = end
+		}
+		start = end
+	}
+
+	if last == 0 {
+		return content
+	}
+	return append(result, content[last:]...)
+}
+
+// endsModulePath reports whether the text following a module path match ends the path,
+// either by starting a package path or by not continuing the module name
+func endsModulePath(after []byte) bool {
+	if len(after) == 0 || after[0] == '/' {
+		return true
+	}
+	// A trailing period ends a sentence, not the path, unless more path follows it
+	if after[0] == '.' {
+		return len(after) == 1 || !isPathByte(after[1])
+	}
+	return !isPathByte(after[0])
+}
+
+// isPathByte reports whether b may appear within a module path element
+func isPathByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' ||
+		b == '-' || b == '_' || b == '.' || b == '~'
+}
+
 // updateDockerCompose updates service names and container names in docker-compose.yml
 func updateDockerCompose() {
 	fmt.Println("🐳 Updating docker-compose.yml with new service and container names...")
@@ -389,6 +635,94 @@ func updateMakefile() {
 	}
 }
 
+// updateSwaggerDocs updates the Swagger annotations and the hardcoded project title
+func updateSwaggerDocs() {
+	fmt.Println("📚 Updating Swagger annotations and project title...")
+
+	contactURL := "https://" + newModuleName
+	updateProjectFile("internal/docs/swagger.go", func(content []byte) []byte {
+		return rewriteSwaggerAnnotations(content, projectTitle, contactURL, swaggerHost)
+	})
+
+	for _, filePath := range titleFiles {
+		updateProjectFile(filePath, func(content []byte) []byte {
+			if filePath == "internal/bootstrap/server.go" {
+				content = rewriteSwaggerAnnotations(content, projectTitle, contactURL, "")
+			}
+			return replaceTitle(content, projectTitle)
+		})
+	}
+}
+
+// swaggerAnnotationPattern matches a Swagger annotation comment line, capturing its indentation
+func swaggerAnnotationPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^([ \t]*)// @` + regexp.QuoteMeta(name) + ` .*$`)
+}
+
+// rewriteSwaggerAnnotations points the title and contact annotations at the new project. The
+// contact email is removed since it can't be derived, and @host is only replaced when host is set
+func rewriteSwaggerAnnotations(content []byte, title, contactURL, host string) []byte {
+	annotations := [][2]string{
+		{"title", title},
+		{"contact.name", title + " Support"},
+		{"contact.url", contactURL},
+	}
+	if host != "" {
+		annotations = append(annotations, [2]string{"host", host})
+	}
+
+	for _, a := range annotations {
+		name, value := a[0], a[1]
+		replacement := "${1}// @" + name + " " + strings.ReplaceAll(value, "$", "$$")
+		content = swaggerAnnotationPattern(name).ReplaceAll(content, []byte(replacement))
+	}
+
+	return regexp.MustCompile(`(?m)^[ \t]*// @contact\.email .*\n`).ReplaceAll(content, nil)
+}
+
+// replaceTitle replaces every spelling of the current title with title, escaped so it stays
+// valid inside the Go and JSON string literals it appears in
+func replaceTitle(content []byte, title string) []byte {
+	quoted := strconv.Quote(title)
+	escaped := []byte(quoted[1 : len(quoted)-1])
+	for _, variant := range titleVariants {
+		content = bytes.ReplaceAll(content, []byte(variant), escaped)
+	}
+	return content
+}
+
+// updateProjectFile applies rewrite to a file, skipping it when it is missing or unchanged
+func updateProjectFile(filePath string, rewrite func([]byte) []byte) {
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		if verbose {
+			fmt.Printf("  - Skipped %s (file not found)\n", filePath)
+		}
+		return
+	}
+	if err != nil {
+		fmt.Printf("❌ Error reading %s: %v\n", filePath, err)
+		return
+	}
+
+	newContent := rewrite(content)
+	if bytes.Equal(content, newContent) {
+		if verbose {
+			fmt.Printf("  - Skipped %s (no changes needed)\n", filePath)
+		}
+		return
+	}
+
+	if err := os.WriteFile(filePath, newContent, 0644); err != nil {
+		fmt.Printf("❌ Error writing %s: %v\n", filePath, err)
+		return
+	}
+
+	if verbose {
+		fmt.Printf("  ✓ Updated %s\n", filePath)
+	}
+}
+
 // handleGitRepository handles Git repository operations
 func handleGitRepository() {
 	// Reset Git repository if requested
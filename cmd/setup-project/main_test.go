@@ -0,0 +1,249 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldModule = "github.com/linkeunid/go-api"
+	newModule = "github.com/acme/shop"
+)
+
+func TestRewriteGoImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name: "SingleImport",
+			src: `package main
+
+import "github.com/linkeunid/go-api/pkg/util"
+`,
+			expected: `package main
+
+import "github.com/acme/shop/pkg/util"
+`,
+		},
+		{
+			name: "ModuleRoot",
+			src: `package main
+
+import _ "github.com/linkeunid/go-api"
+`,
+			expected: `package main
+
+import _ "github.com/acme/shop"
+`,
+		},
+		{
+			name: "GroupedAndAliased",
+			src: `package main
+
+import (
+	"fmt"
+
+	cfg "github.com/linkeunid/go-api/internal/config"
+	"github.com/linkeunid/go-api/pkg/util"
+)
+`,
+			expected: `package main
+
+import (
+	"fmt"
+
+	cfg "github.com/acme/shop/internal/config"
+	"github.com/acme/shop/pkg/util"
+)
+`,
+		},
+		{
+			name: "PrefixModuleUntouched",
+			src: `package main
+
+import (
+	"github.com/linkeunid/go-api-extra/pkg/util"
+	"github.com/linkeunid/go-apis"
+)
+`,
+		},
+		{
+			name: "StringLiteralsAndTagsUntouched",
+			src: `package main
+
+import "embed"
+
+// Module is github.com/linkeunid/go-api
+const Module = "github.com/linkeunid/go-api/pkg/util"
+
+//go:embed github.com/linkeunid/go-api/*.sql
+var files embed.FS
+
+type Config struct {
+	Path string ` + "`" + `json:"path" default:"github.com/linkeunid/go-api/pkg"` + "`" + `
+}
+`,
+		},
+		{
+			name: "GoGenerate",
+			src: `package main
+
+//go:generate go run github.com/linkeunid/go-api/cmd/model-mapper -out github.com/linkeunid/go-api-extra
+// go run github.com/linkeunid/go-api/cmd/model-mapper
+import "github.com/linkeunid/go-api/pkg/util"
+`,
+			expected: `package main
+
+//go:generate go run github.com/acme/shop/cmd/model-mapper -out github.com/linkeunid/go-api-extra
+// go run github.com/linkeunid/go-api/cmd/model-mapper
+import "github.com/acme/shop/pkg/util"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == "" {
+				expected = tt.src
+			}
+
+			result, err := rewriteGoImports([]byte(tt.src), oldModule, newModule)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(result))
+		})
+	}
+
+	t.Run("InvalidSource", func(t *testing.T) {
+		_, err := rewriteGoImports([]byte("package main\nimport (\n"), oldModule, newModule)
+		assert.Error(t, err)
+	})
+}
+
+func TestReplaceModuleReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "YAML",
+			content:  "image: github.com/linkeunid/go-api\npkg: github.com/linkeunid/go-api/pkg/util\n",
+			expected: "image: github.com/acme/shop\npkg: github.com/acme/shop/pkg/util\n",
+		},
+		{
+			name:     "SQLComment",
+			content:  "-- Generated by github.com/linkeunid/go-api/cmd/model-mapper\nCREATE TABLE animals;\n",
+			expected: "-- Generated by github.com/acme/shop/cmd/model-mapper\nCREATE TABLE animals;\n",
+		},
+		{
+			name:     "Ldflags",
+			content:  `-X github.com/linkeunid/go-api/internal/version.version=1.0`,
+			expected: `-X github.com/acme/shop/internal/version.version=1.0`,
+		},
+		{
+			name:     "EndOfSentence",
+			content:  "See github.com/linkeunid/go-api.",
+			expected: "See github.com/acme/shop.",
+		},
+		{
+			name:    "PrefixModuleUntouched",
+			content: "github.com/linkeunid/go-api-extra github.com/linkeunid/go-apis",
+		},
+		{
+			name:    "CloneURLUntouched",
+			content: "git clone https://github.com/linkeunid/go-api.git",
+		},
+		{
+			name:    "LongerHostUntouched",
+			content: "mygithub.com/linkeunid/go-api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == "" {
+				expected = tt.content
+			}
+			assert.Equal(t, expected, string(replaceModuleReferences([]byte(tt.content), oldModule, newModule)))
+		})
+	}
+}
+
+func TestIsModuleReferenceFile(t *testing.T) {
+	assert.True(t, isModuleReferenceFile("001_create_animals.sql"))
+	assert.True(t, isModuleReferenceFile("docker-compose.yml"))
+	assert.True(t, isModuleReferenceFile("config.YAML"))
+	assert.True(t, isModuleReferenceFile("Makefile"))
+	assert.True(t, isModuleReferenceFile("Dockerfile"))
+	assert.False(t, isModuleReferenceFile("main.go"))
+	assert.False(t, isModuleReferenceFile("go.sum"))
+}
+
+func TestTitleFromProjectName(t *testing.T) {
+	tests := []struct {
+		projectName string
+		expected    string
+	}{
+		{projectName: "go-api", expected: "Go API"},
+		{projectName: "awesome-api", expected: "Awesome API"},
+		{projectName: "shop", expected: "Shop"},
+		{projectName: "order_service.v2", expected: "Order Service V2"},
+		{projectName: "my-grpc-gateway", expected: "My GRPC Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.projectName, func(t *testing.T) {
+			assert.Equal(t, tt.expected, titleFromProjectName(tt.projectName))
+		})
+	}
+}
+
+func TestRewriteSwaggerAnnotations(t *testing.T) {
+	src := `// @title Linkeun Go API
+// @version 1.0
+
+// @contact.name API Support - Website
+// @contact.url https://linkeun.com/support
+// @contact.email support@linkeun.com
+
+// @host localhost:4445
+func docs() {
+	// @contact.name API Support - Website
+	// @contact.email Send email to API Support
+}
+`
+
+	t.Run("KeepsHost", func(t *testing.T) {
+		result := rewriteSwaggerAnnotations([]byte(src), "Acme $hop", "https://github.com/acme/shop", "")
+		assert.Equal(t, `// @title Acme $hop
+// @version 1.0
+
+// @contact.name Acme $hop Support
+// @contact.url https://github.com/acme/shop
+
+// @host localhost:4445
+func docs() {
+	// @contact.name Acme $hop Support
+}
+`, string(result))
+	})
+
+	t.Run("ReplacesHost", func(t *testing.T) {
+		result := rewriteSwaggerAnnotations([]byte(src), "Shop", "https://github.com/acme/shop", "api.acme.com")
+		assert.Contains(t, string(result), "// @host api.acme.com\n")
+	})
+}
+
+func TestReplaceTitle(t *testing.T) {
+	content := `fmt.Println("Migration tool for LinkeunID Go API")
+Title: "Linkeun Go API",`
+
+	assert.Equal(t, `fmt.Println("Migration tool for The \"Shop\" API")
+Title: "The \"Shop\" API",`, string(replaceTitle([]byte(content), `The "Shop" API`)))
+}
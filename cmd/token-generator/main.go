@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/linkeunid/go-api/pkg/auth"
@@ -20,6 +21,8 @@ func main() {
 		username string
 		role     string
 		email    string
+		scopes   string
+		audience string
 		secret   string
 		expire   time.Duration
 		force    bool
@@ -30,6 +33,8 @@ func main() {
 	flag.StringVar(&username, "username", "testuser", "Username")
 	flag.StringVar(&role, "role", "user", "User role (user, admin, etc.)")
 	flag.StringVar(&email, "email", "test@example.com", "User email")
+	flag.StringVar(&scopes, "scopes", "", "Comma-separated scopes (e.g., animals:read,animals:write)")
+	flag.StringVar(&audience, "audience", cfg.Auth.Audience, "Token audience (defaults to JWT_AUDIENCE env var)")
 	flag.StringVar(&secret, "secret", cfg.Auth.JWTSecret, "JWT secret key (defaults to JWT_SECRET env var)")
 	flag.DurationVar(&expire, "expire", cfg.Auth.JWTExpiration, "Token expiration duration (e.g., 24h, 30m)")
 	flag.BoolVar(&force, "force", false, "Force token generation even in production (use with caution)")
@@ -63,13 +68,22 @@ func main() {
 		Enabled:       true,
 		JWTSecret:     secret,
 		JWTExpiration: expire,
+		Audience:      audience,
 	}
 
 	// Create JWT service
 	jwtService := auth.NewJWTService(authConfig)
 
+	// Collect the scopes, ignoring spaces and empty entries
+	var scopeList []string
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopeList = append(scopeList, scope)
+		}
+	}
+
 	// Generate token
-	token, err := jwtService.GenerateToken(userID, username, role, email)
+	token, err := jwtService.GenerateToken(userID, username, role, email, scopeList)
 	if err != nil {
 		fmt.Printf("Error generating token: %v\n", err)
 		os.Exit(1)
@@ -88,32 +102,44 @@ func main() {
 	fmt.Printf("  Username: %s\n", username)
 	fmt.Printf("  Role: %s\n", role)
 	fmt.Printf("  Email: %s\n", email)
+	fmt.Printf("  Scopes: %s\n", strings.Join(scopeList, " "))
+	fmt.Printf("  Audience: %s\n", audience)
 	fmt.Printf("  Expires: %s\n", time.Now().Add(expire).Format(time.RFC1123))
 	fmt.Printf("  Environment: %s\n", env)
+
+	// Routes are mounted under SERVER_BASE_PATH; "/" mounts them at the root
+	basePath := strings.TrimSuffix(cfg.Server.BasePath, "/")
+
 	fmt.Println("\nUsage Examples:")
 	fmt.Println("  cURL:")
-	fmt.Printf("    curl -H \"Authorization: Bearer %s\" http://localhost:%d/api/v1/protected\n", token, cfg.Server.Port)
+	fmt.Printf("    curl -H \"Authorization: Bearer %s\" http://localhost:%d%s/protected\n", token, cfg.Server.Port, basePath)
 	fmt.Println("\n  JavaScript Fetch:")
-	fmt.Printf("    fetch('http://localhost:%d/api/v1/protected', {\n      headers: {\n        'Authorization': 'Bearer %s'\n      }\n    })\n", cfg.Server.Port, token)
+	fmt.Printf("    fetch('http://localhost:%d%s/protected', {\n      headers: {\n        'Authorization': 'Bearer %s'\n      }\n    })\n", cfg.Server.Port, basePath, token)
 
 	// Add information about available protected endpoints
 	fmt.Println("\nAvailable Protected Endpoints:")
 	fmt.Println("  1. General User Endpoint:")
-	fmt.Println("     - URL: /api/v1/protected")
+	fmt.Printf("     - URL: %s/protected\n", basePath)
+	fmt.Println("     - Method: GET")
+	fmt.Println("     - Access: Any authenticated user")
+	fmt.Println("     - Returns: The same user information as /me")
+
+	fmt.Printf("\n     - URL: %s/me\n", basePath)
 	fmt.Println("     - Method: GET")
 	fmt.Println("     - Access: Any authenticated user")
-	fmt.Println("     - Returns: User information (ID, username, role, email)")
+	fmt.Println("     - Returns: User information with the token's issuer and expiry")
 
 	fmt.Println("\n  2. Admin-Only Endpoint:")
-	fmt.Println("     - URL: /api/v1/protected/admin")
+	fmt.Printf("     - URL: %s/protected/admin\n", basePath)
 	fmt.Println("     - Method: GET")
 	fmt.Println("     - Access: Only users with admin role")
 	fmt.Println("     - Returns: Admin-specific information")
 
 	fmt.Println("\n  3. Animal Resource Endpoints:")
-	fmt.Println("     - GET    /api/v1/animals            (List all animals)")
-	fmt.Println("     - POST   /api/v1/animals            (Create a new animal)")
-	fmt.Println("     - GET    /api/v1/animals/{animalID} (Get a specific animal)")
-	fmt.Println("     - PUT    /api/v1/animals/{animalID} (Update an animal)")
-	fmt.Println("     - DELETE /api/v1/animals/{animalID} (Delete an animal)")
+	fmt.Printf("     - GET    %s/animals            (List all animals)\n", basePath)
+	fmt.Printf("     - POST   %s/animals            (Create a new animal)\n", basePath)
+	fmt.Printf("     - GET    %s/animals/{animalID} (Get a specific animal)\n", basePath)
+	fmt.Printf("     - PUT    %s/animals/{animalID} (Update an animal)\n", basePath)
+	fmt.Printf("     - DELETE %s/animals/{animalID} (Delete an animal)\n", basePath)
+	fmt.Println("     - Access: Reads are public; writes need the animals:write scope")
 }
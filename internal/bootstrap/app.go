@@ -1,19 +1,31 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io/fs"
+	"net/http"
 	"os"
-	"time"
 
 	"github.com/linkeunid/go-api/internal/controller"
+	"github.com/linkeunid/go-api/internal/graphql"
 	"github.com/linkeunid/go-api/internal/repository"
 	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/migrations"
+	"github.com/linkeunid/go-api/pkg/auth"
 	"github.com/linkeunid/go-api/pkg/config"
 	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/linkeunid/go-api/pkg/jobs"
 	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/migration"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/linkeunid/go-api/pkg/telemetry"
+	"github.com/linkeunid/go-api/pkg/validator"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 )
@@ -23,7 +35,19 @@ type App struct {
 	Logger           *zap.Logger
 	DB               database.Database
 	Config           *config.Config
+	LogLevel         zap.AtomicLevel        // Minimum log level, adjustable while the application runs
+	APIKeys          auth.StaticAPIKeys     // Keys accepted in X-API-Key; nil unless API_KEYS is set
+	Roles            auth.RoleRanker        // Decides which roles RequireRole admits
+	Telemetry        telemetry.ShutdownFunc // Flushes pending trace spans on shutdown
 	AnimalController *controller.Animal
+	FlowerController *controller.Flower
+	AdminController  *controller.Admin
+	AuthController   *controller.Auth
+	Subscriptions    *controller.Subscriptions
+	GraphQLHandler   http.Handler                 // nil unless GRAPHQL_ENABLED is set
+	OutboxDispatcher *repository.OutboxDispatcher // Publishes stored change events; nil unless EVENTS_OUTBOX_ENABLED is set
+	Maintenance      middleware.MaintenanceStore  // Whether writes are rejected while operators run migrations
+	// scaffold:app-fields
 }
 
 // InitializeApp initializes the application dependencies
@@ -36,71 +60,422 @@ func InitializeApp() (*App, error) {
 	}
 
 	// Load configuration
-	cfg := config.LoadConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Parse API keys and roles before anything is started so a malformed entry stops startup
+	var apiKeys auth.StaticAPIKeys
+	if len(cfg.Auth.APIKeys) > 0 {
+		apiKeys, err = auth.ParseAPIKeys(cfg.Auth.APIKeys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_KEYS: %w", err)
+		}
+	}
+	var roles auth.RoleRanker = auth.FlatRoles{}
+	if len(cfg.Auth.RoleHierarchy) > 0 {
+		roles, err = auth.NewRoleHierarchy(cfg.Auth.RoleHierarchy...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_ROLE_HIERARCHY: %w", err)
+		}
+	}
 
-	// Initialize logger
-	logger, err := logging.InitializeLogger(cfg)
+	// Initialize logger with a level that can be changed at runtime
+	logLevel := logging.NewAtomicLevel(cfg)
+	logger, err := logging.InitializeLoggerWithLevel(cfg, logLevel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	// Make the application logger the fallback for logging.FromContext
+	zap.ReplaceGlobals(logger)
+
+	// Initialize tracing before anything that records spans
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), &cfg.Telemetry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize telemetry: %w", err)
+	}
+	if cfg.Telemetry.Enabled {
+		logger.Info("OpenTelemetry tracing enabled",
+			zap.String("endpoint", cfg.Telemetry.Endpoint),
+			zap.Float64("sampleRatio", cfg.Telemetry.SampleRatio))
+	}
+
+	// Apply configurable validation rules
+	validator.SetAllowedSpecies(cfg.Validation.AllowedSpecies)
+	middleware.SetStrictTypes(cfg.Validation.StrictTypes)
+	middleware.SetDisallowUnknownFields(cfg.Validation.StrictJSON)
+
+	// Apply the configured naming of JSON response keys
+	response.SetCamelCaseKeys(cfg.Server.ResponseCase == config.ResponseCaseCamel)
+
+	// Apply configurable page size limits
+	pagination.SetLimits(cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit)
+
 	// Initialize database
 	dbWrapper, err := initializeDatabase(cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	// Compare the schema with the migrations this build was compiled with
+	if err := checkMigrations(context.Background(), cfg, dbWrapper.GetDB(), migrations.FS, logger); err != nil {
+		return nil, err
+	}
+
+	// Changes are published to WebSocket subscribers on every instance sharing the Redis server
+	eventBroker := newEventBroker(cfg, dbWrapper, logger)
+	eventPublisher, outboxDispatcher := newEventPublisher(cfg, dbWrapper, eventBroker, logger)
+
 	// Initialize repositories and services
-	animalRepo := repository.NewAnimalRepository(dbWrapper, logger)
+	animalRepo := repository.NewAnimalRepository(dbWrapper, logger, eventPublisher)
 	animalService := service.NewAnimalService(cfg, logger, animalRepo)
+	flowerRepo := repository.NewFlowerRepository(dbWrapper, logger)
+	flowerService := service.NewFlowerService(cfg, logger, flowerRepo)
+	authService := service.NewAuthService(cfg, logger, repository.NewUserRepository(dbWrapper))
+	// scaffold:services
+
+	// Preload the first page of each collection so a deploy doesn't start with a cold cache
+	cacheWarmer := database.NewCacheWarmer(logger)
+	cacheWarmer.Register("animal", warmFirstPage(animalRepo.FindAllPaginated))
+	cacheWarmer.Register("flower", warmFirstPage(flowerRepo.FindAllPaginated))
+	// scaffold:cache-warmers
+	startCacheWarmer(cfg, dbWrapper, cacheWarmer, logger)
 
 	// Initialize controllers
-	animalController := controller.NewAnimal(logger, animalService)
+	animalController := controller.NewAnimal(animalService, newImportJobStore(cfg, dbWrapper), newIdempotencyMiddleware(cfg, dbWrapper, logger))
+	flowerController := controller.NewFlower(flowerService)
+	// scaffold:controllers
+	cacheFlusher, _ := dbWrapper.GetCacheManager().(database.CacheFlusher)
+	maintenance := newMaintenanceStore(cfg, dbWrapper, logger)
+	status := controller.NewStatusReport(cfg, cacheBackend(dbWrapper))
+	adminController := controller.NewAdmin(logLevel, cacheStats(dbWrapper, logger), cacheFlusher, maintenance, status)
+	subscriptions := controller.NewSubscriptions(eventBroker, cfg.Server.AllowedOrigins)
+	authController := controller.NewAuth(authService, newLoginRateLimit(cfg, dbWrapper, logger)...)
+
+	graphQLHandler, err := newGraphQLHandler(cfg, animalService, maintenance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
 
 	// Configure Swagger
-	SetupSwagger(cfg.Server.Port, cfg.IsDevelopment())
+	SetupSwagger(cfg.Server.Port, cfg.Server.BasePath, cfg.IsDevelopment())
+
+	// Log the effective configuration once everything has started, in the shape GET /admin/status returns
+	logger.Info("Application initialized", zap.Any("status", status))
 
 	// Return the app with all dependencies
 	return &App{
 		Logger:           logger,
 		DB:               dbWrapper,
 		Config:           cfg,
+		LogLevel:         logLevel,
+		APIKeys:          apiKeys,
+		Roles:            roles,
+		Telemetry:        shutdownTelemetry,
 		AnimalController: animalController,
+		FlowerController: flowerController,
+		AdminController:  adminController,
+		AuthController:   authController,
+		Subscriptions:    subscriptions,
+		GraphQLHandler:   graphQLHandler,
+		OutboxDispatcher: outboxDispatcher,
+		Maintenance:      maintenance,
+		// scaffold:app-values
 	}, nil
 }
 
+// loadConfig loads configuration from the file named by CONFIG_FILE, or from
+// environment variables alone when it isn't set
+func loadConfig() (*config.Config, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return config.LoadConfigFromFile(path)
+	}
+	return config.LoadConfig(), nil
+}
+
+// Start starts the background work of the API server, which InitializeApp leaves stopped so
+// that a failed initialization, or a command that only needs the dependencies, has nothing to stop
+func (a *App) Start() {
+	if a.OutboxDispatcher != nil {
+		a.OutboxDispatcher.Start()
+	}
+}
+
+// ReloadLogLevel re-reads the configured log level and applies it to the running logger. The
+// level comes from the config file when CONFIG_FILE is set, and otherwise from LOG_LEVEL, which
+// the .env file overrides in development; the environment of a running process can't be changed
+// from outside, so elsewhere the level only changes with CONFIG_FILE
+func (a *App) ReloadLogLevel() error {
+	if os.Getenv("CONFIG_FILE") == "" {
+		if err := config.ReloadEnvFile("LOG_LEVEL"); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return err
+	}
+
+	previous := a.LogLevel.Level()
+	a.LogLevel.SetLevel(level)
+	a.Logger.Warn("Log level reloaded",
+		zap.String("from", previous.String()),
+		zap.String("to", level.String()))
+
+	return nil
+}
+
+// newIdempotencyMiddleware builds the middleware that replays requests with an Idempotency-Key.
+// Keys must be shared by every instance, so it does nothing unless Redis is the cache backend
+func newIdempotencyMiddleware(cfg *config.Config, db database.Database, logger *zap.Logger) func(http.Handler) http.Handler {
+	var store middleware.IdempotencyStore
+	if redisManager, ok := db.GetCacheManager().(*database.RedisCacheManager); ok {
+		store = middleware.NewRedisIdempotencyStore(redisManager.Client(), cfg.Redis.KeyPrefix)
+		logger.Info("Idempotency keys enabled", zap.Duration("ttl", cfg.Idempotency.TTL))
+	} else {
+		logger.Info("Idempotency keys disabled because Redis is not the cache backend")
+	}
+	return middleware.Idempotency(store, cfg.Idempotency.TTL, logger)
+}
+
+// newLoginRateLimit returns the middleware limiting login attempts per client IP, or none when
+// AUTH_LOGIN_RATE_LIMIT_RPS is 0
+func newLoginRateLimit(cfg *config.Config, db database.Database, logger *zap.Logger) []func(http.Handler) http.Handler {
+	if cfg.Auth.LoginRPS <= 0 {
+		return nil
+	}
+	return []func(http.Handler) http.Handler{
+		rateLimitMiddleware(cfg, db, logger, "Login rate limiting", "login:", cfg.Auth.LoginRPS, cfg.Auth.LoginBurst),
+	}
+}
+
+// newMaintenanceStore returns the store of the maintenance mode flag: Redis when it is the cache
+// backend, so the flag is shared by every instance, and memory otherwise. MAINTENANCE_MODE=true
+// enables it at startup; otherwise a flag already set in Redis is left as it is
+func newMaintenanceStore(cfg *config.Config, db database.Database, logger *zap.Logger) middleware.MaintenanceStore {
+	var store middleware.MaintenanceStore = middleware.NewMemoryMaintenanceStore()
+	if redisManager, ok := db.GetCacheManager().(*database.RedisCacheManager); ok {
+		store = middleware.NewRedisMaintenanceStore(redisManager.Client(), cfg.Redis.KeyPrefix)
+	}
+
+	if cfg.Maintenance.Enabled {
+		if err := store.SetEnabled(context.Background(), true); err != nil {
+			logger.Warn("Failed to enable maintenance mode", zap.Error(err))
+		} else {
+			logger.Warn("Maintenance mode enabled", zap.Strings("methods", cfg.Maintenance.Methods))
+		}
+	}
+	return store
+}
+
+// newImportJobStore returns the store for background import jobs: Redis when it is the cache
+// backend, so a job can be followed through any instance, and memory otherwise
+func newImportJobStore(cfg *config.Config, db database.Database) jobs.Store {
+	if redisManager, ok := db.GetCacheManager().(*database.RedisCacheManager); ok {
+		return jobs.NewRedisStore(redisManager.Client(), cfg.Redis.KeyPrefix)
+	}
+	return jobs.NewMemoryStore()
+}
+
+// newEventBroker builds the broker change events are published through. With Redis as the
+// cache backend events go through Redis pub/sub so subscribers on any instance receive them;
+// otherwise, or if subscribing fails, they only reach subscribers of this instance
+func newEventBroker(cfg *config.Config, db database.Database, logger *zap.Logger) events.Broker {
+	if redisManager, ok := db.GetCacheManager().(*database.RedisCacheManager); ok {
+		broker := events.NewRedisBroker(redisManager.Client(), cfg.Redis.KeyPrefix, events.DefaultBuffer, logger)
+		err := broker.Start(context.Background())
+		if err == nil {
+			logger.Info("Change events shared through Redis pub/sub")
+			return broker
+		}
+		logger.Warn("Failed to subscribe to Redis events, delivering them to this instance only", zap.Error(err))
+	}
+	return events.NewMemoryBroker(events.DefaultBuffer)
+}
+
+// newEventPublisher returns the publisher repositories send change events to. With
+// EVENTS_OUTBOX_ENABLED events are stored in the outbox table in the transaction of each write and
+// published to broker by the returned dispatcher once App.Start starts it; otherwise they go
+// straight to broker once the write commits and the dispatcher is nil
+func newEventPublisher(cfg *config.Config, db database.Database, broker events.Broker, logger *zap.Logger) (events.Publisher, *repository.OutboxDispatcher) {
+	if !cfg.Events.OutboxEnabled {
+		return broker, nil
+	}
+
+	dispatcher := repository.NewOutboxDispatcher(db, broker, logger,
+		cfg.Events.OutboxPollInterval, cfg.Events.OutboxBatchSize, cfg.Events.OutboxRetention)
+	logger.Info("Change events delivered through the outbox",
+		zap.Duration("pollInterval", cfg.Events.OutboxPollInterval),
+		zap.Int("batchSize", cfg.Events.OutboxBatchSize))
+	return repository.NewOutbox(db), dispatcher
+}
+
+// cacheStats returns the operation counts of the cache backend, registered as Prometheus metrics,
+// or nil when caching is disabled
+func cacheStats(db database.Database, logger *zap.Logger) database.CacheStatsProvider {
+	provider, ok := db.GetCacheManager().(database.CacheStatsProvider)
+	if !ok {
+		return nil
+	}
+
+	if err := database.RegisterCacheMetrics(prometheus.DefaultRegisterer, provider); err != nil {
+		logger.Warn("Failed to register cache metrics", zap.Error(err))
+	}
+	return provider
+}
+
+// cacheBackend returns the cache backend in use by db, which is "none" when the configured
+// backend failed to start
+func cacheBackend(db database.Database) string {
+	switch db.GetCacheManager().(type) {
+	case *database.RedisCacheManager:
+		return config.CacheBackendRedis
+	case *database.InMemoryCacheManager:
+		return config.CacheBackendMemory
+	default:
+		return config.CacheBackendNone
+	}
+}
+
+// warmFirstPage returns a WarmFunc that loads the page a list request without query parameters
+// gets: page 1 with the default limit and sort
+func warmFirstPage[R any](findAllPaginated func(ctx context.Context, params pagination.Params, filters repository.Filters) (R, error)) database.WarmFunc {
+	return func(ctx context.Context) error {
+		defaultLimit, _ := pagination.Limits()
+		_, err := findAllPaginated(ctx, pagination.Params{Page: 1, Limit: defaultLimit}, nil)
+		return err
+	}
+}
+
+// newGraphQLHandler builds the GraphQL endpoint over the animal service, or returns nil when
+// GRAPHQL_ENABLED is off
+func newGraphQLHandler(cfg *config.Config, animalService service.AnimalService, maintenance middleware.MaintenanceStore) (http.Handler, error) {
+	if !cfg.GraphQL.Enabled {
+		return nil, nil
+	}
+
+	// Mutations need the scope REST animal writes need
+	var writeScopes []string
+	if cfg.Auth.Enabled {
+		writeScopes = []string{"animals:write"}
+	}
+
+	schema, err := graphql.NewSchema(animalService, writeScopes, maintenance)
+	if err != nil {
+		return nil, err
+	}
+	return graphql.NewHandler(schema), nil
+}
+
+// checkMigrations compares the schema version recorded in the database with the newest migration
+// in fsys when MIGRATION_CHECK is set. A database that is behind or dirty is logged as a warning,
+// or fails startup when MIGRATION_STRICT is set; so does a version that can't be read. A database
+// ahead of the migrations, e.g. while an older build is rolled back, is only logged
+func checkMigrations(ctx context.Context, cfg *config.Config, db *gorm.DB, fsys fs.FS, logger *zap.Logger) error {
+	if !cfg.Migration.Check {
+		return nil
+	}
+
+	status, err := migration.Check(ctx, db, fsys, migration.EmbeddedDir(cfg.Database.Driver))
+	if err != nil {
+		if cfg.Migration.Strict {
+			return fmt.Errorf("failed to check migrations: %w", err)
+		}
+		logger.Warn("Failed to check migrations", zap.Error(err))
+		return nil
+	}
+
+	fields := []zap.Field{zap.Uint("version", status.Current), zap.Uint("latest", status.Latest)}
+	var message string
+	switch {
+	case status.Dirty:
+		message = "Database schema is dirty; fix the failed migration and run migrate -force"
+	case status.Behind():
+		message = "Database schema is behind the migrations of this build; run make migrate"
+	case status.Ahead():
+		logger.Warn("Database schema is ahead of the migrations of this build", fields...)
+		return nil
+	default:
+		logger.Info("Database schema is up to date", fields...)
+		return nil
+	}
+
+	if cfg.Migration.Strict {
+		return fmt.Errorf("migration check failed: %s (version %d, latest %d)", message, status.Current, status.Latest)
+	}
+	logger.Warn(message, fields...)
+	return nil
+}
+
+// startCacheWarmer warms the cache in the background when CACHE_WARM_ON_START is set and
+// Redis is the cache backend; an in-process cache starts empty with every instance anyway
+func startCacheWarmer(cfg *config.Config, db database.Database, warmer *database.CacheWarmer, logger *zap.Logger) {
+	if !cfg.Cache.WarmOnStart {
+		return
+	}
+	if _, ok := db.GetCacheManager().(*database.RedisCacheManager); !ok {
+		logger.Info("Cache warming skipped because Redis is not the cache backend")
+		return
+	}
+
+	logger.Info("Warming cache in the background")
+	warmer.Start(context.Background())
+}
+
 // initializeDatabase sets up the database connection
 func initializeDatabase(cfg *config.Config, logger *zap.Logger) (database.Database, error) {
-	// Configure GORM logger
+	// Log queries through zap: every query at debug level outside production, and slow or
+	// failed queries everywhere. Slow queries are also counted in db_slow_queries_total
 	logLevel := gormlogger.Info
 	if cfg.IsProduction() {
-		logLevel = gormlogger.Error
+		logLevel = gormlogger.Warn
 	}
 
-	gormLogger := gormlogger.New(
-		log.New(os.Stdout, "\r\n", log.LstdFlags),
-		gormlogger.Config{
-			SlowThreshold:             200 * time.Millisecond,
-			LogLevel:                  logLevel,
-			IgnoreRecordNotFoundError: true,
-			Colorful:                  !cfg.IsProduction(),
-		},
-	)
+	slowQueries := logging.NewSlowQueryCounter()
+	if err := prometheus.DefaultRegisterer.Register(slowQueries); err != nil {
+		logger.Warn("Failed to register slow query metric", zap.Error(err))
+	}
+	gormLogger := logging.NewGormLogger(logger, logLevel, cfg.Database.SlowQueryThreshold, slowQueries)
 
 	// Log DSN with password masked for debugging
 	dsnForLog := GetDataSourceInfo(cfg.Database.DSN)
-	logger.Info("Connecting to database", zap.String("dsn", dsnForLog))
+	logger.Info("Connecting to database", zap.String("driver", cfg.Database.Driver), zap.String("dsn", dsnForLog))
 
-	// Connect to database
-	db, err := gorm.Open(mysql.Open(cfg.Database.DSN), &gorm.Config{
-		Logger: gormLogger,
-	})
+	// Connect to database using the dialector for the configured driver, retrying while it starts up
+	var db *gorm.DB
+	err := database.ConnectWithRetry(context.Background(), cfg.Database.ConnectRetries, cfg.Database.ConnectBackoff,
+		database.LogRetries(logger, "database"),
+		func() error {
+			var err error
+			db, err = database.Open(database.Dialector(cfg.Database.Driver, cfg.Database.DSN), &gorm.Config{
+				Logger: gormLogger,
+			})
+			return err
+		})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	logger.Info("Successfully connected to database")
 
+	// Record a span for every statement when tracing is enabled
+	if cfg.Telemetry.Enabled {
+		if err := database.RegisterTracing(db); err != nil {
+			return nil, fmt.Errorf("failed to register database tracing: %w", err)
+		}
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -113,8 +488,9 @@ func initializeDatabase(cfg *config.Config, logger *zap.Logger) (database.Databa
 	// Create cache manager (nil for now)
 	var cacheManager database.CacheManager
 
-	// Initialize Redis cache manager if Redis is enabled
-	if cfg.Redis.Enabled {
+	// Initialize the configured cache backend
+	switch cfg.Cache.Backend {
+	case config.CacheBackendRedis:
 		logger.Info("Redis caching is enabled",
 			zap.String("host", cfg.Redis.Host),
 			zap.Int("port", cfg.Redis.Port),
@@ -128,8 +504,15 @@ func initializeDatabase(cfg *config.Config, logger *zap.Logger) (database.Databa
 			cacheManager = redisManager
 			logger.Info("Redis cache manager initialized successfully")
 		}
-	} else {
-		logger.Info("Redis caching is disabled")
+	case config.CacheBackendMemory:
+		logger.Info("In-memory caching is enabled",
+			zap.Int("maxItems", cfg.Cache.MemoryMaxItems),
+			zap.Duration("cacheTTL", cfg.Redis.CacheTTL))
+		cacheManager = database.NewInMemoryCacheManager(cfg, logger)
+	case config.CacheBackendNone:
+		logger.Info("Caching is disabled")
+	default:
+		logger.Warn("Unknown cache backend, continuing without caching", zap.String("backend", cfg.Cache.Backend))
 	}
 
 	// Create database wrapper
@@ -0,0 +1,120 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestCheckMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"1746764390_create_animal_table.up.sql": {},
+		"1760659200_create_outbox_table.up.sql": {},
+	}
+
+	tests := []struct {
+		name          string
+		version       int
+		dirty         bool
+		strict        bool
+		expectedError string
+		expectedLevel zapcore.Level
+		expectedLog   string
+	}{
+		{name: "UpToDate", version: 1760659200, expectedLevel: zapcore.InfoLevel, expectedLog: "Database schema is up to date"},
+		{name: "Behind", version: 1746764390, expectedLevel: zapcore.WarnLevel, expectedLog: "Database schema is behind the migrations of this build; run make migrate"},
+		{name: "Dirty", version: 1760659200, dirty: true, expectedLevel: zapcore.WarnLevel, expectedLog: "Database schema is dirty; fix the failed migration and run migrate -force"},
+		{name: "Ahead", version: 1770000000, strict: true, expectedLevel: zapcore.WarnLevel, expectedLog: "Database schema is ahead of the migrations of this build"},
+		{name: "StrictBehind", version: 1746764390, strict: true, expectedError: "migration check failed: Database schema is behind"},
+		{name: "StrictDirty", version: 1760659200, dirty: true, strict: true, expectedError: "migration check failed: Database schema is dirty"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+			db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: gormlogger.Discard})
+			require.NoError(t, err)
+			sqlMock.ExpectQuery("schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(tc.version, tc.dirty))
+
+			core, logs := observer.New(zapcore.DebugLevel)
+			cfg := &config.Config{
+				Database:  config.DatabaseConfig{Driver: config.DBDriverMySQL},
+				Migration: config.MigrationConfig{Check: true, Strict: tc.strict},
+			}
+
+			err = checkMigrations(context.Background(), cfg, db, fsys, zap.New(core))
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, logs.Len())
+			entry := logs.All()[0]
+			assert.Equal(t, tc.expectedLevel, entry.Level)
+			assert.Equal(t, tc.expectedLog, entry.Message)
+			assert.Equal(t, map[string]interface{}{"version": uint64(tc.version), "latest": uint64(1760659200)}, entry.ContextMap())
+		})
+	}
+}
+
+func TestCheckMigrations_Disabled(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := &config.Config{Migration: config.MigrationConfig{Check: false}}
+
+	// The database isn't touched when the check is off
+	assert.NoError(t, checkMigrations(context.Background(), cfg, nil, fstest.MapFS{}, zap.New(core)))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestApp_ReloadLogLevel_ReadsEnvFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("LOG_LEVEL", "info")
+	require.NoError(t, os.WriteFile(".env", []byte("LOG_LEVEL=debug\n"), 0o600))
+
+	app := &App{Logger: zap.NewNop(), LogLevel: zap.NewAtomicLevelAt(zapcore.InfoLevel)}
+	require.NoError(t, app.ReloadLogLevel())
+	assert.Equal(t, zapcore.DebugLevel, app.LogLevel.Level())
+}
+
+func TestApp_StartsOutboxDispatcher(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Events: config.EventsConfig{OutboxEnabled: true, OutboxPollInterval: time.Hour, OutboxBatchSize: 10}}
+	_, dispatcher := newEventPublisher(cfg, database.NewDatabase(cfg, zap.NewNop(), db, nil), events.NewMemoryBroker(1), zap.NewNop())
+	require.NotNil(t, dispatcher)
+
+	// Initialization leaves the dispatcher stopped, so there is nothing to wait for
+	stopped, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, dispatcher.Stop(stopped))
+
+	app := &App{OutboxDispatcher: dispatcher}
+	app.Start()
+	assert.NoError(t, dispatcher.Stop(context.Background()))
+
+	// Without the outbox there is nothing to start
+	(&App{}).Start()
+}
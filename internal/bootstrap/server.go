@@ -3,31 +3,35 @@ package bootstrap
 import (
 	"fmt"
 	"net/http"
-	"time"
+	"path"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/linkeunid/go-api/internal/controller"
 	swaggerdocs "github.com/linkeunid/go-api/internal/docs/swaggerdocs"
+	"github.com/linkeunid/go-api/internal/version"
 	"github.com/linkeunid/go-api/pkg/auth"
 	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
 	custommiddleware "github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/openapi"
 	"github.com/linkeunid/go-api/pkg/response"
 	"github.com/linkeunid/go-api/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.uber.org/zap"
 )
 
-// SetupSwagger configures the Swagger documentation
-func SetupSwagger(port int, isDevelopment bool) {
+// SetupSwagger configures the Swagger documentation for an API mounted under basePath
+func SetupSwagger(port int, basePath string, isDevelopment bool) {
 	if isDevelopment {
 		// Set basic Swagger info
 		swaggerdocs.SwaggerInfo.Host = fmt.Sprintf("localhost:%d", port)
 		swaggerdocs.SwaggerInfo.Title = "Linkeun Go API"
-		swaggerdocs.SwaggerInfo.Description = "API for managing various resources including animals"
+		swaggerdocs.SwaggerInfo.Description = "API for managing various resources including animals and flowers"
 		swaggerdocs.SwaggerInfo.Version = "1.0"
-		swaggerdocs.SwaggerInfo.BasePath = "/api/v1"
+		swaggerdocs.SwaggerInfo.BasePath = basePath
 		swaggerdocs.SwaggerInfo.Schemes = []string{"http", "https"}
 
 		// We're using annotation-based Swagger docs, so we need to rely on the following
@@ -42,6 +46,67 @@ func SetupSwagger(port int, isDevelopment bool) {
 	}
 }
 
+// mountAPI registers the routes added by fn under basePath, or on r itself when basePath is "/"
+func mountAPI(r chi.Router, basePath string, fn func(r chi.Router)) {
+	if basePath == "/" {
+		r.Group(fn)
+		return
+	}
+	r.Route(basePath, fn)
+}
+
+// newRateLimitMiddleware builds the per-client rate limiting middleware, keyed by the
+// authenticated user or, for anonymous requests, by IP address
+func newRateLimitMiddleware(app *App) func(http.Handler) http.Handler {
+	cfg := app.Config.RateLimit
+	return rateLimitMiddleware(app.Config, app.DB, app.Logger, "Rate limiting", "", cfg.RPS, cfg.Burst)
+}
+
+// newIPRateLimitMiddleware builds the rate limiting middleware run before authentication, which
+// caps all the requests of an IP address however many clients share it
+func newIPRateLimitMiddleware(app *App) func(http.Handler) http.Handler {
+	cfg := app.Config.RateLimit
+	return rateLimitMiddleware(app.Config, app.DB, app.Logger, "IP rate limiting", "ip:", cfg.IPRPS, cfg.IPBurst)
+}
+
+// rateLimitMiddleware builds a rate limiting middleware, sharing counters through Redis
+// when it is the active cache backend and falling back to an in-memory limiter otherwise.
+// name labels the log line and scope keeps the Redis counters of different limiters apart
+func rateLimitMiddleware(cfg *config.Config, db database.Database, logger *zap.Logger, name, scope string, rps float64, burst int) func(http.Handler) http.Handler {
+	memoryLimiter := custommiddleware.NewMemoryRateLimiter(rps, burst)
+
+	if redisManager, ok := db.GetCacheManager().(*database.RedisCacheManager); ok {
+		logger.Info(name+" enabled with Redis backend",
+			zap.Float64("rps", rps),
+			zap.Int("burst", burst))
+		redisLimiter := custommiddleware.NewRedisRateLimiter(redisManager.Client(), cfg.Redis.KeyPrefix+scope, rps, burst)
+		return custommiddleware.RateLimit(redisLimiter, memoryLimiter, logger)
+	}
+
+	logger.Info(name+" enabled with in-memory backend",
+		zap.Float64("rps", rps),
+		zap.Int("burst", burst))
+	return custommiddleware.RateLimit(memoryLimiter, nil, logger)
+}
+
+// newAuthenticate returns the authentication middleware: a JWT bearer token or, when API_KEYS
+// is set, an X-API-Key header. A non-nil rateLimit is applied once the user is known
+func newAuthenticate(app *App, authMiddleware *custommiddleware.AuthMiddleware, rateLimit func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	authenticate := authMiddleware.Authenticate
+	if app.APIKeys != nil {
+		app.Logger.Info("API key authentication enabled", zap.Int("keys", len(app.APIKeys)))
+		apiKeyMiddleware := custommiddleware.NewAPIKeyMiddleware(app.APIKeys, &app.Config.Auth, app.Logger)
+		authenticate = custommiddleware.AnyAuth(apiKeyMiddleware, authMiddleware)
+	}
+	if rateLimit == nil {
+		return authenticate
+	}
+
+	return func(next http.Handler) http.Handler {
+		return authenticate(rateLimit(next))
+	}
+}
+
 // SetupServer configures and returns an HTTP server with all routes and middleware
 func SetupServer(app *App, animalController *controller.Animal) *http.Server {
 	logger := app.Logger
@@ -54,22 +119,45 @@ func SetupServer(app *App, animalController *controller.Animal) *http.Server {
 	jwtService := auth.NewJWTService(&cfg.Auth)
 
 	// Create auth middleware
-	authMiddleware := custommiddleware.NewAuthMiddleware(jwtService, &cfg.Auth, logger)
+	authMiddleware := custommiddleware.NewAuthMiddleware(jwtService, &cfg.Auth, app.Roles, logger)
+
+	// Clients are rate limited by user once authenticated, so users sharing an IP address get
+	// their own limits; anonymous requests are limited by IP address
+	var clientRateLimit func(http.Handler) http.Handler
+	if cfg.RateLimit.RPS > 0 {
+		clientRateLimit = newRateLimitMiddleware(app)
+	}
+	authenticate := newAuthenticate(app, authMiddleware, clientRateLimit)
+
+	// Resource writes are rejected while maintenance mode is enabled; admin routes are left
+	// out so it can be turned off again
+	maintenance := custommiddleware.MaintenanceMode(app.Maintenance, cfg.Maintenance.Methods, cfg.Maintenance.RetryAfter, logger)
 
 	// Middleware
 	r.Use(chimiddleware.RequestID)
+	r.Use(custommiddleware.RequestIDResponseHeader)
 	r.Use(chimiddleware.RealIP)
-	r.Use(chimiddleware.Logger)
-	r.Use(chimiddleware.Recoverer)
-	r.Use(chimiddleware.Timeout(30 * time.Second))
-	r.Use(custommiddleware.ValidationMiddleware) // Add our custom validation middleware
+	r.Use(custommiddleware.TraceID(logger))
+	if cfg.Telemetry.Enabled {
+		r.Use(custommiddleware.Tracing)
+	}
+	r.Use(custommiddleware.ZapLogger(logger))
+	if cfg.RateLimit.IPRPS > 0 {
+		r.Use(newIPRateLimitMiddleware(app))
+	}
+	if clientRateLimit != nil {
+		r.Use(custommiddleware.WithoutCredentials(clientRateLimit))
+	}
+	r.Use(custommiddleware.Recoverer(logger))
+	r.Use(custommiddleware.WithTimeout(cfg.Server.RequestTimeout)) // Sub-routers may override with their own WithTimeout
+	r.Use(custommiddleware.MaxBodyBytes(cfg.Server.MaxBodyBytes))
 
 	// CORS configuration
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedOrigins:   cfg.Server.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "X-API-Key", "Content-Type", "X-CSRF-Token", "If-None-Match", "Idempotency-Key", "X-Request-ID", "X-Trace-Id", "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"Link", "Location", "ETag", "Idempotent-Replayed", "Retry-After", "X-Request-ID", "X-Trace-Id", response.TotalCountHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -84,16 +172,24 @@ func SetupServer(app *App, animalController *controller.Animal) *http.Server {
 		}
 	})
 
+	// Prometheus metrics, off unless METRICS_ENABLED is set since they describe the deployment
+	if cfg.Metrics.Enabled {
+		r.With(custommiddleware.RequireBearerToken(cfg.Metrics.Token)).Handle("/metrics", promhttp.Handler())
+	}
+
 	// Swagger documentation - only available in development mode
 	if cfg.IsDevelopment() {
 		r.Get("/swagger/*", httpSwagger.Handler(
-			httpSwagger.URL("/swagger/doc.json"), // The URL points to API definition
+			httpSwagger.URL("/swagger/doc.json"),   // The URL points to API definition
+			httpSwagger.PersistAuthorization(true), // Keep the token entered in Authorize across reloads
 		))
+		// The same definition in OpenAPI 3 for tooling that doesn't read Swagger 2.0
+		r.Get("/openapi.json", openapi.Handler(swaggerdocs.SwaggerInfo.ReadDoc))
 		logger.Info("Swagger UI enabled in development mode")
 	}
 
-	// API routes
-	r.Route("/api/v1", func(r chi.Router) {
+	// API routes, under SERVER_BASE_PATH; health, metrics and docs stay at the root for probes
+	mountAPI(r, cfg.Server.BasePath, func(r chi.Router) {
 		// Public routes
 		r.Route("/public", func(r chi.Router) {
 			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -107,26 +203,10 @@ func SetupServer(app *App, animalController *controller.Animal) *http.Server {
 		// Protected routes (require authentication)
 		r.Route("/protected", func(r chi.Router) {
 			// Apply authentication middleware to all routes in this group
-			r.Use(authMiddleware.Authenticate)
+			r.Use(authenticate)
 
-			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-				// Extract user information from context
-				userID := r.Context().Value(custommiddleware.KeyUserID)
-				username := r.Context().Value(custommiddleware.KeyUsername)
-				role := r.Context().Value(custommiddleware.KeyUserRole)
-				email := r.Context().Value(custommiddleware.KeyUserEmail)
-
-				data := map[string]interface{}{
-					"message": "This endpoint requires authentication",
-					"user": map[string]interface{}{
-						"id":       userID,
-						"username": username,
-						"role":     role,
-						"email":    email,
-					},
-				}
-				response.Success(w, r, data, "Protected API is working")
-			})
+			// The authenticated user, as GET /me returns it
+			r.Get("/", app.AuthController.GetMe)
 
 			// Admin-only routes
 			r.Route("/admin", func(r chi.Router) {
@@ -142,8 +222,54 @@ func SetupServer(app *App, animalController *controller.Animal) *http.Server {
 			})
 		})
 
-		// Animal routes
-		animalController.RegisterRoutes(r)
+		// Admin routes change the running application or load whole tables, so they
+		// are only mounted when authentication is enabled to protect them
+		if cfg.Auth.Enabled {
+			r.Group(func(r chi.Router) {
+				r.Use(authenticate)
+				r.Use(authMiddleware.RequireRole("admin"))
+				app.AdminController.RegisterRoutes(r)
+				animalController.RegisterAdminRoutes(r)
+			})
+		} else {
+			logger.Info("Admin routes disabled because authentication is disabled")
+		}
+
+		// Login and refresh issue tokens, which are only checked when authentication is enabled
+		if cfg.Auth.Enabled {
+			app.AuthController.RegisterRoutes(r)
+		}
+
+		// The authenticated user
+		r.Group(func(r chi.Router) {
+			r.Use(authenticate)
+			app.AuthController.RegisterUserRoutes(r)
+		})
+
+		// Build metadata
+		controller.NewVersion().RegisterRoutes(r)
+
+		// GraphQL shares the services, and the authentication, of the REST API
+		if app.GraphQLHandler != nil {
+			r.With(authenticate).Handle("/graphql", app.GraphQLHandler)
+			logger.Info("GraphQL endpoint enabled", zap.String("path", path.Join(cfg.Server.BasePath, "graphql")))
+		}
+
+		// Animal routes and change subscriptions. Reads are public, while writes need the
+		// animals:write scope when authentication is enabled
+		app.Subscriptions.RegisterRoutes(r)
+		r.Group(func(r chi.Router) {
+			if cfg.Auth.Enabled {
+				r.Use(custommiddleware.OnWrites(authenticate, authMiddleware.RequireScope("animals:write")))
+			}
+			r.Use(maintenance)
+			animalController.RegisterRoutes(r)
+		})
+
+		// Flower routes
+		app.FlowerController.RegisterRoutes(r.With(maintenance))
+
+		// scaffold:routes
 	})
 
 	// Create and return server
@@ -154,12 +280,20 @@ func SetupServer(app *App, animalController *controller.Animal) *http.Server {
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
+	// Shutdown doesn't wait for upgraded connections, so subscribers are told to go away
+	server.RegisterOnShutdown(app.Subscriptions.Shutdown)
+
 	return server
 }
 
 // LogServerInfo logs server startup information
 func LogServerInfo(logger *zap.Logger, port int, isDevelopment bool, config *config.Config) {
-	logger.Info("Starting server", zap.Int("port", port))
+	build := version.Get()
+	logger.Info("Starting server",
+		zap.Int("port", port),
+		zap.String("version", build.Version),
+		zap.String("commit", build.Commit),
+		zap.String("buildTime", build.BuildTime))
 	logger.Info("Auth configuration", zap.Bool("enabled", config.Auth.Enabled))
 
 	// Log information about file logging if enabled
@@ -195,6 +329,10 @@ func MaskSensitiveData(dataType string, value string) string {
 		return util.MaskURL(value)
 	case "dsn":
 		return util.MaskDsn(value)
+	case "phone":
+		return util.MaskPhone(value)
+	case "ip":
+		return util.MaskIP(value)
 	default:
 		return util.MaskSensitive(value, 4, 0) // default mask showing only first 4 chars
 	}
@@ -0,0 +1,166 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/controller"
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestConfig returns the configuration of a test application whose API is mounted under basePath
+func newTestConfig(basePath string) *config.Config {
+	return &config.Config{
+		Environment: "test",
+		Server:      config.ServerConfig{RequestTimeout: time.Second, MaxBodyBytes: 1 << 20, BasePath: basePath},
+	}
+}
+
+// newTestApp returns an application configured by cfg with a controller for every resource, so
+// its routes can be registered. The resource generator adds the controllers it scaffolds here
+func newTestApp(cfg *config.Config) *App {
+	return &App{
+		Logger:           zap.NewNop(),
+		Config:           cfg,
+		DB:               database.NewDatabase(cfg, zap.NewNop(), nil, database.NewInMemoryCacheManager(cfg, zap.NewNop())),
+		AnimalController: controller.NewAnimal(nil, nil),
+		FlowerController: controller.NewFlower(nil),
+		AdminController:  controller.NewAdmin(zap.NewAtomicLevel(), nil, nil, middleware.NewMemoryMaintenanceStore(), controller.StatusReport{}),
+		AuthController:   controller.NewAuth(nil),
+		Subscriptions:    controller.NewSubscriptions(events.NewMemoryBroker(1), nil),
+		Maintenance:      middleware.NewMemoryMaintenanceStore(),
+		// scaffold:test-app-values
+	}
+}
+
+// newTestServer sets up the server of an application configured by cfg
+func newTestServer(cfg *config.Config) http.Handler {
+	app := newTestApp(cfg)
+	return SetupServer(app, app.AnimalController).Handler
+}
+
+func TestSetupServer_BasePath(t *testing.T) {
+	tests := []struct {
+		name           string
+		basePath       string
+		path           string
+		expectedStatus int
+	}{
+		{name: "DefaultPrefix", basePath: "/api/v1", path: "/api/v1/version", expectedStatus: http.StatusOK},
+		{name: "CustomPrefix", basePath: "/gateway/api", path: "/gateway/api/version", expectedStatus: http.StatusOK},
+		{name: "DefaultPrefixUnderCustomPrefix", basePath: "/gateway/api", path: "/api/v1/version", expectedStatus: http.StatusNotFound},
+		{name: "RootPrefix", basePath: "/", path: "/version", expectedStatus: http.StatusOK},
+		{name: "HealthStaysAtRoot", basePath: "/gateway/api", path: "/health", expectedStatus: http.StatusOK},
+		{name: "HealthNotUnderPrefix", basePath: "/gateway/api", path: "/gateway/api/health", expectedStatus: http.StatusNotFound},
+		{name: "HealthWithRootPrefix", basePath: "/", path: "/health", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			newTestServer(newTestConfig(tc.basePath)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestSetupServer_Metrics(t *testing.T) {
+	tests := []struct {
+		name           string
+		metrics        config.MetricsConfig
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "DisabledByDefault", expectedStatus: http.StatusNotFound},
+		{name: "Enabled", metrics: config.MetricsConfig{Enabled: true}, expectedStatus: http.StatusOK},
+		{name: "TokenMissing", metrics: config.MetricsConfig{Enabled: true, Token: "scrape"}, expectedStatus: http.StatusUnauthorized},
+		{name: "TokenSent", metrics: config.MetricsConfig{Enabled: true, Token: "scrape"}, authorization: "Bearer scrape", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Metrics stay at the root whatever the base path
+			cfg := newTestConfig("/gateway/api")
+			cfg.Metrics = tc.metrics
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+			newTestServer(cfg).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestSetupServer_RateLimitsUsersSeparately(t *testing.T) {
+	cfg := newTestConfig("/api/v1")
+	cfg.Auth = config.AuthConfig{Enabled: true, JWTSecret: "test-secret", JWTExpiration: time.Hour}
+	cfg.RateLimit = config.RateLimitConfig{RPS: 0.001, Burst: 1, IPRPS: 100, IPBurst: 100}
+	handler := newTestServer(cfg)
+
+	jwtService := auth.NewJWTService(&cfg.Auth)
+	ann, err := jwtService.GenerateToken(1, "ann", "user", "ann@example.com", nil)
+	require.NoError(t, err)
+	bob, err := jwtService.GenerateToken(2, "bob", "user", "bob@example.com", nil)
+	require.NoError(t, err)
+
+	// Every request comes from the same IP address, as from clients behind one NAT
+	get := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:4321"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("/api/v1/me", ann))
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/v1/me", ann), "ann's bucket is empty")
+	assert.Equal(t, http.StatusOK, get("/api/v1/me", bob), "bob's bucket is separate from ann's")
+
+	// Anonymous requests share the bucket of their IP address
+	assert.Equal(t, http.StatusOK, get("/api/v1/version", ""))
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/v1/version", ""))
+}
+
+func TestSetupServer_ProtectedServesCurrentUser(t *testing.T) {
+	cfg := newTestConfig("/api/v1")
+	cfg.Auth = config.AuthConfig{Enabled: true, JWTSecret: "test-secret", JWTExpiration: time.Hour}
+	handler := newTestServer(cfg)
+
+	token, err := auth.NewJWTService(&cfg.Auth).GenerateToken(1, "ann", "user", "ann@example.com", nil)
+	require.NoError(t, err)
+
+	bodies := make([]controller.CurrentUser, 0, 2)
+	for _, path := range []string{"/api/v1/protected/", "/api/v1/me"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, path)
+
+		var resp struct {
+			Data controller.CurrentUser `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		bodies = append(bodies, resp.Data)
+	}
+	assert.Equal(t, bodies[1], bodies[0])
+	assert.Equal(t, uint64(1), bodies[0].ID)
+}
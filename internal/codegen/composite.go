@@ -0,0 +1,131 @@
+// Package codegen provides helpers for the code generation tools that keep registries in Go
+// source files up to date
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+// File is a parsed Go source file
+type File struct {
+	src  []byte
+	fset *token.FileSet
+	ast  *ast.File
+}
+
+// Parse parses Go source, keeping its comments
+func Parse(src []byte) (*File, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	return &File{src: src, fset: fset, ast: file}, nil
+}
+
+// VarCompositeLit returns the composite literal assigned to the package-level variable name,
+// e.g. the map in "var modelMap = map[string]interface{}{...}"
+func (f *File) VarCompositeLit(name string) (*ast.CompositeLit, error) {
+	for _, decl := range f.ast.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, ident := range valueSpec.Names {
+				if ident.Name != name || i >= len(valueSpec.Values) {
+					continue
+				}
+				if lit, ok := valueSpec.Values[i].(*ast.CompositeLit); ok {
+					return lit, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no composite literal assigned to var %s", name)
+}
+
+// ReturnedCompositeLit returns the composite literal returned by the function name,
+// e.g. the slice in "func registerSeeders() []Seeder { return []Seeder{...} }"
+func (f *File) ReturnedCompositeLit(name string) (*ast.CompositeLit, error) {
+	for _, decl := range f.ast.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Name.Name != name || funcDecl.Body == nil {
+			continue
+		}
+
+		var lit *ast.CompositeLit
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			if ret, ok := n.(*ast.ReturnStmt); ok && lit == nil && len(ret.Results) == 1 {
+				lit, _ = ret.Results[0].(*ast.CompositeLit)
+			}
+			return lit == nil
+		})
+		if lit != nil {
+			return lit, nil
+		}
+	}
+	return nil, fmt.Errorf("no composite literal returned by func %s", name)
+}
+
+// ReplaceElements replaces the elements of lit with the given element sources and returns the
+// file formatted with go/format. Comments inside the literal that sit on their own line, such
+// as "// Add more models here", are kept after the new elements
+func (f *File) ReplaceElements(lit *ast.CompositeLit, elements []string) ([]byte, error) {
+	// Lines holding an element, so comments trailing a replaced element go with it
+	elementLines := make(map[int]bool)
+	for _, elt := range lit.Elts {
+		for line := f.line(elt.Pos()); line <= f.line(elt.End()); line++ {
+			elementLines[line] = true
+		}
+	}
+
+	var comments []string
+	for _, group := range f.ast.Comments {
+		if group.Pos() < lit.Lbrace || group.End() > lit.Rbrace {
+			continue
+		}
+		for _, comment := range group.List {
+			if !elementLines[f.line(comment.Pos())] {
+				comments = append(comments, comment.Text)
+			}
+		}
+	}
+
+	var body bytes.Buffer
+	body.WriteString("\n")
+	for _, element := range elements {
+		body.WriteString(element + ",\n")
+	}
+	for _, comment := range comments {
+		body.WriteString(comment + "\n")
+	}
+
+	start := f.fset.Position(lit.Lbrace).Offset + 1
+	end := f.fset.Position(lit.Rbrace).Offset
+
+	var out bytes.Buffer
+	out.Write(f.src[:start])
+	out.Write(body.Bytes())
+	out.Write(f.src[end:])
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// line returns the line number of pos
+func (f *File) line(pos token.Pos) int {
+	return f.fset.Position(pos).Line
+}
@@ -0,0 +1,102 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceElements_Var(t *testing.T) {
+	src := `package main
+
+var other = map[string]int{"a": 1}
+
+var modelMap = map[string]interface{}{
+	"animal":    &model.Animal{}, // trailing comment goes with the element
+		"flower": &model.Flower{},
+
+	// Add more models here as they are implemented
+}
+
+func main() {}
+`
+
+	file, err := Parse([]byte(src))
+	require.NoError(t, err)
+
+	lit, err := file.VarCompositeLit("modelMap")
+	require.NoError(t, err)
+
+	result, err := file.ReplaceElements(lit, []string{
+		`"animal": &model.Animal{}`,
+		`"user_profile": &model.UserProfile{}`,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `package main
+
+var other = map[string]int{"a": 1}
+
+var modelMap = map[string]interface{}{
+	"animal":       &model.Animal{},
+	"user_profile": &model.UserProfile{},
+	// Add more models here as they are implemented
+}
+
+func main() {}
+`, string(result))
+}
+
+func TestReplaceElements_Return(t *testing.T) {
+	src := `package main
+
+// registerSeeders returns the seeders
+func registerSeeders(db Database) []Seeder {
+	return []Seeder{seeder.NewAnimalSeeder(db, counts.get("animal")),
+		// Add more seeders here as they are implemented
+	}
+}
+`
+
+	file, err := Parse([]byte(src))
+	require.NoError(t, err)
+
+	lit, err := file.ReturnedCompositeLit("registerSeeders")
+	require.NoError(t, err)
+
+	result, err := file.ReplaceElements(lit, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, `package main
+
+// registerSeeders returns the seeders
+func registerSeeders(db Database) []Seeder {
+	return []Seeder{
+		// Add more seeders here as they are implemented
+	}
+}
+`, string(result))
+}
+
+func TestCompositeLitNotFound(t *testing.T) {
+	file, err := Parse([]byte("package main\n\nvar modelMap map[string]interface{}\n\nfunc registerSeeders() {}\n"))
+	require.NoError(t, err)
+
+	_, err = file.VarCompositeLit("modelMap")
+	assert.Error(t, err)
+
+	_, err = file.ReturnedCompositeLit("registerSeeders")
+	assert.Error(t, err)
+}
+
+func TestReplaceElements_InvalidElement(t *testing.T) {
+	file, err := Parse([]byte("package main\n\nvar modelMap = map[string]int{}\n"))
+	require.NoError(t, err)
+
+	lit, err := file.VarCompositeLit("modelMap")
+	require.NoError(t, err)
+
+	_, err = file.ReplaceElements(lit, []string{`"animal": (`})
+	assert.Error(t, err)
+}
@@ -0,0 +1,26 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts a string from PascalCase to snake_case the way GORM names columns and
+// tables, so acronyms stay together and digits stick to the word before them
+// e.g., "HTTPServer" -> "http_server", "UserID" -> "user_id", "Animal2" -> "animal2"
+func SnakeCase(s string) string {
+	runes := []rune(s)
+	var result strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Start a new word after a lowercase letter or digit, or at the last capital of an acronym
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				result.WriteByte('_')
+			}
+		}
+		result.WriteRune(unicode.ToLower(r))
+	}
+	return result.String()
+}
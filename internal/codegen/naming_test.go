@@ -0,0 +1,33 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// Existing model keys must not change
+		{input: "Animal", expected: "animal"},
+		{input: "Flower", expected: "flower"},
+		{input: "UserProfile", expected: "user_profile"},
+		{input: "HTTPServer", expected: "http_server"},
+		{input: "UserID", expected: "user_id"},
+		{input: "APIKey", expected: "api_key"},
+		{input: "OAuth2Token", expected: "o_auth2_token"},
+		{input: "Animal2", expected: "animal2"},
+		{input: "V2Animal", expected: "v2_animal"},
+		{input: "ID", expected: "id"},
+		{input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SnakeCase(tt.input))
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/internal/version"
+	"github.com/linkeunid/go-api/pkg/cache"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/linkeunid/go-api/pkg/util"
+	"go.uber.org/zap"
+)
+
+// Admin handles operational requests that inspect or change the running application
+type Admin struct {
+	level        zap.AtomicLevel
+	cacheStats   database.CacheStatsProvider
+	cacheFlusher database.CacheFlusher
+	maintenance  middleware.MaintenanceStore
+	status       StatusReport
+}
+
+// LogLevelRequest is the body accepted by SetLogLevel
+type LogLevelRequest struct {
+	Level string `json:"level" xml:"level" example:"debug"`
+}
+
+// CacheFlushResult is the outcome of FlushCache
+type CacheFlushResult struct {
+	Deleted int `json:"deleted" xml:"deleted" example:"42"`
+}
+
+// MaintenanceRequest is the body accepted by SetMaintenance, and the state it returns
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled" xml:"enabled" example:"true"`
+}
+
+// StatusReport summarizes the effective configuration of the running application for operators.
+// Secrets are masked and the database DSN is reduced to its address
+type StatusReport struct {
+	Environment  string         `json:"environment" xml:"environment" example:"production"`
+	Version      string         `json:"version" xml:"version" example:"v1.2.0"`
+	LogLevel     string         `json:"log_level" xml:"log_level" example:"info"`
+	CacheBackend string         `json:"cache_backend" xml:"cache_backend" example:"redis"`
+	Maintenance  bool           `json:"maintenance" xml:"maintenance" example:"false"`
+	Database     DatabaseStatus `json:"database" xml:"database"`
+	Redis        RedisStatus    `json:"redis" xml:"redis"`
+	Auth         AuthStatus     `json:"auth" xml:"auth"`
+}
+
+// DatabaseStatus is the database part of StatusReport
+type DatabaseStatus struct {
+	Driver string `json:"driver" xml:"driver" example:"mysql"`
+	database.DSNAddress
+}
+
+// RedisStatus is the Redis part of StatusReport
+type RedisStatus struct {
+	Enabled  bool   `json:"enabled" xml:"enabled" example:"true"`
+	Host     string `json:"host" xml:"host" example:"localhost"`
+	Port     int    `json:"port" xml:"port" example:"6379"`
+	Password string `json:"password,omitempty" xml:"password,omitempty" example:"se****et"`
+}
+
+// AuthStatus is the authentication part of StatusReport
+type AuthStatus struct {
+	Enabled   bool   `json:"enabled" xml:"enabled" example:"true"`
+	JWTSecret string `json:"jwt_secret,omitempty" xml:"jwt_secret,omitempty" example:"my******ey"`
+	APIKeys   int    `json:"api_keys" xml:"api_keys" example:"2"`
+}
+
+// NewStatusReport builds the StatusReport of cfg. cacheBackend is the backend actually in use,
+// which is "none" when the configured one failed to start
+func NewStatusReport(cfg *config.Config, cacheBackend string) StatusReport {
+	// The DSN carries the password, so only its address is reported
+	address, _ := database.ParseDSNAddress(cfg.Database.Driver, cfg.Database.DSN)
+
+	return StatusReport{
+		Environment:  cfg.Environment,
+		Version:      version.Get().Version,
+		LogLevel:     cfg.Logging.Level,
+		CacheBackend: cacheBackend,
+		Database:     DatabaseStatus{Driver: cfg.Database.Driver, DSNAddress: address},
+		Redis: RedisStatus{
+			Enabled:  cfg.Redis.Enabled,
+			Host:     cfg.Redis.Host,
+			Port:     cfg.Redis.Port,
+			Password: util.MaskCredential(cfg.Redis.Password),
+		},
+		Auth: AuthStatus{
+			Enabled:   cfg.Auth.Enabled,
+			JWTSecret: util.MaskCredential(cfg.Auth.JWTSecret),
+			APIKeys:   len(cfg.Auth.APIKeys),
+		},
+	}
+}
+
+// cacheEntityPattern matches the entity names accepted by FlushCache, so an entity can't
+// carry a wildcard of its own
+var cacheEntityPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// NewAdmin creates a new Admin controller that adjusts the given log level, reports the
+// counts of cacheStats, flushes keys with cacheFlusher, toggles maintenance mode in
+// maintenance and reports status; the cache arguments are nil when caching is disabled
+func NewAdmin(level zap.AtomicLevel, cacheStats database.CacheStatsProvider, cacheFlusher database.CacheFlusher, maintenance middleware.MaintenanceStore, status StatusReport) *Admin {
+	return &Admin{
+		level:        level,
+		cacheStats:   cacheStats,
+		cacheFlusher: cacheFlusher,
+		maintenance:  maintenance,
+		status:       status,
+	}
+}
+
+// RegisterRoutes registers the admin routes; callers are responsible for protecting them
+func (a *Admin) RegisterRoutes(r chi.Router) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Get("/log-level", a.GetLogLevel)
+		r.Put("/log-level", a.SetLogLevel)
+		r.Get("/cache/stats", a.GetCacheStats)
+		r.Delete("/cache", a.FlushCache)
+		r.Get("/maintenance", a.GetMaintenance)
+		r.Put("/maintenance", a.SetMaintenance)
+		r.Get("/status", a.GetStatus)
+	})
+}
+
+// GetStatus returns a summary of the effective configuration
+// @Summary Get the application status
+// @Description Get the environment, version, database address, Redis and cache settings, auth settings,
+// @Description current log level and maintenance mode of this instance. Secrets are masked and the
+// @Description database DSN is never returned
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=StatusReport}
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/status [get]
+func (a *Admin) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status := a.status
+	status.LogLevel = a.level.Level().String()
+	if a.maintenance != nil {
+		enabled, err := a.maintenance.Enabled(r.Context())
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("Failed to read maintenance mode", zap.Error(err))
+		}
+		status.Maintenance = enabled
+	}
+
+	response.Success(w, r, status, "Status retrieved successfully")
+}
+
+// GetLogLevel returns the current log level
+// @Summary Get the log level
+// @Description Get the minimum level of messages currently being logged
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=LogLevelRequest}
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/log-level [get]
+func (a *Admin) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, r, LogLevelRequest{Level: a.level.Level().String()}, "Log level retrieved successfully")
+}
+
+// SetLogLevel changes the log level without restarting the application
+// @Summary Set the log level
+// @Description Change the minimum level of messages being logged until the next restart
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body LogLevelRequest true "New log level (debug, info, warn, error)"
+// @Success 200 {object} response.APIResponse{data=LogLevelRequest}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/log-level [put]
+func (a *Admin) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, "Invalid JSON format", err)
+		return
+	}
+
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		response.BadRequest(w, r, "Invalid log level", err)
+		return
+	}
+
+	previous := a.level.Level()
+	a.level.SetLevel(level)
+
+	// Logged at warn so the change is recorded at every level except error
+	logging.FromContext(r.Context()).Warn("Log level changed",
+		zap.String("from", previous.String()),
+		zap.String("to", level.String()),
+		zap.String("request_id", chimiddleware.GetReqID(r.Context())))
+
+	response.Success(w, r, LogLevelRequest{Level: level.String()}, "Log level updated successfully")
+}
+
+// GetCacheStats returns the cache operation counts
+// @Summary Get cache statistics
+// @Description Get the cache hits, misses, sets and deletes counted by this instance since it started
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=database.CacheStats}
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/cache/stats [get]
+func (a *Admin) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	if a.cacheStats == nil {
+		response.Error(w, r, http.StatusNotFound, "CACHE_DISABLED", "Caching is disabled")
+		return
+	}
+
+	response.Success(w, r, a.cacheStats.Stats(), "Cache statistics retrieved successfully")
+}
+
+// FlushCache deletes every cached key of an entity, or a single key
+// @Summary Flush cache entries
+// @Description Delete every cached key of an entity (items, field selections and lists), or one exact key,
+// @Description without restarting the cache. Send exactly one of entity and key
+// @Tags admin
+// @Produce json
+// @Param entity query string false "Entity whose keys are deleted, e.g. animals"
+// @Param key query string false "Exact cache key to delete, without the REDIS_KEY_PREFIX, e.g. v1:animals:item:1"
+// @Success 200 {object} response.APIResponse{data=CacheFlushResult}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/cache [delete]
+func (a *Admin) FlushCache(w http.ResponseWriter, r *http.Request) {
+	if a.cacheFlusher == nil {
+		response.Error(w, r, http.StatusNotFound, "CACHE_DISABLED", "Caching is disabled")
+		return
+	}
+
+	entity := r.URL.Query().Get("entity")
+	key := r.URL.Query().Get("key")
+
+	var target string
+	switch {
+	case (entity == "") == (key == ""):
+		response.BadRequest(w, r, "Send exactly one of entity and key", nil)
+		return
+	case entity != "":
+		if !cacheEntityPattern.MatchString(entity) {
+			response.BadRequest(w, r, "Entity must contain only lowercase letters, digits and underscores", nil)
+			return
+		}
+		target = cache.GenerateEntityPattern(entity)
+	default:
+		// A wildcard would turn the single-key form into a pattern delete
+		if strings.Contains(key, "*") {
+			response.BadRequest(w, r, "Key must not contain wildcards; use entity to delete many keys", nil)
+			return
+		}
+		target = key
+	}
+
+	deleted, err := a.cacheFlusher.Flush(r.Context(), target)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to flush cache", zap.String("key", target), zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	// Logged at warn like log level changes, so operator actions are recorded
+	logging.FromContext(r.Context()).Warn("Cache flushed",
+		zap.String("key", target),
+		zap.Int("deleted", deleted),
+		zap.String("request_id", chimiddleware.GetReqID(r.Context())))
+
+	response.Success(w, r, CacheFlushResult{Deleted: deleted}, "Cache flushed successfully")
+}
+
+// GetMaintenance reports whether maintenance mode is enabled
+// @Summary Get maintenance mode
+// @Description Get whether maintenance mode, which rejects writes with 503 while reads stay available, is enabled
+// @Tags admin
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=MaintenanceRequest}
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/maintenance [get]
+func (a *Admin) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	enabled, err := a.maintenance.Enabled(r.Context())
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.Success(w, r, MaintenanceRequest{Enabled: &enabled}, "Maintenance mode retrieved successfully")
+}
+
+// SetMaintenance enables or disables maintenance mode on every instance sharing the Redis server
+// @Summary Set maintenance mode
+// @Description Enable or disable maintenance mode. While it is enabled, writes (MAINTENANCE_METHODS) are rejected
+// @Description with 503 and a Retry-After header while reads stay available. The flag is shared through Redis
+// @Description when it is the cache backend, otherwise it only applies to the instance handling this request
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body MaintenanceRequest true "Whether maintenance mode is enabled"
+// @Success 200 {object} response.APIResponse{data=MaintenanceRequest}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /admin/maintenance [put]
+func (a *Admin) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, "Invalid JSON format", err)
+		return
+	}
+	if req.Enabled == nil {
+		response.BadRequest(w, r, "enabled is required", nil)
+		return
+	}
+
+	if err := a.maintenance.SetEnabled(r.Context(), *req.Enabled); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to set maintenance mode", zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	// Logged at warn like log level changes, so operator actions are recorded
+	logging.FromContext(r.Context()).Warn("Maintenance mode changed",
+		zap.Bool("enabled", *req.Enabled),
+		zap.String("request_id", chimiddleware.GetReqID(r.Context())))
+
+	response.Success(w, r, req, "Maintenance mode updated successfully")
+}
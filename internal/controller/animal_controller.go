@@ -1,45 +1,42 @@
 package controller
 
 import (
-	"context"
 	"net/http"
-	"strconv"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/linkeunid/go-api/internal/model"
 	"github.com/linkeunid/go-api/internal/repository"
 	"github.com/linkeunid/go-api/internal/service"
-	"github.com/linkeunid/go-api/pkg/middleware"
-	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/jobs"
 	"github.com/linkeunid/go-api/pkg/response"
-	"go.uber.org/zap"
 )
 
 // Animal handles animal requests
+// Routing and error mapping come from the embedded CRUDController; the methods
+// below only carry the Swagger annotations for each endpoint
 type Animal struct {
-	logger  *zap.Logger
-	service service.AnimalService
+	*CRUDController[model.Animal]
 }
 
 // NewAnimal creates a new Animal controller instance
-func NewAnimal(logger *zap.Logger, service service.AnimalService) *Animal {
+// importJobs keeps the state of background imports; nil keeps it in memory.
+// createMiddleware is applied to the create route, e.g. middleware.Idempotency
+func NewAnimal(service service.AnimalService, importJobs jobs.Store, createMiddleware ...func(http.Handler) http.Handler) *Animal {
 	return &Animal{
-		logger:  logger,
-		service: service,
+		CRUDController: NewCRUDController[model.Animal](service, CRUDConfig[model.Animal]{
+			Prefix:  "/animals",
+			Tag:     "animal",
+			IDParam: "animalID",
+			ETag: func(animal *model.Animal) string {
+				return response.GenerateETag(animal.ID, animal.UpdatedAt)
+			},
+			SortFields:       repository.AnimalSortFields,
+			FilterFields:     repository.AnimalFilterFields,
+			CreateMiddleware: createMiddleware,
+			ImportJobs:       importJobs,
+		}),
 	}
 }
 
-// RegisterRoutes registers all routes for the animal controller
-func (a *Animal) RegisterRoutes(r chi.Router) {
-	r.Route("/animals", func(r chi.Router) {
-		r.Get("/", a.GetAnimals)
-		r.Post("/", a.CreateAnimal)
-		r.Get("/{animalID}", a.GetAnimal)
-		r.Put("/{animalID}", a.UpdateAnimal)
-		r.Delete("/{animalID}", a.DeleteAnimal)
-	})
-}
-
 // GetAnimals returns all animals
 // @Summary Get all animals
 // @Description Get a paginated list of all animals
@@ -50,43 +47,135 @@ func (a *Animal) RegisterRoutes(r chi.Router) {
 // @Param limit query int false "Items per page (default: 10, max: 100)"
 // @Param sort query string false "Sort field (id, name, species, age, created_at, updated_at)"
 // @Param direction query string false "Sort direction (asc, desc)"
+// @Param fields query string false "Comma-separated fields to return (id, name, species, age, description, version, created_at, updated_at)"
+// @Param include query string false "Comma-separated associations to load with each record"
+// @Param id query int false "Filter by exact ID"
+// @Param name query string false "Filter by exact name"
+// @Param name_like query string false "Filter by names containing the value"
+// @Param species query string false "Filter by exact species"
+// @Param species_like query string false "Filter by species containing the value"
+// @Param age query int false "Filter by exact age"
+// @Param age_gte query int false "Filter by age greater than or equal to the value"
+// @Param age_lte query int false "Filter by age less than or equal to the value"
+// @Param created_at_gte query string false "Filter by creation time on or after the value"
+// @Param created_at_lte query string false "Filter by creation time on or before the value"
+// @Param updated_at_gte query string false "Filter by update time on or after the value"
+// @Param updated_at_lte query string false "Filter by update time on or before the value"
+// @Param created_after query string false "Filter by creation time on or after the value (alias of created_at_gte)"
+// @Param created_before query string false "Filter by creation time on or before the value (alias of created_at_lte)"
+// @Param updated_after query string false "Filter by update time on or after the value (alias of updated_at_gte)"
+// @Param updated_before query string false "Filter by update time on or before the value (alias of updated_at_lte)"
+// @Param tz query string false "IANA time zone of timestamps without an offset (default: UTC)"
 // @Success 200 {object} response.APIResponse{data=pagination.PagedData{items=[]model.Animal}}
+// @Failure 400 {object} response.ValidationErrorResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /animals [get]
 func (a *Animal) GetAnimals(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	// Parse pagination parameters from the request
-	params := pagination.NewParams(r)
-
-	// Extract sort parameters and add to context
-	queryParams := make(map[string]string)
+	a.List(w, r)
+}
 
-	// Add query parameters to the context for cache key generation
-	queryParams["page"] = strconv.Itoa(params.Page)
-	queryParams["limit"] = strconv.Itoa(params.Limit)
-	queryParams["sort"] = r.URL.Query().Get("sort")
-	queryParams["direction"] = r.URL.Query().Get("direction")
+// CountAnimals returns the number of animals matching the filters
+// @Summary Count animals
+// @Description Count the animals matching the filters without fetching them. Accepts the same filter
+// @Description parameters as the list endpoint; pagination and sort are validated but don't change the count
+// @Tags animals
+// @Produce json
+// @Param species query string false "Filter by exact species"
+// @Param species_like query string false "Filter by species containing the value"
+// @Param age_gte query int false "Filter by age greater than or equal to the value"
+// @Param age_lte query int false "Filter by age less than or equal to the value"
+// @Param created_after query string false "Filter by creation time on or after the value"
+// @Param created_before query string false "Filter by creation time on or before the value"
+// @Param tz query string false "IANA time zone of timestamps without an offset (default: UTC)"
+// @Success 200 {object} response.APIResponse{data=controller.CountResult}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /animals/count [get]
+func (a *Animal) CountAnimals(w http.ResponseWriter, r *http.Request) {
+	a.Count(w, r)
+}
 
-	// Create a new context with query parameters using the typed key
-	ctxWithParams := context.WithValue(ctx, repository.KeyQueryParams, queryParams)
+// GetAllAnimals returns every animal without pagination
+// @Summary Get every animal
+// @Description Get all animals, newest first, in a single response. Requires the admin role and is only
+// @Description available when authentication is enabled. Fails with 400 when there are more than 1000
+// @Description animals; use the paginated list or the export endpoint for larger tables. The response is a single page in the envelope of the paginated list
+// @Tags animals
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=pagination.PagedData{items=[]model.Animal}}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /animals/all [get]
+func (a *Animal) GetAllAnimals(w http.ResponseWriter, r *http.Request) {
+	a.ListAll(w, r)
+}
 
-	// Get paginated animals
-	result, err := a.service.GetAllPaginated(ctxWithParams, params)
-	if err != nil {
-		a.logger.Error("Failed to get animals", zap.Error(err))
-		response.InternalServerError(w, r, err)
-		return
-	}
+// ExportAnimals exports all animals as a file
+// @Summary Export animals
+// @Description Stream every animal matching the filters as a CSV or JSON attachment, without pagination.
+// @Description Accepts the same sort, direction and filter parameters as the list endpoint
+// @Tags animals
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Export format (csv, json; default: json)"
+// @Param sort query string false "Sort field (id, name, species, age, created_at, updated_at)"
+// @Param direction query string false "Sort direction (asc, desc)"
+// @Param species query string false "Filter by exact species"
+// @Param age_gte query int false "Filter by age greater than or equal to the value"
+// @Param age_lte query int false "Filter by age less than or equal to the value"
+// @Param created_after query string false "Filter by creation time on or after the value"
+// @Param created_before query string false "Filter by creation time on or before the value"
+// @Param tz query string false "IANA time zone of timestamps without an offset (default: UTC)"
+// @Success 200 {array} model.Animal
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /animals/export [get]
+func (a *Animal) ExportAnimals(w http.ResponseWriter, r *http.Request) {
+	a.Export(w, r)
+}
 
-	// Create a paginated response with cache info
-	pagedData := pagination.PagedData{
-		Items:      result.Data,
-		Pagination: *result.Pagination,
-		CacheInfo:  result.CacheInfo,
-	}
+// ImportAnimals creates animals from an uploaded CSV file
+// @Summary Import animals
+// @Description Create animals from the rows of a CSV file. The header row names the columns after the
+// @Description animal's JSON fields, in any order; unknown columns reject the file. id, version and timestamp
+// @Description columns are ignored. Rows that fail to parse or validate are reported and skipped, and the
+// @Description remaining rows are inserted in a single transaction. With async=true the rows are inserted
+// @Description in the background; the response is the job, and its Location header the job's event stream
+// @Tags animals
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "CSV file with a header row"
+// @Param async query bool false "Insert the rows in the background and return a job to follow"
+// @Success 200 {object} response.APIResponse{data=ImportResult}
+// @Success 202 {object} response.APIResponse{data=jobs.Job}
+// @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 413 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /animals/import [post]
+func (a *Animal) ImportAnimals(w http.ResponseWriter, r *http.Request) {
+	a.Import(w, r)
+}
 
-	response.Success(w, r, pagedData, "Animals retrieved successfully")
+// ImportAnimalsEvents streams the progress of a background animal import
+// @Summary Follow an animal import
+// @Description Stream the state of an import started with async=true as server-sent events: "progress"
+// @Description events carry {processed, total} whenever more rows have been inserted, and the stream ends
+// @Description with a "done" event carrying the import result or an "error" event carrying {error_code, message}.
+// @Description Jobs are kept for an hour after their last update
+// @Tags animals
+// @Produce text/event-stream
+// @Param jobID path string true "Import job ID"
+// @Success 200 {object} ImportProgress
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /animals/import/{jobID}/events [get]
+func (a *Animal) ImportAnimalsEvents(w http.ResponseWriter, r *http.Request) {
+	a.ImportEvents(w, r)
 }
 
 // GetAnimal returns a specific animal by ID
@@ -96,100 +185,77 @@ func (a *Animal) GetAnimals(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param animalID path string true "Animal ID"
+// @Param fields query string false "Comma-separated fields to return (id, name, species, age, description, version, created_at, updated_at)"
+// @Param If-None-Match header string false "ETag from a previous response; ignored when fields is set"
 // @Success 200 {object} response.APIResponse{data=model.Animal}
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.ValidationErrorResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /animals/{animalID} [get]
 func (a *Animal) GetAnimal(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	animalID := chi.URLParam(r, "animalID")
-
-	result, err := a.service.GetByID(ctx, animalID)
-	if err != nil {
-		if err == service.ErrAnimalNotFound {
-			response.NotFound(w, r, "Animal not found")
-			return
-		}
-		a.logger.Error("Failed to get animal", zap.String("id", animalID), zap.Error(err))
-		response.InternalServerError(w, r, err)
-		return
-	}
-
-	response.Success(w, r, result, "Animal retrieved successfully")
+	a.Get(w, r)
 }
 
 // CreateAnimal creates a new animal
 // @Summary Create a new animal
-// @Description Create a new animal with the provided details
+// @Description Create a new animal with the provided details. Send an Idempotency-Key to retry safely:
+// @Description a repeated request with the same key and body replays the first response instead of creating
+// @Description another animal, and reusing the key with a different body fails with 409
 // @Tags animals
 // @Accept json
 // @Produce json
 // @Param animal body model.AnimalCreateRequest true "Animal object to be created"
+// @Param Idempotency-Key header string false "Unique key for this create, up to 255 characters; responses are kept for IDEMPOTENCY_TTL when Redis is enabled"
 // @Success 201 {object} response.APIResponse{data=model.Animal}
-// @Failure 400 {object} response.APIResponse
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 422 {object} response.ValidationErrorResponse
+// @Failure 409 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
 // @Router /animals [post]
 func (a *Animal) CreateAnimal(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var animal model.Animal
-
-	// Validate and decode the request
-	if !middleware.HandleValidateRequest(w, r, &animal) {
-		return
-	}
-
-	if err := a.service.Create(ctx, &animal); err != nil {
-		if err == service.ErrInvalidAnimalData {
-			response.BadRequest(w, r, "Invalid animal data", err)
-			return
-		}
-		a.logger.Error("Failed to create animal", zap.Error(err))
-		response.InternalServerError(w, r, err)
-		return
-	}
-
-	response.Created(w, r, animal, "Animal created successfully")
+	a.Create(w, r)
 }
 
 // UpdateAnimal updates an existing animal
 // @Summary Update an animal
-// @Description Update an existing animal by its ID
+// @Description Update an existing animal by its ID. Send the version from a previous read to
+// @Description reject the update with 409 if the animal has changed since; omit it to overwrite unconditionally
 // @Tags animals
 // @Accept json
 // @Produce json
 // @Param animalID path string true "Animal ID"
 // @Param animal body model.AnimalUpdateRequest true "Updated animal object"
 // @Success 200 {object} response.APIResponse{data=model.Animal}
-// @Failure 400 {object} response.APIResponse
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 422 {object} response.ValidationErrorResponse
 // @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
 // @Router /animals/{animalID} [put]
 func (a *Animal) UpdateAnimal(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	animalID := chi.URLParam(r, "animalID")
-
-	var animal model.Animal
-
-	// Validate and decode the request
-	if !middleware.HandleValidateRequest(w, r, &animal) {
-		return
-	}
-
-	if err := a.service.Update(ctx, animalID, &animal); err != nil {
-		switch err {
-		case service.ErrAnimalNotFound:
-			response.NotFound(w, r, "Animal not found")
-		case service.ErrInvalidAnimalData:
-			response.BadRequest(w, r, "Invalid animal data", err)
-		default:
-			a.logger.Error("Failed to update animal", zap.String("id", animalID), zap.Error(err))
-			response.InternalServerError(w, r, err)
-		}
-		return
-	}
+	a.Update(w, r)
+}
 
-	response.Success(w, r, animal, "Animal updated successfully")
+// PatchAnimal partially updates an existing animal
+// @Summary Partially update an animal
+// @Description Update only the provided fields of an existing animal by its ID
+// @Tags animals
+// @Accept json
+// @Produce json
+// @Param animalID path string true "Animal ID"
+// @Param animal body model.AnimalPatchRequest true "Fields to update"
+// @Success 200 {object} response.APIResponse{data=model.Animal}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 422 {object} response.ValidationErrorResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /animals/{animalID} [patch]
+func (a *Animal) PatchAnimal(w http.ResponseWriter, r *http.Request) {
+	a.Patch(w, r)
 }
 
 // DeleteAnimal deletes an animal
@@ -203,23 +269,8 @@ func (a *Animal) UpdateAnimal(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
 // @Router /animals/{animalID} [delete]
 func (a *Animal) DeleteAnimal(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	animalID := chi.URLParam(r, "animalID")
-
-	if err := a.service.Delete(ctx, animalID); err != nil {
-		switch err {
-		case service.ErrAnimalNotFound:
-			response.NotFound(w, r, "Animal not found")
-		case service.ErrInvalidAnimalData:
-			response.BadRequest(w, r, "Invalid animal ID", err)
-		default:
-			a.logger.Error("Failed to delete animal", zap.String("id", animalID), zap.Error(err))
-			response.InternalServerError(w, r, err)
-		}
-		return
-	}
-
-	response.NoContent(w, r)
+	a.Delete(w, r)
 }
@@ -1,70 +1,40 @@
 package controller
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/linkeunid/go-api/internal/model"
 	"github.com/linkeunid/go-api/internal/repository"
 	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/internal/service/servicetest"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/jobs"
+	"github.com/linkeunid/go-api/pkg/middleware"
 	"github.com/linkeunid/go-api/pkg/pagination"
 	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/linkeunid/go-api/pkg/validator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"go.uber.org/zap"
+	"github.com/stretchr/testify/require"
 )
 
-// Add these at the top of the file, below the imports
-type contextKey string
-
-const validatedModelKey contextKey = "validated_model"
-
-// MockAnimalService is a mock implementation of the service.AnimalService interface
-type MockAnimalService struct {
-	mock.Mock
-}
-
-func (m *MockAnimalService) GetAll(ctx context.Context) (service.AnimalCollectionResponse, error) {
-	args := m.Called(ctx)
-	return args.Get(0).(service.AnimalCollectionResponse), args.Error(1)
-}
-
-func (m *MockAnimalService) GetAllPaginated(ctx context.Context, params pagination.Params) (service.AnimalCollectionResponse, error) {
-	args := m.Called(ctx, params)
-	return args.Get(0).(service.AnimalCollectionResponse), args.Error(1)
-}
-
-func (m *MockAnimalService) GetByID(ctx context.Context, id string) (service.AnimalResponse, error) {
-	args := m.Called(ctx, id)
-	return args.Get(0).(service.AnimalResponse), args.Error(1)
-}
-
-func (m *MockAnimalService) Create(ctx context.Context, animal *model.Animal) error {
-	args := m.Called(ctx, animal)
-	return args.Error(0)
-}
-
-func (m *MockAnimalService) Update(ctx context.Context, id string, animal *model.Animal) error {
-	args := m.Called(ctx, id, animal)
-	return args.Error(0)
-}
-
-func (m *MockAnimalService) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
-	return args.Error(0)
-}
+// MockAnimalService is the shared mock of service.AnimalService
+type MockAnimalService = servicetest.MockAnimalService
 
 func TestAnimal_GetAnimals(t *testing.T) {
-	// Create a test logger
-	logger, _ := zap.NewDevelopment()
-
 	tests := []struct {
 		name           string
 		serviceReturn  service.AnimalCollectionResponse
@@ -110,10 +80,10 @@ func TestAnimal_GetAnimals(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a mock service
 			mockService := new(MockAnimalService)
-			mockService.On("GetAllPaginated", mock.Anything, mock.Anything).Return(tt.serviceReturn, tt.serviceError)
+			mockService.On("GetAllPaginated", mock.Anything, mock.Anything, mock.Anything).Return(tt.serviceReturn, tt.serviceError)
 
 			// Create controller with mock service
-			controller := NewAnimal(logger, mockService)
+			controller := NewAnimal(mockService, nil)
 
 			// Create test request
 			req, err := http.NewRequest("GET", "/animals", nil)
@@ -135,10 +105,164 @@ func TestAnimal_GetAnimals(t *testing.T) {
 	}
 }
 
-func TestAnimal_GetAnimal(t *testing.T) {
-	// Create a test logger
-	logger, _ := zap.NewDevelopment()
+func TestAnimal_GetAnimals_Filters(t *testing.T) {
+	// Pagination and sort parameters must not be treated as filters
+	expectedFilters := repository.Filters{"species": "Cat", "age_gte": "2", "name_like": "Flu"}
+
+	mockService := new(MockAnimalService)
+	mockService.On("GetAllPaginated", mock.Anything, mock.Anything, expectedFilters).Return(service.AnimalCollectionResponse{
+		Data:       []model.Animal{},
+		Pagination: &pagination.Params{Page: 2, Limit: 5},
+	}, nil)
+
+	controller := NewAnimal(mockService, nil)
+
+	req, err := http.NewRequest("GET", "/animals?species=Cat&age_gte=2&name_like=Flu&page=2&limit=5&sort=age&direction=desc", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(controller.GetAnimals).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	// Navigation links keep the filters and only change the page
+	var resp struct {
+		Data pagination.PagedData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data.Links)
+	assert.Equal(t, "/animals?age_gte=2&direction=desc&limit=5&name_like=Flu&page=1&sort=age&species=Cat", resp.Data.Links.First)
+
+	// The links are repeated in the Link header for header-based clients
+	assert.Contains(t, rr.Header().Get("Link"), `</animals?age_gte=2&direction=desc&limit=5&name_like=Flu&page=1&sort=age&species=Cat>; rel="first"`)
+	assert.Equal(t, "0", rr.Header().Get(response.TotalCountHeader))
+}
+
+func TestAnimal_GetAnimals_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedField string
+		expectedError string
+	}{
+		{name: "PageNotAnInteger", query: "page=abc", expectedField: "page", expectedError: "page must be an integer"},
+		{name: "PageZero", query: "page=0", expectedField: "page", expectedError: "page must be 1 or greater"},
+		{name: "LimitZero", query: "limit=0", expectedField: "limit", expectedError: "limit must be 1 or greater"},
+		{name: "LimitAboveMaximum", query: "limit=500", expectedField: "limit", expectedError: "limit must be 100 or less"},
+		{name: "UnknownSort", query: "sort=owner", expectedField: "sort", expectedError: "sort must be one of id, name, species, age, created_at, updated_at"},
+		{name: "UnknownDirection", query: "direction=down", expectedField: "direction", expectedError: "direction must be one of [asc desc]"},
+		{name: "FilterNotAnInteger", query: "age_gte=abc", expectedField: "age_gte", expectedError: `invalid age_gte "abc": expected an integer`},
+		{name: "IDNotAnInteger", query: "id=first", expectedField: "id", expectedError: `invalid id "first": expected an integer`},
+		{name: "InvalidTimeRange", query: "created_after=garbage", expectedField: "created_after", expectedError: `invalid created_after "garbage": expected an RFC3339 timestamp or a YYYY-MM-DD date`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The service is never called for rejected queries
+			mockService := new(MockAnimalService)
+			r := chi.NewRouter()
+			NewAnimal(mockService, nil).RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals?"+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			var resp struct {
+				Data []validator.ValidationError `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Data, 1)
+			assert.Equal(t, tt.expectedField, resp.Data[0].Field)
+			assert.Equal(t, tt.expectedError, resp.Data[0].Error)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		mockService := new(MockAnimalService)
+		mockService.On("GetAllPaginated", mock.Anything, pagination.Params{Page: 2, Limit: 100}, repository.Filters{"species": "Cat"}).Return(service.AnimalCollectionResponse{
+			Data:       []model.Animal{},
+			Pagination: &pagination.Params{Page: 2, Limit: 100},
+		}, nil)
+		r := chi.NewRouter()
+		NewAnimal(mockService, nil).RegisterRoutes(r)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals?page=2&limit=100&sort=age&direction=desc&species=Cat", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAnimal_GetAnimals_TimeRange(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		expectedFilters repository.Filters
+		expectedStatus  int
+		expectedField   string
+	}{
+		{
+			name:            "UTCByDefault",
+			query:           "created_after=2024-01-01&created_before=2024-02-01T00:00:00Z",
+			expectedFilters: repository.Filters{"created_at_gte": "2024-01-01T00:00:00Z", "created_at_lte": "2024-02-01T00:00:00Z"},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "TimeZone",
+			query:           "updated_after=2024-01-01T09:00:00&tz=Asia/Jakarta",
+			expectedFilters: repository.Filters{"updated_at_gte": "2024-01-01T02:00:00Z"},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "OffsetOverridesTimeZone",
+			query:           "created_at_lte=2024-01-01T00:00:00%2B02:00&tz=Asia/Jakarta",
+			expectedFilters: repository.Filters{"created_at_lte": "2023-12-31T22:00:00Z"},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:           "InvalidTimestamp",
+			query:          "created_after=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "created_after",
+		},
+		{
+			name:           "InvalidTimeZone",
+			query:          "created_after=2024-01-01&tz=Mars/Olympus",
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "tz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAnimalService)
+			if tt.expectedFilters != nil {
+				mockService.On("GetAllPaginated", mock.Anything, mock.Anything, tt.expectedFilters).Return(service.AnimalCollectionResponse{
+					Data:       []model.Animal{},
+					Pagination: &pagination.Params{Page: 1, Limit: 10},
+				}, nil)
+			}
+
+			controller := NewAnimal(mockService, nil)
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(controller.GetAnimals).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals?"+tt.query, nil))
 
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedField != "" {
+				var resp response.ValidationErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 1)
+				assert.Equal(t, tt.expectedField, resp.Data[0].Field)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnimal_GetAnimal(t *testing.T) {
 	tests := []struct {
 		name           string
 		animalID       string
@@ -188,7 +312,7 @@ func TestAnimal_GetAnimal(t *testing.T) {
 			mockService.On("GetByID", mock.Anything, tt.animalID).Return(tt.serviceReturn, tt.serviceError)
 
 			// Create controller with mock service
-			controller := NewAnimal(logger, mockService)
+			controller := NewAnimal(mockService, nil)
 
 			// Create chi router for the URL parameter
 			r := chi.NewRouter()
@@ -213,10 +337,250 @@ func TestAnimal_GetAnimal(t *testing.T) {
 	}
 }
 
-func TestAnimal_CreateAnimal(t *testing.T) {
-	// Create a test logger
-	logger, _ := zap.NewDevelopment()
+func TestAnimal_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "NotFound", serviceError: service.ErrAnimalNotFound, expectedStatus: http.StatusNotFound, expectedCode: "ANIMAL_NOT_FOUND"},
+		{name: "InvalidID", serviceError: service.ErrInvalidAnimalID, expectedStatus: http.StatusBadRequest, expectedCode: "INVALID_ANIMAL_ID"},
+		{name: "InvalidData", serviceError: service.ErrInvalidAnimalData, expectedStatus: http.StatusBadRequest, expectedCode: "INVALID_ANIMAL_DATA"},
+		{name: "AlreadyExists", serviceError: service.ErrAnimalAlreadyExists, expectedStatus: http.StatusConflict, expectedCode: "ANIMAL_ALREADY_EXISTS"},
+		{name: "VersionConflict", serviceError: service.ErrAnimalVersionConflict, expectedStatus: http.StatusConflict, expectedCode: "ANIMAL_VERSION_CONFLICT"},
+		{name: "DuplicateKey", serviceError: fmt.Errorf("%w: Duplicate entry 'Fluffy'", database.ErrDuplicateKey), expectedStatus: http.StatusConflict, expectedCode: "ANIMAL_ALREADY_EXISTS"},
+		{name: "Timeout", serviceError: context.DeadlineExceeded, expectedStatus: http.StatusGatewayTimeout, expectedCode: response.CodeTimeout},
+		{name: "Internal", serviceError: errors.New("database error"), expectedStatus: http.StatusInternalServerError, expectedCode: response.CodeInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAnimalService)
+			mockService.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{}, tt.serviceError)
+
+			r := chi.NewRouter()
+			r.Get("/{animalID}", NewAnimal(mockService, nil).GetAnimal)
+
+			req := httptest.NewRequest(http.MethodGet, "/1", nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			var resp response.APIResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedCode, resp.ErrorCode)
+		})
+	}
+}
+
+func TestAnimal_GetAnimal_ConditionalGet(t *testing.T) {
+	animal := &model.Animal{
+		ID:        1,
+		Name:      "Fluffy",
+		Species:   "Cat",
+		Age:       3,
+		UpdatedAt: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	etag := response.GenerateETag(animal.ID, animal.UpdatedAt)
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{name: "NoHeader", ifNoneMatch: "", expectedStatus: http.StatusOK},
+		{name: "Matching", ifNoneMatch: etag, expectedStatus: http.StatusNotModified},
+		{name: "WeakMatching", ifNoneMatch: "W/" + etag, expectedStatus: http.StatusNotModified},
+		{name: "Stale", ifNoneMatch: `"stale"`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a mock service
+			mockService := new(MockAnimalService)
+			mockService.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{Data: animal}, nil)
+
+			// Create controller with mock service
+			controller := NewAnimal(mockService, nil)
+
+			// Create chi router for the URL parameter
+			r := chi.NewRouter()
+			r.Get("/{animalID}", controller.GetAnimal)
+
+			// Create test request
+			req, err := http.NewRequest("GET", "/1", nil)
+			assert.NoError(t, err)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			// Create recorder to capture response
+			rr := httptest.NewRecorder()
+
+			// Call the handler with the chi router
+			r.ServeHTTP(rr, req)
+
+			// Assert status code and ETag header
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, etag, rr.Header().Get("ETag"))
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, rr.Body.Bytes())
+			}
+
+			// Verify that service was called
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnimal_GetAnimal_ConditionalGetPerMediaType(t *testing.T) {
+	animal := &model.Animal{ID: 1, Name: "Fluffy", UpdatedAt: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}
+	jsonETag := response.GenerateETag(animal.ID, animal.UpdatedAt)
+
+	mockService := new(MockAnimalService)
+	mockService.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{Data: animal}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/{animalID}", NewAnimal(mockService, nil).GetAnimal)
+
+	// A JSON:API client revalidating with the JSON ETag must get the JSON:API document
+	req := httptest.NewRequest("GET", "/1", nil)
+	req.Header.Set("Accept", response.JSONAPIMediaType)
+	req.Header.Set("If-None-Match", jsonETag)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, response.JSONAPIMediaType, rr.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+	jsonAPIETag := rr.Header().Get("ETag")
+	assert.NotEmpty(t, jsonAPIETag)
+	assert.NotEqual(t, jsonETag, jsonAPIETag)
+
+	req = httptest.NewRequest("GET", "/1", nil)
+	req.Header.Set("Accept", response.JSONAPIMediaType)
+	req.Header.Set("If-None-Match", jsonAPIETag)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+}
+
+func TestAnimal_GetAnimal_SparseFields(t *testing.T) {
+	animal := &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat", UpdatedAt: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	mockService := new(MockAnimalService)
+	mockService.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return reflect.DeepEqual(ctx.Value(repository.KeyFields), repository.Fields{"name", "species"})
+	}), "1").Return(service.AnimalResponse{Data: animal}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/{animalID}", NewAnimal(mockService, nil).GetAnimal)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/1?fields=name,species", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("ETag"), "trimmed records must not share the full record's ETag")
+
+	// Fields that were not selected are left out rather than sent as zero values
+	var resp struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]interface{}{"name": "Fluffy", "species": "Cat"}, resp.Data.Data)
+	mockService.AssertExpectations(t)
+}
+
+func TestAnimal_GetAnimals_SparseFields(t *testing.T) {
+	mockService := new(MockAnimalService)
+	mockService.On("GetAllPaginated", mock.Anything, mock.Anything, repository.Filters{}).Return(service.AnimalCollectionResponse{
+		Data:       []model.Animal{{ID: 1, Name: "Fluffy", Species: "Cat", Age: 3}},
+		Pagination: &pagination.Params{Page: 1, Limit: 10},
+	}, nil)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewAnimal(mockService, nil).GetAnimals).ServeHTTP(rr, httptest.NewRequest("GET", "/animals?fields=id,age", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data struct {
+			Items []map[string]interface{} `json:"items"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []map[string]interface{}{{"id": float64(1), "age": float64(3)}}, resp.Data.Items)
+	mockService.AssertExpectations(t)
+}
+
+func TestAnimal_CountAnimals(t *testing.T) {
+	mockService := new(MockAnimalService)
+	mockService.On("Count", mock.Anything, repository.Filters{"species": "Cat"}).Return(int64(7), nil)
+
+	r := chi.NewRouter()
+	NewAnimal(mockService, nil).RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/animals/count?species=Cat&page=2&sort=name&include=owner", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data CountResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, int64(7), resp.Data.Count)
+
+	// Count checks filters like the list route, before the service is called
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/animals/count?age=old", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAnimal_GetAnimals_UnknownInclude(t *testing.T) {
+	mockService := new(MockAnimalService)
+	includesOwner := mock.MatchedBy(func(ctx context.Context) bool {
+		preloads, _ := ctx.Value(repository.KeyPreloads).(repository.Preloads)
+		return assert.ObjectsAreEqual(repository.Preloads{"owner"}, preloads)
+	})
+	mockService.On("GetAllPaginated", includesOwner, mock.Anything, repository.Filters{}).
+		Return(service.AnimalCollectionResponse{}, &repository.InvalidPreloadsError{Includes: []string{"owner"}})
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(NewAnimal(mockService, nil).GetAnimals).ServeHTTP(rr, httptest.NewRequest("GET", "/animals?include=owner", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp response.ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "include", resp.Data[0].Field)
+	assert.Equal(t, "owner", resp.Data[0].Value)
+	mockService.AssertExpectations(t)
+}
 
+func TestAnimal_GetAnimal_UnknownFields(t *testing.T) {
+	mockService := new(MockAnimalService)
+	mockService.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{}, &repository.InvalidFieldsError{Fields: []string{"owner"}})
+
+	r := chi.NewRouter()
+	r.Get("/{animalID}", NewAnimal(mockService, nil).GetAnimal)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/1?fields=name,owner", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp response.ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "fields", resp.Data[0].Field)
+	assert.Equal(t, "owner", resp.Data[0].Value)
+}
+
+func TestAnimal_CreateAnimal(t *testing.T) {
 	// Define test cases
 	tests := []struct {
 		name           string
@@ -244,7 +608,7 @@ func TestAnimal_CreateAnimal(t *testing.T) {
 				"description": "Invalid data",
 			},
 			serviceError:   service.ErrInvalidAnimalData,
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusUnprocessableEntity,
 		},
 		{
 			name: "InternalServerError",
@@ -273,7 +637,7 @@ func TestAnimal_CreateAnimal(t *testing.T) {
 			}
 
 			// Create controller with mock service
-			controller := NewAnimal(logger, mockService)
+			controller := NewAnimal(mockService, nil)
 
 			// Create request body
 			jsonBody, _ := json.Marshal(tt.requestBody)
@@ -286,10 +650,10 @@ func TestAnimal_CreateAnimal(t *testing.T) {
 			// Create recorder to capture response
 			rr := httptest.NewRecorder()
 
-			// For the InvalidData test case, we'll simulate the middleware rejecting the request
+			// For the InvalidData test case, no validated model is in the context so the
+			// controller validates the body itself and rejects it
 			if tt.name == "InvalidData" {
-				// Direct call to ValidationError
-				response.ValidationError(rr, req, []string{"Validation failed"})
+				controller.CreateAnimal(rr, req)
 			} else {
 				// Setup a middleware to set the validated animal in the request context
 				// This is typically done by the validation middleware in a real application
@@ -302,7 +666,7 @@ func TestAnimal_CreateAnimal(t *testing.T) {
 					}
 
 					// Store the validated animal in the context
-					ctx := context.WithValue(r.Context(), validatedModelKey, &animal)
+					ctx := context.WithValue(r.Context(), middleware.KeyValidatedModel, &animal)
 					controller.CreateAnimal(w, r.WithContext(ctx))
 				})
 
@@ -319,10 +683,61 @@ func TestAnimal_CreateAnimal(t *testing.T) {
 	}
 }
 
-func TestAnimal_UpdateAnimal(t *testing.T) {
-	// Create a test logger
-	logger, _ := zap.NewDevelopment()
+func TestAnimal_CreateAnimal_BodyTooLarge(t *testing.T) {
+	// The service must not be called when the body exceeds the limit
+	mockService := new(MockAnimalService)
+	controller := NewAnimal(mockService, nil)
+
+	// Wrap the handler with a tiny body limit
+	handler := middleware.MaxBodyBytes(16)(http.HandlerFunc(controller.CreateAnimal))
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{
+		"name":        "Fluffy",
+		"species":     "Cat",
+		"description": "A body that is clearly larger than sixteen bytes",
+	})
 
+	req, err := http.NewRequest("POST", "/animals", bytes.NewBuffer(jsonBody))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAnimal_CreateAnimal_CreateMiddleware(t *testing.T) {
+	// Create middleware runs on the create route only, before the request is validated
+	var calls []string
+	createMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method)
+			w.Header().Set("X-Create-Middleware", "applied")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	mockService := new(MockAnimalService)
+	r := chi.NewRouter()
+	NewAnimal(mockService, nil, createMiddleware).RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/animals/1", strings.NewReader(`{"name":1}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, calls)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/animals", strings.NewReader(`{"name":1}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "applied", rr.Header().Get("X-Create-Middleware"))
+	assert.Equal(t, []string{http.MethodPost}, calls)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAnimal_UpdateAnimal(t *testing.T) {
 	// Define test cases
 	tests := []struct {
 		name           string
@@ -365,7 +780,7 @@ func TestAnimal_UpdateAnimal(t *testing.T) {
 				"description": "Invalid data",
 			},
 			serviceError:   service.ErrInvalidAnimalData,
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusUnprocessableEntity,
 		},
 		{
 			name:     "InternalServerError",
@@ -395,7 +810,7 @@ func TestAnimal_UpdateAnimal(t *testing.T) {
 			}
 
 			// Create controller with mock service
-			controller := NewAnimal(logger, mockService)
+			controller := NewAnimal(mockService, nil)
 
 			// Create chi router for the URL parameter
 			r := chi.NewRouter()
@@ -411,10 +826,10 @@ func TestAnimal_UpdateAnimal(t *testing.T) {
 			// Create recorder to capture response
 			rr := httptest.NewRecorder()
 
-			// For the InvalidData test case, we'll simulate the middleware rejecting the request
+			// For the InvalidData test case, no validated model is in the context so the
+			// controller validates the body itself and rejects it
 			if tt.name == "InvalidData" {
-				// Direct call to ValidationError
-				response.ValidationError(rr, req, []string{"Validation failed"})
+				controller.UpdateAnimal(rr, req)
 			} else {
 				// Setup the test handler with validation context
 				r.Put("/{animalID}", func(w http.ResponseWriter, r *http.Request) {
@@ -426,7 +841,7 @@ func TestAnimal_UpdateAnimal(t *testing.T) {
 					}
 
 					// Store the validated animal in the context
-					ctx := context.WithValue(r.Context(), validatedModelKey, &animal)
+					ctx := context.WithValue(r.Context(), middleware.KeyValidatedModel, &animal)
 					controller.UpdateAnimal(w, r.WithContext(ctx))
 				})
 
@@ -443,10 +858,118 @@ func TestAnimal_UpdateAnimal(t *testing.T) {
 	}
 }
 
-func TestAnimal_DeleteAnimal(t *testing.T) {
-	// Create a test logger
-	logger, _ := zap.NewDevelopment()
+func TestAnimal_PatchAnimal(t *testing.T) {
+	// Define test cases
+	tests := []struct {
+		name           string
+		animalID       string
+		requestBody    string
+		serviceError   error
+		expectPatch    bool
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			animalID:       "1",
+			requestBody:    `{"age":4}`,
+			expectPatch:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "NotFound",
+			animalID:       "999",
+			requestBody:    `{"age":4}`,
+			serviceError:   service.ErrAnimalNotFound,
+			expectPatch:    true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "ValidationError",
+			animalID:    "1",
+			requestBody: `{"color":"Black"}`,
+			serviceError: service.ValidationErrors{
+				{Field: "color", Tag: "unknown", Error: "color is not an updatable field"},
+			},
+			expectPatch:    true,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "InvalidJSON",
+			animalID:       "1",
+			requestBody:    `{"age":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "WrongType",
+			animalID:       "1",
+			requestBody:    `{"age":"old"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "NotAnObject",
+			animalID:       "1",
+			requestBody:    `[{"age":4}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a mock service
+			mockService := new(MockAnimalService)
+			if tt.expectPatch {
+				mockService.On("Patch", mock.Anything, tt.animalID, mock.AnythingOfType("map[string]interface {}")).Return(tt.serviceError)
+			}
+			if tt.expectPatch && tt.serviceError == nil {
+				mockService.On("GetByID", mock.Anything, tt.animalID).Return(service.AnimalResponse{
+					Data: &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat", Age: 4},
+				}, nil)
+			}
+
+			// Create controller with mock service
+			controller := NewAnimal(mockService, nil)
+
+			// Create chi router for the URL parameter
+			r := chi.NewRouter()
+			r.Patch("/{animalID}", controller.PatchAnimal)
+
+			// Create test request
+			req, err := http.NewRequest("PATCH", "/"+tt.animalID, bytes.NewBufferString(tt.requestBody))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			// Create recorder to capture response
+			rr := httptest.NewRecorder()
+
+			// Call the handler with the chi router
+			r.ServeHTTP(rr, req)
+
+			// Assert status code
+			assert.Equal(t, tt.expectedStatus, rr.Code)
 
+			// Verify that service was called
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnimal_PatchAnimal_BodyTooLarge(t *testing.T) {
+	// The service must not be called when the body exceeds the limit
+	mockService := new(MockAnimalService)
+	r := chi.NewRouter()
+	r.With(middleware.MaxBodyBytes(16)).Patch("/{animalID}", NewAnimal(mockService, nil).PatchAnimal)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("PATCH", "/1", bytes.NewBufferString(`{"description":"A body that is clearly larger than sixteen bytes"}`)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, response.CodePayloadTooLarge, resp.ErrorCode)
+	mockService.AssertExpectations(t)
+}
+
+func TestAnimal_DeleteAnimal(t *testing.T) {
 	// Define test cases
 	tests := []struct {
 		name           string
@@ -489,7 +1012,7 @@ func TestAnimal_DeleteAnimal(t *testing.T) {
 			mockService.On("Delete", mock.Anything, tt.animalID).Return(tt.serviceError)
 
 			// Create controller with mock service
-			controller := NewAnimal(logger, mockService)
+			controller := NewAnimal(mockService, nil)
 
 			// Create chi router for the URL parameter
 			r := chi.NewRouter()
@@ -514,15 +1037,409 @@ func TestAnimal_DeleteAnimal(t *testing.T) {
 	}
 }
 
-func TestAnimal_RegisterRoutes(t *testing.T) {
-	// Create a test logger
-	logger, _ := zap.NewDevelopment()
+func TestAnimalController_ExportAnimals(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	batches := [][]model.Animal{
+		{{ID: 1, Name: "Fluffy", Species: "Cat", Age: 3, Description: "Likes naps, mostly", Version: 1, CreatedAt: created, UpdatedAt: created}},
+		{{ID: 2, Name: "Rex", Species: "Dog", Age: 5, Version: 2, CreatedAt: created, UpdatedAt: created}},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		sort           string
+		direction      string
+		filters        repository.Filters
+		batches        [][]model.Animal
+		serviceError   error
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "CSV",
+			query:          "?format=csv&sort=name&direction=desc&species=Cat",
+			sort:           "name",
+			direction:      "desc",
+			filters:        repository.Filters{"species": "Cat"},
+			batches:        batches,
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody: "id,name,species,age,description,version,created_at,updated_at\n" +
+				"1,Fluffy,Cat,3,\"Likes naps, mostly\",1,2025-01-02T03:04:05Z,2025-01-02T03:04:05Z\n" +
+				"2,Rex,Dog,5,,2,2025-01-02T03:04:05Z,2025-01-02T03:04:05Z\n",
+		},
+		{
+			name:           "EmptyJSON",
+			query:          "",
+			filters:        repository.Filters{},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			expectedBody:   "[]\n",
+		},
+		{
+			name:           "InvalidFormat",
+			query:          "?format=xlsx",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ServiceError",
+			query:          "?format=csv",
+			filters:        repository.Filters{},
+			serviceError:   errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedType:   "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAnimalService)
+			if tt.filters != nil {
+				mockService.On("Export", mock.Anything, tt.sort, tt.direction, tt.filters).Return(tt.batches, tt.serviceError)
+			}
+
+			controller := NewAnimal(mockService, nil)
+			r := chi.NewRouter()
+			controller.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/animals/export"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedType != "" {
+				assert.Equal(t, tt.expectedType, rr.Header().Get("Content-Type"))
+			}
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, rr.Header().Get("Content-Disposition"), "attachment; filename=\"animals-")
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			} else {
+				assert.Empty(t, rr.Header().Get("Content-Disposition"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	// A JSON export must decode back to the exported animals
+	mockService := new(MockAnimalService)
+	mockService.On("Export", mock.Anything, "", "", repository.Filters{}).Return(batches, nil)
+	r := chi.NewRouter()
+	NewAnimal(mockService, nil).RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals/export?format=json", nil))
+
+	var exported []model.Animal
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &exported))
+	assert.Equal(t, []model.Animal{batches[0][0], batches[1][0]}, exported)
+}
+
+func TestAnimal_GetAllAnimals(t *testing.T) {
+	animals := []model.Animal{{ID: 1, Name: "Fluffy", Species: "Cat"}, {ID: 2, Name: "Rex", Species: "Dog"}}
+
+	tests := []struct {
+		name           string
+		response       service.AnimalCollectionResponse
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			response:       service.AnimalCollectionResponse{Data: animals},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "TooManyAnimals",
+			serviceError:   service.ErrTooManyAnimals,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "ServiceError",
+			serviceError:   errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAnimalService)
+			mockService.On("GetAll", mock.Anything).Return(tt.response, tt.serviceError)
+
+			// The admin route is registered first, as in the server, and must win over /animals/{animalID}
+			controller := NewAnimal(mockService, nil)
+			r := chi.NewRouter()
+			controller.RegisterAdminRoutes(r)
+			controller.RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals/all", nil))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				// The same envelope and headers as the paginated list, as a single page
+				var resp struct {
+					Data struct {
+						Items      []model.Animal    `json:"items"`
+						Pagination pagination.Params `json:"pagination"`
+					} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, animals, resp.Data.Items)
+				assert.Equal(t, int64(2), resp.Data.Pagination.TotalItems)
+				assert.Equal(t, 1, resp.Data.Pagination.TotalPages)
+				assert.Equal(t, "2", rr.Header().Get(response.TotalCountHeader))
+				assert.Contains(t, rr.Header().Get("Link"), `rel="first"`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	// Without the admin routes, "all" is treated as an animal ID
+	mockService := new(MockAnimalService)
+	mockService.On("GetByID", mock.Anything, "all").Return(service.AnimalResponse{}, service.ErrInvalidAnimalID)
+	r := chi.NewRouter()
+	NewAnimal(mockService, nil).RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals/all", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+}
+
+func TestAnimalController_ImportAnimals(t *testing.T) {
+	upload := func(t *testing.T, content string) *http.Request {
+		return csvUpload(t, "/animals/import", content)
+	}
+
+	t.Run("InsertsValidRowsAndReportsFailures", func(t *testing.T) {
+		mockService := new(MockAnimalService)
+		mockService.On("Import", mock.Anything, []model.Animal{
+			{ID: 7, Name: "Fluffy", Species: "Cat", Age: 3},
+			{Name: "Rex", Species: "Dog", Description: "Good boy"},
+		}).Return(nil)
+
+		r := chi.NewRouter()
+		NewAnimal(mockService, nil).RegisterRoutes(r)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, upload(t, "species,name,age,id,description\n"+
+			"Cat,Fluffy,3,7,\n"+
+			"Dog,Rex,,,Good boy\n"+
+			"Cat,Old Tom,ancient,,\n"+
+			"Cat,X,2,,\n"))
 
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var resp struct {
+			Data ImportResult `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.Data.Inserted)
+		assert.Equal(t, 2, resp.Data.Failed)
+		require.Len(t, resp.Data.Errors, 2)
+		assert.Equal(t, 4, resp.Data.Errors[0].Line)
+		assert.Contains(t, resp.Data.Errors[0].Error, "column age")
+		assert.Equal(t, 5, resp.Data.Errors[1].Line)
+		require.NotEmpty(t, resp.Data.Errors[1].Fields)
+		assert.Equal(t, "name", resp.Data.Errors[1].Fields[0].Field)
+		mockService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name           string
+		request        func(t *testing.T) *http.Request
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "UnexpectedColumn",
+			request:        func(t *testing.T) *http.Request { return upload(t, "name,species,owner\nFluffy,Cat,Ann\n") },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "MissingFile",
+			request: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/animals/import", strings.NewReader("name,species\n"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "TooLarge",
+			request:        func(t *testing.T) *http.Request { return upload(t, "name,species\n"+strings.Repeat("Fluffy,Cat\n", 200)) },
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Duplicate",
+			request:        func(t *testing.T) *http.Request { return upload(t, "name,species\nFluffy,Cat\n") },
+			serviceError:   service.ErrAnimalAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAnimalService)
+			if tt.serviceError != nil {
+				mockService.On("Import", mock.Anything, mock.Anything).Return(tt.serviceError)
+			}
+
+			controller := NewAnimal(mockService, nil)
+			controller.config.MaxImportBytes = 1024
+
+			r := chi.NewRouter()
+			controller.RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, tt.request(t))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// csvUpload builds a multipart import request carrying content as the file field
+func csvUpload(t *testing.T, target, content string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "animals.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// readServerSentEvent reads the next event from a text/event-stream body
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestAnimalController_ImportAnimalsAsync(t *testing.T) {
+	defer func(interval time.Duration) { importPollInterval = interval }(importPollInterval)
+	importPollInterval = 5 * time.Millisecond
+
+	// startImport uploads content in the background and returns the job it started
+	startImport := func(t *testing.T, r http.Handler, content string) jobs.Job {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, csvUpload(t, "/animals/import?async=true", content))
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var resp struct {
+			Data jobs.Job `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "/animals/import/"+resp.Data.ID+"/events", rr.Header().Get("Location"))
+		return resp.Data
+	}
+
+	t.Run("StreamsProgressAndResult", func(t *testing.T) {
+		store := jobs.NewMemoryStore()
+		release := make(chan struct{})
+		mockService := new(MockAnimalService)
+		mockService.On("Import", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			progress := args.Get(0).(context.Context).Value(repository.KeyProgress).(func(done int))
+			progress(1)
+			<-release
+			progress(2)
+		}).Return(nil)
+
+		r := chi.NewRouter()
+		NewAnimal(mockService, store).RegisterRoutes(r)
+		server := httptest.NewServer(r)
+		defer server.Close()
+
+		job := startImport(t, r, "name,species\nFluffy,Cat\nRex,Dog\nX,Cat\n")
+		assert.Equal(t, jobs.Job{ID: job.ID, Status: jobs.StatusRunning, Total: 2}, job)
+		require.Eventually(t, func() bool {
+			stored, err := store.Get(context.Background(), job.ID)
+			return err == nil && stored.Processed == 1
+		}, time.Second, 5*time.Millisecond)
+
+		resp, err := http.Get(server.URL + "/animals/import/" + job.ID + "/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		reader := bufio.NewReader(resp.Body)
+
+		event, data := readServerSentEvent(t, reader)
+		assert.Equal(t, "progress", event)
+		assert.JSONEq(t, `{"processed":1,"total":2}`, data)
+
+		close(release)
+		event, data = readServerSentEvent(t, reader)
+		assert.Equal(t, "progress", event)
+		assert.JSONEq(t, `{"processed":2,"total":2}`, data)
+
+		event, data = readServerSentEvent(t, reader)
+		assert.Equal(t, "done", event)
+		var result ImportResult
+		require.NoError(t, json.Unmarshal([]byte(data), &result))
+		assert.Equal(t, 2, result.Inserted)
+		assert.Equal(t, 1, result.Failed)
+	})
+
+	t.Run("ReportsFailure", func(t *testing.T) {
+		store := jobs.NewMemoryStore()
+		mockService := new(MockAnimalService)
+		mockService.On("Import", mock.Anything, mock.Anything).Return(service.ErrAnimalAlreadyExists)
+
+		r := chi.NewRouter()
+		NewAnimal(mockService, store).RegisterRoutes(r)
+
+		job := startImport(t, r, "name,species\nFluffy,Cat\n")
+		require.Eventually(t, func() bool {
+			stored, err := store.Get(context.Background(), job.ID)
+			return err == nil && stored.Finished()
+		}, time.Second, 5*time.Millisecond)
+
+		// The stream of a finished job ends straight away
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals/import/"+job.ID+"/events", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "event: progress\ndata: {\"processed\":0,\"total\":1}\n\n"+
+			"event: error\ndata: {\"error_code\":\"ANIMAL_ALREADY_EXISTS\",\"message\":\"Animal already exists\"}\n\n", rr.Body.String())
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		r := chi.NewRouter()
+		NewAnimal(new(MockAnimalService), nil).RegisterRoutes(r)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals/import/missing/events", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestAnimal_RegisterRoutes(t *testing.T) {
 	// Create a mock service that doesn't expect any calls
 	mockService := new(MockAnimalService)
 
 	// Create controller with mock service
-	controller := NewAnimal(logger, mockService)
+	controller := NewAnimal(mockService, nil)
 
 	// Create a new Chi router
 	r := chi.NewRouter()
@@ -537,9 +1454,13 @@ func TestAnimal_RegisterRoutes(t *testing.T) {
 		path   string
 	}{
 		{http.MethodGet, "/animals"},
+		{http.MethodGet, "/animals/export"},
+		{http.MethodPost, "/animals/import"},
+		{http.MethodGet, "/animals/import/1/events"},
 		{http.MethodPost, "/animals"},
 		{http.MethodGet, "/animals/1"},
 		{http.MethodPut, "/animals/1"},
+		{http.MethodPatch, "/animals/1"},
 		{http.MethodDelete, "/animals/1"},
 	}
 
@@ -561,5 +1482,5 @@ func TestAnimal_RegisterRoutes(t *testing.T) {
 
 	// If we can't check using reflection, we'll fall back to a simple count of expected routes
 	t.Logf("Using fallback route check method")
-	assert.Equal(t, 5, len(routes), "Should have 5 routes registered")
+	assert.Equal(t, 9, len(routes), "Should have 9 routes registered")
 }
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/response"
+	"go.uber.org/zap"
+)
+
+// LoginRequest is the body of a login
+type LoginRequest struct {
+	Email    string `json:"email" example:"john@example.com"`
+	Password string `json:"password" example:"correct horse battery staple"`
+}
+
+// RefreshRequest is the body of a token refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// CurrentUser is the authenticated user, as the authentication middleware identified them
+type CurrentUser struct {
+	ID       uint64     `json:"id" example:"123"`
+	Username string     `json:"username,omitempty" example:"johndoe"`
+	Role     string     `json:"role" example:"admin"`
+	Email    string     `json:"email,omitempty" example:"john@example.com"`
+	Scopes   []string   `json:"scopes,omitempty" example:"animals:read,animals:write"`
+	Token    *TokenInfo `json:"token,omitempty"` // Absent for API key callers
+}
+
+// TokenInfo describes the JWT a request was authenticated with
+type TokenInfo struct {
+	Issuer    string     `json:"issuer" example:"linkeun-go-api"`
+	IssuedAt  *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Auth issues tokens to users and serves information about the authenticated user
+type Auth struct {
+	service         service.AuthService
+	loginMiddleware []func(http.Handler) http.Handler
+}
+
+// NewAuth creates a new Auth controller. loginMiddleware, such as a rate limiter, is applied
+// to the login route only
+func NewAuth(service service.AuthService, loginMiddleware ...func(http.Handler) http.Handler) *Auth {
+	return &Auth{
+		service:         service,
+		loginMiddleware: loginMiddleware,
+	}
+}
+
+// RegisterRoutes registers the login, refresh and logout routes
+func (a *Auth) RegisterRoutes(r chi.Router) {
+	r.Route("/auth", func(r chi.Router) {
+		r.With(a.loginMiddleware...).Post("/login", a.Login)
+		r.Post("/refresh", a.Refresh)
+		r.Post("/logout", a.Logout)
+	})
+}
+
+// RegisterUserRoutes registers the routes about the authenticated user; they must be mounted
+// behind the authentication middleware
+func (a *Auth) RegisterUserRoutes(r chi.Router) {
+	r.Get("/me", a.GetMe)
+}
+
+// Login exchanges an email and password for tokens
+// @Summary Log in
+// @Description Check an email and password against the users table and return an access token with a refresh token. Attempts are rate limited per client IP
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "Credentials"
+// @Success 200 {object} response.APIResponse{data=service.TokenPair}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Router /auth/login [post]
+func (a *Auth) Login(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, "Invalid JSON format", err)
+		return
+	}
+	if req.Email == "" || req.Password == "" {
+		response.BadRequest(w, r, "Email and password are required", nil)
+		return
+	}
+
+	pair, err := a.service.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		a.handleError(w, r, "log in", err)
+		return
+	}
+	response.Success(w, r, pair, "Logged in successfully")
+}
+
+// Refresh exchanges a refresh token for new tokens
+// @Summary Refresh tokens
+// @Description Exchange a refresh token from a login for a new access token and refresh token. A refresh token is accepted once; using it revokes every earlier refresh token of the user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} response.APIResponse{data=service.TokenPair}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /auth/refresh [post]
+func (a *Auth) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, "Invalid JSON format", err)
+		return
+	}
+	if req.RefreshToken == "" {
+		response.BadRequest(w, r, "Refresh token is required", nil)
+		return
+	}
+
+	pair, err := a.service.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		a.handleError(w, r, "refresh tokens", err)
+		return
+	}
+	response.Success(w, r, pair, "Tokens refreshed successfully")
+}
+
+// Logout revokes the refresh tokens of a user
+// @Summary Log out
+// @Description Revoke every refresh token of the user a refresh token belongs to. Access tokens stay valid until they expire
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /auth/logout [post]
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, "Invalid JSON format", err)
+		return
+	}
+	if req.RefreshToken == "" {
+		response.BadRequest(w, r, "Refresh token is required", nil)
+		return
+	}
+
+	if err := a.service.Logout(r.Context(), req.RefreshToken); err != nil {
+		a.handleError(w, r, "log out", err)
+		return
+	}
+	response.NoContent(w, r)
+}
+
+// handleError maps auth service errors to the matching response helper
+func (a *Auth) handleError(w http.ResponseWriter, r *http.Request, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		response.Unauthorized(w, r, "Invalid email or password")
+	case errors.Is(err, service.ErrInvalidRefreshToken):
+		response.Unauthorized(w, r, "Invalid or expired refresh token")
+	case errors.Is(err, context.DeadlineExceeded):
+		response.GatewayTimeout(w, r, "Failed to "+action+" in time")
+	default:
+		logging.FromContext(r.Context()).Error("Failed to "+action, zap.Error(err))
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// GetMe returns the authenticated user
+// @Summary Get the authenticated user
+// @Description Get the ID, username, role, email and scopes of the authenticated user, with the issuer and expiry of their token, so clients can restore user state
+// @Tags auth
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=CurrentUser}
+// @Failure 401 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /me [get]
+func (a *Auth) GetMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Nothing is stored when authentication is disabled
+	userID, ok := ctx.Value(middleware.KeyUserID).(uint64)
+	if !ok {
+		response.Unauthorized(w, r, "Not authenticated")
+		return
+	}
+
+	user := CurrentUser{ID: userID}
+	user.Username, _ = ctx.Value(middleware.KeyUsername).(string)
+	user.Role, _ = ctx.Value(middleware.KeyUserRole).(string)
+	user.Email, _ = ctx.Value(middleware.KeyUserEmail).(string)
+	user.Scopes, _ = ctx.Value(middleware.KeyUserScopes).([]string)
+
+	if claims, ok := ctx.Value(middleware.KeyTokenClaims).(*auth.Claims); ok {
+		user.Token = &TokenInfo{Issuer: claims.Issuer}
+		if claims.IssuedAt != nil {
+			user.Token.IssuedAt = &claims.IssuedAt.Time
+		}
+		if claims.ExpiresAt != nil {
+			user.Token.ExpiresAt = &claims.ExpiresAt.Time
+		}
+	}
+
+	response.Success(w, r, user, "User retrieved successfully")
+}
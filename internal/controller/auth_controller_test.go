@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// authRouter mounts the user routes behind the JWT middleware, as the server does
+func authRouter(cfg *config.AuthConfig) *chi.Mux {
+	am := middleware.NewAuthMiddleware(auth.NewJWTService(cfg), cfg, nil, zap.NewNop())
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(am.Authenticate)
+		NewAuth(nil).RegisterUserRoutes(r)
+	})
+	return r
+}
+
+func TestAuth_GetMe(t *testing.T) {
+	cfg := &config.AuthConfig{Enabled: true, JWTSecret: "test-secret", JWTExpiration: time.Hour}
+	token, err := auth.NewJWTService(cfg).GenerateToken(7, "ann", "admin", "ann@example.com", []string{"animals:write"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	authRouter(cfg).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data CurrentUser `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, uint64(7), resp.Data.ID)
+	assert.Equal(t, "ann", resp.Data.Username)
+	assert.Equal(t, "admin", resp.Data.Role)
+	assert.Equal(t, "ann@example.com", resp.Data.Email)
+	assert.Equal(t, []string{"animals:write"}, resp.Data.Scopes)
+	require.NotNil(t, resp.Data.Token)
+	assert.Equal(t, "linkeun-go-api", resp.Data.Token.Issuer)
+	require.NotNil(t, resp.Data.Token.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *resp.Data.Token.ExpiresAt, time.Minute)
+}
+
+func TestAuth_GetMeUnauthenticated(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		header  string
+	}{
+		{name: "MissingToken", enabled: true},
+		{name: "InvalidToken", enabled: true, header: "Bearer not-a-token"},
+		{name: "AuthDisabled", enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.AuthConfig{Enabled: tt.enabled, JWTSecret: "test-secret", JWTExpiration: time.Hour}
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			authRouter(cfg).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		})
+	}
+}
+
+// MockAuthService is a mock implementation of the service.AuthService interface
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (service.TokenPair, error) {
+	args := m.Called(ctx, email, password)
+	return args.Get(0).(service.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) Refresh(ctx context.Context, refreshToken string) (service.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(service.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, refreshToken string) error {
+	args := m.Called(ctx, refreshToken)
+	return args.Error(0)
+}
+
+func TestAuth_Login(t *testing.T) {
+	pair := service.TokenPair{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", ExpiresIn: 3600}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(m *MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"email":"ann@example.com","password":"s3cret"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("Login", mock.Anything, "ann@example.com", "s3cret").Return(pair, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "InvalidCredentials",
+			body: `{"email":"ann@example.com","password":"guess"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("Login", mock.Anything, "ann@example.com", "guess").Return(service.TokenPair{}, service.ErrInvalidCredentials)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "ServiceError",
+			body: `{"email":"ann@example.com","password":"s3cret"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("Login", mock.Anything, "ann@example.com", "s3cret").Return(service.TokenPair{}, errors.New("database down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{name: "MissingPassword", body: `{"email":"ann@example.com"}`, expectedStatus: http.StatusBadRequest},
+		{name: "InvalidJSON", body: `{`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			r := chi.NewRouter()
+			NewAuth(mockService).RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp struct {
+					Data service.TokenPair `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, pair, resp.Data)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuth_LoginMiddleware(t *testing.T) {
+	limited := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+	}
+	r := chi.NewRouter()
+	NewAuth(new(MockAuthService), limited).RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	// Refreshing isn't limited
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestAuth_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(m *MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"refresh_token":"refresh"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("Logout", mock.Anything, "refresh").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "RevokedToken",
+			body: `{"refresh_token":"refresh"}`,
+			setupMock: func(m *MockAuthService) {
+				m.On("Logout", mock.Anything, "refresh").Return(service.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{name: "MissingToken", body: `{}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			r := chi.NewRouter()
+			NewAuth(mockService).RegisterRoutes(r)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/auth/logout", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,998 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/export"
+	"github.com/linkeunid/go-api/pkg/jobs"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/linkeunid/go-api/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// CRUDConfig describes how a resource is exposed by CRUDController
+type CRUDConfig[T any] struct {
+	// Prefix is the route prefix the resource is mounted under, e.g. "/animals"
+	Prefix string
+	// Tag is the resource name used in logs and response messages, e.g. "animal"
+	Tag string
+	// IDParam is the URL parameter holding the record ID, e.g. "animalID"
+	IDParam string
+	// ETag optionally derives an ETag from a record to support conditional GETs
+	ETag func(item *T) string
+	// MaxImportBytes caps the size of CSV import uploads, replacing the server-wide
+	// body limit on that route. Defaults to DefaultMaxImportBytes
+	MaxImportBytes int64
+	// CreateMiddleware is applied to the create route before request validation,
+	// e.g. middleware.Idempotency
+	CreateMiddleware []func(http.Handler) http.Handler
+	// ImportJobs keeps the state of imports run in the background. Defaults to an
+	// in-memory store, which only the instance running the import can read
+	ImportJobs jobs.Store
+	// SortFields lists the values accepted by the sort query parameter of the list and
+	// export routes. Empty accepts any value, leaving the repository to fall back to id
+	SortFields []string
+	// FilterFields lists the filterable columns and their kinds, which the list, count and
+	// export routes check filter values against. Filters on other columns are not checked
+	FilterFields map[string]repository.FilterKind
+}
+
+// DefaultMaxImportBytes is the default maximum size of a CSV import upload (10MB)
+const DefaultMaxImportBytes int64 = 10 << 20
+
+const (
+	// importJobTTL is how long the state of an import job is kept after its last update
+	importJobTTL = time.Hour
+	// importJobTimeout bounds an import job, and each stream following it
+	importJobTimeout = 30 * time.Minute
+)
+
+// importPollInterval is how often ImportEvents checks a job for changes
+var importPollInterval = 250 * time.Millisecond
+
+// ImportProgress is the data of the progress events of an import job
+type ImportProgress struct {
+	Processed int `json:"processed" example:"200"`
+	Total     int `json:"total" example:"500"`
+}
+
+// ImportFailure is the data of the error event of an import job
+type ImportFailure struct {
+	ErrorCode string `json:"error_code" example:"ANIMAL_ALREADY_EXISTS"`
+	Message   string `json:"message" example:"Animal already exists"`
+}
+
+// ImportResult summarizes a CSV import
+type ImportResult struct {
+	Inserted int              `json:"inserted" xml:"inserted" example:"98"`
+	Failed   int              `json:"failed" xml:"failed" example:"2"`
+	Errors   []ImportRowError `json:"errors" xml:"errors>error"`
+}
+
+// ImportRowError explains why a CSV row was not imported
+// Error is set for rows that could not be parsed, Fields for rows that failed validation
+type ImportRowError struct {
+	Line   int                         `json:"line" xml:"line" example:"3"`
+	Error  string                      `json:"error,omitempty" xml:"error,omitempty" example:"column age: invalid integer \"old\""`
+	Fields []validator.ValidationError `json:"fields,omitempty" xml:"fields>field,omitempty"`
+}
+
+// CountResult is the body of a count response
+type CountResult struct {
+	Count int64 `json:"count" xml:"count" example:"42"`
+}
+
+// ListQuery holds the pagination, sort and filter query parameters of the list, count and
+// export routes, bound and validated by middleware.ValidateQuery before the handler runs. Page
+// and Limit are pointers so an explicit 0 is rejected rather than taken as absent
+type ListQuery struct {
+	Page      *int   `json:"page" schema:"page" validate:"omitempty,min=1"`
+	Limit     *int   `json:"limit" schema:"limit" validate:"omitempty,min=1"`
+	Sort      string `json:"sort" schema:"sort"`
+	Direction string `json:"direction" schema:"direction" validate:"omitempty,oneof=asc desc"`
+	TZ        string `json:"tz" schema:"tz"`
+	// Filters holds every other parameter as a filter, with timestamps normalized to UTC
+	Filters repository.Filters `json:"filters"`
+
+	sortFields   []string
+	filterFields map[string]repository.FilterKind
+}
+
+// listParams are the query parameters of the list, count and export routes that aren't filters
+var listParams = map[string]bool{
+	"page": true, "limit": true, "sort": true, "direction": true, "tz": true,
+	"fields": true, "include": true, "format": true,
+}
+
+// BindQuery collects the filters, normalizing their timestamps in the tz time zone and checking
+// each value against the kind of its column
+func (q *ListQuery) BindQuery(query url.Values) []validator.ValidationError {
+	filters := make(repository.Filters)
+	for key, values := range query {
+		if listParams[key] || len(values) == 0 {
+			continue
+		}
+		filters[key] = values[0]
+	}
+
+	filters, err := filters.NormalizeTimes(q.TZ)
+	if err == nil {
+		err = filters.CheckKinds(q.filterFields)
+	}
+	if err != nil {
+		return filterValidationErrors(err)
+	}
+	q.Filters = filters
+	return nil
+}
+
+// Validate checks the tagged rules, that limit is within the configured maximum and that sort
+// names one of the sortable fields
+func (q *ListQuery) Validate() []validator.ValidationError {
+	validationErrors := validator.Validate(q)
+
+	if _, maxLimit := pagination.Limits(); q.Limit != nil && *q.Limit > maxLimit {
+		validationErrors = append(validationErrors, validator.ValidationError{
+			Field: "limit",
+			Tag:   "max",
+			Value: strconv.Itoa(*q.Limit),
+			Error: fmt.Sprintf("limit must be %d or less", maxLimit),
+		})
+	}
+	if q.Sort != "" && len(q.sortFields) > 0 && !slices.Contains(q.sortFields, q.Sort) {
+		validationErrors = append(validationErrors, validator.ValidationError{
+			Field: "sort",
+			Tag:   "oneof",
+			Value: q.Sort,
+			Error: fmt.Sprintf("sort must be one of %s", strings.Join(q.sortFields, ", ")),
+		})
+	}
+	return validationErrors
+}
+
+// Params returns the pagination parameters, with the defaults for a page or limit not given
+func (q *ListQuery) Params() pagination.Params {
+	page, limit := 0, 0
+	if q.Page != nil {
+		page = *q.Page
+	}
+	if q.Limit != nil {
+		limit = *q.Limit
+	}
+	return pagination.ParamsFor(page, limit)
+}
+
+// sparseItem is a single record trimmed to the fields the client selected, with its cache info
+type sparseItem struct {
+	Data      response.Record       `json:"data" xml:"data"`
+	CacheInfo *repository.CacheInfo `json:"cacheInfo,omitempty" xml:"cacheInfo,omitempty"`
+}
+
+// CRUDController serves list, get, create, update, patch and delete endpoints
+// for any resource backed by a service.CRUDService
+type CRUDController[T any] struct {
+	service service.CRUDService[T]
+	config  CRUDConfig[T]
+}
+
+// NewCRUDController creates a new generic CRUD controller
+func NewCRUDController[T any](svc service.CRUDService[T], cfg CRUDConfig[T]) *CRUDController[T] {
+	if cfg.IDParam == "" {
+		cfg.IDParam = "id"
+	}
+	if cfg.MaxImportBytes <= 0 {
+		cfg.MaxImportBytes = DefaultMaxImportBytes
+	}
+	if cfg.ImportJobs == nil {
+		cfg.ImportJobs = jobs.NewMemoryStore()
+	}
+	return &CRUDController[T]{
+		service: svc,
+		config:  cfg,
+	}
+}
+
+// RegisterRoutes registers all CRUD routes under the configured prefix
+func (c *CRUDController[T]) RegisterRoutes(r chi.Router) {
+	idPath := "/{" + c.config.IDParam + "}"
+	validateList := middleware.ValidateQuery(c.newListQuery())
+	r.Route(c.config.Prefix, func(r chi.Router) {
+		r.With(validateList).Get("/", c.List)
+		if _, ok := c.service.(service.Counter); ok {
+			r.With(validateList).Get("/count", c.Count)
+		}
+		if _, ok := c.service.(service.Exporter[T]); ok {
+			r.With(validateList).Get("/export", c.Export)
+		}
+		if _, ok := c.service.(service.Importer[T]); ok {
+			r.With(middleware.MaxBodyBytes(c.config.MaxImportBytes)).Post("/import", c.Import)
+			r.With(middleware.WithTimeout(importJobTimeout)).Get("/import/{jobID}/events", c.ImportEvents)
+		}
+		r.With(c.config.CreateMiddleware...).With(middleware.ValidationMiddleware[T]).Post("/", c.Create)
+		r.Get(idPath, c.Get)
+		r.With(middleware.ValidationMiddleware[T]).Put(idPath, c.Update)
+		r.Patch(idPath, c.Patch)
+		r.Delete(idPath, c.Delete)
+	})
+}
+
+// RegisterAdminRoutes registers the routes that can load a whole table under the configured
+// prefix; callers are responsible for protecting them. Only services implementing
+// service.Lister get a route
+func (c *CRUDController[T]) RegisterAdminRoutes(r chi.Router) {
+	if _, ok := c.service.(service.Lister[T]); ok {
+		r.Get(c.config.Prefix+"/all", c.ListAll)
+	}
+}
+
+// ListAll returns every record without pagination, refusing tables larger than
+// repository.MaxFindAllResults
+func (c *CRUDController[T]) ListAll(w http.ResponseWriter, r *http.Request) {
+	lister, ok := c.service.(service.Lister[T])
+	if !ok {
+		response.NotFound(w, r, "Listing all "+c.plural()+" is not supported")
+		return
+	}
+
+	result, err := lister.GetAll(r.Context())
+	if err != nil {
+		c.handleError(w, r, "list", "", err)
+		return
+	}
+	if result.Pagination == nil {
+		result.Pagination = pagination.SinglePage(int64(len(result.Data)))
+	}
+
+	c.sendPage(w, r, result, nil, "All "+c.plural()+" retrieved successfully")
+}
+
+// List returns a paginated, filtered list of records
+func (c *CRUDController[T]) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query, ok := c.listQuery(w, r)
+	if !ok {
+		return
+	}
+	params := query.Params()
+
+	// Add query parameters to the context for cache key generation
+	queryParams := map[string]string{
+		"page":      strconv.Itoa(params.Page),
+		"limit":     strconv.Itoa(params.Limit),
+		"sort":      query.Sort,
+		"direction": query.Direction,
+		"fields":    r.URL.Query().Get("fields"),
+		"include":   r.URL.Query().Get("include"),
+	}
+	ctxWithParams := context.WithValue(ctx, repository.KeyQueryParams, queryParams)
+
+	fields := repository.ParseFields(queryParams["fields"])
+	ctxWithParams = repository.WithFields(ctxWithParams, fields)
+	ctxWithParams = repository.WithPreloads(ctxWithParams, repository.ParsePreloads(queryParams["include"]))
+
+	result, err := c.service.GetAllPaginated(ctxWithParams, params, query.Filters)
+	if err != nil {
+		var fieldsErr *repository.InvalidFieldsError
+		if errors.As(err, &fieldsErr) {
+			response.ValidationError(w, r, fieldValidationErrors(fieldsErr))
+			return
+		}
+		var preloadsErr *repository.InvalidPreloadsError
+		if errors.As(err, &preloadsErr) {
+			response.ValidationError(w, r, includeValidationErrors(preloadsErr))
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			response.GatewayTimeout(w, r, "Listing "+c.plural()+" took too long")
+			return
+		}
+		c.logError(r, "Failed to get "+c.plural(), zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	c.sendPage(w, r, result, fields, c.title(c.plural())+" retrieved successfully")
+}
+
+// sendPage sends a page of records in the paginated envelope, or as a JSON:API document,
+// with the pagination headers. A non-empty fields limits each record to those fields
+func (c *CRUDController[T]) sendPage(w http.ResponseWriter, r *http.Request, result service.CollectionResponse[T], fields []string, message string) {
+	links := pagination.NewLinks(r.URL, *result.Pagination)
+	response.SetPaginationHeaders(w, *result.Pagination, links)
+	if response.WantsJSONAPI(r) {
+		meta := map[string]interface{}{"pagination": result.Pagination}
+		if result.CacheInfo != nil {
+			meta["cacheInfo"] = result.CacheInfo
+		}
+		resources := make([]response.JSONAPIResource, 0, len(result.Data))
+		for i := range result.Data {
+			resource, err := response.NewJSONAPIResource(c.plural(), &result.Data[i], fields)
+			if err != nil {
+				c.logError(r, "Failed to encode "+c.plural(), zap.Error(err))
+				response.InternalServerError(w, r, err)
+				return
+			}
+			resources = append(resources, resource)
+		}
+		response.JSONAPI(w, r, http.StatusOK, response.JSONAPIDocument{Data: resources, Meta: meta, Links: links})
+		return
+	}
+
+	var items interface{} = result.Data
+	if len(fields) > 0 {
+		sparse := make([]response.Record, 0, len(result.Data))
+		for i := range result.Data {
+			item, err := response.SelectFields(&result.Data[i], fields)
+			if err != nil {
+				c.logError(r, "Failed to encode "+c.plural(), zap.Error(err))
+				response.InternalServerError(w, r, err)
+				return
+			}
+			sparse = append(sparse, item)
+		}
+		items = sparse
+	}
+
+	// Create a paginated response with navigation links and cache info
+	pagedData := pagination.PagedData{
+		Items:      items,
+		Pagination: *result.Pagination,
+		Links:      links,
+		CacheInfo:  result.CacheInfo,
+	}
+
+	response.Success(w, r, pagedData, message)
+}
+
+// Count returns the number of records matching the same filters as List
+func (c *CRUDController[T]) Count(w http.ResponseWriter, r *http.Request) {
+	counter, ok := c.service.(service.Counter)
+	if !ok {
+		response.NotFound(w, r, "Counting "+c.plural()+" is not supported")
+		return
+	}
+
+	query, ok := c.listQuery(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := counter.Count(r.Context(), query.Filters)
+	if err != nil {
+		c.handleError(w, r, "count", "", err)
+		return
+	}
+
+	response.Success(w, r, CountResult{Count: count}, c.title(c.plural())+" counted successfully")
+}
+
+// Export streams every record matching the list endpoint's sort and filter parameters
+// as a CSV or JSON attachment, chosen by the format query parameter
+func (c *CRUDController[T]) Export(w http.ResponseWriter, r *http.Request) {
+	exporter, ok := c.service.(service.Exporter[T])
+	if !ok {
+		response.NotFound(w, r, "Export is not supported for "+c.plural())
+		return
+	}
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		response.BadRequest(w, r, "Invalid export format", err)
+		return
+	}
+
+	query, ok := c.listQuery(w, r)
+	if !ok {
+		return
+	}
+
+	// Headers are only sent with the first batch, so errors before it can still get a JSON response
+	filename := fmt.Sprintf("%s-%s.%s", c.plural(), time.Now().Format("20060102"), format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	written := false
+	encoder := export.NewEncoder[T](w, format)
+	err = exporter.Export(r.Context(), query.Sort, query.Direction, query.Filters, func(batch []T) error {
+		written = true
+		if err := encoder.Write(batch); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = encoder.Close()
+		written = true
+	}
+
+	if err != nil {
+		if written {
+			// The status has been sent; all that's left is to cut the download short
+			c.logError(r, "Failed to export "+c.plural(), zap.Error(err))
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		c.handleError(w, r, "export", "", err)
+	}
+}
+
+// Import creates records from the rows of a CSV file uploaded in the "file" form field.
+// Columns are matched to fields by their JSON names; rows that fail to parse or validate
+// are reported and skipped, and the valid rows are created together in one transaction.
+// With ?async=true the rows are created in the background and the response is the job
+// to follow with ImportEvents
+func (c *CRUDController[T]) Import(w http.ResponseWriter, r *http.Request) {
+	importer, ok := c.service.(service.Importer[T])
+	if !ok {
+		response.NotFound(w, r, "Import is not supported for "+c.plural())
+		return
+	}
+
+	items, result, ok := c.decodeImport(w, r)
+	if !ok {
+		return
+	}
+
+	if r.URL.Query().Get("async") == "true" {
+		c.startImportJob(w, r, importer, items, result)
+		return
+	}
+
+	if len(items) > 0 {
+		if err := importer.Import(r.Context(), items); err != nil {
+			c.handleError(w, r, "import", "", err)
+			return
+		}
+	}
+
+	result.Inserted = len(items)
+	result.Failed = len(result.Errors)
+	response.Success(w, r, result, fmt.Sprintf("Imported %d %s, %d rows failed", result.Inserted, c.plural(), result.Failed))
+}
+
+// decodeImport reads the uploaded CSV file, returning the valid rows and the errors of the
+// others. It sends the error response and returns false if the file can't be read
+func (c *CRUDController[T]) decodeImport(w http.ResponseWriter, r *http.Request) ([]T, ImportResult, bool) {
+	result := ImportResult{Errors: []ImportRowError{}}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.PayloadTooLarge(w, r, fmt.Sprintf("Upload too large: limit is %d bytes", maxBytesErr.Limit))
+			return nil, result, false
+		}
+		response.BadRequest(w, r, "A CSV file is required in the file form field", err)
+		return nil, result, false
+	}
+	defer file.Close()
+	defer func() {
+		if r.MultipartForm != nil {
+			_ = r.MultipartForm.RemoveAll()
+		}
+	}()
+
+	decoder, err := export.NewCSVDecoder[T](file)
+	if err != nil {
+		response.BadRequest(w, r, "Invalid CSV file", err)
+		return nil, result, false
+	}
+
+	var items []T
+	for {
+		item, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *export.RowError
+		if errors.As(err, &rowErr) {
+			result.Errors = append(result.Errors, ImportRowError{Line: rowErr.Line, Error: rowErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			c.logError(r, "Failed to read "+c.config.Tag+" import", zap.Error(err))
+			response.InternalServerError(w, r, err)
+			return nil, result, false
+		}
+
+		if validationErrors := validator.Validate(item); len(validationErrors) > 0 {
+			result.Errors = append(result.Errors, ImportRowError{Line: decoder.Line(), Fields: validationErrors})
+			continue
+		}
+		items = append(items, *item)
+	}
+
+	return items, result, true
+}
+
+// startImportJob creates items in the background and responds with 202 Accepted, the job
+// and a Location header pointing at its event stream
+func (c *CRUDController[T]) startImportJob(w http.ResponseWriter, r *http.Request, importer service.Importer[T], items []T, result ImportResult) {
+	id, err := jobs.NewID()
+	if err != nil {
+		c.logError(r, "Failed to start "+c.config.Tag+" import", zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	job := jobs.Job{ID: id, Status: jobs.StatusRunning, Total: len(items)}
+	if err := c.config.ImportJobs.Save(r.Context(), job, importJobTTL); err != nil {
+		c.logError(r, "Failed to start "+c.config.Tag+" import", zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	// The job outlives the request: it keeps the request's values, such as the logger, but
+	// not its deadline
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), importJobTimeout)
+	go func() {
+		defer cancel()
+		c.runImportJob(ctx, importer, job, items, result)
+	}()
+
+	w.Header().Set("Location", r.URL.Path+"/"+id+"/events")
+	response.Accepted(w, r, job, "Import of "+strconv.Itoa(len(items))+" "+c.plural()+" started")
+}
+
+// runImportJob creates items, storing the job's progress after each batch and its outcome
+// at the end
+func (c *CRUDController[T]) runImportJob(ctx context.Context, importer service.Importer[T], job jobs.Job, items []T, result ImportResult) {
+	logger := logging.FromContext(ctx).With(zap.String("job_id", job.ID))
+	save := func() {
+		// Saved even when the job ran out of time, so followers learn how it ended
+		if err := c.config.ImportJobs.Save(context.WithoutCancel(ctx), job, importJobTTL); err != nil {
+			logger.Warn("Failed to store "+c.config.Tag+" import job", zap.Error(err))
+		}
+	}
+
+	if len(items) > 0 {
+		progressCtx := repository.WithProgress(ctx, func(done int) {
+			job.Processed = done
+			save()
+		})
+		if err := importer.Import(progressCtx, items); err != nil {
+			job.Status = jobs.StatusFailed
+			job.ErrorCode, job.Error = c.importJobError(logger, err)
+			save()
+			return
+		}
+	}
+
+	result.Inserted = len(items)
+	result.Failed = len(result.Errors)
+	data, err := response.MarshalJSON(result)
+	if err != nil {
+		job.Status = jobs.StatusFailed
+		job.ErrorCode, job.Error = c.importJobError(logger, err)
+		save()
+		return
+	}
+
+	job.Status = jobs.StatusDone
+	job.Processed = len(items)
+	job.Result = data
+	save()
+}
+
+// importJobError returns the error code and message a failed import job reports for err,
+// matching the responses of a synchronous import
+func (c *CRUDController[T]) importJobError(logger *zap.Logger, err error) (string, string) {
+	switch {
+	case errors.Is(err, service.ErrAlreadyExists), errors.Is(err, database.ErrDuplicateKey):
+		return c.errorCode("%s_ALREADY_EXISTS"), c.title(c.config.Tag) + " already exists"
+	case errors.Is(err, service.ErrInvalidData):
+		return c.errorCode("INVALID_%s_DATA"), "Invalid " + c.config.Tag + " data"
+	case errors.Is(err, context.DeadlineExceeded):
+		return response.CodeTimeout, "Failed to import " + c.plural() + " in time"
+	default:
+		logger.Error("Failed to import "+c.plural(), zap.Error(err))
+		return response.CodeInternalError, "An internal server error occurred"
+	}
+}
+
+// ImportEvents streams the state of an import job as server-sent events: a progress event
+// whenever more rows have been written, then a done event carrying the ImportResult or an
+// error event carrying an ImportFailure. A stream opened after the job ended gets the last
+// progress and the outcome straight away
+func (c *CRUDController[T]) ImportEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "jobID")
+
+	job, err := c.config.ImportJobs.Get(ctx, id)
+	if err != nil {
+		c.logError(r, "Failed to read "+c.config.Tag+" import job", zap.String("job_id", id), zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+	if job == nil {
+		response.NotFound(w, r, "Import job not found")
+		return
+	}
+
+	// The stream lasts as long as the job rather than the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep proxies such as nginx from buffering events
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(importPollInterval)
+	defer ticker.Stop()
+
+	sent := -1
+	for {
+		if job.Processed != sent {
+			if err := writeServerSentEvent(w, "progress", ImportProgress{Processed: job.Processed, Total: job.Total}); err != nil {
+				return
+			}
+			sent = job.Processed
+		}
+
+		if job.Finished() {
+			if job.Status == jobs.StatusDone {
+				err = writeServerSentEvent(w, "done", job.Result)
+			} else {
+				err = writeServerSentEvent(w, "error", ImportFailure{ErrorCode: job.ErrorCode, Message: job.Error})
+			}
+			if err == nil {
+				_ = rc.Flush()
+			}
+			return
+		}
+
+		if err := rc.Flush(); err != nil {
+			c.logError(r, "Failed to stream "+c.config.Tag+" import job", zap.String("job_id", id), zap.Error(err))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		job, err = c.config.ImportJobs.Get(ctx, id)
+		if err != nil || job == nil {
+			c.logError(r, "Failed to read "+c.config.Tag+" import job", zap.String("job_id", id), zap.Error(err))
+			return
+		}
+	}
+}
+
+// writeServerSentEvent writes one event in the text/event-stream format with data encoded as JSON,
+// in the key case of the other responses
+func writeServerSentEvent(w io.Writer, event string, data interface{}) error {
+	payload, err := response.MarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+// Get returns a single record by ID
+func (c *CRUDController[T]) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, c.config.IDParam)
+	fields := repository.ParseFields(r.URL.Query().Get("fields"))
+
+	result, err := c.service.GetByID(repository.WithFields(ctx, fields), id)
+	if err != nil {
+		c.handleError(w, r, "get", id, err)
+		return
+	}
+
+	// Honor conditional GET requests. A trimmed record is a different representation,
+	// so it is served without an ETag
+	etag := ""
+	if c.config.ETag != nil && len(fields) == 0 {
+		etag = response.ETagFor(r, c.config.ETag(result.Data))
+		if response.MatchesETag(r, etag) {
+			response.NotModified(w, r, etag)
+			return
+		}
+	}
+
+	if response.WantsJSONAPI(r) {
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		c.sendJSONAPIResource(w, r, http.StatusOK, result.Data, fields, result.CacheInfo)
+		return
+	}
+
+	message := c.title(c.config.Tag) + " retrieved successfully"
+	if len(fields) > 0 {
+		data, err := response.SelectFields(result.Data, fields)
+		if err != nil {
+			c.logError(r, "Failed to encode "+c.config.Tag, zap.String("id", id), zap.Error(err))
+			response.InternalServerError(w, r, err)
+			return
+		}
+		response.Success(w, r, sparseItem{Data: data, CacheInfo: result.CacheInfo}, message)
+		return
+	}
+
+	response.SuccessWithETag(w, r, result, message, etag)
+}
+
+// Create creates a new record from the validated request body
+func (c *CRUDController[T]) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	item, ok := c.validatedItem(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Create(ctx, item); err != nil {
+		c.handleError(w, r, "create", "", err)
+		return
+	}
+
+	if response.WantsJSONAPI(r) {
+		c.sendJSONAPIResource(w, r, http.StatusCreated, item, nil, nil)
+		return
+	}
+
+	response.Created(w, r, item, c.title(c.config.Tag)+" created successfully")
+}
+
+// Update replaces an existing record
+func (c *CRUDController[T]) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, c.config.IDParam)
+
+	item, ok := c.validatedItem(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Update(ctx, id, item); err != nil {
+		c.handleError(w, r, "update", id, err)
+		return
+	}
+
+	if response.WantsJSONAPI(r) {
+		c.sendJSONAPIResource(w, r, http.StatusOK, item, nil, nil)
+		return
+	}
+
+	response.Success(w, r, item, c.title(c.config.Tag)+" updated successfully")
+}
+
+// Patch updates only the provided fields of an existing record
+func (c *CRUDController[T]) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, c.config.IDParam)
+
+	fields, ok := middleware.HandleDecodeFields(w, r, new(T))
+	if !ok {
+		return
+	}
+
+	if err := c.service.Patch(ctx, id, fields); err != nil {
+		c.handleError(w, r, "patch", id, err)
+		return
+	}
+
+	// Return the updated record
+	result, err := c.service.GetByID(ctx, id)
+	if err != nil {
+		c.logError(r, "Failed to get patched "+c.config.Tag, zap.String("id", id), zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if response.WantsJSONAPI(r) {
+		c.sendJSONAPIResource(w, r, http.StatusOK, result.Data, nil, result.CacheInfo)
+		return
+	}
+
+	response.Success(w, r, result, c.title(c.config.Tag)+" updated successfully")
+}
+
+// Delete removes a record by ID
+func (c *CRUDController[T]) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, c.config.IDParam)
+
+	if err := c.service.Delete(ctx, id); err != nil {
+		c.handleError(w, r, "delete", id, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// validatedItem returns the model validated by ValidationMiddleware, decoding and
+// validating the body itself when the handler is mounted without the middleware
+func (c *CRUDController[T]) validatedItem(w http.ResponseWriter, r *http.Request) (*T, bool) {
+	if item, ok := middleware.ValidatedModel[T](r); ok {
+		return item, true
+	}
+
+	item := new(T)
+	if !middleware.HandleValidateRequest(w, r, item) {
+		return nil, false
+	}
+	return item, true
+}
+
+// newListQuery returns the list query parameters are bound into, holding the sortable and
+// filterable fields of the resource
+func (c *CRUDController[T]) newListQuery() ListQuery {
+	return ListQuery{sortFields: c.config.SortFields, filterFields: c.config.FilterFields}
+}
+
+// listQuery returns the query parameters validated by middleware.ValidateQuery, binding and
+// validating them itself when the handler is mounted without the middleware
+func (c *CRUDController[T]) listQuery(w http.ResponseWriter, r *http.Request) (*ListQuery, bool) {
+	if query, ok := middleware.ValidatedQuery[ListQuery](r); ok {
+		return query, true
+	}
+
+	query := c.newListQuery()
+	if !middleware.HandleValidateQuery(w, r, &query) {
+		return nil, false
+	}
+	return &query, true
+}
+
+// sendJSONAPIResource sends item as a JSON:API resource document typed after the resource's plural name,
+// trimmed to fields when any are given. Cache info, if present, is reported as meta
+func (c *CRUDController[T]) sendJSONAPIResource(w http.ResponseWriter, r *http.Request, statusCode int, item *T, fields []string, cacheInfo *repository.CacheInfo) {
+	resource, err := response.NewJSONAPIResource(c.plural(), item, fields)
+	if err != nil {
+		c.logError(r, "Failed to encode "+c.config.Tag, zap.Error(err))
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	doc := response.JSONAPIDocument{Data: resource}
+	if cacheInfo != nil {
+		doc.Meta = map[string]interface{}{"cacheInfo": cacheInfo}
+	}
+	response.JSONAPI(w, r, statusCode, doc)
+}
+
+// handleError maps service errors to the matching response helper
+func (c *CRUDController[T]) handleError(w http.ResponseWriter, r *http.Request, action, id string, err error) {
+	var validationErrors service.ValidationErrors
+	var fieldsErr *repository.InvalidFieldsError
+	switch {
+	case errors.As(err, &validationErrors):
+		response.UnprocessableEntity(w, r, []validator.ValidationError(validationErrors))
+	case errors.As(err, &fieldsErr):
+		response.ValidationError(w, r, fieldValidationErrors(fieldsErr))
+	case errors.Is(err, service.ErrNotFound):
+		response.Error(w, r, http.StatusNotFound, c.errorCode("%s_NOT_FOUND"), c.title(c.config.Tag)+" not found")
+	case errors.Is(err, service.ErrAlreadyExists), errors.Is(err, database.ErrDuplicateKey):
+		// Writes other than Create surface a unique index violation as it comes from the database
+		response.ErrorWithDetail(w, r, http.StatusConflict, c.errorCode("%s_ALREADY_EXISTS"),
+			c.title(c.config.Tag)+" already exists", err)
+	case errors.Is(err, service.ErrVersionConflict):
+		response.ErrorWithDetail(w, r, http.StatusConflict, c.errorCode("%s_VERSION_CONFLICT"),
+			c.title(c.config.Tag)+" has been modified since it was read", err)
+	case errors.Is(err, service.ErrInvalidID):
+		response.ErrorWithDetail(w, r, http.StatusBadRequest, c.errorCode("INVALID_%s_ID"), "Invalid "+c.config.Tag+" ID", err)
+	case errors.Is(err, service.ErrInvalidData):
+		response.ErrorWithDetail(w, r, http.StatusBadRequest, c.errorCode("INVALID_%s_DATA"), "Invalid "+c.config.Tag+" data", err)
+	case errors.Is(err, service.ErrTooManyResults):
+		response.ErrorWithDetail(w, r, http.StatusBadRequest, response.CodeTooManyResults, fmt.Sprintf("More than %d %s; use the paginated list or export instead",
+			repository.MaxFindAllResults, c.plural()), err)
+	case errors.Is(err, context.DeadlineExceeded):
+		response.GatewayTimeout(w, r, "Failed to "+action+" "+c.config.Tag+" in time")
+	default:
+		fields := []zap.Field{zap.Error(err)}
+		if id != "" {
+			fields = append(fields, zap.String("id", id))
+		}
+		c.logError(r, "Failed to "+action+" "+c.config.Tag, fields...)
+		response.InternalServerError(w, r, err)
+	}
+}
+
+// errorCode fills the resource tag into an error code format, e.g. "%s_NOT_FOUND" becomes ANIMAL_NOT_FOUND
+func (c *CRUDController[T]) errorCode(format string) string {
+	tag := strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(c.config.Tag))
+	return fmt.Sprintf(format, tag)
+}
+
+// logError logs an error with the request-scoped logger, tagged with the request ID so it can be
+// matched to the client's report
+func (c *CRUDController[T]) logError(r *http.Request, message string, fields ...zap.Field) {
+	if reqID := chimiddleware.GetReqID(r.Context()); reqID != "" {
+		fields = append(fields, zap.String("request_id", reqID))
+	}
+	logging.FromContext(r.Context()).Error(message, fields...)
+}
+
+// plural returns the plural form of the resource tag
+func (c *CRUDController[T]) plural() string {
+	return c.config.Tag + "s"
+}
+
+// filterValidationErrors reports an invalid filter parameter as a validation error
+func filterValidationErrors(err error) []validator.ValidationError {
+	var filterErr *repository.InvalidFilterError
+	if !errors.As(err, &filterErr) {
+		return []validator.ValidationError{{Field: "filters", Error: err.Error()}}
+	}
+
+	tag := "timezone"
+	switch filterErr.Kind {
+	case repository.FilterInt, repository.FilterFloat:
+		tag = "numeric"
+	case repository.FilterBool:
+		tag = "boolean"
+	case repository.FilterTime:
+		tag = "datetime"
+	}
+	return []validator.ValidationError{{
+		Field: filterErr.Param,
+		Tag:   tag,
+		Value: filterErr.Value,
+		Error: filterErr.Error(),
+	}}
+}
+
+// fieldValidationErrors reports each unknown field of a fields query parameter as a validation error
+func fieldValidationErrors(err *repository.InvalidFieldsError) []validator.ValidationError {
+	errs := make([]validator.ValidationError, 0, len(err.Fields))
+	for _, field := range err.Fields {
+		errs = append(errs, validator.ValidationError{
+			Field: "fields",
+			Tag:   "oneof",
+			Value: field,
+			Error: fmt.Sprintf("fields contains unknown field %q", field),
+		})
+	}
+	return errs
+}
+
+// includeValidationErrors reports each unknown association of an include query parameter as a validation error
+func includeValidationErrors(err *repository.InvalidPreloadsError) []validator.ValidationError {
+	errs := make([]validator.ValidationError, 0, len(err.Includes))
+	for _, include := range err.Includes {
+		errs = append(errs, validator.ValidationError{
+			Field: "include",
+			Tag:   "oneof",
+			Value: include,
+			Error: fmt.Sprintf("include contains unknown association %q", include),
+		})
+	}
+	return errs
+}
+
+// title capitalizes the first letter of s for use in response messages
+func (c *CRUDController[T]) title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRUDController_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "NotFound",
+			serviceError:   service.ErrAnimalNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "WrappedNotFound",
+			serviceError:   fmt.Errorf("lookup failed: %w", service.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "InvalidID",
+			serviceError:   service.ErrInvalidAnimalID,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "InvalidData",
+			serviceError:   service.ErrInvalidAnimalData,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "AlreadyExists",
+			serviceError:   service.ErrAnimalAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "VersionConflict",
+			serviceError:   service.ErrAnimalVersionConflict,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "DeadlineExceeded",
+			serviceError:   context.DeadlineExceeded,
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:           "InternalServerError",
+			serviceError:   fmt.Errorf("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAnimalService)
+			mockService.On("GetByID", mock.Anything, "42").Return(service.AnimalResponse{}, tt.serviceError)
+
+			controller := NewCRUDController[model.Animal](mockService, CRUDConfig[model.Animal]{
+				Prefix:  "/pets",
+				Tag:     "pet",
+				IDParam: "petID",
+			})
+
+			// Routes are mounted under the configured prefix and ID parameter
+			router := chi.NewRouter()
+			controller.RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodGet, "/pets/42", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCRUDController_JSONAPI(t *testing.T) {
+	animal := model.Animal{ID: 7, Name: "Fluffy", Species: "Cat", Age: 3}
+
+	mockService := new(MockAnimalService)
+	mockService.On("GetByID", mock.Anything, "7").Return(service.AnimalResponse{Data: &animal}, nil)
+	mockService.On("GetByID", mock.Anything, "8").Return(service.AnimalResponse{}, service.ErrAnimalNotFound)
+	mockService.On("GetAllPaginated", mock.Anything, mock.Anything, mock.Anything).Return(service.AnimalCollectionResponse{
+		Data:       []model.Animal{animal},
+		Pagination: &pagination.Params{Page: 1, Limit: 10, TotalItems: 1, TotalPages: 1},
+	}, nil)
+
+	router := chi.NewRouter()
+	NewAnimal(mockService, nil).RegisterRoutes(router)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", response.JSONAPIMediaType)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Resource", func(t *testing.T) {
+		rr := serve("/animals/7?fields=name")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, response.JSONAPIMediaType, rr.Header().Get("Content-Type"))
+
+		var doc struct {
+			Data response.JSONAPIResource `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+		assert.Equal(t, "animals", doc.Data.Type)
+		assert.Equal(t, "7", doc.Data.ID)
+		assert.Equal(t, map[string]json.RawMessage{"name": json.RawMessage(`"Fluffy"`)}, doc.Data.Attributes)
+	})
+
+	t.Run("Collection", func(t *testing.T) {
+		rr := serve("/animals")
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var doc struct {
+			Data  []response.JSONAPIResource `json:"data"`
+			Meta  map[string]json.RawMessage `json:"meta"`
+			Links pagination.Links           `json:"links"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+		require.Len(t, doc.Data, 1)
+		assert.Equal(t, "7", doc.Data[0].ID)
+		assert.JSONEq(t, `"Cat"`, string(doc.Data[0].Attributes["species"]))
+		assert.Contains(t, doc.Meta, "pagination")
+		assert.Equal(t, "/animals?page=1", doc.Links.First)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		rr := serve("/animals/8")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, response.JSONAPIMediaType, rr.Header().Get("Content-Type"))
+
+		var doc response.JSONAPIDocument
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+		assert.Nil(t, doc.Data)
+		assert.Equal(t, []response.JSONAPIError{{Status: "404", Code: "ANIMAL_NOT_FOUND", Title: "Animal not found"}}, doc.Errors)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		mockService.On("GetByID", mock.Anything, "9").Return(service.AnimalResponse{}, &repository.InvalidFieldsError{Fields: []string{"owner"}})
+
+		rr := serve("/animals/9?fields=owner")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		var doc response.JSONAPIDocument
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+		require.Len(t, doc.Errors, 1)
+		assert.Equal(t, "400", doc.Errors[0].Status)
+		assert.Contains(t, doc.Errors[0].Detail, "owner")
+	})
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/response"
+)
+
+// Flower handles flower requests
+// Routing and error mapping come from the embedded CRUDController; the methods
+// below only carry the Swagger annotations for each endpoint
+type Flower struct {
+	*CRUDController[model.Flower]
+}
+
+// NewFlower creates a new Flower controller instance
+func NewFlower(service service.FlowerService) *Flower {
+	return &Flower{
+		CRUDController: NewCRUDController[model.Flower](service, CRUDConfig[model.Flower]{
+			Prefix:  "/flowers",
+			Tag:     "flower",
+			IDParam: "flowerID",
+			ETag: func(flower *model.Flower) string {
+				return response.GenerateETag(flower.ID, flower.UpdatedAt)
+			},
+			SortFields:   repository.FlowerSortFields,
+			FilterFields: repository.FlowerFilterFields,
+		}),
+	}
+}
+
+// GetFlowers returns all flowers
+// @Summary Get all flowers
+// @Description Get a paginated list of all flowers
+// @Tags flowers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Param sort query string false "Sort field (id, name, species, color, seasonal, created_at, updated_at)"
+// @Param direction query string false "Sort direction (asc, desc)"
+// @Param fields query string false "Comma-separated fields to return (id, name, species, color, description, seasonal, created_at, updated_at)"
+// @Param include query string false "Comma-separated associations to load with each record"
+// @Param id query int false "Filter by exact ID"
+// @Param name query string false "Filter by exact name"
+// @Param name_like query string false "Filter by names containing the value"
+// @Param species query string false "Filter by exact species"
+// @Param species_like query string false "Filter by species containing the value"
+// @Param color query string false "Filter by exact color"
+// @Param color_like query string false "Filter by colors containing the value"
+// @Param seasonal query bool false "Filter by seasonal flag"
+// @Param created_at_gte query string false "Filter by creation time on or after the value"
+// @Param created_at_lte query string false "Filter by creation time on or before the value"
+// @Param updated_at_gte query string false "Filter by update time on or after the value"
+// @Param updated_at_lte query string false "Filter by update time on or before the value"
+// @Param created_after query string false "Filter by creation time on or after the value (alias of created_at_gte)"
+// @Param created_before query string false "Filter by creation time on or before the value (alias of created_at_lte)"
+// @Param updated_after query string false "Filter by update time on or after the value (alias of updated_at_gte)"
+// @Param updated_before query string false "Filter by update time on or before the value (alias of updated_at_lte)"
+// @Param tz query string false "IANA time zone of timestamps without an offset (default: UTC)"
+// @Success 200 {object} response.APIResponse{data=pagination.PagedData{items=[]model.Flower}}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers [get]
+func (f *Flower) GetFlowers(w http.ResponseWriter, r *http.Request) {
+	f.List(w, r)
+}
+
+// CountFlowers returns the number of flowers matching the filters
+// @Summary Count flowers
+// @Description Count the flowers matching the filters without fetching them. Accepts the same filter
+// @Description parameters as the list endpoint; pagination and sort are validated but don't change the count
+// @Tags flowers
+// @Produce json
+// @Param id query int false "Filter by exact ID"
+// @Param name query string false "Filter by exact name"
+// @Param name_like query string false "Filter by names containing the value"
+// @Param species query string false "Filter by exact species"
+// @Param species_like query string false "Filter by species containing the value"
+// @Param color query string false "Filter by exact color"
+// @Param color_like query string false "Filter by colors containing the value"
+// @Param seasonal query bool false "Filter by seasonal flag"
+// @Param created_at_gte query string false "Filter by creation time on or after the value"
+// @Param created_at_lte query string false "Filter by creation time on or before the value"
+// @Param updated_at_gte query string false "Filter by update time on or after the value"
+// @Param updated_at_lte query string false "Filter by update time on or before the value"
+// @Param created_after query string false "Filter by creation time on or after the value (alias of created_at_gte)"
+// @Param created_before query string false "Filter by creation time on or before the value (alias of created_at_lte)"
+// @Param updated_after query string false "Filter by update time on or after the value (alias of updated_at_gte)"
+// @Param updated_before query string false "Filter by update time on or before the value (alias of updated_at_lte)"
+// @Param tz query string false "IANA time zone of timestamps without an offset (default: UTC)"
+// @Success 200 {object} response.APIResponse{data=controller.CountResult}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers/count [get]
+func (f *Flower) CountFlowers(w http.ResponseWriter, r *http.Request) {
+	f.Count(w, r)
+}
+
+// GetFlower returns a specific flower by ID
+// @Summary Get a flower by ID
+// @Description Get a flower by its ID
+// @Tags flowers
+// @Accept json
+// @Produce json
+// @Param flowerID path string true "Flower ID"
+// @Param fields query string false "Comma-separated fields to return (id, name, species, color, description, seasonal, created_at, updated_at)"
+// @Param If-None-Match header string false "ETag from a previous response; ignored when fields is set"
+// @Success 200 {object} response.APIResponse{data=model.Flower}
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers/{flowerID} [get]
+func (f *Flower) GetFlower(w http.ResponseWriter, r *http.Request) {
+	f.Get(w, r)
+}
+
+// CreateFlower creates a new flower
+// @Summary Create a new flower
+// @Description Create a new flower with the provided details
+// @Tags flowers
+// @Accept json
+// @Produce json
+// @Param flower body model.FlowerCreateRequest true "Flower object to be created"
+// @Success 201 {object} response.APIResponse{data=model.Flower}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 422 {object} response.ValidationErrorResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers [post]
+func (f *Flower) CreateFlower(w http.ResponseWriter, r *http.Request) {
+	f.Create(w, r)
+}
+
+// UpdateFlower updates an existing flower
+// @Summary Update a flower
+// @Description Update an existing flower by its ID
+// @Tags flowers
+// @Accept json
+// @Produce json
+// @Param flowerID path string true "Flower ID"
+// @Param flower body model.FlowerUpdateRequest true "Updated flower object"
+// @Success 200 {object} response.APIResponse{data=model.Flower}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 422 {object} response.ValidationErrorResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers/{flowerID} [put]
+func (f *Flower) UpdateFlower(w http.ResponseWriter, r *http.Request) {
+	f.Update(w, r)
+}
+
+// PatchFlower partially updates an existing flower
+// @Summary Partially update a flower
+// @Description Update only the provided fields of an existing flower by its ID
+// @Tags flowers
+// @Accept json
+// @Produce json
+// @Param flowerID path string true "Flower ID"
+// @Param flower body model.FlowerPatchRequest true "Fields to update"
+// @Success 200 {object} response.APIResponse{data=model.Flower}
+// @Failure 400 {object} response.ValidationErrorResponse
+// @Failure 422 {object} response.ValidationErrorResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers/{flowerID} [patch]
+func (f *Flower) PatchFlower(w http.ResponseWriter, r *http.Request) {
+	f.Patch(w, r)
+}
+
+// DeleteFlower deletes a flower
+// @Summary Delete a flower
+// @Description Delete a flower by its ID
+// @Tags flowers
+// @Accept json
+// @Produce json
+// @Param flowerID path string true "Flower ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Router /flowers/{flowerID} [delete]
+func (f *Flower) DeleteFlower(w http.ResponseWriter, r *http.Request) {
+	f.Delete(w, r)
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/response"
+	"go.uber.org/zap"
+)
+
+const (
+	// subscriptionWriteWait is how long a single message may take to reach the client
+	subscriptionWriteWait = 10 * time.Second
+	// subscriptionPongWait is how long the client may go without answering a ping
+	subscriptionPongWait = 60 * time.Second
+	// subscriptionPingPeriod is how often the client is pinged; it must be shorter than subscriptionPongWait
+	subscriptionPingPeriod = subscriptionPongWait * 9 / 10
+	// subscriptionMaxMessage is the largest message accepted from the client, which only sends control frames
+	subscriptionMaxMessage = 512
+)
+
+// Subscriptions streams record changes to clients over WebSocket
+type Subscriptions struct {
+	subscriber events.Subscriber
+	upgrader   websocket.Upgrader
+	done       chan struct{} // Closed by Shutdown to end every stream
+	shutdown   sync.Once
+}
+
+// NewSubscriptions creates a new Subscriptions controller delivering the events of subscriber.
+// Browsers may only connect from allowedOrigins, matched like the CORS policy's allowed origins
+func NewSubscriptions(subscriber events.Subscriber, allowedOrigins []string) *Subscriptions {
+	return &Subscriptions{
+		subscriber: subscriber,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				// Clients other than browsers don't send an Origin
+				origin := r.Header.Get("Origin")
+				return origin == "" || originAllowed(origin, allowedOrigins)
+			},
+			Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+				if status == http.StatusForbidden {
+					response.Forbidden(w, r, "Origin not allowed")
+					return
+				}
+				response.BadRequest(w, r, "WebSocket upgrade required", reason)
+			},
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// Shutdown closes every subscription with a going away close frame, since the HTTP server
+// doesn't track the connections it has handed over
+func (s *Subscriptions) Shutdown() {
+	s.shutdown.Do(func() { close(s.done) })
+}
+
+// originAllowed reports whether origin matches one of allowed, which may be "*" for any origin or
+// contain one "*" standing for any part, e.g. https://*.example.com
+func originAllowed(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		switch {
+		case pattern == "*", pattern == origin:
+			return true
+		case wildcard && len(origin) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix):
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterRoutes registers the subscription routes
+func (s *Subscriptions) RegisterRoutes(r chi.Router) {
+	r.Get("/animals/subscribe", s.SubscribeAnimals)
+}
+
+// SubscribeAnimals upgrades the request to a WebSocket and sends an event for every animal
+// created, updated or deleted until the client disconnects
+// @Summary Subscribe to animal changes
+// @Description Open a WebSocket that receives an event {type, id, data} whenever an animal is created, updated or deleted. Clients that fall too far behind are disconnected with close code 1013 and should reconnect and reload
+// @Tags animals
+// @Produce json
+// @Success 101 {object} events.Event
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /animals/subscribe [get]
+func (s *Subscriptions) SubscribeAnimals(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, repository.AnimalEventsTopic)
+}
+
+// serve upgrades the connection and hands it to a goroutine streaming the events of topic.
+// The handler returns straight away so request timeouts don't apply to the connection
+func (s *Subscriptions) serve(w http.ResponseWriter, r *http.Request, topic string) {
+	logger := logging.FromContext(r.Context())
+
+	// The upgrader has already replied with 400, or 403 for a foreign origin, when this fails
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Debug("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+
+	sub := s.subscriber.Subscribe(topic)
+	go stream(conn, sub, s.done, logger.With(zap.String("topic", topic)))
+}
+
+// stream writes the events of sub to conn until the client goes away, a write fails, the
+// subscription is dropped for falling behind or done is closed
+func stream(conn *websocket.Conn, sub *events.Subscription, done <-chan struct{}, logger *zap.Logger) {
+	defer conn.Close()
+	defer sub.Close()
+
+	// Reading is needed to process pongs and the client's close frame
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		conn.SetReadLimit(subscriptionMaxMessage)
+		_ = conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(subscriptionPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-disconnected:
+			return
+		case <-done:
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(subscriptionWriteWait))
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Dropped() {
+					logger.Warn("Dropping WebSocket subscriber that fell behind")
+					closeMessage := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber too slow")
+					_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(subscriptionWriteWait))
+				}
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(subscriptionWriteWait))
+			data, err := response.MarshalJSON(event)
+			if err == nil {
+				err = conn.WriteMessage(websocket.TextMessage, data)
+			}
+			if err != nil {
+				if !errors.Is(err, websocket.ErrCloseSent) {
+					logger.Debug("Failed to send event to WebSocket subscriber", zap.Error(err))
+				}
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(subscriptionWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
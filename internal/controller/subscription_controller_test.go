@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// laggingSubscriber hands out subscriptions that have already fallen behind by backlog events
+type laggingSubscriber struct {
+	broker  *events.MemoryBroker
+	backlog int
+}
+
+func (s *laggingSubscriber) Subscribe(topic string) *events.Subscription {
+	sub := s.broker.Subscribe(topic)
+	for i := 0; i < s.backlog; i++ {
+		_ = s.broker.Publish(context.Background(), topic, events.Event{Type: events.TypeUpdated, ID: "1"})
+	}
+	return sub
+}
+
+// signalingSubscriber closes subscribed once a subscription has been started
+type signalingSubscriber struct {
+	events.Subscriber
+	subscribed chan struct{}
+}
+
+func (s *signalingSubscriber) Subscribe(topic string) *events.Subscription {
+	defer close(s.subscribed)
+	return s.Subscriber.Subscribe(topic)
+}
+
+// dialSubscriptions starts a server for the subscription routes of subscriptions and connects to
+// the animal route
+func dialSubscriptions(t *testing.T, subscriptions *Subscriptions) *websocket.Conn {
+	t.Helper()
+
+	conn, resp, err := dialSubscriptionsFrom(t, subscriptions, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// dialSubscriptionsFrom connects to the animal route of subscriptions like a browser on origin,
+// or like any other client when origin is empty
+func dialSubscriptionsFrom(t *testing.T, subscriptions *Subscriptions, origin string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	r := chi.NewRouter()
+	subscriptions.RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/animals/subscribe"
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func TestSubscriptions_SubscribeAnimals(t *testing.T) {
+	broker := events.NewMemoryBroker(4)
+	subscriber := &signalingSubscriber{Subscriber: broker, subscribed: make(chan struct{})}
+	conn := dialSubscriptions(t, NewSubscriptions(subscriber, nil))
+
+	<-subscriber.subscribed
+	require.NoError(t, broker.Publish(context.Background(), repository.AnimalEventsTopic, events.Event{Type: events.TypeDeleted, ID: "3"}))
+
+	var event events.Event
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, events.Event{Type: events.TypeDeleted, ID: "3"}, event)
+}
+
+func TestSubscriptions_DropsSlowSubscriber(t *testing.T) {
+	conn := dialSubscriptions(t, NewSubscriptions(&laggingSubscriber{broker: events.NewMemoryBroker(1), backlog: 2}, nil))
+
+	// The buffered event is delivered before the connection is closed
+	var event events.Event
+	require.NoError(t, conn.ReadJSON(&event))
+	assert.Equal(t, events.TypeUpdated, event.Type)
+
+	err := conn.ReadJSON(&event)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "unexpected error: %v", err)
+}
+
+func TestSubscriptions_RejectsPlainRequests(t *testing.T) {
+	r := chi.NewRouter()
+	NewSubscriptions(events.NewMemoryBroker(1), nil).RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/animals/subscribe", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSubscriptions_ChecksOrigin(t *testing.T) {
+	allowed := []string{"https://app.example.com", "https://*.example.org"}
+
+	tests := []struct {
+		name           string
+		origin         string
+		expectedStatus int
+	}{
+		{name: "NoOrigin", origin: "", expectedStatus: http.StatusSwitchingProtocols},
+		{name: "Allowed", origin: "https://app.example.com", expectedStatus: http.StatusSwitchingProtocols},
+		{name: "AllowedIgnoringCase", origin: "https://App.Example.com", expectedStatus: http.StatusSwitchingProtocols},
+		{name: "Wildcard", origin: "https://admin.example.org", expectedStatus: http.StatusSwitchingProtocols},
+		{name: "Foreign", origin: "https://evil.example.net", expectedStatus: http.StatusForbidden},
+		{name: "WildcardSuffixOnly", origin: "https://example.org", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, resp, err := dialSubscriptionsFrom(t, NewSubscriptions(events.NewMemoryBroker(1), allowed), tt.origin)
+			if conn != nil {
+				_ = conn.Close()
+			}
+			if tt.expectedStatus != http.StatusSwitchingProtocols {
+				assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+			}
+			require.NotNil(t, resp)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestSubscriptions_AnyOrigin(t *testing.T) {
+	conn, resp, err := dialSubscriptionsFrom(t, NewSubscriptions(events.NewMemoryBroker(1), []string{"*"}), "https://anywhere.example")
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+}
+
+func TestSubscriptions_ShutdownClosesSubscribers(t *testing.T) {
+	broker := events.NewMemoryBroker(1)
+	subscriber := &signalingSubscriber{Subscriber: broker, subscribed: make(chan struct{})}
+	subscriptions := NewSubscriptions(subscriber, nil)
+	conn := dialSubscriptions(t, subscriptions)
+
+	<-subscriber.subscribed
+	subscriptions.Shutdown()
+	subscriptions.Shutdown() // Calling it again is harmless
+
+	var event events.Event
+	err := conn.ReadJSON(&event)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/linkeunid/go-api/internal/version"
+	"github.com/linkeunid/go-api/pkg/response"
+)
+
+// Version reports which build of the application is running
+type Version struct{}
+
+// NewVersion creates a new Version controller
+func NewVersion() *Version {
+	return &Version{}
+}
+
+// RegisterRoutes registers the version route
+func (v *Version) RegisterRoutes(r chi.Router) {
+	r.Get("/version", v.GetVersion)
+}
+
+// GetVersion returns the build metadata of the running application
+// @Summary Get the running version
+// @Description Get the version, commit and build time the application was built with, and its Go version
+// @Tags system
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=version.Info}
+// @Router /version [get]
+func (v *Version) GetVersion(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, r, version.Get(), "Version retrieved successfully")
+}
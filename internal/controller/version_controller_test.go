@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/linkeunid/go-api/internal/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion_GetVersion(t *testing.T) {
+	r := chi.NewRouter()
+	NewVersion().RegisterRoutes(r)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data version.Info `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, version.Get(), resp.Data)
+	assert.Equal(t, runtime.Version(), resp.Data.GoVersion)
+}
@@ -8,7 +8,7 @@ import (
 
 // @title Linkeun Go API
 // @version 1.0
-// @description API for managing various resources including animals
+// @description API for managing various resources including animals and flowers
 // @termsOfService http://swagger.io/terms/
 
 // @contact.name API Support - Website
@@ -22,6 +22,11 @@ import (
 // @BasePath /api/v1
 // @schemes http https
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT access token sent as "Bearer <token>"
+
 // GetSwaggerHost returns the host for Swagger UI based on environment variables
 func GetSwaggerHost() string {
 	host := os.Getenv("API_HOST")
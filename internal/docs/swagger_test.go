@@ -0,0 +1,69 @@
+package docs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/linkeunid/go-api/internal/docs/swaggerdocs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// swaggerDoc is the part of the generated document the tests read
+type swaggerDoc struct {
+	SecurityDefinitions map[string]struct {
+		Type string `json:"type"`
+		In   string `json:"in"`
+		Name string `json:"name"`
+	} `json:"securityDefinitions"`
+	Paths map[string]map[string]struct {
+		Security []map[string][]string `json:"security"`
+	} `json:"paths"`
+}
+
+// readSwaggerDoc decodes the generated document
+func readSwaggerDoc(t *testing.T) swaggerDoc {
+	t.Helper()
+
+	var doc swaggerDoc
+	require.NoError(t, json.Unmarshal([]byte(swaggerdocs.SwaggerInfo.ReadDoc()), &doc))
+	return doc
+}
+
+func TestSwagger_DeclaresBearerAuth(t *testing.T) {
+	doc := readSwaggerDoc(t)
+
+	scheme, ok := doc.SecurityDefinitions["BearerAuth"]
+	require.True(t, ok, "BearerAuth is missing; run make swagger")
+	assert.Equal(t, "apiKey", scheme.Type)
+	assert.Equal(t, "header", scheme.In)
+	assert.Equal(t, "Authorization", scheme.Name)
+}
+
+func TestSwagger_ProtectedOperationsRequireBearerAuth(t *testing.T) {
+	doc := readSwaggerDoc(t)
+
+	// Admin routes and animal writes need a token; reads stay public
+	protected := func(path, method string) bool {
+		switch {
+		case strings.HasPrefix(path, "/admin/"), path == "/animals/all", path == "/me":
+			return true
+		case strings.HasPrefix(path, "/animals") && path != "/animals/subscribe" && !strings.HasSuffix(path, "/events"):
+			return method != "get"
+		}
+		return false
+	}
+
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			requiresAuth := false
+			for _, requirement := range operation.Security {
+				if _, ok := requirement["BearerAuth"]; ok {
+					requiresAuth = true
+				}
+			}
+			assert.Equal(t, protected(path, method), requiresAuth, "%s %s", strings.ToUpper(method), path)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/linkeunid/go-api/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// Error is a GraphQL error carrying the same machine-readable code as the REST error response,
+// reported in the error's extensions
+type Error struct {
+	Message    string
+	Code       string
+	Validation []validator.ValidationError
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.Code}
+	if len(e.Validation) > 0 {
+		extensions["validation"] = e.Validation
+	}
+	return extensions
+}
+
+// resolveError maps a service error to the Error returned to the client. Unexpected errors are
+// logged and hidden behind a generic message, as the REST API does
+func resolveError(ctx context.Context, action string, err error) error {
+	var validationErrors service.ValidationErrors
+	var fieldsErr *repository.InvalidFieldsError
+	switch {
+	case errors.As(err, &validationErrors):
+		return &Error{Message: "Validation failed", Code: response.CodeValidationFailed, Validation: validationErrors}
+	case errors.As(err, &fieldsErr):
+		return &Error{Message: fieldsErr.Error(), Code: response.CodeValidationFailed}
+	case errors.Is(err, service.ErrNotFound):
+		return &Error{Message: "Animal not found", Code: "ANIMAL_NOT_FOUND"}
+	case errors.Is(err, service.ErrAlreadyExists), errors.Is(err, database.ErrDuplicateKey):
+		return &Error{Message: "Animal already exists", Code: "ANIMAL_ALREADY_EXISTS"}
+	case errors.Is(err, service.ErrVersionConflict):
+		return &Error{Message: "Animal has been modified since it was read", Code: "ANIMAL_VERSION_CONFLICT"}
+	case errors.Is(err, service.ErrInvalidID):
+		return &Error{Message: "Invalid animal ID", Code: "INVALID_ANIMAL_ID"}
+	case errors.Is(err, service.ErrInvalidData):
+		return &Error{Message: "Invalid animal data", Code: "INVALID_ANIMAL_DATA"}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Message: "Failed to " + action + " animal in time", Code: response.CodeTimeout}
+	default:
+		logging.FromContext(ctx).Error("Failed to "+action+" animal",
+			zap.Error(err),
+			zap.String("request_id", chimiddleware.GetReqID(ctx)))
+		return &Error{Message: "Internal server error", Code: response.CodeInternalError}
+	}
+}
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// Request is the body of a GraphQL request
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler serves GraphQL requests over HTTP POST
+type Handler struct {
+	schema graphqlgo.Schema
+}
+
+// NewHandler creates a new GraphQL handler for schema
+func NewHandler(schema graphqlgo.Schema) *Handler {
+	return &Handler{schema: schema}
+}
+
+// ServeHTTP executes the GraphQL request in the body. Errors raised while resolving fields
+// are returned in the result with status 200, as GraphQL clients expect; only a request that
+// can't be read gets 400
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResult(w, r, http.StatusMethodNotAllowed, errorResult("GraphQL requests must be sent with POST"))
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResult(w, r, http.StatusBadRequest, errorResult("Invalid JSON format"))
+		return
+	}
+	if req.Query == "" {
+		writeResult(w, r, http.StatusBadRequest, errorResult("The query is required"))
+		return
+	}
+
+	result := graphqlgo.Do(graphqlgo.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        r.Context(),
+	})
+	writeResult(w, r, http.StatusOK, result)
+}
+
+// errorResult creates a result holding a single error
+func errorResult(message string) *graphqlgo.Result {
+	return &graphqlgo.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
+
+// writeResult sends a GraphQL result as JSON
+func writeResult(w http.ResponseWriter, r *http.Request, statusCode int, result *graphqlgo.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to write GraphQL response", zap.Error(err))
+	}
+}
@@ -0,0 +1,319 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/internal/service/servicetest"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAnimalService is the shared mock of service.AnimalService
+type MockAnimalService = servicetest.MockAnimalService
+
+// graphQLResponse is the decoded body of a GraphQL response
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// execute sends a GraphQL request to a handler over svc and decodes the response
+func execute(t *testing.T, svc service.AnimalService, query string, variables map[string]interface{}) (int, graphQLResponse) {
+	t.Helper()
+
+	schema, err := NewSchema(svc, nil, nil)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(Request{Query: query, Variables: variables})
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	NewHandler(schema).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+
+	var resp graphQLResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return rr.Code, resp
+}
+
+func TestHandler_Animal(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fluffy := &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat", Age: 3, Version: 2, CreatedAt: createdAt, UpdatedAt: createdAt}
+
+	tests := []struct {
+		name         string
+		serviceError error
+		expectedData string
+		expectedCode string
+	}{
+		{
+			name:         "Found",
+			expectedData: `{"id":"1","name":"Fluffy","species":"Cat","age":3,"version":2,"createdAt":"2024-05-01T12:00:00Z"}`,
+		},
+		{
+			name:         "NotFound",
+			serviceError: service.ErrAnimalNotFound,
+			expectedData: `null`,
+			expectedCode: "ANIMAL_NOT_FOUND",
+		},
+		{
+			name:         "InvalidID",
+			serviceError: service.ErrInvalidAnimalID,
+			expectedData: `null`,
+			expectedCode: "INVALID_ANIMAL_ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockAnimalService)
+			svc.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{Data: fluffy}, tt.serviceError)
+
+			status, resp := execute(t, svc,
+				`query ($id: ID!) { animal(id: $id) { id name species age version createdAt } }`,
+				map[string]interface{}{"id": "1"})
+
+			assert.Equal(t, http.StatusOK, status)
+			assert.JSONEq(t, tt.expectedData, string(resp.Data["animal"]))
+			if tt.expectedCode == "" {
+				assert.Empty(t, resp.Errors)
+			} else if assert.Len(t, resp.Errors, 1) {
+				assert.Equal(t, tt.expectedCode, resp.Errors[0].Extensions["code"])
+			}
+		})
+	}
+}
+
+func TestHandler_Animals(t *testing.T) {
+	svc := new(MockAnimalService)
+	page := service.AnimalCollectionResponse{
+		Data:       []model.Animal{{ID: 1, Name: "Fluffy"}, {ID: 2, Name: "Rex"}},
+		Pagination: &pagination.Params{Page: 2, Limit: 2, TotalItems: 6, TotalPages: 3},
+	}
+	// Sorting reaches the repository through the query parameters, as for the REST list
+	sortedByName := mock.MatchedBy(func(ctx context.Context) bool {
+		params, _ := ctx.Value(repository.KeyQueryParams).(map[string]string)
+		return params["sort"] == "name" && params["direction"] == "desc"
+	})
+	svc.On("GetAllPaginated", sortedByName, pagination.Params{Page: 2, Limit: 2}, repository.Filters{}).Return(page, nil)
+
+	status, resp := execute(t, svc,
+		`{ animals(page: 2, limit: 2, sort: "name", direction: "desc") { items { id name } pageInfo { page limit totalItems totalPages } } }`,
+		nil)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{
+		"items": [{"id":"1","name":"Fluffy"},{"id":"2","name":"Rex"}],
+		"pageInfo": {"page":2,"limit":2,"totalItems":6,"totalPages":3}
+	}`, string(resp.Data["animals"]))
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_Mutations(t *testing.T) {
+	t.Run("CreateAnimal", func(t *testing.T) {
+		svc := new(MockAnimalService)
+		svc.On("Create", mock.Anything, mock.AnythingOfType("*model.Animal")).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.Animal).ID = 7
+		}).Return(nil)
+
+		_, resp := execute(t, svc,
+			`mutation { createAnimal(input: {name: "Fluffy", species: "Cat", age: 3}) { id name age } }`, nil)
+
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"id":"7","name":"Fluffy","age":3}`, string(resp.Data["createAnimal"]))
+	})
+
+	t.Run("CreateAnimalValidationFailed", func(t *testing.T) {
+		svc := new(MockAnimalService)
+
+		_, resp := execute(t, svc,
+			`mutation { createAnimal(input: {name: "F", species: "Cat", age: 500}) { id } }`, nil)
+
+		if assert.Len(t, resp.Errors, 1) {
+			assert.Equal(t, "VALIDATION_FAILED", resp.Errors[0].Extensions["code"])
+			assert.Len(t, resp.Errors[0].Extensions["validation"], 2)
+		}
+		svc.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UpdateAnimalVersionConflict", func(t *testing.T) {
+		svc := new(MockAnimalService)
+		svc.On("Update", mock.Anything, "1", mock.MatchedBy(func(a *model.Animal) bool {
+			return a.Name == "Fluffy" && a.Version == 3
+		})).Return(service.ErrAnimalVersionConflict)
+
+		_, resp := execute(t, svc,
+			`mutation { updateAnimal(id: "1", version: 3, input: {name: "Fluffy", species: "Cat"}) { id } }`, nil)
+
+		if assert.Len(t, resp.Errors, 1) {
+			assert.Equal(t, "ANIMAL_VERSION_CONFLICT", resp.Errors[0].Extensions["code"])
+		}
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("DeleteAnimal", func(t *testing.T) {
+		svc := new(MockAnimalService)
+		svc.On("Delete", mock.Anything, "1").Return(nil)
+
+		_, resp := execute(t, svc, `mutation { deleteAnimal(id: "1") }`, nil)
+
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `true`, string(resp.Data["deleteAnimal"]))
+	})
+
+	t.Run("UnexpectedErrorIsHidden", func(t *testing.T) {
+		svc := new(MockAnimalService)
+		svc.On("Delete", mock.Anything, "1").Return(assert.AnError)
+
+		_, resp := execute(t, svc, `mutation { deleteAnimal(id: "1") }`, nil)
+
+		if assert.Len(t, resp.Errors, 1) {
+			assert.Equal(t, "Internal server error", resp.Errors[0].Message)
+			assert.Equal(t, "INTERNAL_ERROR", resp.Errors[0].Extensions["code"])
+		}
+	})
+}
+
+func TestHandler_MutationScopes(t *testing.T) {
+	tests := []struct {
+		name         string
+		scopes       []string
+		expectedCode string
+	}{
+		{name: "ReadOnlyToken", scopes: []string{"animals:read"}, expectedCode: "FORBIDDEN"},
+		{name: "NoScopes", expectedCode: "FORBIDDEN"},
+		{name: "WriteToken", scopes: []string{"animals:read", "animals:write"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(MockAnimalService)
+			if tt.expectedCode == "" {
+				svc.On("Delete", mock.Anything, "1").Return(nil)
+			}
+			schema, err := NewSchema(svc, []string{"animals:write"}, nil)
+			require.NoError(t, err)
+
+			body := `{"query":"mutation { deleteAnimal(id: \"1\") }"}`
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.KeyUserScopes, tt.scopes))
+			rr := httptest.NewRecorder()
+			NewHandler(schema).ServeHTTP(rr, req)
+
+			var resp graphQLResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tt.expectedCode == "" {
+				assert.Empty(t, resp.Errors)
+				assert.JSONEq(t, `true`, string(resp.Data["deleteAnimal"]))
+			} else if assert.Len(t, resp.Errors, 1) {
+				assert.Equal(t, tt.expectedCode, resp.Errors[0].Extensions["code"])
+			}
+			svc.AssertExpectations(t)
+		})
+	}
+
+	t.Run("QueriesNeedNoWriteScope", func(t *testing.T) {
+		svc := new(MockAnimalService)
+		svc.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{Data: &model.Animal{ID: 1, Name: "Fluffy"}}, nil)
+		schema, err := NewSchema(svc, []string{"animals:write"}, nil)
+		require.NoError(t, err)
+
+		body := `{"query":"{ animal(id: \"1\") { name } }"}`
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.KeyUserScopes, []string{"animals:read"}))
+		rr := httptest.NewRecorder()
+		NewHandler(schema).ServeHTTP(rr, req)
+
+		var resp graphQLResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"name":"Fluffy"}`, string(resp.Data["animal"]))
+	})
+}
+
+func TestHandler_MutationsDuringMaintenance(t *testing.T) {
+	svc := new(MockAnimalService)
+	svc.On("GetByID", mock.Anything, "1").Return(service.AnimalResponse{Data: &model.Animal{ID: 1, Name: "Fluffy"}}, nil)
+	maintenance := middleware.NewMemoryMaintenanceStore()
+	require.NoError(t, maintenance.SetEnabled(context.Background(), true))
+	schema, err := NewSchema(svc, nil, maintenance)
+	require.NoError(t, err)
+
+	send := func(query string) graphQLResponse {
+		body, err := json.Marshal(Request{Query: query})
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		NewHandler(schema).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+
+		var resp graphQLResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp
+	}
+
+	// Mutations are rejected without reaching the service
+	for _, query := range []string{
+		`mutation { createAnimal(input: {name: "Rex", species: "Dog"}) { id } }`,
+		`mutation { updateAnimal(id: "1", input: {name: "Rex", species: "Dog"}) { id } }`,
+		`mutation { deleteAnimal(id: "1") }`,
+	} {
+		resp := send(query)
+		if assert.Len(t, resp.Errors, 1, query) {
+			assert.Equal(t, "SERVICE_UNAVAILABLE", resp.Errors[0].Extensions["code"])
+		}
+	}
+
+	// Queries keep working
+	resp := send(`{ animal(id: "1") { name } }`)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"name":"Fluffy"}`, string(resp.Data["animal"]))
+
+	// Once maintenance ends, mutations go through again
+	require.NoError(t, maintenance.SetEnabled(context.Background(), false))
+	svc.On("Delete", mock.Anything, "1").Return(nil)
+	resp = send(`mutation { deleteAnimal(id: "1") }`)
+	assert.Empty(t, resp.Errors)
+	svc.AssertExpectations(t)
+}
+
+func TestHandler_InvalidRequests(t *testing.T) {
+	schema, err := NewSchema(new(MockAnimalService), nil, nil)
+	require.NoError(t, err)
+	handler := NewHandler(schema)
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+	}{
+		{name: "NotPost", method: http.MethodGet, expectedStatus: http.StatusMethodNotAllowed},
+		{name: "InvalidJSON", method: http.MethodPost, body: `{"query":`, expectedStatus: http.StatusBadRequest},
+		{name: "NoQuery", method: http.MethodPost, body: `{}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tt.method, "/graphql", bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			var resp graphQLResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Len(t, resp.Errors, 1)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	graphqlgo "github.com/graphql-go/graphql"
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/linkeunid/go-api/pkg/middleware"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/linkeunid/go-api/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// animalType is the GraphQL type of model.Animal
+var animalType = graphqlgo.NewObject(graphqlgo.ObjectConfig{
+	Name: "Animal",
+	Fields: graphqlgo.Fields{
+		"id": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(graphqlgo.ID),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				return strconv.FormatUint(p.Source.(*model.Animal).ID, 10), nil
+			},
+		},
+		"name":        &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"species":     &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"age":         &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"description": &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"version":     &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"createdAt": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(graphqlgo.DateTime),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Animal).CreatedAt, nil
+			},
+		},
+		"updatedAt": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(graphqlgo.DateTime),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				return p.Source.(*model.Animal).UpdatedAt, nil
+			},
+		},
+	},
+})
+
+// pageInfoType is the GraphQL type of pagination.Params
+var pageInfoType = graphqlgo.NewObject(graphqlgo.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphqlgo.Fields{
+		"page":  &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"limit": &graphqlgo.Field{Type: graphqlgo.NewNonNull(graphqlgo.Int)},
+		"totalItems": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(graphqlgo.Int),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				return p.Source.(*pagination.Params).TotalItems, nil
+			},
+		},
+		"totalPages": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(graphqlgo.Int),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				return p.Source.(*pagination.Params).TotalPages, nil
+			},
+		},
+	},
+})
+
+// animalPageType is a page of animals, the GraphQL form of service.AnimalCollectionResponse
+var animalPageType = graphqlgo.NewObject(graphqlgo.ObjectConfig{
+	Name: "AnimalPage",
+	Fields: graphqlgo.Fields{
+		"items": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(graphqlgo.NewList(graphqlgo.NewNonNull(animalType))),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				page := p.Source.(service.AnimalCollectionResponse)
+				items := make([]*model.Animal, len(page.Data))
+				for i := range page.Data {
+					items[i] = &page.Data[i]
+				}
+				return items, nil
+			},
+		},
+		"pageInfo": &graphqlgo.Field{
+			Type: graphqlgo.NewNonNull(pageInfoType),
+			Resolve: func(p graphqlgo.ResolveParams) (interface{}, error) {
+				return p.Source.(service.AnimalCollectionResponse).Pagination, nil
+			},
+		},
+	},
+})
+
+// animalInputType holds the fields accepted by createAnimal and updateAnimal
+var animalInputType = graphqlgo.NewInputObject(graphqlgo.InputObjectConfig{
+	Name: "AnimalInput",
+	Fields: graphqlgo.InputObjectConfigFieldMap{
+		"name":        &graphqlgo.InputObjectFieldConfig{Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"species":     &graphqlgo.InputObjectFieldConfig{Type: graphqlgo.NewNonNull(graphqlgo.String)},
+		"age":         &graphqlgo.InputObjectFieldConfig{Type: graphqlgo.Int, DefaultValue: 0},
+		"description": &graphqlgo.InputObjectFieldConfig{Type: graphqlgo.String, DefaultValue: ""},
+	},
+})
+
+// resolver answers GraphQL fields by delegating to the animal service, so the GraphQL and
+// REST APIs share their business rules
+type resolver struct {
+	animals     service.AnimalService
+	writeScopes []string
+	maintenance middleware.MaintenanceStore
+}
+
+// NewSchema builds the GraphQL schema over the animal service. Mutations require every one of
+// writeScopes, like REST writes do; pass none when authentication is disabled. They are also
+// rejected while maintenance mode is on in maintenance, which may be nil. Method-based
+// middleware can't tell them apart from queries, since both are POSTs
+func NewSchema(animals service.AnimalService, writeScopes []string, maintenance middleware.MaintenanceStore) (graphqlgo.Schema, error) {
+	res := &resolver{animals: animals, writeScopes: writeScopes, maintenance: maintenance}
+
+	query := graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: "Query",
+		Fields: graphqlgo.Fields{
+			"animal": &graphqlgo.Field{
+				Type:        animalType,
+				Description: "An animal by ID",
+				Args: graphqlgo.FieldConfigArgument{
+					"id": &graphqlgo.ArgumentConfig{Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+				},
+				Resolve: res.animal,
+			},
+			"animals": &graphqlgo.Field{
+				Type:        graphqlgo.NewNonNull(animalPageType),
+				Description: "A page of animals, with the same defaults and sort fields as GET /api/v1/animals",
+				Args: graphqlgo.FieldConfigArgument{
+					"page":      &graphqlgo.ArgumentConfig{Type: graphqlgo.Int, DefaultValue: 1},
+					"limit":     &graphqlgo.ArgumentConfig{Type: graphqlgo.Int},
+					"sort":      &graphqlgo.ArgumentConfig{Type: graphqlgo.String},
+					"direction": &graphqlgo.ArgumentConfig{Type: graphqlgo.String, Description: "asc (default) or desc"},
+				},
+				Resolve: res.animalPage,
+			},
+		},
+	})
+
+	mutation := graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphqlgo.Fields{
+			"createAnimal": &graphqlgo.Field{
+				Type: graphqlgo.NewNonNull(animalType),
+				Args: graphqlgo.FieldConfigArgument{
+					"input": &graphqlgo.ArgumentConfig{Type: graphqlgo.NewNonNull(animalInputType)},
+				},
+				Resolve: res.guard(res.createAnimal),
+			},
+			"updateAnimal": &graphqlgo.Field{
+				Type: graphqlgo.NewNonNull(animalType),
+				Description: "Replace an animal. When version is given the update is rejected " +
+					"if the animal has changed since that version was read",
+				Args: graphqlgo.FieldConfigArgument{
+					"id":      &graphqlgo.ArgumentConfig{Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+					"input":   &graphqlgo.ArgumentConfig{Type: graphqlgo.NewNonNull(animalInputType)},
+					"version": &graphqlgo.ArgumentConfig{Type: graphqlgo.Int},
+				},
+				Resolve: res.guard(res.updateAnimal),
+			},
+			"deleteAnimal": &graphqlgo.Field{
+				Type: graphqlgo.NewNonNull(graphqlgo.Boolean),
+				Args: graphqlgo.FieldConfigArgument{
+					"id": &graphqlgo.ArgumentConfig{Type: graphqlgo.NewNonNull(graphqlgo.ID)},
+				},
+				Resolve: res.guard(res.deleteAnimal),
+			},
+		},
+	})
+
+	return graphqlgo.NewSchema(graphqlgo.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// guard wraps the resolver of a mutation with the checks REST writes get from middleware
+func (r *resolver) guard(resolve graphqlgo.FieldResolveFn) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		if len(r.writeScopes) > 0 && !middleware.HasScopes(p.Context, r.writeScopes...) {
+			return nil, &Error{Message: "Insufficient scope", Code: response.CodeForbidden}
+		}
+		if r.maintenance != nil {
+			enabled, err := r.maintenance.Enabled(p.Context)
+			if err != nil {
+				logging.FromContext(p.Context).Warn("Maintenance store unavailable", zap.Error(err))
+			}
+			if enabled {
+				return nil, &Error{Message: "The API is in maintenance mode; changes are disabled until it ends", Code: response.CodeUnavailable}
+			}
+		}
+		return resolve(p)
+	}
+}
+
+// animal resolves Query.animal
+func (r *resolver) animal(p graphqlgo.ResolveParams) (interface{}, error) {
+	result, err := r.animals.GetByID(p.Context, p.Args["id"].(string))
+	if err != nil {
+		return nil, resolveError(p.Context, "get", err)
+	}
+	return result.Data, nil
+}
+
+// animalPage resolves Query.animals
+func (r *resolver) animalPage(p graphqlgo.ResolveParams) (interface{}, error) {
+	page, _ := p.Args["page"].(int)
+	limit, _ := p.Args["limit"].(int)
+	params := pagination.ParamsFor(page, limit)
+
+	// The repository reads sorting from the query parameters, as it does for the REST list
+	sort, _ := p.Args["sort"].(string)
+	direction, _ := p.Args["direction"].(string)
+	ctx := context.WithValue(p.Context, repository.KeyQueryParams, map[string]string{
+		"page":      strconv.Itoa(params.Page),
+		"limit":     strconv.Itoa(params.Limit),
+		"sort":      sort,
+		"direction": direction,
+	})
+
+	result, err := r.animals.GetAllPaginated(ctx, params, repository.Filters{})
+	if err != nil {
+		return nil, resolveError(p.Context, "list", err)
+	}
+	return result, nil
+}
+
+// createAnimal resolves Mutation.createAnimal
+func (r *resolver) createAnimal(p graphqlgo.ResolveParams) (interface{}, error) {
+	animal, err := animalFromInput(p.Args["input"])
+	if err != nil {
+		return nil, resolveError(p.Context, "create", err)
+	}
+
+	if err := r.animals.Create(p.Context, animal); err != nil {
+		return nil, resolveError(p.Context, "create", err)
+	}
+	return animal, nil
+}
+
+// updateAnimal resolves Mutation.updateAnimal
+func (r *resolver) updateAnimal(p graphqlgo.ResolveParams) (interface{}, error) {
+	animal, err := animalFromInput(p.Args["input"])
+	if err != nil {
+		return nil, resolveError(p.Context, "update", err)
+	}
+	if version, ok := p.Args["version"].(int); ok {
+		if version < 0 {
+			return nil, resolveError(p.Context, "update", fmt.Errorf("%w: version must not be negative", service.ErrInvalidAnimalData))
+		}
+		animal.Version = uint(version)
+	}
+
+	if err := r.animals.Update(p.Context, p.Args["id"].(string), animal); err != nil {
+		return nil, resolveError(p.Context, "update", err)
+	}
+	return animal, nil
+}
+
+// deleteAnimal resolves Mutation.deleteAnimal
+func (r *resolver) deleteAnimal(p graphqlgo.ResolveParams) (interface{}, error) {
+	if err := r.animals.Delete(p.Context, p.Args["id"].(string)); err != nil {
+		return nil, resolveError(p.Context, "delete", err)
+	}
+	return true, nil
+}
+
+// animalFromInput converts an AnimalInput argument into an animal, validated with the
+// same rules as REST request bodies
+func animalFromInput(input interface{}) (*model.Animal, error) {
+	fields, _ := input.(map[string]interface{})
+	animal := &model.Animal{}
+	animal.Name, _ = fields["name"].(string)
+	animal.Species, _ = fields["species"].(string)
+	animal.Age, _ = fields["age"].(int)
+	animal.Description, _ = fields["description"].(string)
+
+	if validationErrors := validator.Validate(animal); len(validationErrors) > 0 {
+		return nil, service.ValidationErrors(validationErrors)
+	}
+	return animal, nil
+}
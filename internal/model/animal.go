@@ -8,13 +8,14 @@ import (
 
 // Animal represents an animal entity
 type Animal struct {
-	ID          uint64    `json:"id" gorm:"primaryKey;type:bigint unsigned;autoIncrement"`
-	Name        string    `json:"name" validate:"required,min=2,max=100,animalname" gorm:"type:varchar(100);not null;index:idx_animal_name" example:"Fluffy"`
-	Species     string    `json:"species" validate:"required,min=2,max=100" gorm:"type:varchar(100);not null;index:idx_animal_species" example:"Cat"`
-	Age         int       `json:"age" validate:"gte=0,lte=200" gorm:"type:int;index:idx_animal_age" example:"3"`
-	Description string    `json:"description" validate:"omitempty,max=1000" gorm:"type:text" example:"A friendly cat with white fur"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_animal_created_at"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime;index:idx_animal_updated_at"`
+	ID          uint64    `json:"id" xml:"id" gorm:"primaryKey;type:bigint unsigned;autoIncrement"`
+	Name        string    `json:"name" xml:"name" validate:"required,min=2,max=100,animalname" gorm:"type:varchar(100);not null;index:idx_animal_name" example:"Fluffy"`
+	Species     string    `json:"species" xml:"species" validate:"required,min=2,max=100,species" gorm:"type:varchar(100);not null;index:idx_animal_species" example:"Cat"`
+	Age         int       `json:"age" xml:"age" validate:"gte=0,lte=200" gorm:"type:int;index:idx_animal_age" example:"3"`
+	Description string    `json:"description" xml:"description" validate:"omitempty,max=1000" gorm:"type:text" example:"A friendly cat with white fur"`
+	Version     uint      `json:"version" xml:"version" gorm:"type:int unsigned;not null;default:1" example:"1"`
+	CreatedAt   time.Time `json:"created_at" xml:"created_at" gorm:"autoCreateTime;index:idx_animal_created_at"`
+	UpdatedAt   time.Time `json:"updated_at" xml:"updated_at" gorm:"autoUpdateTime;index:idx_animal_updated_at"`
 }
 
 // AnimalCreateRequest represents a request body example for creating a new animal
@@ -27,12 +28,25 @@ type AnimalCreateRequest struct {
 }
 
 // AnimalUpdateRequest represents a request body example for updating an animal
+// Version is the version the client last read; the update is rejected with 409
+// if the animal has changed since. Omit it to overwrite unconditionally
 // @name AnimalUpdateRequest
 type AnimalUpdateRequest struct {
 	Name        string `json:"name" example:"Fluffy"`
 	Species     string `json:"species" example:"Cat"`
 	Age         int    `json:"age" example:"3"`
 	Description string `json:"description" example:"A friendly cat with white fur"`
+	Version     uint   `json:"version,omitempty" example:"1"`
+}
+
+// AnimalPatchRequest represents a request body example for partially updating an animal
+// All fields are optional; omitted fields are left unchanged
+// @name AnimalPatchRequest
+type AnimalPatchRequest struct {
+	Name        string `json:"name,omitempty" example:"Fluffy"`
+	Species     string `json:"species,omitempty" example:"Cat"`
+	Age         int    `json:"age,omitempty" example:"4"`
+	Description string `json:"description,omitempty" example:"A friendly cat with white fur"`
 }
 
 // TableName returns the table name for the Animal model
@@ -9,14 +9,14 @@ import (
 
 // Flower represents a flower entity
 type Flower struct {
-	ID          uint64    `json:"id" gorm:"primaryKey;type:bigint unsigned;autoIncrement"`
-	Name        string    `json:"name" validate:"required,min=2,max=100" gorm:"type:varchar(100);not null;index:idx_flower_name" example:"Rose"`
-	Species     string    `json:"species" validate:"required,min=2,max=100" gorm:"type:varchar(100);not null;index:idx_flower_species" example:"Rosa"`
-	Color       string    `json:"color" validate:"required,min=2,max=50" gorm:"type:varchar(50);not null;index:idx_flower_color" example:"Red"`
-	Description string    `json:"description" validate:"omitempty,max=1000" gorm:"type:text" example:"A beautiful red rose with thorny stems"`
-	Seasonal    bool      `json:"seasonal" gorm:"type:boolean;default:false" example:"true"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_flower_created_at"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime;index:idx_flower_updated_at"`
+	ID          uint64    `json:"id" xml:"id" gorm:"primaryKey;type:bigint unsigned;autoIncrement"`
+	Name        string    `json:"name" xml:"name" validate:"required,min=2,max=100" gorm:"type:varchar(100);not null;index:idx_flower_name" example:"Rose"`
+	Species     string    `json:"species" xml:"species" validate:"required,min=2,max=100" gorm:"type:varchar(100);not null;index:idx_flower_species" example:"Rosa"`
+	Color       string    `json:"color" xml:"color" validate:"required,min=2,max=50" gorm:"type:varchar(50);not null;index:idx_flower_color" example:"Red"`
+	Description string    `json:"description" xml:"description" validate:"omitempty,max=1000" gorm:"type:text" example:"A beautiful red rose with thorny stems"`
+	Seasonal    bool      `json:"seasonal" xml:"seasonal" gorm:"type:boolean;default:false" example:"true"`
+	CreatedAt   time.Time `json:"created_at" xml:"created_at" gorm:"autoCreateTime;index:idx_flower_created_at"`
+	UpdatedAt   time.Time `json:"updated_at" xml:"updated_at" gorm:"autoUpdateTime;index:idx_flower_updated_at"`
 }
 
 // FlowerCreateRequest represents a request body example for creating a new flower
@@ -39,6 +39,17 @@ type FlowerUpdateRequest struct {
 	Seasonal    bool   `json:"seasonal" example:"true"`
 }
 
+// FlowerPatchRequest represents a request body example for partially updating a flower
+// All fields are optional; omitted fields are left unchanged
+// @name FlowerPatchRequest
+type FlowerPatchRequest struct {
+	Name        string `json:"name,omitempty" example:"Rose"`
+	Species     string `json:"species,omitempty" example:"Rosa"`
+	Color       string `json:"color,omitempty" example:"White"`
+	Description string `json:"description,omitempty" example:"A beautiful red rose with thorny stems"`
+	Seasonal    bool   `json:"seasonal,omitempty" example:"false"`
+}
+
 // TableName returns the table name for the Flower model
 func (Flower) TableName() string {
 	return "flowers"
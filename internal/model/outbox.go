@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// OutboxEvent is a change event stored in the same transaction as the write it describes, so it
+// is published even if the process stops right after the commit
+type OutboxEvent struct {
+	ID           uint64     `json:"id" gorm:"primaryKey;type:bigint unsigned;autoIncrement"`
+	Topic        string     `json:"topic" gorm:"type:varchar(100);not null"`
+	EventType    string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	RecordID     string     `json:"record_id" gorm:"type:varchar(100);not null"`
+	Payload      string     `json:"payload" gorm:"type:text"` // JSON-encoded record carried by the event; empty when it has none
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	DispatchedAt *time.Time `json:"dispatched_at" gorm:"index:idx_outbox_dispatched_at"` // Set once the event has been published
+}
+
+// TableName returns the table name for the OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
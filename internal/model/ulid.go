@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/linkeunid/go-api/pkg/util"
+	"gorm.io/gorm"
+)
+
+// WithULID gives a model a ULID primary key, stored as char(26), instead of an
+// auto-increment integer. Embed it in place of the model's ID field
+type WithULID struct {
+	ID string `json:"id" xml:"id" gorm:"primaryKey;type:char(26)" example:"01ARZ3NDEKTSV4RRFFQ69G5FAV"`
+}
+
+// BeforeCreate assigns a new ULID unless one is already set
+// A model embedding WithULID that defines its own BeforeCreate must call this one
+func (m *WithULID) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = util.NewULID()
+	}
+	return nil
+}
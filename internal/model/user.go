@@ -0,0 +1,28 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// User represents an account that can log in to obtain tokens
+type User struct {
+	ID           uint64    `json:"id" gorm:"primaryKey;type:bigint unsigned;autoIncrement"`
+	Email        string    `json:"email" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_email" example:"john@example.com"`
+	PasswordHash string    `json:"-" gorm:"type:varchar(255);not null"` // bcrypt hash, never serialized
+	Role         string    `json:"role" gorm:"type:varchar(50);not null;default:user" example:"user"`
+	Scopes       string    `json:"scopes" gorm:"type:varchar(500);not null;default:''" example:"animals:read animals:write"` // Space-separated scopes granted to the user's tokens
+	TokenVersion uint64    `json:"-" gorm:"type:bigint unsigned;not null;default:0"`                                         // Bumped to revoke the user's refresh tokens
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for the User model
+func (User) TableName() string {
+	return "users"
+}
+
+// ScopeList returns the scopes granted to the user
+func (u User) ScopeList() []string {
+	return strings.Fields(u.Scopes)
+}
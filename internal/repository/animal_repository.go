@@ -4,14 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/linkeunid/go-api/internal/model"
 	"github.com/linkeunid/go-api/pkg/cache"
 	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
 	"github.com/linkeunid/go-api/pkg/pagination"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CachedPaginatedResult represents both data and pagination info for caching
@@ -22,11 +25,10 @@ type CachedPaginatedResult struct {
 
 // CacheInfo holds information about cache usage for a query
 type CacheInfo struct {
-	Status   database.CacheStatus `json:"status"`   // hit, miss, or disabled
-	Key      string               `json:"key"`      // cache key used
-	Enabled  bool                 `json:"enabled"`  // whether caching is enabled
-	TTL      string               `json:"ttl"`      // time-to-live of the cache
-	UseCount int                  `json:"useCount"` // number of times used (not implemented yet)
+	Status  database.CacheStatus `json:"status" xml:"status"`   // hit, miss, or disabled
+	Key     string               `json:"key" xml:"key"`         // cache key used
+	Enabled bool                 `json:"enabled" xml:"enabled"` // whether caching is enabled
+	TTL     string               `json:"ttl" xml:"ttl"`         // time-to-live of the cache
 }
 
 // AnimalResult wraps the animal data with cache information
@@ -46,75 +48,72 @@ type AnimalCollectionResult struct {
 type ContextKey string
 
 const (
-	// KeyCustomCacheKey is the context key for custom cache keys
-	KeyCustomCacheKey ContextKey = "customCacheKey"
 	// KeyQueryParams is the context key for query parameters
 	KeyQueryParams ContextKey = "queryParams"
 )
 
+// AnimalEventsTopic is the topic animal changes are published on
+const AnimalEventsTopic = "animals"
+
 // AnimalRepository defines the interface for animal data access
 type AnimalRepository interface {
+	// FindAll returns every animal, or ErrTooManyResults if there are more than MaxFindAllResults
 	FindAll(ctx context.Context) (AnimalCollectionResult, error)
-	FindAllPaginated(ctx context.Context, params pagination.Params) (AnimalCollectionResult, error)
+	FindAllPaginated(ctx context.Context, params pagination.Params, filters Filters) (AnimalCollectionResult, error)
+	// Count returns the number of animals matching filters, applied as in FindAllPaginated
+	Count(ctx context.Context, filters Filters) (int64, error)
 	FindByID(ctx context.Context, id uint64) (AnimalResult, error)
 	Create(ctx context.Context, animal *model.Animal) error
+	// CreateBatch inserts animals in a single transaction; either all of them are created or none
+	CreateBatch(ctx context.Context, animals []model.Animal) error
 	Update(ctx context.Context, animal *model.Animal) error
+	// Transaction runs fn in a database transaction; caches for rows written with the
+	// *Tx methods are invalidated after it commits
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	// FindByIDForUpdate reads an animal inside tx and locks its row until tx ends; returns nil if not found
+	FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Animal, error)
+	// UpdateTx updates an animal inside tx if its version is unchanged; returns ErrVersionConflict otherwise
+	UpdateTx(tx *gorm.DB, animal *model.Animal) error
+	Patch(ctx context.Context, id uint64, fields map[string]interface{}) error
 	Delete(ctx context.Context, id uint64) error
+	// FindInBatches streams every animal matching filters, sorted like FindAllPaginated,
+	// to fn batchSize rows at a time without caching. It stops at the first error fn returns
+	FindInBatches(ctx context.Context, sort, direction string, filters Filters, batchSize int, fn func(batch []model.Animal) error) error
 }
 
 // mysqlAnimalRepository implements AnimalRepository using MySQL with Redis cache
 type mysqlAnimalRepository struct {
-	db     database.Database
-	logger *zap.Logger
+	db        database.Database
+	logger    *zap.Logger
+	publisher events.Publisher // Receives an event for each write; nil disables them
 	// Store TTL settings
 	defaultTTL   string
 	paginatedTTL string
+	countTTL     time.Duration // How long counts are cached; 0 disables
 }
 
-// NewAnimalRepository creates a new animal repository
-func NewAnimalRepository(db database.Database, logger *zap.Logger) AnimalRepository {
-	// Get the default TTL from configuration or use sensible defaults
-	// The actual TTL is applied in the CachedFind method
-	defaultTTL := "30m"
-	paginatedTTL := "5m"
-
-	// If db has config, get TTL values from it
-	if cacheManager := db.GetCacheManager(); cacheManager != nil {
-		if redisMgr, ok := cacheManager.(*database.RedisCacheManager); ok && redisMgr.GetConfig() != nil {
-			cfg := redisMgr.GetConfig()
-
-			// Use the REDIS_CACHE_TTL from config (set to 15m in .env)
-			defaultTTL = cfg.Redis.CacheTTL.String()
-
-			// Use the REDIS_PAGINATED_TTL from config if defined, otherwise default to 1/3 of CacheTTL
-			if cfg.Redis.PaginatedTTL > 0 {
-				paginatedTTL = cfg.Redis.PaginatedTTL.String()
-				logger.Info("Using configured paginated TTL",
-					zap.String("paginatedTTL", paginatedTTL))
-			} else {
-				// Otherwise use a fraction of the default TTL (1/3)
-				paginatedTTL = (cfg.Redis.CacheTTL / 3).String()
-				logger.Info("Using calculated paginated TTL (1/3 of default TTL)",
-					zap.String("defaultTTL", defaultTTL),
-					zap.String("paginatedTTL", paginatedTTL))
-			}
-		}
-	}
+// NewAnimalRepository creates a new animal repository. Successful writes are published on
+// AnimalEventsTopic when publisher isn't nil; a TxPublisher receives them inside the write's transaction
+func NewAnimalRepository(db database.Database, logger *zap.Logger, publisher events.Publisher) AnimalRepository {
+	// Resolve TTL settings used for cache info reporting and paginated caching
+	defaultTTL, paginatedTTL := resolveCacheTTLs(db, logger)
 
 	return &mysqlAnimalRepository{
 		db:           db,
 		logger:       logger,
+		publisher:    publisher,
 		defaultTTL:   defaultTTL,
 		paginatedTTL: paginatedTTL,
+		countTTL:     resolveCountTTL(db),
 	}
 }
 
-// createContextWithCacheKey creates a new context with a cache key
+// createContextWithCacheKey creates a new context with a cache key and a recorder for its cache status
 func (r *mysqlAnimalRepository) createContextWithCacheKey(ctx context.Context, key string) context.Context {
-	return context.WithValue(ctx, KeyCustomCacheKey, key)
+	return database.WithCacheStatus(context.WithValue(ctx, database.ContextKeyCustomCacheKey, key))
 }
 
-// createCacheInfo creates a CacheInfo struct from context and TTL
+// createCacheInfo creates a CacheInfo struct from the call-scoped context and TTL
 func (r *mysqlAnimalRepository) createCacheInfo(ctx context.Context, ttl string) *CacheInfo {
 	status, key := r.db.GetCacheStatus(ctx)
 	return &CacheInfo{
@@ -138,26 +137,70 @@ func (r *mysqlAnimalRepository) invalidateCache(ctx context.Context, itemID uint
 		if err := cacheManager.GetCache().Delete(ctx, cacheKey); err != nil {
 			r.logger.Warn("Failed to invalidate animal cache", zap.Uint64("id", itemID), zap.Error(err))
 		}
+		fieldsPattern := cache.GenerateItemFieldsPattern("animals", itemID)
+		if err := cacheManager.GetCache().Delete(ctx, fieldsPattern); err != nil {
+			r.logger.Warn("Failed to invalidate animal field selection cache", zap.Uint64("id", itemID), zap.Error(err))
+		}
 	}
 
 	// Invalidate collection cache if requested
 	if invalidateCollection {
-		listKey := fmt.Sprintf("%s:animals:list", cache.CurrentVersion)
-		if err := cacheManager.GetCache().Delete(ctx, listKey+"*"); err != nil {
+		listPattern := cache.GenerateListPattern("animals")
+		if err := cacheManager.GetCache().Delete(ctx, listPattern); err != nil {
 			r.logger.Warn("Failed to invalidate animal collection cache", zap.Error(err))
 		}
 	}
 }
 
-// FindAll retrieves all animals with caching
+// animalEvent describes a change to the animal with id
+func animalEvent(eventType string, id uint64, data interface{}) events.Event {
+	return events.Event{Type: eventType, ID: strconv.FormatUint(id, 10), Data: data}
+}
+
+// publish sends event to subscribers. A failure is only logged: the write it describes has
+// already succeeded
+func (r *mysqlAnimalRepository) publish(ctx context.Context, event events.Event) {
+	if r.publisher == nil {
+		return
+	}
+
+	if err := r.publisher.Publish(ctx, AnimalEventsTopic, event); err != nil {
+		r.logger.Warn("Failed to publish animal event", zap.String("type", event.Type), zap.String("id", event.ID), zap.Error(err))
+	}
+}
+
+// write runs fn, which performs a write and returns the event describing it. With a TxPublisher
+// fn runs in a transaction that also stores the event; otherwise the event is published once fn
+// succeeds
+func (r *mysqlAnimalRepository) write(ctx context.Context, fn func(tx *gorm.DB) (events.Event, error)) error {
+	txPublisher, ok := r.publisher.(TxPublisher)
+	if !ok {
+		event, err := fn(r.db.GetDB().WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		r.publish(ctx, event)
+		return nil
+	}
+
+	return r.db.Transaction(ctx, func(tx *gorm.DB) error {
+		event, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		return txPublisher.PublishTx(tx, AnimalEventsTopic, event)
+	})
+}
+
+// FindAll retrieves all animals with caching, up to MaxFindAllResults
 func (r *mysqlAnimalRepository) FindAll(ctx context.Context) (AnimalCollectionResult, error) {
 	var animals []model.Animal
 
-	// Build the query
-	query := r.db.GetDB().Order("created_at DESC")
+	// Build the query, reading one row past the cap to detect larger tables
+	query := r.db.GetDB().WithContext(ctx).Order("created_at DESC").Limit(MaxFindAllResults + 1)
 
 	// Create a custom cache key
-	cacheKey := cache.GenerateListKey("animals", 1, 0, "created_at", "desc")
+	cacheKey := cache.GenerateListKey("animals", 1, MaxFindAllResults+1, "created_at", "desc", nil, nil, nil)
 
 	// Add the cache key to the context
 	ctxWithKey := r.createContextWithCacheKey(ctx, cacheKey)
@@ -175,14 +218,18 @@ func (r *mysqlAnimalRepository) FindAll(ctx context.Context) (AnimalCollectionRe
 
 	if err != nil {
 		r.logger.Error("Failed to retrieve animals", zap.Error(err))
-		return result, err
+		return result, contextError(ctx, err)
+	}
+
+	if len(animals) > MaxFindAllResults {
+		return AnimalCollectionResult{}, ErrTooManyResults
 	}
 
 	return result, nil
 }
 
-// FindAllPaginated retrieves paginated animals
-func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pagination.Params) (AnimalCollectionResult, error) {
+// FindAllPaginated retrieves paginated animals matching the given filters
+func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pagination.Params, filters Filters) (AnimalCollectionResult, error) {
 	var animals []model.Animal
 	result := AnimalCollectionResult{
 		Pagination: &params,
@@ -208,8 +255,7 @@ func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pag
 
 	if field, exists := queryParams["sort"]; exists && field != "" {
 		// Basic sanitization to prevent SQL injection
-		allowedFields := map[string]bool{"id": true, "name": true, "species": true, "age": true, "created_at": true, "updated_at": true}
-		if allowedFields[field] {
+		if animalSortableFields[field] {
 			sortField = field
 		}
 	}
@@ -220,6 +266,21 @@ func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pag
 		}
 	}
 
+	// Only keep filters on whitelisted columns
+	activeFilters := filters.sanitize(AnimalFilterFields)
+
+	// Reject selections of unknown columns rather than silently returning full records
+	fields, err := selectedFields(ctx, animalSelectableFields)
+	if err != nil {
+		return result, err
+	}
+
+	// Reject includes of associations that aren't whitelisted
+	preloads, err := selectedPreloads(ctx, animalIncludableAssociations)
+	if err != nil {
+		return result, err
+	}
+
 	// Generate a structured cache key using our key generator
 	cacheKey := cache.GenerateListKey(
 		"animals",
@@ -227,6 +288,9 @@ func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pag
 		params.Limit,
 		sortField,
 		sortDirection,
+		activeFilters,
+		fields,
+		preloads,
 	)
 
 	// Check if we have this query in cache
@@ -266,18 +330,14 @@ func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pag
 
 	// If cache miss or disabled, we need to query the database
 	if !cacheHit {
-		// Important: Apply our pagination directly in the query
-		baseQuery := r.db.GetDB().Model(&model.Animal{})
+		// Build the filtered base query
+		baseQuery := activeFilters.apply(r.db.GetDB().WithContext(ctx).Model(&model.Animal{}))
 
-		// Apply sorting
-		orderClause := fmt.Sprintf("%s %s", sortField, sortDirection)
-		baseQuery = baseQuery.Order(orderClause)
-
-		// Count total rows
+		// Count total rows matching the filters
 		var totalRows int64
-		if err := baseQuery.Count(&totalRows).Error; err != nil {
+		if err := baseQuery.Session(&gorm.Session{}).Count(&totalRows).Error; err != nil {
 			r.logger.Error("Failed to count animals", zap.Error(err))
-			return result, err
+			return result, contextError(ctx, err)
 		}
 
 		// Calculate pagination metadata
@@ -285,18 +345,15 @@ func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pag
 		result.Pagination = &params
 
 		// Calculate offset
-		offset := (params.Page - 1) * params.Limit
-
-		// Construct SQL query for pagination
-		sqlQuery := fmt.Sprintf("SELECT * FROM animals ORDER BY %s %s LIMIT %d OFFSET %d",
-			sortField, sortDirection, params.Limit, offset)
+		offset := params.GetOffset()
 
-		// Use direct SQL parameters for pagination to ensure they are applied
-		err := r.db.GetDB().Raw(sqlQuery).Scan(&animals).Error
+		// Apply sorting and pagination
+		orderClause := fmt.Sprintf("%s %s", sortField, sortDirection)
+		err := preloads.apply(fields.apply(baseQuery), animalIncludableAssociations).Order(orderClause).Limit(params.Limit).Offset(offset).Find(&animals).Error
 
 		if err != nil {
 			r.logger.Error("Failed to retrieve paginated animals", zap.Error(err))
-			return result, err
+			return result, contextError(ctx, err)
 		}
 
 		// Log the actual number of animals returned
@@ -349,6 +406,20 @@ func (r *mysqlAnimalRepository) FindAllPaginated(ctx context.Context, params pag
 	return result, nil
 }
 
+// Count returns the number of animals matching filters. Counts are cached for REDIS_COUNT_TTL
+// and dropped with the collection cache whenever an animal is written
+func (r *mysqlAnimalRepository) Count(ctx context.Context, filters Filters) (int64, error) {
+	activeFilters := filters.sanitize(AnimalFilterFields)
+	query := activeFilters.apply(r.db.GetDB().WithContext(ctx).Model(&model.Animal{}))
+
+	count, err := cachedCount(ctx, r.db, r.logger, cache.GenerateCountKey("animals", activeFilters), r.countTTL, query)
+	if err != nil {
+		r.logger.Error("Failed to count animals", zap.Error(err))
+		return 0, contextError(ctx, err)
+	}
+	return count, nil
+}
+
 // FindByID retrieves an animal by ID with caching
 func (r *mysqlAnimalRepository) FindByID(ctx context.Context, id uint64) (AnimalResult, error) {
 	if id == 0 {
@@ -358,17 +429,22 @@ func (r *mysqlAnimalRepository) FindByID(ctx context.Context, id uint64) (Animal
 	var animal model.Animal
 	result := AnimalResult{}
 
+	fields, err := selectedFields(ctx, animalSelectableFields)
+	if err != nil {
+		return result, err
+	}
+
 	// Build the query
-	query := r.db.GetDB().Where("id = ?", id)
+	query := fields.apply(r.db.GetDB().WithContext(ctx).Where("id = ?", id))
 
-	// Generate a structured cache key for the item
-	cacheKey := cache.GenerateItemKey("animals", id)
+	// Generate a structured cache key for the item and field selection
+	cacheKey := cache.GenerateItemFieldsKey("animals", id, fields)
 
 	// Add the cache key to the context
 	ctxWithKey := r.createContextWithCacheKey(ctx, cacheKey)
 
 	// Use cached find
-	err := r.db.CachedFind(ctxWithKey, query, &animal)
+	err = r.db.CachedFind(ctxWithKey, query, &animal)
 
 	// Get cache status
 	cacheInfo := r.createCacheInfo(ctxWithKey, r.defaultTTL)
@@ -379,10 +455,12 @@ func (r *mysqlAnimalRepository) FindByID(ctx context.Context, id uint64) (Animal
 			return result, nil // Return empty result for not found
 		}
 		r.logger.Error("Failed to retrieve animal by ID", zap.Uint64("id", id), zap.Error(err))
-		return result, err
+		return result, contextError(ctx, err)
 	}
 
-	// Check if the record was actually found (GORM might not return ErrRecordNotFound)
+	// Check if the record was actually found (GORM might not return ErrRecordNotFound); an empty
+	// record is also what CachedFind keeps for REDIS_NEGATIVE_TTL after a miss, so repeated
+	// lookups of a missing ID are answered from the cache
 	if animal.ID == 0 {
 		return result, nil // Return empty result for not found
 	}
@@ -394,26 +472,98 @@ func (r *mysqlAnimalRepository) FindByID(ctx context.Context, id uint64) (Animal
 // Create saves a new animal
 func (r *mysqlAnimalRepository) Create(ctx context.Context, animal *model.Animal) error {
 	// Create the record (ID will be auto-generated by the database)
-	if err := r.db.GetDB().Create(animal).Error; err != nil {
+	err := r.write(ctx, func(tx *gorm.DB) (events.Event, error) {
+		err := tx.Create(animal).Error
+		return animalEvent(events.TypeCreated, animal.ID, *animal), err
+	})
+	if err != nil {
 		r.logger.Error("Failed to create animal", zap.Error(err))
-		return err
+		return contextError(ctx, database.TranslateError(err))
 	}
 
+	// Invalidate the collection cache and any not-found entry cached for the new ID
+	r.invalidateCache(ctx, animal.ID, true)
+
+	return nil
+}
+
+// createBatchSize is the number of rows inserted per statement by CreateBatch
+const createBatchSize = 100
+
+// CreateBatch inserts animals createBatchSize rows per statement inside one transaction,
+// reporting the rows inserted after each statement to the function set with WithProgress
+func (r *mysqlAnimalRepository) CreateBatch(ctx context.Context, animals []model.Animal) error {
+	if len(animals) == 0 {
+		return nil
+	}
+
+	txPublisher, _ := r.publisher.(TxPublisher)
+	err := r.db.Transaction(ctx, func(tx *gorm.DB) error {
+		for start := 0; start < len(animals); start += createBatchSize {
+			// The batch shares its backing array with animals, so the generated IDs are kept
+			batch := animals[start:min(start+createBatchSize, len(animals))]
+			if err := tx.Create(&batch).Error; err != nil {
+				return err
+			}
+			if txPublisher != nil {
+				for _, animal := range batch {
+					if err := txPublisher.PublishTx(tx, AnimalEventsTopic, animalEvent(events.TypeCreated, animal.ID, animal)); err != nil {
+						return err
+					}
+				}
+			}
+			reportProgress(ctx, start+len(batch))
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to create animals", zap.Int("count", len(animals)), zap.Error(err))
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	// Drop not-found entries cached for the new IDs; field selections of them expire with REDIS_NEGATIVE_TTL
+	// rather than scanning for every row
+	r.forgetNotFound(ctx, animals)
+
 	// Invalidate the collection cache
 	r.invalidateCache(ctx, 0, true)
 
+	if txPublisher == nil {
+		for _, animal := range animals {
+			r.publish(ctx, animalEvent(events.TypeCreated, animal.ID, animal))
+		}
+	}
+
 	return nil
 }
 
+// forgetNotFound removes the cached not-found entries for the IDs of newly created animals
+func (r *mysqlAnimalRepository) forgetNotFound(ctx context.Context, animals []model.Animal) {
+	cacheManager := r.db.GetCacheManager()
+	if cacheManager == nil || cacheManager.GetCache() == nil {
+		return
+	}
+
+	for _, animal := range animals {
+		if err := cacheManager.GetCache().Delete(ctx, cache.GenerateItemKey("animals", animal.ID)); err != nil {
+			r.logger.Warn("Failed to invalidate animal cache", zap.Uint64("id", animal.ID), zap.Error(err))
+		}
+	}
+}
+
 // Update updates an existing animal
 func (r *mysqlAnimalRepository) Update(ctx context.Context, animal *model.Animal) error {
 	if animal.ID == 0 {
 		return errors.New("invalid ID")
 	}
 
-	if err := r.db.GetDB().Save(animal).Error; err != nil {
+	err := r.write(ctx, func(tx *gorm.DB) (events.Event, error) {
+		err := tx.Save(animal).Error
+		return animalEvent(events.TypeUpdated, animal.ID, *animal), err
+	})
+	if err != nil {
 		r.logger.Error("Failed to update animal", zap.Uint64("id", animal.ID), zap.Error(err))
-		return err
+		return contextError(ctx, database.TranslateError(err))
 	}
 
 	// Invalidate both individual and collection caches
@@ -422,15 +572,130 @@ func (r *mysqlAnimalRepository) Update(ctx context.Context, animal *model.Animal
 	return nil
 }
 
+// Transaction runs fn in a database transaction and, once it commits, invalidates the caches
+// of rows written through the *Tx methods and publishes their events
+func (r *mysqlAnimalRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	txCtx, writes := withTxWrites[uint64](ctx)
+
+	if err := r.db.Transaction(txCtx, fn); err != nil {
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	for _, id := range writes.ids {
+		r.invalidateCache(ctx, id, true)
+	}
+	for _, event := range writes.events {
+		r.publish(ctx, event)
+	}
+
+	return nil
+}
+
+// FindByIDForUpdate reads an animal inside tx with SELECT ... FOR UPDATE, bypassing the cache
+func (r *mysqlAnimalRepository) FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Animal, error) {
+	if id == 0 {
+		return nil, errors.New("invalid ID")
+	}
+
+	var animal model.Animal
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(&animal).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to lock animal by ID", zap.Uint64("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &animal, nil
+}
+
+// UpdateTx updates an animal inside tx only if its stored version still equals animal.Version,
+// bumping the version on success. Returns ErrVersionConflict if no row matched.
+// Its cache is invalidated when the transaction commits
+func (r *mysqlAnimalRepository) UpdateTx(tx *gorm.DB, animal *model.Animal) error {
+	if animal.ID == 0 {
+		return errors.New("invalid ID")
+	}
+
+	// Updating through a map skips the model's autoUpdateTime, so stamp it here
+	// to hand the caller the same updated_at (and ETag) that was written
+	updatedAt := time.Now()
+	result := tx.Model(&model.Animal{}).
+		Where("id = ? AND version = ?", animal.ID, animal.Version).
+		Updates(map[string]interface{}{
+			"name":        animal.Name,
+			"species":     animal.Species,
+			"age":         animal.Age,
+			"description": animal.Description,
+			"updated_at":  updatedAt,
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		r.logger.Error("Failed to update animal", zap.Uint64("id", animal.ID), zap.Error(result.Error))
+		return database.TranslateError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+
+	animal.Version++
+	animal.UpdatedAt = updatedAt
+	recordTxWrite(tx, animal.ID)
+
+	event := animalEvent(events.TypeUpdated, animal.ID, *animal)
+	if txPublisher, ok := r.publisher.(TxPublisher); ok {
+		return txPublisher.PublishTx(tx, AnimalEventsTopic, event)
+	}
+	recordTxEvent[uint64](tx, event)
+
+	return nil
+}
+
+// Patch updates only the provided columns of an existing animal
+func (r *mysqlAnimalRepository) Patch(ctx context.Context, id uint64, fields map[string]interface{}) error {
+	if id == 0 {
+		return errors.New("invalid ID")
+	}
+
+	// Any write bumps the version so optimistic updates based on the old one conflict
+	updates := make(map[string]interface{}, len(fields)+1)
+	for column, value := range fields {
+		updates[column] = value
+	}
+	updates["version"] = gorm.Expr("version + 1")
+
+	// Updates with a map leaves unspecified columns untouched. Only the patched columns are
+	// known, so the event carries no record
+	err := r.write(ctx, func(tx *gorm.DB) (events.Event, error) {
+		err := tx.Model(&model.Animal{ID: id}).Updates(updates).Error
+		return animalEvent(events.TypeUpdated, id, nil), err
+	})
+	if err != nil {
+		r.logger.Error("Failed to patch animal", zap.Uint64("id", id), zap.Error(err))
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	// Invalidate both individual and collection caches
+	r.invalidateCache(ctx, id, true)
+
+	return nil
+}
+
 // Delete removes an animal
 func (r *mysqlAnimalRepository) Delete(ctx context.Context, id uint64) error {
 	if id == 0 {
 		return errors.New("invalid ID")
 	}
 
-	if err := r.db.GetDB().Delete(&model.Animal{}, id).Error; err != nil {
+	err := r.write(ctx, func(tx *gorm.DB) (events.Event, error) {
+		err := tx.Delete(&model.Animal{}, id).Error
+		return animalEvent(events.TypeDeleted, id, nil), err
+	})
+	if err != nil {
 		r.logger.Error("Failed to delete animal", zap.Uint64("id", id), zap.Error(err))
-		return err
+		return contextError(ctx, err)
 	}
 
 	// Invalidate both individual and collection caches
@@ -438,3 +703,52 @@ func (r *mysqlAnimalRepository) Delete(ctx context.Context, id uint64) error {
 
 	return nil
 }
+
+// FindInBatches streams animals matching filters to fn in batches. Sorting by ascending ID
+// uses GORM's FindInBatches, which pages by primary key; any other order pages with
+// LIMIT/OFFSET, breaking ties by ID so no row is skipped or repeated between batches
+func (r *mysqlAnimalRepository) FindInBatches(ctx context.Context, sort, direction string, filters Filters, batchSize int, fn func(batch []model.Animal) error) error {
+	if !animalSortableFields[sort] {
+		sort = "id"
+	}
+	if direction != "desc" {
+		direction = "asc"
+	}
+
+	query := filters.sanitize(AnimalFilterFields).apply(r.db.GetDB().WithContext(ctx).Model(&model.Animal{}))
+
+	if sort == "id" && direction == "asc" {
+		var animals []model.Animal
+		var fnErr error
+		err := query.FindInBatches(&animals, batchSize, func(tx *gorm.DB, batch int) error {
+			fnErr = fn(animals)
+			return fnErr
+		}).Error
+		if fnErr != nil {
+			return fnErr
+		}
+		if err != nil {
+			r.logger.Error("Failed to stream animals", zap.Error(err))
+			return contextError(ctx, err)
+		}
+		return nil
+	}
+
+	order := fmt.Sprintf("%s %s, id %s", sort, direction, direction)
+	for offset := 0; ; offset += batchSize {
+		var animals []model.Animal
+		if err := query.Session(&gorm.Session{}).Order(order).Limit(batchSize).Offset(offset).Find(&animals).Error; err != nil {
+			r.logger.Error("Failed to stream animals", zap.Error(err))
+			return contextError(ctx, err)
+		}
+		if len(animals) == 0 {
+			return nil
+		}
+		if err := fn(animals); err != nil {
+			return err
+		}
+		if len(animals) < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,541 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/cache"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestRepository creates a repository backed by a dry-run GORM connection and an in-memory cache
+func newTestRepository(t *testing.T) (*mysqlAnimalRepository, database.Cache) {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:0)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		Redis: config.RedisConfig{QueryCache: true, CacheTTL: time.Minute, PaginatedTTL: time.Minute, NegativeTTL: time.Minute},
+		Cache: config.CacheConfig{Backend: config.CacheBackendMemory},
+	}
+	cacheManager := database.NewInMemoryCacheManager(cfg, zap.NewNop())
+	wrapper := database.NewDatabase(cfg, zap.NewNop(), db, cacheManager)
+
+	return NewAnimalRepository(wrapper, zap.NewNop(), nil).(*mysqlAnimalRepository), cacheManager.GetCache()
+}
+
+func TestAnimalRepository_CreateInvalidatesListCache(t *testing.T) {
+	repo, c := newTestRepository(t)
+	ctx := context.Background()
+
+	// Cache a list page and a filtered list page using the repository's key scheme
+	listKey := cache.GenerateListKey("animals", 1, 10, "id", "asc", nil, nil, nil)
+	filteredKey := cache.GenerateListKey("animals", 1, 10, "id", "asc", map[string]string{"species": "Cat"}, nil, nil)
+	require.NoError(t, c.Set(ctx, listKey, CachedPaginatedResult{}, time.Minute))
+	require.NoError(t, c.Set(ctx, filteredKey, CachedPaginatedResult{}, time.Minute))
+
+	require.NoError(t, repo.Create(ctx, &model.Animal{Name: "Fluffy", Species: "Cat"}))
+
+	var cached CachedPaginatedResult
+	assert.Error(t, c.Get(ctx, listKey, &cached), "list cache should be invalidated")
+	assert.Error(t, c.Get(ctx, filteredKey, &cached), "filtered list cache should be invalidated")
+}
+
+func TestAnimalRepository_UpdateInvalidatesItemCache(t *testing.T) {
+	repo, c := newTestRepository(t)
+	ctx := context.Background()
+
+	// FindByID must cache under the same key that invalidation deletes
+	_, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+
+	itemKey := cache.GenerateItemKey("animals", uint64(1))
+	var cached model.Animal
+	require.NoError(t, c.Get(ctx, itemKey, &cached), "FindByID should cache under GenerateItemKey")
+
+	// Keep an unrelated item cached to make sure invalidation is targeted
+	otherKey := cache.GenerateItemKey("animals", uint64(2))
+	require.NoError(t, c.Set(ctx, otherKey, model.Animal{ID: 2}, time.Minute))
+
+	require.NoError(t, repo.Update(ctx, &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat"}))
+
+	assert.Error(t, c.Get(ctx, itemKey, &cached), "item cache should be invalidated")
+	assert.NoError(t, c.Get(ctx, otherKey, &cached), "other items should stay cached")
+}
+
+func TestAnimalRepository_CreateInvalidatesNotFoundCache(t *testing.T) {
+	repo, c := newTestRepository(t)
+	ctx := context.Background()
+
+	// The dry-run connection finds nothing, so the lookup caches a not-found entry
+	result, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, result.Data)
+
+	itemKey := cache.GenerateItemKey("animals", uint64(1))
+	var cached model.Animal
+	require.NoError(t, c.Get(ctx, itemKey, &cached), "a missing ID should be cached")
+
+	require.NoError(t, repo.Create(ctx, &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat"}))
+
+	assert.Error(t, c.Get(ctx, itemKey, &cached), "creating the ID should drop its not-found entry")
+}
+
+func TestAnimalRepository_PublishesWrites(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	broker := events.NewMemoryBroker(8)
+	repo.publisher = broker
+	sub := broker.Subscribe(AnimalEventsTopic)
+	ctx := context.Background()
+
+	fluffy := model.Animal{ID: 1, Name: "Fluffy", Species: "Cat"}
+	require.NoError(t, repo.Create(ctx, &fluffy))
+	created := fluffy // Update moves UpdatedAt on
+	require.NoError(t, repo.Update(ctx, &fluffy))
+	require.NoError(t, repo.Patch(ctx, 1, map[string]interface{}{"age": 4}))
+	require.NoError(t, repo.Delete(ctx, 1))
+
+	expected := []events.Event{
+		{Type: events.TypeCreated, ID: "1", Data: created},
+		{Type: events.TypeUpdated, ID: "1", Data: fluffy},
+		{Type: events.TypeUpdated, ID: "1"},
+		{Type: events.TypeDeleted, ID: "1"},
+	}
+	for _, event := range expected {
+		assert.Equal(t, event, <-sub.Events())
+	}
+	assert.Empty(t, sub.Events())
+}
+
+func TestAnimalRepository_QueriesHonorContextCancellation(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	// Caching is disabled so the query goes straight to the database
+	wrapper := database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil)
+	repo := NewAnimalRepository(wrapper, zap.NewNop(), nil)
+
+	// The query would take far longer than the test is willing to wait
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals`").
+		WillDelayFor(time.Minute).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = repo.FindByID(ctx, 1)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second, "query should abort when the context is canceled")
+}
+
+func TestAnimalRepository_UpdateTxVersionConflict(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	wrapper := database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil)
+	repo := NewAnimalRepository(wrapper, zap.NewNop(), nil)
+
+	// The row has moved past version 2, so the conditional update matches nothing
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("UPDATE `animals` SET .*`version`=version \\+ 1.* WHERE id = \\? AND version = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectRollback()
+
+	animal := &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat", Version: 2}
+	err = repo.Transaction(context.Background(), func(tx *gorm.DB) error {
+		return repo.UpdateTx(tx, animal)
+	})
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, uint(2), animal.Version, "version should not advance on conflict")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_UpdateTxAdvancesUpdatedAt(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	repo := NewAnimalRepository(database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil), zap.NewNop(), nil)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("UPDATE `animals` SET .*`updated_at`=\\?.* WHERE id = \\? AND version = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	previous := time.Now().Add(-time.Hour)
+	animal := &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat", Version: 2, UpdatedAt: previous}
+	oldETag := response.GenerateETag(animal.ID, animal.UpdatedAt)
+
+	err = repo.Transaction(context.Background(), func(tx *gorm.DB) error {
+		return repo.UpdateTx(tx, animal)
+	})
+
+	require.NoError(t, err)
+	assert.True(t, animal.UpdatedAt.After(previous), "updated_at should reflect the write")
+	assert.NotEqual(t, oldETag, response.GenerateETag(animal.ID, animal.UpdatedAt))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_UpdateTxPublishesOnCommit(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	broker := events.NewMemoryBroker(8)
+	sub := broker.Subscribe(AnimalEventsTopic)
+	repo := NewAnimalRepository(database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil), zap.NewNop(), broker)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("UPDATE `animals`").WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	animal := &model.Animal{ID: 1, Name: "Fluffy", Species: "Cat", Version: 2}
+	err = repo.Transaction(context.Background(), func(tx *gorm.DB) error {
+		if err := repo.UpdateTx(tx, animal); err != nil {
+			return err
+		}
+		assert.Empty(t, sub.Events(), "nothing should be published before the commit")
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, events.Event{Type: events.TypeUpdated, ID: "1", Data: *animal}, <-sub.Events())
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_CreateBatchReportsProgress(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	repo := NewAnimalRepository(database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil), zap.NewNop(), nil)
+
+	animals := make([]model.Animal, createBatchSize+50)
+	for i := range animals {
+		animals[i] = model.Animal{Name: "Fluffy", Species: "Cat"}
+	}
+
+	// One statement per batch, in a single transaction
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO `animals`").WillReturnResult(sqlmock.NewResult(1, createBatchSize))
+	sqlMock.ExpectExec("INSERT INTO `animals`").WillReturnResult(sqlmock.NewResult(101, 50))
+	sqlMock.ExpectCommit()
+
+	var progress []int
+	ctx := WithProgress(context.Background(), func(done int) { progress = append(progress, done) })
+	require.NoError(t, repo.CreateBatch(ctx, animals))
+
+	assert.Equal(t, []int{createBatchSize, createBatchSize + 50}, progress)
+	assert.Equal(t, uint64(101), animals[createBatchSize].ID, "generated IDs should be kept")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_FindInBatches(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	wrapper := database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil)
+	repo := NewAnimalRepository(wrapper, zap.NewNop(), nil)
+
+	collect := func(sort, direction string) []uint64 {
+		var ids []uint64
+		err := repo.FindInBatches(context.Background(), sort, direction, Filters{"species": "Cat"}, 2, func(batch []model.Animal) error {
+			for _, animal := range batch {
+				ids = append(ids, animal.ID)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return ids
+	}
+
+	// The default order pages by primary key
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals` WHERE species = \\? ORDER BY `animals`.`id` LIMIT \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals` WHERE species = \\? AND `animals`.`id` > \\? ORDER BY `animals`.`id` LIMIT \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	assert.Equal(t, []uint64{1, 2, 3}, collect("", ""))
+
+	// Other orders page with LIMIT/OFFSET and break ties by ID
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals` WHERE species = \\? ORDER BY name desc, id desc LIMIT \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(4))
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals` WHERE species = \\? ORDER BY name desc, id desc LIMIT \\? OFFSET \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	assert.Equal(t, []uint64{5, 4}, collect("name", "desc"))
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_CountIsCachedUntilWrite(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Redis: config.RedisConfig{QueryCache: true, CacheTTL: time.Minute, CountTTL: time.Minute}}
+	cacheManager := database.NewInMemoryCacheManager(cfg, zap.NewNop())
+	repo := NewAnimalRepository(database.NewDatabase(cfg, zap.NewNop(), db, cacheManager), zap.NewNop(), nil).(*mysqlAnimalRepository)
+	ctx := context.Background()
+	filters := Filters{"species": "Cat", "age_gte": "2", "password": "x"}
+
+	// Filters are applied as in FindAllPaginated, dropping columns that aren't whitelisted
+	sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `animals` WHERE age >= \\? AND species = \\?").
+		WithArgs("2", "Cat").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	for i := 0; i < 2; i++ {
+		count, err := repo.Count(ctx, filters)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	}
+	require.NoError(t, sqlMock.ExpectationsWereMet(), "the second count should be served from cache")
+
+	// Writes drop cached counts along with the lists
+	repo.invalidateCache(ctx, 0, true)
+	sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `animals`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := repo.Count(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_CountNotCachedWithoutQueryCaching(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Redis: config.RedisConfig{QueryCache: false, CacheTTL: time.Minute, CountTTL: time.Minute}}
+	cacheManager := database.NewInMemoryCacheManager(cfg, zap.NewNop())
+	repo := NewAnimalRepository(database.NewDatabase(cfg, zap.NewNop(), db, cacheManager), zap.NewNop(), nil)
+
+	// REDIS_QUERY_CACHING=false turns off count caching like it does list caching
+	for i := int64(1); i <= 2; i++ {
+		sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM `animals`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(i))
+
+		count, err := repo.Count(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_FindAllCapsResults(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	wrapper := database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil)
+	repo := NewAnimalRepository(wrapper, zap.NewNop(), nil)
+
+	rows := func(n int) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id"})
+		for i := 1; i <= n; i++ {
+			rows.AddRow(i)
+		}
+		return rows
+	}
+
+	// The query reads one row past the cap
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals` ORDER BY created_at DESC LIMIT \\?").
+		WithArgs(MaxFindAllResults + 1).
+		WillReturnRows(rows(MaxFindAllResults))
+	result, err := repo.FindAll(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, result.Data, MaxFindAllResults)
+
+	sqlMock.ExpectQuery("SELECT \\* FROM `animals` ORDER BY created_at DESC LIMIT \\?").
+		WillReturnRows(rows(MaxFindAllResults + 1))
+	_, err = repo.FindAll(context.Background())
+	assert.ErrorIs(t, err, ErrTooManyResults)
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_FindByIDSelectsFields(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Redis: config.RedisConfig{QueryCache: true, CacheTTL: time.Minute}}
+	cacheManager := database.NewInMemoryCacheManager(cfg, zap.NewNop())
+	repo := NewAnimalRepository(database.NewDatabase(cfg, zap.NewNop(), db, cacheManager), zap.NewNop(), nil)
+	ctx := WithFields(context.Background(), ParseFields("species, name,name"))
+
+	// Only the selected columns are read, plus the primary key
+	sqlMock.ExpectQuery("SELECT `id`,`name`,`species` FROM `animals` WHERE id = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "species"}).AddRow(1, "Fluffy", "Cat"))
+
+	result, err := repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, result.Data)
+	assert.Equal(t, "Fluffy", result.Data.Name)
+	assert.Empty(t, result.Data.Description)
+
+	// The selection is part of the cache key so it is never served to a full request
+	assert.Equal(t, "v1:animals:item:1:fields=name,species", result.CacheInfo.Key)
+	var cached model.Animal
+	require.NoError(t, cacheManager.GetCache().Get(ctx, result.CacheInfo.Key, &cached))
+	assert.Error(t, cacheManager.GetCache().Get(ctx, cache.GenerateItemKey("animals", uint64(1)), &cached))
+
+	// Writes invalidate every cached selection of the item
+	sqlMock.ExpectExec("DELETE FROM `animals`").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Delete(context.Background(), 1))
+	assert.Error(t, cacheManager.GetCache().Get(ctx, result.CacheInfo.Key, &cached))
+
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAnimalRepository_RejectsUnknownFields(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := WithFields(context.Background(), ParseFields("name,password,owner"))
+
+	_, err := repo.FindByID(ctx, 1)
+	var fieldsErr *InvalidFieldsError
+	require.ErrorAs(t, err, &fieldsErr)
+	assert.Equal(t, []string{"password", "owner"}, fieldsErr.Fields)
+
+	_, err = repo.FindAllPaginated(ctx, pagination.Params{Page: 1, Limit: 10}, nil)
+	assert.ErrorAs(t, err, &fieldsErr)
+}
+
+func TestAnimalRepository_RejectsUnknownIncludes(t *testing.T) {
+	repo, _ := newTestRepository(t)
+	ctx := WithPreloads(context.Background(), ParsePreloads("owner, tags,owner"))
+
+	_, err := repo.FindAllPaginated(ctx, pagination.Params{Page: 1, Limit: 10}, nil)
+	var preloadsErr *InvalidPreloadsError
+	require.ErrorAs(t, err, &preloadsErr)
+	assert.Equal(t, []string{"owner", "tags"}, preloadsErr.Includes)
+}
+
+func TestSelectedPreloads(t *testing.T) {
+	allowed := map[string]string{"owner": "Owner", "tags": "Tags"}
+
+	preloads, err := selectedPreloads(WithPreloads(context.Background(), Preloads{"tags", "owner"}), allowed)
+	require.NoError(t, err)
+	assert.Equal(t, Preloads{"owner", "tags"}, preloads, "includes are sorted so equal sets share a cache key")
+
+	preloads, err = selectedPreloads(context.Background(), allowed)
+	require.NoError(t, err)
+	assert.Nil(t, preloads)
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/database"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// resolveCacheTTLs returns the default and paginated cache TTLs for a repository
+func resolveCacheTTLs(db database.Database, logger *zap.Logger) (defaultTTL, paginatedTTL string) {
+	// Get the default TTL from configuration or use sensible defaults
+	// The actual TTL is applied in the CachedFind method
+	defaultTTL = "30m"
+	paginatedTTL = "5m"
+
+	// If db has config, get TTL values from it
+	if cacheManager := db.GetCacheManager(); cacheManager != nil {
+		if cfg := cacheManager.GetConfig(); cfg != nil {
+			// Use the REDIS_CACHE_TTL from config (set to 15m in .env)
+			defaultTTL = cfg.Redis.CacheTTL.String()
+
+			// Use the REDIS_PAGINATED_TTL from config if defined, otherwise default to 1/3 of CacheTTL
+			if cfg.Redis.PaginatedTTL > 0 {
+				paginatedTTL = cfg.Redis.PaginatedTTL.String()
+				logger.Info("Using configured paginated TTL",
+					zap.String("paginatedTTL", paginatedTTL))
+			} else {
+				// Otherwise use a fraction of the default TTL (1/3)
+				paginatedTTL = (cfg.Redis.CacheTTL / 3).String()
+				logger.Info("Using calculated paginated TTL (1/3 of default TTL)",
+					zap.String("defaultTTL", defaultTTL),
+					zap.String("paginatedTTL", paginatedTTL))
+			}
+		}
+	}
+
+	return defaultTTL, paginatedTTL
+}
+
+// resolveCountTTL returns how long collection counts are cached, or 0 if they aren't because
+// query caching is off
+func resolveCountTTL(db database.Database) time.Duration {
+	if cacheManager := db.GetCacheManager(); cacheManager != nil {
+		if cfg := cacheManager.GetConfig(); cfg != nil && cfg.Redis.QueryCache {
+			return cfg.Redis.CountTTL
+		}
+	}
+	return 0
+}
+
+// cachedCount returns the number of rows query matches, caching it under key for ttl when ttl
+// is positive. Count keys live under the list namespace, so writes that drop a collection's
+// cached lists drop its counts too
+func cachedCount(ctx context.Context, db database.Database, logger *zap.Logger, key string, ttl time.Duration, query *gorm.DB) (int64, error) {
+	var c database.Cache
+	if cacheManager := db.GetCacheManager(); cacheManager != nil && ttl > 0 {
+		c = cacheManager.GetCache()
+	}
+
+	var count int64
+	if c != nil {
+		if err := c.Get(ctx, key, &count); err == nil {
+			return count, nil
+		}
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if c != nil {
+		if err := c.Set(ctx, key, count, ttl); err != nil {
+			logger.Warn("Failed to cache count", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrVersionConflict is returned by a versioned update when the row's version no
+// longer matches the one the caller read, i.e. another write got there first
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrTooManyResults is returned by FindAll when the table holds more than MaxFindAllResults rows
+var ErrTooManyResults = errors.New("too many results")
+
+// MaxFindAllResults caps the number of rows FindAll loads; larger tables must be read
+// page by page or streamed with FindInBatches
+const MaxFindAllResults = 1000
+
+// contextError returns the context's error when a query failed after ctx ended,
+// so callers can match context.Canceled and context.DeadlineExceeded regardless of
+// how the database driver reports the aborted query
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
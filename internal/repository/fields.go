@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// KeyFields is the context key for the fields a client selected with the fields query parameter
+const KeyFields ContextKey = "fields"
+
+// Fields lists the columns a client asked for, e.g. ?fields=id,name; empty selects every column
+type Fields []string
+
+// animalSelectableFields is the whitelist of animal columns a client may select
+var animalSelectableFields = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"species":     true,
+	"age":         true,
+	"description": true,
+	"version":     true,
+	"created_at":  true,
+	"updated_at":  true,
+}
+
+// flowerSelectableFields is the whitelist of flower columns a client may select
+var flowerSelectableFields = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"species":     true,
+	"color":       true,
+	"description": true,
+	"seasonal":    true,
+	"created_at":  true,
+	"updated_at":  true,
+}
+
+// InvalidFieldsError is returned when a client selects fields that do not exist or may not be selected
+type InvalidFieldsError struct {
+	Fields []string
+}
+
+// Error implements the error interface
+func (e *InvalidFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.Fields, ", ")
+}
+
+// ParseFields splits a comma-separated fields parameter, dropping blanks and duplicates
+func ParseFields(raw string) Fields {
+	var fields Fields
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// WithFields returns a copy of ctx that limits reads to fields
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, KeyFields, fields)
+}
+
+// selectedFields returns the fields stored in ctx, sorted so equal selections share a cache key,
+// or an InvalidFieldsError naming every field outside allowedFields
+func selectedFields(ctx context.Context, allowedFields map[string]bool) (Fields, error) {
+	fields, _ := ctx.Value(KeyFields).(Fields)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	var invalid []string
+	for _, field := range fields {
+		if !allowedFields[field] {
+			invalid = append(invalid, field)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, &InvalidFieldsError{Fields: invalid}
+	}
+
+	sorted := append(Fields(nil), fields...)
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// apply restricts the query to the selected columns, always including the primary key
+// so callers can still tell a found record from a missing one
+func (f Fields) apply(query *gorm.DB) *gorm.DB {
+	if len(f) == 0 {
+		return query
+	}
+
+	columns := []string{"id"}
+	for _, field := range f {
+		if field != "id" {
+			columns = append(columns, field)
+		}
+	}
+	return query.Select(columns)
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Filters maps filter expressions to their values, e.g. "species" => "Cat" or "age_gte" => "2"
+type Filters map[string]string
+
+// Supported filter operator suffixes
+const (
+	filterOpGte  = "_gte"
+	filterOpLte  = "_lte"
+	filterOpLike = "_like"
+)
+
+// FilterKind is the type of value a filterable column holds, which filter values must parse as
+type FilterKind int
+
+// Supported filter kinds
+const (
+	FilterString FilterKind = iota
+	FilterInt
+	FilterFloat
+	FilterBool
+	FilterTime
+)
+
+// AnimalFilterFields lists the animal columns that may be filtered on and their kinds
+var AnimalFilterFields = map[string]FilterKind{
+	"id":         FilterInt,
+	"name":       FilterString,
+	"species":    FilterString,
+	"age":        FilterInt,
+	"created_at": FilterTime,
+	"updated_at": FilterTime,
+}
+
+// FlowerFilterFields lists the flower columns that may be filtered on and their kinds
+var FlowerFilterFields = map[string]FilterKind{
+	"id":         FilterInt,
+	"name":       FilterString,
+	"species":    FilterString,
+	"color":      FilterString,
+	"seasonal":   FilterBool,
+	"created_at": FilterTime,
+	"updated_at": FilterTime,
+}
+
+// timeColumns are the filterable columns holding timestamps
+var timeColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// timeRangeAliases maps the range parameters used by reports to the filter expressions they stand for
+var timeRangeAliases = map[string]string{
+	"created_after":  "created_at" + filterOpGte,
+	"created_before": "created_at" + filterOpLte,
+	"updated_after":  "updated_at" + filterOpGte,
+	"updated_before": "updated_at" + filterOpLte,
+}
+
+// timeLayoutsWithoutZone are the accepted timestamp layouts that carry no UTC offset
+var timeLayoutsWithoutZone = []string{"2006-01-02T15:04:05", "2006-01-02"}
+
+// InvalidFilterError is returned when a filter parameter has a value that can't be used
+type InvalidFilterError struct {
+	Param  string
+	Value  string
+	Kind   FilterKind // Kind the value should have had, FilterString for the tz parameter
+	Reason string
+}
+
+// Error implements the error interface
+func (e *InvalidFilterError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Param, e.Value, e.Reason)
+}
+
+// NormalizeTimes replaces the created_after, created_before, updated_after and updated_before
+// parameters with the created_at/updated_at range filters they stand for, and rewrites every
+// timestamp filter value as UTC RFC3339 so equal instants share a cache key. Values may be RFC3339
+// timestamps or dates and times without an offset, which are read in the IANA time zone tz,
+// or UTC if tz is empty
+func (f Filters) NormalizeTimes(tz string) (Filters, error) {
+	loc := time.UTC
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, &InvalidFilterError{Param: "tz", Value: tz, Reason: "unknown time zone"}
+		}
+	}
+
+	// params records the query parameter each filter came from, for error reporting
+	normalized := make(Filters, len(f))
+	params := make(map[string]string, len(f))
+	for expr, value := range f {
+		if aliased, ok := timeRangeAliases[expr]; ok {
+			if _, set := f[aliased]; set {
+				continue
+			}
+			normalized[aliased], params[aliased] = value, expr
+		} else {
+			normalized[expr], params[expr] = value, expr
+		}
+	}
+
+	// Check in a stable order so the same request always reports the same error
+	exprs := make([]string, 0, len(normalized))
+	for expr := range normalized {
+		exprs = append(exprs, expr)
+	}
+	sort.Strings(exprs)
+
+	for _, expr := range exprs {
+		value := normalized[expr]
+		column, op := parseFilter(expr)
+		if !timeColumns[column] || op == filterOpLike || value == "" {
+			continue
+		}
+
+		t, err := parseTime(value, loc)
+		if err != nil {
+			return nil, &InvalidFilterError{Param: params[expr], Value: value, Kind: FilterTime, Reason: timeReason}
+		}
+		normalized[expr] = t.UTC().Format(time.RFC3339Nano)
+	}
+	return normalized, nil
+}
+
+// timeReason explains the accepted formats of timestamp filters
+const timeReason = "expected an RFC3339 timestamp or a YYYY-MM-DD date"
+
+// CheckKinds returns an error for the first filter, in a stable order, whose value doesn't parse
+// as the kind of its column in fields. Filters on other columns, empty values and _like
+// filters, which match text, are not checked
+func (f Filters) CheckKinds(fields map[string]FilterKind) error {
+	exprs := make([]string, 0, len(f))
+	for expr := range f {
+		exprs = append(exprs, expr)
+	}
+	sort.Strings(exprs)
+
+	for _, expr := range exprs {
+		value := f[expr]
+		column, op := parseFilter(expr)
+		kind, ok := fields[column]
+		if !ok || op == filterOpLike || value == "" {
+			continue
+		}
+
+		var (
+			err    error
+			reason string
+		)
+		switch kind {
+		case FilterInt:
+			_, err = strconv.ParseInt(value, 10, 64)
+			reason = "expected an integer"
+		case FilterFloat:
+			_, err = strconv.ParseFloat(value, 64)
+			reason = "expected a number"
+		case FilterBool:
+			_, err = strconv.ParseBool(value)
+			reason = "expected true or false"
+		case FilterTime:
+			_, err = parseTime(value, time.UTC)
+			reason = timeReason
+		}
+		if err != nil {
+			return &InvalidFilterError{Param: expr, Value: value, Kind: kind, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// parseTime parses an RFC3339 timestamp, or a date and time without an offset in loc
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range timeLayoutsWithoutZone {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// filterValue returns the value bound for a filter on column. Timestamps are bound as time.Time
+// so the driver converts them to the connection's time zone (the DSN's loc), matching how the
+// stored values were written
+func filterValue(column, value string) interface{} {
+	if timeColumns[column] {
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+	}
+	return value
+}
+
+// parseFilter splits a filter expression into its column and operator
+func parseFilter(expr string) (column, op string) {
+	for _, suffix := range []string{filterOpGte, filterOpLte, filterOpLike} {
+		if strings.HasSuffix(expr, suffix) {
+			return strings.TrimSuffix(expr, suffix), suffix
+		}
+	}
+	return expr, ""
+}
+
+// sanitize returns only the filters that target whitelisted columns
+func (f Filters) sanitize(allowedFields map[string]FilterKind) Filters {
+	active := make(Filters)
+	for expr, value := range f {
+		if value == "" {
+			continue
+		}
+		column, _ := parseFilter(expr)
+		if _, ok := allowedFields[column]; ok {
+			active[expr] = value
+		}
+	}
+	return active
+}
+
+// apply adds WHERE clauses with bound parameters for each filter. A column with both a
+// lower and an upper bound is filtered with BETWEEN
+func (f Filters) apply(query *gorm.DB) *gorm.DB {
+	// Apply in a stable order so the generated SQL is deterministic
+	exprs := make([]string, 0, len(f))
+	for expr := range f {
+		exprs = append(exprs, expr)
+	}
+	sort.Strings(exprs)
+
+	for _, expr := range exprs {
+		value := f[expr]
+		column, op := parseFilter(expr)
+		switch op {
+		case filterOpGte:
+			if upper, ok := f[column+filterOpLte]; ok {
+				query = query.Where(fmt.Sprintf("%s BETWEEN ? AND ?", column), filterValue(column, value), filterValue(column, upper))
+			} else {
+				query = query.Where(fmt.Sprintf("%s >= ?", column), filterValue(column, value))
+			}
+		case filterOpLte:
+			if _, ok := f[column+filterOpGte]; !ok {
+				query = query.Where(fmt.Sprintf("%s <= ?", column), filterValue(column, value))
+			}
+		case filterOpLike:
+			query = query.Where(fmt.Sprintf("%s LIKE ?", column), "%"+escapeLike(value)+"%")
+		default:
+			query = query.Where(fmt.Sprintf("%s = ?", column), filterValue(column, value))
+		}
+	}
+	return query
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters_NormalizeTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  Filters
+		tz       string
+		expected Filters
+		param    string // Parameter reported as invalid, if any
+	}{
+		{
+			name:     "Aliases",
+			filters:  Filters{"created_after": "2024-01-01", "created_before": "2024-02-01", "updated_after": "2024-01-01T10:00:00Z", "species": "Cat"},
+			expected: Filters{"created_at_gte": "2024-01-01T00:00:00Z", "created_at_lte": "2024-02-01T00:00:00Z", "updated_at_gte": "2024-01-01T10:00:00Z", "species": "Cat"},
+		},
+		{
+			name:     "ExplicitFilterWins",
+			filters:  Filters{"created_after": "2024-01-01", "created_at_gte": "2024-03-01"},
+			expected: Filters{"created_at_gte": "2024-03-01T00:00:00Z"},
+		},
+		{
+			name:     "TimeZone",
+			filters:  Filters{"created_at": "2024-06-01T12:30:00", "updated_before": "2024-06-02"},
+			tz:       "America/New_York",
+			expected: Filters{"created_at": "2024-06-01T16:30:00Z", "updated_at_lte": "2024-06-02T04:00:00Z"},
+		},
+		{
+			name:     "OffsetKept",
+			filters:  Filters{"created_after": "2024-01-01T00:00:00.5+07:00"},
+			tz:       "America/New_York",
+			expected: Filters{"created_at_gte": "2023-12-31T17:00:00.5Z"},
+		},
+		{
+			name:     "OtherColumnsUntouched",
+			filters:  Filters{"name": "2024-01-01", "created_at_like": "2024"},
+			expected: Filters{"name": "2024-01-01", "created_at_like": "2024"},
+		},
+		{
+			name:    "InvalidAlias",
+			filters: Filters{"created_before": "last week"},
+			param:   "created_before",
+		},
+		{
+			name:    "InvalidFilter",
+			filters: Filters{"updated_at_gte": "2024-13-01"},
+			param:   "updated_at_gte",
+		},
+		{
+			name:    "InvalidTimeZone",
+			filters: Filters{"created_after": "2024-01-01"},
+			tz:      "Nowhere/Special",
+			param:   "tz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.filters.NormalizeTimes(tt.tz)
+			if tt.param != "" {
+				var filterErr *InvalidFilterError
+				require.ErrorAs(t, err, &filterErr)
+				assert.Equal(t, tt.param, filterErr.Param)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFilters_CheckKinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters Filters
+		param   string // Parameter reported as invalid, if any
+		kind    FilterKind
+	}{
+		{name: "Valid", filters: Filters{"id": "3", "age_gte": "2", "name": "Fluffy", "created_at_lte": "2024-01-01T00:00:00Z"}},
+		{name: "LikeIsText", filters: Filters{"age_like": "1x"}},
+		{name: "UnknownColumnsIgnored", filters: Filters{"owner": "anyone", "age": ""}},
+		{name: "NotAnInteger", filters: Filters{"age_gte": "abc"}, param: "age_gte", kind: FilterInt},
+		{name: "NotATimestamp", filters: Filters{"updated_at": "soon"}, param: "updated_at", kind: FilterTime},
+		{name: "FirstInOrder", filters: Filters{"id": "x", "age": "y"}, param: "age", kind: FilterInt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filters.CheckKinds(AnimalFilterFields)
+			if tt.param == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var filterErr *InvalidFilterError
+			require.ErrorAs(t, err, &filterErr)
+			assert.Equal(t, tt.param, filterErr.Param)
+			assert.Equal(t, tt.kind, filterErr.Kind)
+		})
+	}
+
+	err := Filters{"seasonal": "sometimes"}.CheckKinds(FlowerFilterFields)
+	var filterErr *InvalidFilterError
+	require.ErrorAs(t, err, &filterErr)
+	assert.Equal(t, FilterBool, filterErr.Kind)
+}
+
+func TestFilters_Apply(t *testing.T) {
+	repo, _ := newTestRepository(t)
+
+	filters := Filters{
+		"created_at_gte": "2024-01-01T00:00:00Z",
+		"created_at_lte": "2024-02-01T00:00:00Z",
+		"updated_at_gte": "2024-01-15T00:00:00Z",
+		"age_lte":        "5",
+	}
+	stmt := filters.apply(repo.db.GetDB().Model(&model.Animal{})).Find(&[]model.Animal{}).Statement
+
+	assert.Equal(t, "SELECT * FROM `animals` WHERE age <= ? AND (created_at BETWEEN ? AND ?) AND updated_at >= ?", stmt.SQL.String())
+
+	// Timestamps are bound as time.Time so the driver converts them to the connection's time zone
+	assert.Equal(t, []interface{}{
+		"5",
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+	}, stmt.Vars)
+}
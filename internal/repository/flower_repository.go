@@ -0,0 +1,517 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/cache"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CachedPaginatedFlowerResult represents both flower data and pagination info for caching
+type CachedPaginatedFlowerResult struct {
+	Flowers    []model.Flower     `json:"flowers"`
+	Pagination *pagination.Params `json:"pagination"`
+}
+
+// FlowerResult wraps the flower data with cache information
+type FlowerResult struct {
+	Data      *model.Flower `json:"data"`
+	CacheInfo *CacheInfo    `json:"cacheInfo,omitempty"`
+}
+
+// FlowerCollectionResult wraps the flower collection with cache information
+type FlowerCollectionResult struct {
+	Data       []model.Flower     `json:"data"`
+	Pagination *pagination.Params `json:"pagination,omitempty"`
+	CacheInfo  *CacheInfo         `json:"cacheInfo,omitempty"`
+}
+
+// FlowerRepository defines the interface for flower data access
+type FlowerRepository interface {
+	FindAll(ctx context.Context) (FlowerCollectionResult, error)
+	FindAllPaginated(ctx context.Context, params pagination.Params, filters Filters) (FlowerCollectionResult, error)
+	FindByID(ctx context.Context, id uint64) (FlowerResult, error)
+	// Count returns the number of flowers matching filters, applied as in FindAllPaginated
+	Count(ctx context.Context, filters Filters) (int64, error)
+	Create(ctx context.Context, flower *model.Flower) error
+	Update(ctx context.Context, flower *model.Flower) error
+	// Transaction runs fn in a database transaction; caches for rows written with the
+	// *Tx methods are invalidated after it commits
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	// FindByIDForUpdate reads a flower inside tx and locks its row until tx ends; returns nil if not found
+	FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Flower, error)
+	// UpdateTx updates a flower inside tx
+	UpdateTx(tx *gorm.DB, flower *model.Flower) error
+	Patch(ctx context.Context, id uint64, fields map[string]interface{}) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+// mysqlFlowerRepository implements FlowerRepository using MySQL with Redis cache
+type mysqlFlowerRepository struct {
+	db     database.Database
+	logger *zap.Logger
+	// Store TTL settings
+	defaultTTL   string
+	paginatedTTL string
+	countTTL     time.Duration
+}
+
+// NewFlowerRepository creates a new flower repository
+func NewFlowerRepository(db database.Database, logger *zap.Logger) FlowerRepository {
+	// Resolve TTL settings used for cache info reporting and paginated caching
+	defaultTTL, paginatedTTL := resolveCacheTTLs(db, logger)
+
+	return &mysqlFlowerRepository{
+		db:           db,
+		logger:       logger,
+		defaultTTL:   defaultTTL,
+		paginatedTTL: paginatedTTL,
+		countTTL:     resolveCountTTL(db),
+	}
+}
+
+// createContextWithCacheKey creates a new context with a cache key and a recorder for its cache status
+func (r *mysqlFlowerRepository) createContextWithCacheKey(ctx context.Context, key string) context.Context {
+	return database.WithCacheStatus(context.WithValue(ctx, database.ContextKeyCustomCacheKey, key))
+}
+
+// createCacheInfo creates a CacheInfo struct from the call-scoped context and TTL
+func (r *mysqlFlowerRepository) createCacheInfo(ctx context.Context, ttl string) *CacheInfo {
+	status, key := r.db.GetCacheStatus(ctx)
+	return &CacheInfo{
+		Status:  status,
+		Key:     key,
+		Enabled: status != database.CacheDisabled,
+		TTL:     ttl,
+	}
+}
+
+// invalidateCache invalidates cache entries for a flower or collection
+func (r *mysqlFlowerRepository) invalidateCache(ctx context.Context, itemID uint64, invalidateCollection bool) {
+	cacheManager := r.db.GetCacheManager()
+	if cacheManager == nil || cacheManager.GetCache() == nil {
+		return
+	}
+
+	// Invalidate individual cache if itemID is provided
+	if itemID > 0 {
+		cacheKey := cache.GenerateItemKey("flowers", itemID)
+		if err := cacheManager.GetCache().Delete(ctx, cacheKey); err != nil {
+			r.logger.Warn("Failed to invalidate flower cache", zap.Uint64("id", itemID), zap.Error(err))
+		}
+		fieldsPattern := cache.GenerateItemFieldsPattern("flowers", itemID)
+		if err := cacheManager.GetCache().Delete(ctx, fieldsPattern); err != nil {
+			r.logger.Warn("Failed to invalidate flower field selection cache", zap.Uint64("id", itemID), zap.Error(err))
+		}
+	}
+
+	// Invalidate collection cache if requested
+	if invalidateCollection {
+		listPattern := cache.GenerateListPattern("flowers")
+		if err := cacheManager.GetCache().Delete(ctx, listPattern); err != nil {
+			r.logger.Warn("Failed to invalidate flower collection cache", zap.Error(err))
+		}
+	}
+}
+
+// FindAll retrieves all flowers with caching, up to MaxFindAllResults
+func (r *mysqlFlowerRepository) FindAll(ctx context.Context) (FlowerCollectionResult, error) {
+	var flowers []model.Flower
+
+	// Build the query, reading one row past the cap to detect larger tables
+	query := r.db.GetDB().WithContext(ctx).Order("created_at DESC").Limit(MaxFindAllResults + 1)
+
+	// Create a custom cache key
+	cacheKey := cache.GenerateListKey("flowers", 1, MaxFindAllResults+1, "created_at", "desc", nil, nil, nil)
+
+	// Add the cache key to the context
+	ctxWithKey := r.createContextWithCacheKey(ctx, cacheKey)
+
+	// Use cached find with default TTL
+	err := r.db.CachedFind(ctxWithKey, query, &flowers)
+
+	// Get cache status
+	cacheInfo := r.createCacheInfo(ctxWithKey, r.defaultTTL)
+
+	result := FlowerCollectionResult{
+		Data:      flowers,
+		CacheInfo: cacheInfo,
+	}
+
+	if err != nil {
+		r.logger.Error("Failed to retrieve flowers", zap.Error(err))
+		return result, contextError(ctx, err)
+	}
+
+	if len(flowers) > MaxFindAllResults {
+		return FlowerCollectionResult{}, ErrTooManyResults
+	}
+
+	return result, nil
+}
+
+// FindAllPaginated retrieves paginated flowers matching the given filters
+func (r *mysqlFlowerRepository) FindAllPaginated(ctx context.Context, params pagination.Params, filters Filters) (FlowerCollectionResult, error) {
+	var flowers []model.Flower
+	result := FlowerCollectionResult{
+		Pagination: &params,
+	}
+
+	// Get sort field and direction from query parameters
+	sortField := "id"      // Default sort field
+	sortDirection := "asc" // Default sort direction
+
+	// Query values
+	queryParams := make(map[string]string)
+	values := ctx.Value(KeyQueryParams)
+	if values != nil {
+		if existingParams, ok := values.(map[string]string); ok {
+			queryParams = existingParams
+		}
+	}
+
+	// Make sure pagination parameters are included in the query context
+	// This will ensure they're part of the cache key
+	queryParams["page"] = fmt.Sprintf("%d", params.Page)
+	queryParams["limit"] = fmt.Sprintf("%d", params.Limit)
+
+	if field, exists := queryParams["sort"]; exists && field != "" {
+		// Basic sanitization to prevent SQL injection
+		if flowerSortableFields[field] {
+			sortField = field
+		}
+	}
+
+	if dir, exists := queryParams["direction"]; exists {
+		if dir == "desc" {
+			sortDirection = "desc"
+		}
+	}
+
+	// Only keep filters on whitelisted columns
+	activeFilters := filters.sanitize(FlowerFilterFields)
+
+	// Reject selections of unknown columns rather than silently returning full records
+	fields, err := selectedFields(ctx, flowerSelectableFields)
+	if err != nil {
+		return result, err
+	}
+
+	// Reject includes of associations that aren't whitelisted
+	preloads, err := selectedPreloads(ctx, flowerIncludableAssociations)
+	if err != nil {
+		return result, err
+	}
+
+	// Generate a structured cache key using our key generator
+	cacheKey := cache.GenerateListKey(
+		"flowers",
+		params.Page,
+		params.Limit,
+		sortField,
+		sortDirection,
+		activeFilters,
+		fields,
+		preloads,
+	)
+
+	// Check if we have this query in cache
+	var cacheStatus database.CacheStatus
+	var cacheHit bool
+	var cachedResult CachedPaginatedFlowerResult
+
+	if r.db.GetCacheManager() != nil && r.db.GetCacheManager().GetCache() != nil {
+		// Try to get data from cache (including pagination metadata)
+		err := r.db.GetCacheManager().GetCache().Get(ctx, cacheKey, &cachedResult)
+		if err == nil {
+			// Cache hit - use both flowers and pagination from cache
+			cacheStatus = database.CacheHit
+			cacheHit = true
+			flowers = cachedResult.Flowers
+
+			// Use the cached pagination data
+			if cachedResult.Pagination != nil {
+				result.Pagination = cachedResult.Pagination
+			}
+
+			r.logger.Debug("Cache hit for paginated query",
+				zap.String("key", cacheKey),
+				zap.Int("page", params.Page),
+				zap.Int("limit", params.Limit),
+				zap.Int64("total_items", result.Pagination.TotalItems),
+				zap.Int("total_pages", result.Pagination.TotalPages))
+		} else {
+			// Cache miss
+			cacheStatus = database.CacheMiss
+			cacheHit = false
+		}
+	} else {
+		// Cache disabled
+		cacheStatus = database.CacheDisabled
+	}
+
+	// If cache miss or disabled, we need to query the database
+	if !cacheHit {
+		// Build the filtered base query
+		baseQuery := activeFilters.apply(r.db.GetDB().WithContext(ctx).Model(&model.Flower{}))
+
+		// Count total rows matching the filters
+		var totalRows int64
+		if err := baseQuery.Session(&gorm.Session{}).Count(&totalRows).Error; err != nil {
+			r.logger.Error("Failed to count flowers", zap.Error(err))
+			return result, contextError(ctx, err)
+		}
+
+		// Calculate pagination metadata
+		params.CalculatePages(totalRows)
+		result.Pagination = &params
+
+		// Calculate offset
+		offset := params.GetOffset()
+
+		// Apply sorting and pagination
+		orderClause := fmt.Sprintf("%s %s", sortField, sortDirection)
+		err := preloads.apply(fields.apply(baseQuery), flowerIncludableAssociations).Order(orderClause).Limit(params.Limit).Offset(offset).Find(&flowers).Error
+
+		if err != nil {
+			r.logger.Error("Failed to retrieve paginated flowers", zap.Error(err))
+			return result, contextError(ctx, err)
+		}
+
+		// Log the actual number of flowers returned
+		r.logger.Debug("Query returned results",
+			zap.Int("count", len(flowers)),
+			zap.Int("page", params.Page),
+			zap.Int("limit", params.Limit),
+			zap.Int("offset", offset),
+			zap.Int64("total_items", params.TotalItems),
+			zap.Int("total_pages", params.TotalPages))
+
+		// Cache the results with pagination metadata if caching is enabled
+		if cacheStatus != database.CacheDisabled && r.db.GetCacheManager() != nil && r.db.GetCacheManager().GetCache() != nil {
+			// Parse duration from string
+			ttl, err := time.ParseDuration(r.paginatedTTL)
+			if err != nil {
+				r.logger.Error("Failed to parse TTL", zap.String("ttl", r.paginatedTTL), zap.Error(err))
+				ttl = time.Minute * 5 // Use default of 5 minutes on error
+			}
+
+			// Prepare data to cache (both flowers and pagination)
+			cacheData := CachedPaginatedFlowerResult{
+				Flowers:    flowers,
+				Pagination: result.Pagination,
+			}
+
+			// Store in cache
+			if err := r.db.GetCacheManager().GetCache().Set(ctx, cacheKey, cacheData, ttl); err != nil {
+				r.logger.Warn("Failed to cache paginated flowers", zap.Error(err))
+			} else {
+				r.logger.Debug("Stored paginated results in cache",
+					zap.String("key", cacheKey),
+					zap.Duration("ttl", ttl),
+					zap.Int64("total_items", result.Pagination.TotalItems),
+					zap.Int("total_pages", result.Pagination.TotalPages))
+			}
+		}
+	}
+
+	// Create cache info
+	cacheInfo := &CacheInfo{
+		Status:  cacheStatus,
+		Key:     cacheKey,
+		Enabled: cacheStatus != database.CacheDisabled,
+		TTL:     r.paginatedTTL,
+	}
+
+	result.Data = flowers
+	result.CacheInfo = cacheInfo
+	return result, nil
+}
+
+// FindByID retrieves a flower by ID with caching
+func (r *mysqlFlowerRepository) FindByID(ctx context.Context, id uint64) (FlowerResult, error) {
+	if id == 0 {
+		return FlowerResult{}, errors.New("invalid ID")
+	}
+
+	var flower model.Flower
+	result := FlowerResult{}
+
+	fields, err := selectedFields(ctx, flowerSelectableFields)
+	if err != nil {
+		return result, err
+	}
+
+	// Build the query
+	query := fields.apply(r.db.GetDB().WithContext(ctx).Where("id = ?", id))
+
+	// Generate a structured cache key for the item and field selection
+	cacheKey := cache.GenerateItemFieldsKey("flowers", id, fields)
+
+	// Add the cache key to the context
+	ctxWithKey := r.createContextWithCacheKey(ctx, cacheKey)
+
+	// Use cached find
+	err = r.db.CachedFind(ctxWithKey, query, &flower)
+
+	// Get cache status
+	cacheInfo := r.createCacheInfo(ctxWithKey, r.defaultTTL)
+	result.CacheInfo = cacheInfo
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return result, nil // Return empty result for not found
+		}
+		r.logger.Error("Failed to retrieve flower by ID", zap.Uint64("id", id), zap.Error(err))
+		return result, contextError(ctx, err)
+	}
+
+	// Check if the record was actually found (GORM might not return ErrRecordNotFound); an empty
+	// record is also what CachedFind keeps for REDIS_NEGATIVE_TTL after a miss, so repeated
+	// lookups of a missing ID are answered from the cache
+	if flower.ID == 0 {
+		return result, nil // Return empty result for not found
+	}
+
+	result.Data = &flower
+	return result, nil
+}
+
+// Count returns the number of flowers matching filters. Counts are cached for REDIS_COUNT_TTL
+// and dropped with the collection cache whenever a flower is written
+func (r *mysqlFlowerRepository) Count(ctx context.Context, filters Filters) (int64, error) {
+	activeFilters := filters.sanitize(FlowerFilterFields)
+	query := activeFilters.apply(r.db.GetDB().WithContext(ctx).Model(&model.Flower{}))
+
+	count, err := cachedCount(ctx, r.db, r.logger, cache.GenerateCountKey("flowers", activeFilters), r.countTTL, query)
+	if err != nil {
+		r.logger.Error("Failed to count flowers", zap.Error(err))
+		return 0, contextError(ctx, err)
+	}
+	return count, nil
+}
+
+// Create saves a new flower
+func (r *mysqlFlowerRepository) Create(ctx context.Context, flower *model.Flower) error {
+	// Create the record (ID will be auto-generated by the database)
+	if err := r.db.GetDB().WithContext(ctx).Create(flower).Error; err != nil {
+		r.logger.Error("Failed to create flower", zap.Error(err))
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	// Invalidate the collection cache and any not-found entry cached for the new ID
+	r.invalidateCache(ctx, flower.ID, true)
+
+	return nil
+}
+
+// Update updates an existing flower
+func (r *mysqlFlowerRepository) Update(ctx context.Context, flower *model.Flower) error {
+	if flower.ID == 0 {
+		return errors.New("invalid ID")
+	}
+
+	if err := r.db.GetDB().WithContext(ctx).Save(flower).Error; err != nil {
+		r.logger.Error("Failed to update flower", zap.Uint64("id", flower.ID), zap.Error(err))
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	// Invalidate both individual and collection caches
+	r.invalidateCache(ctx, flower.ID, true)
+
+	return nil
+}
+
+// Transaction runs fn in a database transaction and invalidates the caches of
+// rows written through the *Tx methods once it commits
+func (r *mysqlFlowerRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	txCtx, writes := withTxWrites[uint64](ctx)
+
+	if err := r.db.Transaction(txCtx, fn); err != nil {
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	for _, id := range writes.ids {
+		r.invalidateCache(ctx, id, true)
+	}
+
+	return nil
+}
+
+// FindByIDForUpdate reads a flower inside tx with SELECT ... FOR UPDATE, bypassing the cache
+func (r *mysqlFlowerRepository) FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Flower, error) {
+	if id == 0 {
+		return nil, errors.New("invalid ID")
+	}
+
+	var flower model.Flower
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(&flower).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to lock flower by ID", zap.Uint64("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	return &flower, nil
+}
+
+// UpdateTx updates a flower inside tx; its cache is invalidated when the transaction commits
+func (r *mysqlFlowerRepository) UpdateTx(tx *gorm.DB, flower *model.Flower) error {
+	if flower.ID == 0 {
+		return errors.New("invalid ID")
+	}
+
+	if err := tx.Save(flower).Error; err != nil {
+		r.logger.Error("Failed to update flower", zap.Uint64("id", flower.ID), zap.Error(err))
+		return database.TranslateError(err)
+	}
+
+	recordTxWrite(tx, flower.ID)
+
+	return nil
+}
+
+// Patch updates only the provided columns of an existing flower
+func (r *mysqlFlowerRepository) Patch(ctx context.Context, id uint64, fields map[string]interface{}) error {
+	if id == 0 {
+		return errors.New("invalid ID")
+	}
+
+	// Updates with a map leaves unspecified columns untouched
+	if err := r.db.GetDB().WithContext(ctx).Model(&model.Flower{ID: id}).Updates(fields).Error; err != nil {
+		r.logger.Error("Failed to patch flower", zap.Uint64("id", id), zap.Error(err))
+		return contextError(ctx, database.TranslateError(err))
+	}
+
+	// Invalidate both individual and collection caches
+	r.invalidateCache(ctx, id, true)
+
+	return nil
+}
+
+// Delete removes a flower
+func (r *mysqlFlowerRepository) Delete(ctx context.Context, id uint64) error {
+	if id == 0 {
+		return errors.New("invalid ID")
+	}
+
+	if err := r.db.GetDB().WithContext(ctx).Delete(&model.Flower{}, id).Error; err != nil {
+		r.logger.Error("Failed to delete flower", zap.Uint64("id", id), zap.Error(err))
+		return contextError(ctx, err)
+	}
+
+	// Invalidate both individual and collection caches
+	r.invalidateCache(ctx, id, true)
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TxPublisher is implemented by publishers that store an event in the transaction of the write
+// it describes, such as Outbox, instead of sending it once the write has committed.
+// Repositories given one make every write and its event commit or roll back together
+type TxPublisher interface {
+	events.Publisher
+	PublishTx(tx *gorm.DB, topic string, event events.Event) error
+}
+
+// Outbox is a TxPublisher storing events in the outbox table, from which an OutboxDispatcher
+// publishes them
+type Outbox struct {
+	db database.Database
+}
+
+// NewOutbox creates an outbox writing to db
+func NewOutbox(db database.Database) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Publish stores event for topic outside of any transaction
+func (o *Outbox) Publish(ctx context.Context, topic string, event events.Event) error {
+	return o.PublishTx(o.db.GetDB().WithContext(ctx), topic, event)
+}
+
+// PublishTx stores event for topic inside tx
+func (o *Outbox) PublishTx(tx *gorm.DB, topic string, event events.Event) error {
+	row := model.OutboxEvent{Topic: topic, EventType: event.Type, RecordID: event.ID}
+	if event.Data != nil {
+		payload, err := json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		row.Payload = string(payload)
+	}
+
+	if err := tx.Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to store event: %w", err)
+	}
+	return nil
+}
+
+// OutboxDispatcher publishes the events stored in the outbox table in the order they were
+// written, marking each row dispatched once it has been published. Delivery is at least once:
+// an event is published again if the process stops before its row is marked
+type OutboxDispatcher struct {
+	db        database.Database
+	publisher events.Publisher
+	logger    *zap.Logger
+	interval  time.Duration // How often the outbox is polled
+	batchSize int           // Most events published per poll
+	retention time.Duration // How long dispatched rows are kept; 0 keeps them
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOutboxDispatcher creates a dispatcher publishing the events of db's outbox through publisher.
+// Call Start to begin polling
+func NewOutboxDispatcher(db database.Database, publisher events.Publisher, logger *zap.Logger, interval time.Duration, batchSize int, retention time.Duration) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		db:        db,
+		publisher: publisher,
+		logger:    logger,
+		interval:  interval,
+		batchSize: batchSize,
+		retention: retention,
+	}
+}
+
+// Dispatch publishes up to batchSize undispatched events and returns how many were published.
+// Rows are locked with SKIP LOCKED so instances polling at the same time don't publish the
+// same events. Publishing stops at the first failure so later events aren't delivered first
+func (d *OutboxDispatcher) Dispatch(ctx context.Context) (int, error) {
+	published := 0
+	var publishErr error
+
+	err := d.db.Transaction(ctx, func(tx *gorm.DB) error {
+		var rows []model.OutboxEvent
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL").
+			Order("id").
+			Limit(d.batchSize).
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(rows))
+		for _, row := range rows {
+			if publishErr = d.publisher.Publish(ctx, row.Topic, outboxEvent(row)); publishErr != nil {
+				break
+			}
+			ids = append(ids, row.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.OutboxEvent{}).Where("id IN ?", ids).Update("dispatched_at", time.Now()).Error; err != nil {
+			return err
+		}
+		published = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, contextError(ctx, err)
+	}
+	if publishErr != nil {
+		return published, fmt.Errorf("failed to publish outbox event: %w", publishErr)
+	}
+	return published, nil
+}
+
+// outboxEvent rebuilds the event stored in row
+func outboxEvent(row model.OutboxEvent) events.Event {
+	event := events.Event{Type: row.EventType, ID: row.RecordID}
+	if row.Payload != "" {
+		event.Data = json.RawMessage(row.Payload)
+	}
+	return event
+}
+
+// purge deletes the rows dispatched longer than the retention ago
+func (d *OutboxDispatcher) purge(ctx context.Context) error {
+	if d.retention <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-d.retention)
+	return d.db.GetDB().WithContext(ctx).Where("dispatched_at < ?", cutoff).Delete(&model.OutboxEvent{}).Error
+}
+
+// Start polls the outbox in a background goroutine until Stop is called. A poll that publishes
+// a full batch is followed straight away by another so a backlog drains quickly
+func (d *OutboxDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.run(ctx)
+}
+
+// run dispatches events every interval until ctx is done
+func (d *OutboxDispatcher) run(ctx context.Context) {
+	defer close(d.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		published, err := d.Dispatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.Warn("Failed to dispatch outbox events", zap.Int("published", published), zap.Error(err))
+		}
+		if err == nil && published == d.batchSize {
+			timer.Reset(0)
+			continue
+		}
+
+		if err := d.purge(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Warn("Failed to purge dispatched outbox events", zap.Error(err))
+		}
+		timer.Reset(d.interval)
+	}
+}
+
+// Stop ends polling and waits until the dispatcher has stopped or ctx is done. A poll in
+// progress is abandoned; the events it hadn't marked are published again by the next dispatcher
+func (d *OutboxDispatcher) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newOutboxTestDatabase creates a database backed by sqlmock without a cache
+func newOutboxTestDatabase(t *testing.T) (database.Database, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	return database.NewDatabase(&config.Config{}, zap.NewNop(), db, nil), sqlMock
+}
+
+// failingPublisher publishes through Publisher until fail events have been sent, then fails
+type failingPublisher struct {
+	events.Publisher
+	fail int
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, topic string, event events.Event) error {
+	if p.fail == 0 {
+		return errors.New("redis unavailable")
+	}
+	p.fail--
+	return p.Publisher.Publish(ctx, topic, event)
+}
+
+func TestAnimalRepository_WritesEventsToOutbox(t *testing.T) {
+	db, sqlMock := newOutboxTestDatabase(t)
+	repo := NewAnimalRepository(db, zap.NewNop(), NewOutbox(db))
+
+	// The event is stored in the same transaction as the animal
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO `animals`").WillReturnResult(sqlmock.NewResult(7, 1))
+	sqlMock.ExpectExec("INSERT INTO `outbox`").
+		WithArgs(AnimalEventsTopic, events.TypeCreated, "7", sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	require.NoError(t, repo.Create(context.Background(), &model.Animal{Name: "Fluffy", Species: "Cat"}))
+
+	// If the event can't be stored the write is rolled back
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("DELETE FROM `animals`").WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec("INSERT INTO `outbox`").WillReturnError(errors.New("table is full"))
+	sqlMock.ExpectRollback()
+
+	assert.Error(t, repo.Delete(context.Background(), 7))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestOutboxDispatcher_Dispatch(t *testing.T) {
+	db, sqlMock := newOutboxTestDatabase(t)
+	broker := events.NewMemoryBroker(8)
+	sub := broker.Subscribe(AnimalEventsTopic)
+	dispatcher := NewOutboxDispatcher(db, broker, zap.NewNop(), 0, 10, 0)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery("SELECT \\* FROM `outbox` WHERE dispatched_at IS NULL ORDER BY id LIMIT \\? FOR UPDATE SKIP LOCKED").
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "event_type", "record_id", "payload"}).
+			AddRow(1, AnimalEventsTopic, events.TypeCreated, "7", `{"id":7,"name":"Fluffy"}`).
+			AddRow(2, AnimalEventsTopic, events.TypeDeleted, "7", ""))
+	sqlMock.ExpectExec("UPDATE `outbox` SET `dispatched_at`=\\? WHERE id IN \\(\\?,\\?\\)").
+		WithArgs(sqlmock.AnyArg(), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlMock.ExpectCommit()
+
+	published, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, events.Event{Type: events.TypeCreated, ID: "7", Data: json.RawMessage(`{"id":7,"name":"Fluffy"}`)}, <-sub.Events())
+	assert.Equal(t, events.Event{Type: events.TypeDeleted, ID: "7"}, <-sub.Events())
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestOutboxDispatcher_StopsAtPublishFailure(t *testing.T) {
+	db, sqlMock := newOutboxTestDatabase(t)
+	dispatcher := NewOutboxDispatcher(db, &failingPublisher{Publisher: events.NewMemoryBroker(8), fail: 1}, zap.NewNop(), 0, 10, 0)
+
+	// Only the event published before the failure is marked; the rest are retried in order
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery("SELECT \\* FROM `outbox`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "event_type", "record_id"}).
+			AddRow(1, AnimalEventsTopic, events.TypeUpdated, "1").
+			AddRow(2, AnimalEventsTopic, events.TypeUpdated, "2").
+			AddRow(3, AnimalEventsTopic, events.TypeUpdated, "3"))
+	sqlMock.ExpectExec("UPDATE `outbox` SET `dispatched_at`=\\? WHERE id IN \\(\\?\\)").
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	published, err := dispatcher.Dispatch(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, published)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
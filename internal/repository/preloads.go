@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// KeyPreloads is the context key for the associations a client asked for with the include query parameter
+const KeyPreloads ContextKey = "preloads"
+
+// Preloads lists the associations to load alongside each record, e.g. ?include=owner,tags
+type Preloads []string
+
+// animalIncludableAssociations maps the include names clients may use for animals to the GORM
+// association they preload, e.g. "owner": "Owner". Animals have no associations yet
+var animalIncludableAssociations = map[string]string{}
+
+// flowerIncludableAssociations maps the include names clients may use for flowers to the GORM
+// association they preload. Flowers have no associations yet
+var flowerIncludableAssociations = map[string]string{}
+
+// InvalidPreloadsError is returned when a client includes associations that do not exist or may not be included
+type InvalidPreloadsError struct {
+	Includes []string
+}
+
+// Error implements the error interface
+func (e *InvalidPreloadsError) Error() string {
+	return "unknown includes: " + strings.Join(e.Includes, ", ")
+}
+
+// ParsePreloads splits a comma-separated include parameter, dropping blanks and duplicates
+func ParsePreloads(raw string) Preloads {
+	return Preloads(ParseFields(raw))
+}
+
+// WithPreloads returns a copy of ctx that loads preloads alongside list results
+func WithPreloads(ctx context.Context, preloads Preloads) context.Context {
+	return context.WithValue(ctx, KeyPreloads, preloads)
+}
+
+// selectedPreloads returns the preloads stored in ctx, sorted so equal sets share a cache key,
+// or an InvalidPreloadsError naming every include outside allowed
+func selectedPreloads(ctx context.Context, allowed map[string]string) (Preloads, error) {
+	preloads, _ := ctx.Value(KeyPreloads).(Preloads)
+	if len(preloads) == 0 {
+		return nil, nil
+	}
+
+	var invalid []string
+	for _, include := range preloads {
+		if _, ok := allowed[include]; !ok {
+			invalid = append(invalid, include)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, &InvalidPreloadsError{Includes: invalid}
+	}
+
+	sorted := append(Preloads(nil), preloads...)
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// apply preloads each association in a single query per association rather than one per record
+func (p Preloads) apply(query *gorm.DB, allowed map[string]string) *gorm.DB {
+	for _, include := range p {
+		query = query.Preload(allowed[include])
+	}
+	return query
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// KeyProgress is the context key for the function bulk writes report their progress to
+const KeyProgress ContextKey = "progress"
+
+// WithProgress returns a copy of ctx whose bulk writes, such as CreateBatch, call fn with the
+// number of rows written so far after each statement
+func WithProgress(ctx context.Context, fn func(done int)) context.Context {
+	return context.WithValue(ctx, KeyProgress, fn)
+}
+
+// reportProgress passes done to the progress function stored in ctx, if any
+func reportProgress(ctx context.Context, done int) {
+	if fn, ok := ctx.Value(KeyProgress).(func(done int)); ok {
+		fn(done)
+	}
+}
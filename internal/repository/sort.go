@@ -0,0 +1,22 @@
+package repository
+
+// AnimalSortFields lists the columns animals may be sorted by
+var AnimalSortFields = []string{"id", "name", "species", "age", "created_at", "updated_at"}
+
+// FlowerSortFields lists the columns flowers may be sorted by
+var FlowerSortFields = []string{"id", "name", "species", "color", "seasonal", "created_at", "updated_at"}
+
+// animalSortableFields is the whitelist of columns animals may be sorted by
+var animalSortableFields = fieldSet(AnimalSortFields)
+
+// flowerSortableFields is the whitelist of columns flowers may be sorted by
+var flowerSortableFields = fieldSet(FlowerSortFields)
+
+// fieldSet returns a whitelist holding fields
+func fieldSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/linkeunid/go-api/pkg/events"
+	"gorm.io/gorm"
+)
+
+// txWrites collects the IDs written inside a transaction so their caches can be
+// invalidated once it commits, rather than while other readers can still see old rows,
+// and the events to publish then. K is the type of the resource's primary key
+type txWrites[K comparable] struct {
+	mu     sync.Mutex
+	ids    []K
+	events []events.Event
+}
+
+// txWritesKey is the context key for the transaction's txWrites
+type txWritesKey struct{}
+
+// withTxWrites returns a context that records writes made through the *Tx repository methods
+func withTxWrites[K comparable](ctx context.Context) (context.Context, *txWrites[K]) {
+	writes := &txWrites[K]{}
+	return context.WithValue(ctx, txWritesKey{}, writes), writes
+}
+
+// recordTxWrite notes that id was written inside tx
+func recordTxWrite[K comparable](tx *gorm.DB, id K) {
+	if tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	if writes, ok := tx.Statement.Context.Value(txWritesKey{}).(*txWrites[K]); ok {
+		writes.mu.Lock()
+		writes.ids = append(writes.ids, id)
+		writes.mu.Unlock()
+	}
+}
+
+// recordTxEvent queues event to be published once tx commits
+func recordTxEvent[K comparable](tx *gorm.DB, event events.Event) {
+	if tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	if writes, ok := tx.Statement.Context.Value(txWritesKey{}).(*txWrites[K]); ok {
+		writes.mu.Lock()
+		writes.events = append(writes.events, event)
+		writes.mu.Unlock()
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// UserRepository defines the interface for user data access. Users aren't cached, so a
+// changed password or role applies to the next login
+type UserRepository interface {
+	// FindByEmail returns the user with email, or nil if there is none
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	// FindByID returns the user with id, or nil if there is none
+	FindByID(ctx context.Context, id uint64) (*model.User, error)
+	// BumpTokenVersion moves the token version of user id from version to version+1, revoking
+	// the refresh tokens issued for version. It reports false if the version had already moved on
+	BumpTokenVersion(ctx context.Context, id, version uint64) (bool, error)
+}
+
+// mysqlUserRepository implements UserRepository using MySQL
+type mysqlUserRepository struct {
+	db database.Database
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db database.Database) UserRepository {
+	return &mysqlUserRepository{db: db}
+}
+
+// FindByEmail implements UserRepository
+func (r *mysqlUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.first(ctx, "email = ?", email)
+}
+
+// FindByID implements UserRepository
+func (r *mysqlUserRepository) FindByID(ctx context.Context, id uint64) (*model.User, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+// first returns the first user matching the condition, or nil if there is none
+func (r *mysqlUserRepository) first(ctx context.Context, query string, args ...interface{}) (*model.User, error) {
+	var user model.User
+	err := r.db.GetDB().WithContext(ctx).Where(query, args...).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", contextError(ctx, err))
+	}
+	return &user, nil
+}
+
+// BumpTokenVersion implements UserRepository
+func (r *mysqlUserRepository) BumpTokenVersion(ctx context.Context, id, version uint64) (bool, error) {
+	result := r.db.GetDB().WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND token_version = ?", id, version).
+		Update("token_version", gorm.Expr("token_version + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to bump token version: %w", contextError(ctx, result.Error))
+	}
+	return result.RowsAffected > 0, nil
+}
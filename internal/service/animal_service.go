@@ -9,42 +9,57 @@ import (
 	"github.com/linkeunid/go-api/internal/model"
 	"github.com/linkeunid/go-api/internal/repository"
 	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
 	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/validator"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 var (
 	// ErrAnimalNotFound is returned when an animal cannot be found
-	ErrAnimalNotFound = errors.New("animal not found")
+	ErrAnimalNotFound = newResourceError("animal not found", ErrNotFound)
 
 	// ErrInvalidAnimalData is returned when animal data is invalid
-	ErrInvalidAnimalData = errors.New("invalid animal data")
+	ErrInvalidAnimalData = newResourceError("invalid animal data", ErrInvalidData)
 
 	// ErrInvalidAnimalID is returned when animal ID is invalid
-	ErrInvalidAnimalID = errors.New("invalid animal ID")
+	ErrInvalidAnimalID = newResourceError("invalid animal ID", ErrInvalidID)
+
+	// ErrAnimalAlreadyExists is returned when an animal collides with an existing one on a unique field
+	ErrAnimalAlreadyExists = newResourceError("animal already exists", ErrAlreadyExists)
+
+	// ErrAnimalVersionConflict is returned when an update carries a stale animal version
+	ErrAnimalVersionConflict = newResourceError("animal was modified by another request", ErrVersionConflict)
+
+	// ErrTooManyAnimals is returned when there are too many animals to return without pagination
+	ErrTooManyAnimals = newResourceError("too many animals to return at once", ErrTooManyResults)
 )
 
+// animalExportBatchSize is the number of animals read per query when exporting
+const animalExportBatchSize = 500
+
+// animalReadOnlyFields lists the fields that cannot be changed through a patch
+var animalReadOnlyFields = []string{"id", "version", "created_at", "updated_at"}
+
 // AnimalResponse wraps an animal with metadata
-type AnimalResponse struct {
-	Data      *model.Animal         `json:"data"`
-	CacheInfo *repository.CacheInfo `json:"cacheInfo,omitempty"`
-}
+type AnimalResponse = ItemResponse[model.Animal]
 
 // AnimalCollectionResponse wraps multiple animals with metadata
-type AnimalCollectionResponse struct {
-	Data       []model.Animal        `json:"data"`
-	Pagination *pagination.Params    `json:"pagination,omitempty"`
-	CacheInfo  *repository.CacheInfo `json:"cacheInfo,omitempty"`
-}
+type AnimalCollectionResponse = CollectionResponse[model.Animal]
 
 // AnimalService defines the interface for animal operations
 type AnimalService interface {
 	GetAll(ctx context.Context) (AnimalCollectionResponse, error)
-	GetAllPaginated(ctx context.Context, params pagination.Params) (AnimalCollectionResponse, error)
+	GetAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (AnimalCollectionResponse, error)
 	GetByID(ctx context.Context, id string) (AnimalResponse, error)
+	Count(ctx context.Context, filters repository.Filters) (int64, error)
 	Create(ctx context.Context, animal *model.Animal) error
 	Update(ctx context.Context, id string, animal *model.Animal) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
+	Export(ctx context.Context, sort, direction string, filters repository.Filters, fn func(batch []model.Animal) error) error
+	Import(ctx context.Context, animals []model.Animal) error
 }
 
 // AnimalServiceImpl implements AnimalService
@@ -52,6 +67,7 @@ type AnimalServiceImpl struct {
 	logger     *zap.Logger
 	config     *config.Config
 	repository repository.AnimalRepository
+	timeout    time.Duration // Longest a call may take unless the caller's deadline is earlier
 }
 
 // NewAnimalService creates a new animal service
@@ -64,33 +80,38 @@ func NewAnimalService(
 		logger:     logger,
 		config:     cfg,
 		repository: repository,
+		timeout:    cfg.Service.OperationTimeout,
 	}
 }
 
-// GetAll retrieves all animals
+// GetAll retrieves all animals as a single page, up to repository.MaxFindAllResults
 func (s *AnimalServiceImpl) GetAll(ctx context.Context) (AnimalCollectionResponse, error) {
 	// Add a timeout to the context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
 	defer cancel()
 
 	result, err := s.repository.FindAll(ctx)
 	if err != nil {
+		if errors.Is(err, repository.ErrTooManyResults) {
+			return AnimalCollectionResponse{}, ErrTooManyAnimals
+		}
 		return AnimalCollectionResponse{}, err
 	}
 
 	return AnimalCollectionResponse{
-		Data:      result.Data,
-		CacheInfo: result.CacheInfo,
+		Data:       result.Data,
+		Pagination: pagination.SinglePage(int64(len(result.Data))),
+		CacheInfo:  result.CacheInfo,
 	}, nil
 }
 
-// GetAllPaginated retrieves paginated animals
-func (s *AnimalServiceImpl) GetAllPaginated(ctx context.Context, params pagination.Params) (AnimalCollectionResponse, error) {
+// GetAllPaginated retrieves paginated animals matching the given filters
+func (s *AnimalServiceImpl) GetAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (AnimalCollectionResponse, error) {
 	// Add a timeout to the context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
 	defer cancel()
 
-	result, err := s.repository.FindAllPaginated(ctx, params)
+	result, err := s.repository.FindAllPaginated(ctx, params, filters)
 	if err != nil {
 		return AnimalCollectionResponse{}, err
 	}
@@ -102,6 +123,15 @@ func (s *AnimalServiceImpl) GetAllPaginated(ctx context.Context, params paginati
 	}, nil
 }
 
+// Count returns the number of animals matching the given filters
+func (s *AnimalServiceImpl) Count(ctx context.Context, filters repository.Filters) (int64, error) {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return s.repository.Count(ctx, filters)
+}
+
 // GetByID retrieves an animal by ID
 func (s *AnimalServiceImpl) GetByID(ctx context.Context, id string) (AnimalResponse, error) {
 	if id == "" {
@@ -116,7 +146,7 @@ func (s *AnimalServiceImpl) GetByID(ctx context.Context, id string) (AnimalRespo
 	}
 
 	// Add a timeout to the context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
 	defer cancel()
 
 	result, err := s.repository.FindByID(ctx, numericID)
@@ -141,10 +171,17 @@ func (s *AnimalServiceImpl) Create(ctx context.Context, animal *model.Animal) er
 	}
 
 	// Add a timeout to the context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
 	defer cancel()
 
-	return s.repository.Create(ctx, animal)
+	if err := s.repository.Create(ctx, animal); err != nil {
+		if errors.Is(err, database.ErrDuplicateKey) {
+			return ErrAnimalAlreadyExists
+		}
+		return err
+	}
+
+	return nil
 }
 
 // Update updates an existing animal
@@ -164,7 +201,61 @@ func (s *AnimalServiceImpl) Update(ctx context.Context, id string, animal *model
 	animal.ID = numericID
 
 	// Add a timeout to the context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Read and write in one transaction, locking the row so a concurrent
+	// update or delete cannot interleave between the existence check and the write
+	return s.repository.Transaction(ctx, func(tx *gorm.DB) error {
+		existing, err := s.repository.FindByIDForUpdate(tx, numericID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return ErrAnimalNotFound
+		}
+
+		// Preserve created_at timestamp
+		animal.CreatedAt = existing.CreatedAt
+
+		// Without an expected version from the client the update is unconditional
+		if animal.Version == 0 {
+			animal.Version = existing.Version
+		}
+
+		if err := s.repository.UpdateTx(tx, animal); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return ErrAnimalVersionConflict
+			}
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Patch partially updates an existing animal with the provided fields
+func (s *AnimalServiceImpl) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	if id == "" || len(fields) == 0 {
+		return ErrInvalidAnimalData
+	}
+
+	// Convert string ID to uint64
+	numericID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		s.logger.Error("Invalid animal ID format", zap.String("id", id), zap.Error(err))
+		return ErrInvalidAnimalID
+	}
+
+	// Validate only the provided fields
+	updates, validationErrors := validator.ValidatePartial(model.Animal{}, fields, animalReadOnlyFields...)
+	if len(validationErrors) > 0 {
+		return ValidationErrors(validationErrors)
+	}
+
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
 	defer cancel()
 
 	// Check if the animal exists
@@ -177,10 +268,7 @@ func (s *AnimalServiceImpl) Update(ctx context.Context, id string, animal *model
 		return ErrAnimalNotFound
 	}
 
-	// Preserve created_at timestamp
-	animal.CreatedAt = result.Data.CreatedAt
-
-	return s.repository.Update(ctx, animal)
+	return s.repository.Patch(ctx, numericID, updates)
 }
 
 // Delete removes an animal
@@ -197,7 +285,7 @@ func (s *AnimalServiceImpl) Delete(ctx context.Context, id string) error {
 	}
 
 	// Add a timeout to the context
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
 	defer cancel()
 
 	// Check if the animal exists
@@ -212,3 +300,32 @@ func (s *AnimalServiceImpl) Delete(ctx context.Context, id string) error {
 
 	return s.repository.Delete(ctx, numericID)
 }
+
+// Export streams all animals matching filters to fn in batches
+// It is bounded by the request's deadline rather than the per-call timeout used for single queries
+func (s *AnimalServiceImpl) Export(ctx context.Context, sort, direction string, filters repository.Filters, fn func(batch []model.Animal) error) error {
+	return s.repository.FindInBatches(ctx, sort, direction, filters, animalExportBatchSize, fn)
+}
+
+// Import creates the given animals in one transaction
+// IDs, versions and timestamps are assigned by the database, so exported rows can be imported again
+func (s *AnimalServiceImpl) Import(ctx context.Context, animals []model.Animal) error {
+	for i := range animals {
+		if animals[i].Name == "" || animals[i].Species == "" {
+			return ErrInvalidAnimalData
+		}
+		animals[i].ID = 0
+		animals[i].Version = 0
+		animals[i].CreatedAt = time.Time{}
+		animals[i].UpdatedAt = time.Time{}
+	}
+
+	if err := s.repository.CreateBatch(ctx, animals); err != nil {
+		if errors.Is(err, database.ErrDuplicateKey) {
+			return ErrAnimalAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
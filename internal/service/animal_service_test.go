@@ -3,16 +3,21 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/linkeunid/go-api/internal/model"
 	"github.com/linkeunid/go-api/internal/repository"
 	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
 	"github.com/linkeunid/go-api/pkg/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // MockAnimalRepository is a mock implementation of the repository.AnimalRepository interface
@@ -25,8 +30,8 @@ func (m *MockAnimalRepository) FindAll(ctx context.Context) (repository.AnimalCo
 	return args.Get(0).(repository.AnimalCollectionResult), args.Error(1)
 }
 
-func (m *MockAnimalRepository) FindAllPaginated(ctx context.Context, params pagination.Params) (repository.AnimalCollectionResult, error) {
-	args := m.Called(ctx, params)
+func (m *MockAnimalRepository) FindAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (repository.AnimalCollectionResult, error) {
+	args := m.Called(ctx, params, filters)
 	return args.Get(0).(repository.AnimalCollectionResult), args.Error(1)
 }
 
@@ -40,16 +45,62 @@ func (m *MockAnimalRepository) Create(ctx context.Context, animal *model.Animal)
 	return args.Error(0)
 }
 
+func (m *MockAnimalRepository) CreateBatch(ctx context.Context, animals []model.Animal) error {
+	args := m.Called(ctx, animals)
+	return args.Error(0)
+}
+
 func (m *MockAnimalRepository) Update(ctx context.Context, animal *model.Animal) error {
 	args := m.Called(ctx, animal)
 	return args.Error(0)
 }
 
+func (m *MockAnimalRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(nil)
+}
+
+func (m *MockAnimalRepository) FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Animal, error) {
+	args := m.Called(tx, id)
+	animal, _ := args.Get(0).(*model.Animal)
+	return animal, args.Error(1)
+}
+
+func (m *MockAnimalRepository) UpdateTx(tx *gorm.DB, animal *model.Animal) error {
+	args := m.Called(tx, animal)
+	return args.Error(0)
+}
+
+func (m *MockAnimalRepository) Patch(ctx context.Context, id uint64, fields map[string]interface{}) error {
+	args := m.Called(ctx, id, fields)
+	return args.Error(0)
+}
+
 func (m *MockAnimalRepository) Delete(ctx context.Context, id uint64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockAnimalRepository) Count(ctx context.Context, filters repository.Filters) (int64, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAnimalRepository) FindInBatches(ctx context.Context, sort, direction string, filters repository.Filters, batchSize int, fn func(batch []model.Animal) error) error {
+	args := m.Called(ctx, sort, direction, filters, batchSize)
+	if batches, ok := args.Get(0).([][]model.Animal); ok {
+		for _, batch := range batches {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func TestAnimalServiceImpl_GetAll(t *testing.T) {
 	// Create test logger
 	logger, _ := zap.NewDevelopment()
@@ -98,7 +149,8 @@ func TestAnimalServiceImpl_GetAll(t *testing.T) {
 				}, nil)
 			},
 			expectedResponse: AnimalCollectionResponse{
-				Data: animals,
+				Data:       animals,
+				Pagination: &pagination.Params{Page: 1, Limit: 2, TotalItems: 2, TotalPages: 1},
 				CacheInfo: &repository.CacheInfo{
 					Status:  "miss",
 					Enabled: true,
@@ -114,6 +166,14 @@ func TestAnimalServiceImpl_GetAll(t *testing.T) {
 			expectedResponse: AnimalCollectionResponse{},
 			expectedError:    errors.New("database error"),
 		},
+		{
+			name: "TooManyResults",
+			mockSetup: func(mockRepo *MockAnimalRepository) {
+				mockRepo.On("FindAll", mock.Anything).Return(repository.AnimalCollectionResult{}, repository.ErrTooManyResults)
+			},
+			expectedResponse: AnimalCollectionResponse{},
+			expectedError:    ErrTooManyAnimals,
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,6 +200,7 @@ func TestAnimalServiceImpl_GetAll(t *testing.T) {
 
 			// Assert the response
 			assert.Equal(t, len(tt.expectedResponse.Data), len(result.Data))
+			assert.Equal(t, tt.expectedResponse.Pagination, result.Pagination)
 
 			// Verify that repository method was called
 			mockRepo.AssertExpectations(t)
@@ -175,6 +236,9 @@ func TestAnimalServiceImpl_GetAllPaginated(t *testing.T) {
 		TotalPages: 1,
 	}
 
+	// Define filters that should be passed through to the repository
+	filters := repository.Filters{"species": "Cat"}
+
 	// Define test cases
 	tests := []struct {
 		name             string
@@ -185,7 +249,7 @@ func TestAnimalServiceImpl_GetAllPaginated(t *testing.T) {
 		{
 			name: "Success",
 			mockSetup: func(mockRepo *MockAnimalRepository) {
-				mockRepo.On("FindAllPaginated", mock.Anything, params).Return(repository.AnimalCollectionResult{
+				mockRepo.On("FindAllPaginated", mock.Anything, params, filters).Return(repository.AnimalCollectionResult{
 					Data: animals,
 					Pagination: &pagination.Params{
 						Page:       1,
@@ -217,7 +281,7 @@ func TestAnimalServiceImpl_GetAllPaginated(t *testing.T) {
 		{
 			name: "RepositoryError",
 			mockSetup: func(mockRepo *MockAnimalRepository) {
-				mockRepo.On("FindAllPaginated", mock.Anything, params).Return(repository.AnimalCollectionResult{}, errors.New("database error"))
+				mockRepo.On("FindAllPaginated", mock.Anything, params, filters).Return(repository.AnimalCollectionResult{}, errors.New("database error"))
 			},
 			expectedResponse: AnimalCollectionResponse{},
 			expectedError:    errors.New("database error"),
@@ -236,7 +300,7 @@ func TestAnimalServiceImpl_GetAllPaginated(t *testing.T) {
 			service := NewAnimalService(cfg, logger, mockRepo)
 
 			// Call the method being tested
-			result, err := service.GetAllPaginated(context.Background(), params)
+			result, err := service.GetAllPaginated(context.Background(), params, filters)
 
 			// Assert the error
 			if tt.expectedError != nil {
@@ -451,6 +515,17 @@ func TestAnimalServiceImpl_Create(t *testing.T) {
 			},
 			expectedError: errors.New("database error"),
 		},
+		{
+			name: "DuplicateKey",
+			animal: &model.Animal{
+				Name:    "Fluffy",
+				Species: "Cat",
+			},
+			mockSetup: func(mockRepo *MockAnimalRepository) {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Animal")).Return(fmt.Errorf("%w: Duplicate entry", database.ErrDuplicateKey))
+			},
+			expectedError: ErrAnimalAlreadyExists,
+		},
 	}
 
 	for _, tt := range tests {
@@ -470,7 +545,7 @@ func TestAnimalServiceImpl_Create(t *testing.T) {
 			// Assert the error
 			if tt.expectedError != nil {
 				assert.Error(t, err)
-				if tt.expectedError == ErrInvalidAnimalData {
+				if tt.expectedError == ErrInvalidAnimalData || tt.expectedError == ErrAnimalAlreadyExists {
 					assert.Equal(t, tt.expectedError, err)
 				} else {
 					assert.Equal(t, tt.expectedError.Error(), err.Error())
@@ -499,6 +574,7 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 		Species:     "Cat",
 		Age:         3,
 		Description: "A fluffy cat",
+		Version:     3,
 		CreatedAt:   time.Now().Add(-24 * time.Hour), // Created yesterday
 		UpdatedAt:   time.Now().Add(-12 * time.Hour), // Updated 12 hours ago
 	}
@@ -521,16 +597,38 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 				Description: "An updated fluffy cat",
 			},
 			mockSetup: func(mockRepo *MockAnimalRepository) {
-				// First call to FindByID to check if the animal exists
-				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.AnimalResult{
-					Data: &existingAnimal,
-				}, nil)
-
-				// Second call to Update to update the animal
-				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Animal")).Return(nil)
+				// Lock the row to check if the animal exists
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(&existingAnimal, nil)
+
+				// Without a client version the update is checked against the current one
+				mockRepo.On("UpdateTx", mock.Anything, mock.MatchedBy(func(animal *model.Animal) bool {
+					return animal.Version == existingAnimal.Version
+				})).Return(nil)
 			},
 			expectedError: nil,
 		},
+		{
+			name:     "VersionConflict",
+			animalID: "1",
+			animal: &model.Animal{
+				Name:        "Fluffy Updated",
+				Species:     "Cat",
+				Age:         4,
+				Description: "An updated fluffy cat",
+				Version:     2,
+			},
+			mockSetup: func(mockRepo *MockAnimalRepository) {
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(&existingAnimal, nil)
+
+				// The stale version from the client matches no row
+				mockRepo.On("UpdateTx", mock.Anything, mock.MatchedBy(func(animal *model.Animal) bool {
+					return animal.Version == 2
+				})).Return(repository.ErrVersionConflict)
+			},
+			expectedError: ErrAnimalVersionConflict,
+		},
 		{
 			name:     "NotFound",
 			animalID: "999",
@@ -542,9 +640,8 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 			},
 			mockSetup: func(mockRepo *MockAnimalRepository) {
 				// Animal not found
-				mockRepo.On("FindByID", mock.Anything, uint64(999)).Return(repository.AnimalResult{
-					Data: nil,
-				}, nil)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(999)).Return(nil, nil)
 			},
 			expectedError: ErrAnimalNotFound,
 		},
@@ -623,8 +720,9 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 				Description: "An updated fluffy cat",
 			},
 			mockSetup: func(mockRepo *MockAnimalRepository) {
-				// Error during FindByID
-				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.AnimalResult{}, errors.New("database error"))
+				// Error during the locking read
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(nil, errors.New("database error"))
 			},
 			expectedError: errors.New("database error"),
 		},
@@ -638,13 +736,12 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 				Description: "An updated fluffy cat",
 			},
 			mockSetup: func(mockRepo *MockAnimalRepository) {
-				// First call to FindByID succeeds
-				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.AnimalResult{
-					Data: &existingAnimal,
-				}, nil)
+				// Locking read succeeds
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(&existingAnimal, nil)
 
-				// Second call to Update fails
-				mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Animal")).Return(errors.New("database error"))
+				// Update fails
+				mockRepo.On("UpdateTx", mock.Anything, mock.AnythingOfType("*model.Animal")).Return(errors.New("database error"))
 			},
 			expectedError: errors.New("database error"),
 		},
@@ -667,7 +764,7 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 			// Assert the error
 			if tt.expectedError != nil {
 				assert.Error(t, err)
-				if tt.expectedError == ErrAnimalNotFound || tt.expectedError == ErrInvalidAnimalData || tt.expectedError == ErrInvalidAnimalID {
+				if tt.expectedError == ErrAnimalNotFound || tt.expectedError == ErrInvalidAnimalData || tt.expectedError == ErrInvalidAnimalID || tt.expectedError == ErrAnimalVersionConflict {
 					assert.Equal(t, tt.expectedError, err)
 				} else {
 					assert.Equal(t, tt.expectedError.Error(), err.Error())
@@ -682,6 +779,122 @@ func TestAnimalServiceImpl_Update(t *testing.T) {
 	}
 }
 
+func TestAnimalServiceImpl_Patch(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	existingAnimal := model.Animal{
+		ID:          1,
+		Name:        "Fluffy",
+		Species:     "Cat",
+		Age:         3,
+		Description: "A fluffy cat",
+	}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		animalID      string
+		fields        map[string]interface{}
+		mockSetup     func(mockRepo *MockAnimalRepository)
+		expectedError error
+		expectInvalid bool
+	}{
+		{
+			name:     "Success",
+			animalID: "1",
+			fields:   map[string]interface{}{"age": float64(4)},
+			mockSetup: func(mockRepo *MockAnimalRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.AnimalResult{
+					Data: &existingAnimal,
+				}, nil)
+
+				// Only the provided column is passed to the repository, converted to its model type
+				mockRepo.On("Patch", mock.Anything, uint64(1), map[string]interface{}{"age": 4}).Return(nil)
+			},
+		},
+		{
+			name:     "NotFound",
+			animalID: "999",
+			fields:   map[string]interface{}{"age": float64(4)},
+			mockSetup: func(mockRepo *MockAnimalRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(999)).Return(repository.AnimalResult{
+					Data: nil,
+				}, nil)
+			},
+			expectedError: ErrAnimalNotFound,
+		},
+		{
+			name:          "EmptyFields",
+			animalID:      "1",
+			fields:        map[string]interface{}{},
+			mockSetup:     func(mockRepo *MockAnimalRepository) {},
+			expectedError: ErrInvalidAnimalData,
+		},
+		{
+			name:          "InvalidID",
+			animalID:      "invalid",
+			fields:        map[string]interface{}{"age": float64(4)},
+			mockSetup:     func(mockRepo *MockAnimalRepository) {},
+			expectedError: ErrInvalidAnimalID,
+		},
+		{
+			name:          "UnknownField",
+			animalID:      "1",
+			fields:        map[string]interface{}{"color": "Black"},
+			mockSetup:     func(mockRepo *MockAnimalRepository) {},
+			expectInvalid: true,
+		},
+		{
+			name:          "ReadOnlyField",
+			animalID:      "1",
+			fields:        map[string]interface{}{"id": float64(2)},
+			mockSetup:     func(mockRepo *MockAnimalRepository) {},
+			expectInvalid: true,
+		},
+		{
+			name:          "InvalidValue",
+			animalID:      "1",
+			fields:        map[string]interface{}{"age": float64(500)},
+			mockSetup:     func(mockRepo *MockAnimalRepository) {},
+			expectInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockAnimalRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewAnimalService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			err := service.Patch(context.Background(), tt.animalID, tt.fields)
+
+			// Assert the error
+			switch {
+			case tt.expectInvalid:
+				var validationErrors ValidationErrors
+				assert.ErrorAs(t, err, &validationErrors)
+			case tt.expectedError != nil:
+				assert.Equal(t, tt.expectedError, err)
+			default:
+				assert.NoError(t, err)
+			}
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAnimalServiceImpl_Delete(t *testing.T) {
 	// Create test logger
 	logger, _ := zap.NewDevelopment()
@@ -802,3 +1015,181 @@ func TestAnimalServiceImpl_Delete(t *testing.T) {
 		})
 	}
 }
+
+// lockingAnimalRepository is an in-memory repository whose transactions hold a
+// row lock, mimicking SELECT ... FOR UPDATE, to exercise concurrent updates
+type lockingAnimalRepository struct {
+	MockAnimalRepository
+	rowLock sync.Mutex
+	mu      sync.Mutex
+	rows    map[uint64]model.Animal
+	inTx    bool
+}
+
+func (r *lockingAnimalRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	r.rowLock.Lock()
+	defer r.rowLock.Unlock()
+
+	r.inTx = true
+	defer func() { r.inTx = false }()
+
+	return fn(nil)
+}
+
+func (r *lockingAnimalRepository) FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Animal, error) {
+	if !r.inTx {
+		return nil, errors.New("locking read outside a transaction")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	animal, ok := r.rows[id]
+	if !ok {
+		return nil, nil
+	}
+	return &animal, nil
+}
+
+func (r *lockingAnimalRepository) UpdateTx(tx *gorm.DB, animal *model.Animal) error {
+	if !r.inTx {
+		return errors.New("write outside a transaction")
+	}
+
+	// Like GORM's Save, this inserts the row if it no longer exists
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[animal.ID] = *animal
+	return nil
+}
+
+func (r *lockingAnimalRepository) FindByID(ctx context.Context, id uint64) (repository.AnimalResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	animal, ok := r.rows[id]
+	if !ok {
+		return repository.AnimalResult{}, nil
+	}
+	return repository.AnimalResult{Data: &animal}, nil
+}
+
+func (r *lockingAnimalRepository) Delete(ctx context.Context, id uint64) error {
+	// Deleting a row waits for any transaction holding its lock
+	r.rowLock.Lock()
+	defer r.rowLock.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.rows, id)
+	return nil
+}
+
+func TestAnimalServiceImpl_Import(t *testing.T) {
+	logger := zap.NewNop()
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Exported rows keep their database-assigned columns; they must be reassigned on import
+	mockRepo := new(MockAnimalRepository)
+	mockRepo.On("CreateBatch", mock.Anything, []model.Animal{
+		{Name: "Fluffy", Species: "Cat", Age: 3},
+	}).Return(nil).Once()
+	mockRepo.On("CreateBatch", mock.Anything, []model.Animal{
+		{Name: "Rex", Species: "Dog"},
+	}).Return(database.ErrDuplicateKey).Once()
+
+	svc := NewAnimalService(&config.Config{}, logger, mockRepo)
+
+	err := svc.Import(context.Background(), []model.Animal{
+		{ID: 9, Name: "Fluffy", Species: "Cat", Age: 3, Version: 4, CreatedAt: created, UpdatedAt: created},
+	})
+	assert.NoError(t, err)
+
+	err = svc.Import(context.Background(), []model.Animal{{Name: "Rex", Species: "Dog"}})
+	assert.ErrorIs(t, err, ErrAnimalAlreadyExists)
+
+	err = svc.Import(context.Background(), []model.Animal{{Name: "Nameless"}})
+	assert.ErrorIs(t, err, ErrInvalidAnimalData)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAnimalServiceImpl_ConcurrentUpdates(t *testing.T) {
+	logger := zap.NewNop()
+	createdAt := time.Now().Add(-24 * time.Hour)
+
+	repo := &lockingAnimalRepository{
+		rows: map[uint64]model.Animal{
+			1: {ID: 1, Name: "Fluffy", Species: "Cat", CreatedAt: createdAt},
+		},
+	}
+	svc := NewAnimalService(&config.Config{}, logger, repo)
+
+	var wg sync.WaitGroup
+	var deleted bool
+	results := make(chan error, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(age int) {
+			defer wg.Done()
+			results <- svc.Update(context.Background(), "1", &model.Animal{Name: "Fluffy", Species: "Cat", Age: age})
+		}(i)
+
+		// Delete the animal while updates are in flight
+		if i == 10 {
+			assert.NoError(t, svc.Delete(context.Background(), "1"))
+			deleted = true
+		}
+	}
+
+	wg.Wait()
+	close(results)
+
+	for err := range results {
+		// Every update either ran before the delete or saw the animal was gone
+		if err != nil {
+			assert.Equal(t, ErrAnimalNotFound, err)
+		}
+	}
+
+	// No update may resurrect the deleted row
+	assert.True(t, deleted)
+	_, exists := repo.rows[1]
+	assert.False(t, exists, "deleted animal must not be recreated by a concurrent update")
+}
+
+func TestAnimalServiceImpl_OperationTimeout(t *testing.T) {
+	cfg := &config.Config{Service: config.ServiceConfig{OperationTimeout: 5 * time.Second}}
+
+	tests := []struct {
+		name        string
+		callerLimit time.Duration // 0 leaves the caller's context without a deadline
+		expected    time.Duration
+	}{
+		{name: "CallerDeadlineKept", callerLimit: time.Second, expected: time.Second},
+		{name: "LongerCallerDeadlineShortened", callerLimit: time.Minute, expected: 5 * time.Second},
+		{name: "NoCallerDeadline", expected: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.callerLimit > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callerLimit)
+				defer cancel()
+			}
+
+			var deadline time.Time
+			mockRepo := new(MockAnimalRepository)
+			mockRepo.On("FindByID", mock.Anything, uint64(1)).Run(func(args mock.Arguments) {
+				deadline, _ = args.Get(0).(context.Context).Deadline()
+			}).Return(repository.AnimalResult{Data: &model.Animal{ID: 1}}, nil)
+
+			_, err := NewAnimalService(cfg, zap.NewNop(), mockRepo).GetByID(ctx, "1")
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(tt.expected), deadline, 500*time.Millisecond)
+		})
+	}
+}
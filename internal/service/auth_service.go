@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/config"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the email and password don't match a user
+	ErrInvalidCredentials = errors.New("invalid email or password")
+
+	// ErrInvalidRefreshToken is returned when a refresh token is invalid, expired or its user is gone
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+)
+
+// dummyPasswordHash is compared against when no user has the email, so a login takes as long
+// whether or not the account exists
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// TokenPair is the tokens issued to a user at login
+type TokenPair struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"86400"` // Seconds until the access token expires
+}
+
+// AuthService defines the interface for issuing tokens to users
+type AuthService interface {
+	// Login checks email and password and returns new tokens for the user
+	Login(ctx context.Context, email, password string) (TokenPair, error)
+	// Refresh exchanges a refresh token for new tokens; the refresh token can't be used again
+	Refresh(ctx context.Context, refreshToken string) (TokenPair, error)
+	// Logout revokes the refresh tokens of the user a refresh token belongs to
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+// AuthServiceImpl implements AuthService
+type AuthServiceImpl struct {
+	logger     *zap.Logger
+	config     *config.Config
+	jwtService *auth.JWTService
+	users      repository.UserRepository
+	timeout    time.Duration // Longest a call may take unless the caller's deadline is earlier
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(
+	cfg *config.Config,
+	logger *zap.Logger,
+	users repository.UserRepository,
+) AuthService {
+	return &AuthServiceImpl{
+		logger:     logger,
+		config:     cfg,
+		jwtService: auth.NewJWTService(&cfg.Auth),
+		users:      users,
+		timeout:    cfg.Service.OperationTimeout,
+	}
+}
+
+// Login checks email and password and returns new tokens for the user
+func (s *AuthServiceImpl) Login(ctx context.Context, email, password string) (TokenPair, error) {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Emails are stored in lowercase
+	user, err := s.users.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	// bcrypt compares in constant time; an unknown email still pays for a comparison
+	hash := dummyPasswordHash()
+	if user != nil {
+		hash = []byte(user.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || user == nil {
+		return TokenPair{}, ErrInvalidCredentials
+	}
+
+	return s.issue(user)
+}
+
+// Refresh exchanges a refresh token for new tokens, reading the user again so a changed role
+// or a deleted account applies. The user's token version is bumped, so the refresh token, and
+// any other refresh token issued to the user before it, is rejected from then on
+func (s *AuthServiceImpl) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	user, err := s.revoke(ctx, refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return s.issue(user)
+}
+
+// Logout revokes the refresh tokens of the user a refresh token belongs to. Access tokens
+// already issued stay valid until they expire
+func (s *AuthServiceImpl) Logout(ctx context.Context, refreshToken string) error {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := s.revoke(ctx, refreshToken)
+	return err
+}
+
+// revoke checks a refresh token against its user's token version and bumps the version, so
+// each refresh token is accepted once. It returns the user with the new version
+func (s *AuthServiceImpl) revoke(ctx context.Context, refreshToken string) (*model.User, error) {
+	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || claims.Version != user.TokenVersion {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// A concurrent use of the same token bumps the version first
+	bumped, err := s.users.BumpTokenVersion(ctx, user.ID, user.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !bumped {
+		return nil, ErrInvalidRefreshToken
+	}
+	user.TokenVersion++
+
+	return user, nil
+}
+
+// issue generates an access and refresh token for user
+func (s *AuthServiceImpl) issue(user *model.User) (TokenPair, error) {
+	accessToken, err := s.jwtService.GenerateToken(user.ID, "", user.Role, user.Email, user.ScopeList())
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	refreshToken, err := s.jwtService.GenerateRefreshToken(user.ID, user.TokenVersion)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.config.Auth.JWTExpiration / time.Second),
+	}, nil
+}
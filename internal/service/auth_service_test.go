@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockUserRepository is a mock implementation of the repository.UserRepository interface
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id uint64) (*model.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) BumpTokenVersion(ctx context.Context, id, version uint64) (bool, error) {
+	args := m.Called(ctx, id, version)
+	return args.Bool(0), args.Error(1)
+}
+
+// newTestAuthService returns an auth service for one user, ann@example.com with password "s3cret"
+func newTestAuthService(t *testing.T) (AuthService, *MockUserRepository, *model.User) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &model.User{ID: 7, Email: "ann@example.com", PasswordHash: string(hash), Role: "editor", Scopes: "animals:read animals:write"}
+
+	cfg := &config.Config{Auth: config.AuthConfig{
+		Enabled:           true,
+		JWTSecret:         "test-secret",
+		JWTExpiration:     time.Hour,
+		RefreshExpiration: 24 * time.Hour,
+	}}
+	users := new(MockUserRepository)
+	return NewAuthService(cfg, zap.NewNop(), users), users, user
+}
+
+func TestAuthService_Login(t *testing.T) {
+	svc, users, user := newTestAuthService(t)
+	users.On("FindByEmail", mock.Anything, "ann@example.com").Return(user, nil)
+
+	pair, err := svc.Login(context.Background(), " Ann@Example.com", "s3cret")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", pair.TokenType)
+	assert.Equal(t, int64(3600), pair.ExpiresIn)
+
+	jwtService := auth.NewJWTService(&config.AuthConfig{JWTSecret: "test-secret"})
+	claims, err := jwtService.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+	assert.Equal(t, "editor", claims.Role)
+	assert.Equal(t, []string{"animals:read", "animals:write"}, claims.Scopes())
+
+	// The refresh token can't be used to authenticate
+	_, err = jwtService.ValidateToken(pair.RefreshToken)
+	assert.ErrorIs(t, err, auth.ErrTokenInvalid)
+}
+
+func TestAuthService_LoginInvalidCredentials(t *testing.T) {
+	svc, users, user := newTestAuthService(t)
+	users.On("FindByEmail", mock.Anything, "ann@example.com").Return(user, nil)
+	users.On("FindByEmail", mock.Anything, "bob@example.com").Return(nil, nil)
+
+	_, err := svc.Login(context.Background(), "ann@example.com", "guess")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = svc.Login(context.Background(), "bob@example.com", "s3cret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	svc, users, user := newTestAuthService(t)
+	users.On("FindByEmail", mock.Anything, "ann@example.com").Return(user, nil)
+	pair, err := svc.Login(context.Background(), "ann@example.com", "s3cret")
+	require.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		users.On("FindByID", mock.Anything, uint64(7)).Return(user, nil).Once()
+		users.On("BumpTokenVersion", mock.Anything, uint64(7), uint64(0)).Return(true, nil).Once()
+
+		refreshed, err := svc.Refresh(context.Background(), pair.RefreshToken)
+		require.NoError(t, err)
+		assert.NotEmpty(t, refreshed.AccessToken)
+
+		claims, err := auth.NewJWTService(&config.AuthConfig{JWTSecret: "test-secret"}).ValidateRefreshToken(refreshed.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), claims.Version, "the new refresh token carries the bumped version")
+		assert.NotEmpty(t, claims.ID)
+	})
+
+	t.Run("UsedToken", func(t *testing.T) {
+		// The user is at version 1 after the refresh above
+		users.On("FindByID", mock.Anything, uint64(7)).Return(user, nil).Once()
+
+		_, err := svc.Refresh(context.Background(), pair.RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("ConcurrentUse", func(t *testing.T) {
+		other := *user
+		other.TokenVersion = 0
+		users.On("FindByID", mock.Anything, uint64(7)).Return(&other, nil).Once()
+		users.On("BumpTokenVersion", mock.Anything, uint64(7), uint64(0)).Return(false, nil).Once()
+
+		_, err := svc.Refresh(context.Background(), pair.RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("AccessToken", func(t *testing.T) {
+		_, err := svc.Refresh(context.Background(), pair.AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("DeletedUser", func(t *testing.T) {
+		users.On("FindByID", mock.Anything, uint64(7)).Return(nil, nil).Once()
+
+		_, err := svc.Refresh(context.Background(), pair.RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	svc, users, user := newTestAuthService(t)
+	users.On("FindByEmail", mock.Anything, "ann@example.com").Return(user, nil)
+	pair, err := svc.Login(context.Background(), "ann@example.com", "s3cret")
+	require.NoError(t, err)
+
+	users.On("FindByID", mock.Anything, uint64(7)).Return(user, nil)
+	users.On("BumpTokenVersion", mock.Anything, uint64(7), uint64(0)).Return(true, nil).Once()
+	require.NoError(t, svc.Logout(context.Background(), pair.RefreshToken))
+
+	// The refresh token is revoked
+	_, err = svc.Refresh(context.Background(), pair.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	users.AssertExpectations(t)
+}
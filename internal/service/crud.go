@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/pkg/pagination"
+)
+
+var (
+	// ErrNotFound is the error kind wrapped by every resource-specific not found error
+	ErrNotFound = errors.New("not found")
+
+	// ErrInvalidData is the error kind wrapped by every resource-specific invalid data error
+	ErrInvalidData = errors.New("invalid data")
+
+	// ErrInvalidID is the error kind wrapped by every resource-specific invalid ID error
+	ErrInvalidID = errors.New("invalid ID")
+
+	// ErrAlreadyExists is the error kind wrapped by every resource-specific conflict error
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrVersionConflict is the error kind wrapped by every resource-specific optimistic locking error
+	ErrVersionConflict = errors.New("version conflict")
+
+	// ErrTooManyResults is the error kind wrapped by every resource-specific error for a collection
+	// too large to return unpaginated
+	ErrTooManyResults = errors.New("too many results")
+)
+
+// withOperationTimeout bounds ctx by timeout, as configured in SERVICE_OPERATION_TIMEOUT, unless
+// the caller already set an earlier deadline or timeout is 0
+func withOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// resourceError is a resource-specific error that keeps its own message
+// but can be matched against a generic kind with errors.Is
+type resourceError struct {
+	message string
+	kind    error
+}
+
+// newResourceError creates an error with the given message that unwraps to kind
+func newResourceError(message string, kind error) error {
+	return &resourceError{message: message, kind: kind}
+}
+
+// Error implements the error interface
+func (e *resourceError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the generic error kind
+func (e *resourceError) Unwrap() error {
+	return e.kind
+}
+
+// ItemResponse wraps a single record with metadata
+type ItemResponse[T any] struct {
+	Data      *T                    `json:"data" xml:"data"`
+	CacheInfo *repository.CacheInfo `json:"cacheInfo,omitempty" xml:"cacheInfo,omitempty"`
+}
+
+// CollectionResponse wraps multiple records with metadata
+type CollectionResponse[T any] struct {
+	Data       []T                   `json:"data" xml:"data>item"`
+	Pagination *pagination.Params    `json:"pagination,omitempty" xml:"pagination,omitempty"`
+	CacheInfo  *repository.CacheInfo `json:"cacheInfo,omitempty" xml:"cacheInfo,omitempty"`
+}
+
+// CRUDService defines the operations a resource service must provide to be
+// served by the generic CRUD controller
+type CRUDService[T any] interface {
+	GetAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (CollectionResponse[T], error)
+	GetByID(ctx context.Context, id string) (ItemResponse[T], error)
+	Create(ctx context.Context, item *T) error
+	Update(ctx context.Context, id string, item *T) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Exporter is implemented by services that can stream every record of a resource.
+// The CRUD controller serves an export endpoint for services that implement it
+type Exporter[T any] interface {
+	// Export calls fn with successive batches of the records matching filters, sorted by
+	// sort and direction as in GetAllPaginated, stopping at the first error fn returns
+	Export(ctx context.Context, sort, direction string, filters repository.Filters, fn func(batch []T) error) error
+}
+
+// Counter is implemented by services that can count records without loading them.
+// The CRUD controller serves a count endpoint for services that implement it
+type Counter interface {
+	// Count returns the number of records matching filters, applied as in GetAllPaginated
+	Count(ctx context.Context, filters repository.Filters) (int64, error)
+}
+
+// Lister is implemented by services that can return every record of a resource at once.
+// The CRUD controller serves it through RegisterAdminRoutes
+type Lister[T any] interface {
+	// GetAll returns every record as a single page, or an error wrapping ErrTooManyResults if
+	// there are too many
+	GetAll(ctx context.Context) (CollectionResponse[T], error)
+}
+
+// Importer is implemented by services that can create many records at once.
+// The CRUD controller serves an import endpoint for services that implement it
+type Importer[T any] interface {
+	// Import creates all items in one transaction; if any fails, none are created
+	Import(ctx context.Context, items []T) error
+}
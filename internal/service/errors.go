@@ -0,0 +1,19 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/linkeunid/go-api/pkg/validator"
+)
+
+// ValidationErrors is returned when field-level validation fails
+type ValidationErrors []validator.ValidationError
+
+// Error implements the error interface
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, e := range v {
+		messages = append(messages, e.Error)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
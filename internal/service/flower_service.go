@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/validator"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrFlowerNotFound is returned when a flower cannot be found
+	ErrFlowerNotFound = newResourceError("flower not found", ErrNotFound)
+
+	// ErrInvalidFlowerData is returned when flower data is invalid
+	ErrInvalidFlowerData = newResourceError("invalid flower data", ErrInvalidData)
+
+	// ErrInvalidFlowerID is returned when flower ID is invalid
+	ErrInvalidFlowerID = newResourceError("invalid flower ID", ErrInvalidID)
+
+	// ErrFlowerAlreadyExists is returned when a flower collides with an existing one on a unique field
+	ErrFlowerAlreadyExists = newResourceError("flower already exists", ErrAlreadyExists)
+
+	// ErrTooManyFlowers is returned when there are too many flowers to return without pagination
+	ErrTooManyFlowers = newResourceError("too many flowers to return at once", ErrTooManyResults)
+)
+
+// flowerReadOnlyFields lists the fields that cannot be changed through a patch
+var flowerReadOnlyFields = []string{"id", "created_at", "updated_at"}
+
+// FlowerResponse wraps a flower with metadata
+type FlowerResponse = ItemResponse[model.Flower]
+
+// FlowerCollectionResponse wraps multiple flowers with metadata
+type FlowerCollectionResponse = CollectionResponse[model.Flower]
+
+// FlowerService defines the interface for flower operations
+type FlowerService interface {
+	GetAll(ctx context.Context) (FlowerCollectionResponse, error)
+	GetAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (FlowerCollectionResponse, error)
+	GetByID(ctx context.Context, id string) (FlowerResponse, error)
+	Count(ctx context.Context, filters repository.Filters) (int64, error)
+	Create(ctx context.Context, flower *model.Flower) error
+	Update(ctx context.Context, id string, flower *model.Flower) error
+	Patch(ctx context.Context, id string, fields map[string]interface{}) error
+	Delete(ctx context.Context, id string) error
+}
+
+// FlowerServiceImpl implements FlowerService
+type FlowerServiceImpl struct {
+	logger     *zap.Logger
+	config     *config.Config
+	repository repository.FlowerRepository
+	timeout    time.Duration // Longest a call may take unless the caller's deadline is earlier
+}
+
+// NewFlowerService creates a new flower service
+func NewFlowerService(
+	cfg *config.Config,
+	logger *zap.Logger,
+	repository repository.FlowerRepository,
+) FlowerService {
+	return &FlowerServiceImpl{
+		logger:     logger,
+		config:     cfg,
+		repository: repository,
+		timeout:    cfg.Service.OperationTimeout,
+	}
+}
+
+// GetAll retrieves all flowers as a single page
+func (s *FlowerServiceImpl) GetAll(ctx context.Context) (FlowerCollectionResponse, error) {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	result, err := s.repository.FindAll(ctx)
+	if err != nil {
+		if errors.Is(err, repository.ErrTooManyResults) {
+			return FlowerCollectionResponse{}, ErrTooManyFlowers
+		}
+		return FlowerCollectionResponse{}, err
+	}
+
+	return FlowerCollectionResponse{
+		Data:       result.Data,
+		Pagination: pagination.SinglePage(int64(len(result.Data))),
+		CacheInfo:  result.CacheInfo,
+	}, nil
+}
+
+// GetAllPaginated retrieves paginated flowers matching the given filters
+func (s *FlowerServiceImpl) GetAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (FlowerCollectionResponse, error) {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	result, err := s.repository.FindAllPaginated(ctx, params, filters)
+	if err != nil {
+		return FlowerCollectionResponse{}, err
+	}
+
+	return FlowerCollectionResponse{
+		Data:       result.Data,
+		Pagination: result.Pagination,
+		CacheInfo:  result.CacheInfo,
+	}, nil
+}
+
+// Count returns the number of flowers matching the given filters
+func (s *FlowerServiceImpl) Count(ctx context.Context, filters repository.Filters) (int64, error) {
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return s.repository.Count(ctx, filters)
+}
+
+// GetByID retrieves a flower by ID
+func (s *FlowerServiceImpl) GetByID(ctx context.Context, id string) (FlowerResponse, error) {
+	if id == "" {
+		return FlowerResponse{}, ErrInvalidFlowerData
+	}
+
+	// Convert string ID to uint64
+	numericID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		s.logger.Error("Invalid flower ID format", zap.String("id", id), zap.Error(err))
+		return FlowerResponse{}, ErrInvalidFlowerID
+	}
+
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	result, err := s.repository.FindByID(ctx, numericID)
+	if err != nil {
+		return FlowerResponse{}, err
+	}
+
+	if result.Data == nil {
+		return FlowerResponse{}, ErrFlowerNotFound
+	}
+
+	return FlowerResponse{
+		Data:      result.Data,
+		CacheInfo: result.CacheInfo,
+	}, nil
+}
+
+// Create creates a new flower
+func (s *FlowerServiceImpl) Create(ctx context.Context, flower *model.Flower) error {
+	if flower == nil || flower.Name == "" || flower.Species == "" || flower.Color == "" {
+		return ErrInvalidFlowerData
+	}
+
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if err := s.repository.Create(ctx, flower); err != nil {
+		if errors.Is(err, database.ErrDuplicateKey) {
+			return ErrFlowerAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Update updates an existing flower
+func (s *FlowerServiceImpl) Update(ctx context.Context, id string, flower *model.Flower) error {
+	if id == "" || flower == nil || flower.Name == "" || flower.Species == "" || flower.Color == "" {
+		return ErrInvalidFlowerData
+	}
+
+	// Convert string ID to uint64
+	numericID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		s.logger.Error("Invalid flower ID format", zap.String("id", id), zap.Error(err))
+		return ErrInvalidFlowerID
+	}
+
+	// Ensure the ID in the path matches the flower ID
+	flower.ID = numericID
+
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Read and write in one transaction, locking the row so a concurrent
+	// update or delete cannot interleave between the existence check and the write
+	return s.repository.Transaction(ctx, func(tx *gorm.DB) error {
+		existing, err := s.repository.FindByIDForUpdate(tx, numericID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return ErrFlowerNotFound
+		}
+
+		// Preserve created_at timestamp
+		flower.CreatedAt = existing.CreatedAt
+
+		return s.repository.UpdateTx(tx, flower)
+	})
+}
+
+// Patch partially updates an existing flower with the provided fields
+func (s *FlowerServiceImpl) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	if id == "" || len(fields) == 0 {
+		return ErrInvalidFlowerData
+	}
+
+	// Convert string ID to uint64
+	numericID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		s.logger.Error("Invalid flower ID format", zap.String("id", id), zap.Error(err))
+		return ErrInvalidFlowerID
+	}
+
+	// Validate only the provided fields
+	updates, validationErrors := validator.ValidatePartial(model.Flower{}, fields, flowerReadOnlyFields...)
+	if len(validationErrors) > 0 {
+		return ValidationErrors(validationErrors)
+	}
+
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Check if the flower exists
+	result, err := s.repository.FindByID(ctx, numericID)
+	if err != nil {
+		return err
+	}
+
+	if result.Data == nil {
+		return ErrFlowerNotFound
+	}
+
+	return s.repository.Patch(ctx, numericID, updates)
+}
+
+// Delete removes a flower
+func (s *FlowerServiceImpl) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidFlowerData
+	}
+
+	// Convert string ID to uint64
+	numericID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		s.logger.Error("Invalid flower ID format", zap.String("id", id), zap.Error(err))
+		return ErrInvalidFlowerID
+	}
+
+	// Add a timeout to the context
+	ctx, cancel := withOperationTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Check if the flower exists
+	result, err := s.repository.FindByID(ctx, numericID)
+	if err != nil {
+		return err
+	}
+
+	if result.Data == nil {
+		return ErrFlowerNotFound
+	}
+
+	return s.repository.Delete(ctx, numericID)
+}
@@ -0,0 +1,961 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MockFlowerRepository is a mock implementation of the repository.FlowerRepository interface
+type MockFlowerRepository struct {
+	mock.Mock
+}
+
+func (m *MockFlowerRepository) FindAll(ctx context.Context) (repository.FlowerCollectionResult, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(repository.FlowerCollectionResult), args.Error(1)
+}
+
+func (m *MockFlowerRepository) FindAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (repository.FlowerCollectionResult, error) {
+	args := m.Called(ctx, params, filters)
+	return args.Get(0).(repository.FlowerCollectionResult), args.Error(1)
+}
+
+func (m *MockFlowerRepository) FindByID(ctx context.Context, id uint64) (repository.FlowerResult, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(repository.FlowerResult), args.Error(1)
+}
+
+func (m *MockFlowerRepository) Create(ctx context.Context, flower *model.Flower) error {
+	args := m.Called(ctx, flower)
+	return args.Error(0)
+}
+
+func (m *MockFlowerRepository) Update(ctx context.Context, flower *model.Flower) error {
+	args := m.Called(ctx, flower)
+	return args.Error(0)
+}
+
+func (m *MockFlowerRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(nil)
+}
+
+func (m *MockFlowerRepository) Count(ctx context.Context, filters repository.Filters) (int64, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockFlowerRepository) FindByIDForUpdate(tx *gorm.DB, id uint64) (*model.Flower, error) {
+	args := m.Called(tx, id)
+	flower, _ := args.Get(0).(*model.Flower)
+	return flower, args.Error(1)
+}
+
+func (m *MockFlowerRepository) UpdateTx(tx *gorm.DB, flower *model.Flower) error {
+	args := m.Called(tx, flower)
+	return args.Error(0)
+}
+
+func (m *MockFlowerRepository) Patch(ctx context.Context, id uint64, fields map[string]interface{}) error {
+	args := m.Called(ctx, id, fields)
+	return args.Error(0)
+}
+
+func (m *MockFlowerRepository) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestFlowerServiceImpl_GetAll(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	// Create test data
+	flowers := []model.Flower{
+		{
+			ID:          1,
+			Name:        "Rose",
+			Species:     "Rosa",
+			Color:       "Red",
+			Description: "A red rose",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
+		{
+			ID:          2,
+			Name:        "Tulip",
+			Species:     "Tulipa",
+			Color:       "Yellow",
+			Description: "A yellow tulip",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
+	}
+
+	// Define test cases
+	tests := []struct {
+		name             string
+		mockSetup        func(mockRepo *MockFlowerRepository)
+		expectedResponse FlowerCollectionResponse
+		expectedError    error
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindAll", mock.Anything).Return(repository.FlowerCollectionResult{
+					Data: flowers,
+					CacheInfo: &repository.CacheInfo{
+						Status:  "miss",
+						Enabled: true,
+					},
+				}, nil)
+			},
+			expectedResponse: FlowerCollectionResponse{
+				Data:       flowers,
+				Pagination: &pagination.Params{Page: 1, Limit: 2, TotalItems: 2, TotalPages: 1},
+				CacheInfo: &repository.CacheInfo{
+					Status:  "miss",
+					Enabled: true,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "RepositoryError",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindAll", mock.Anything).Return(repository.FlowerCollectionResult{}, errors.New("database error"))
+			},
+			expectedResponse: FlowerCollectionResponse{},
+			expectedError:    errors.New("database error"),
+		},
+		{
+			name: "TooManyResults",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindAll", mock.Anything).Return(repository.FlowerCollectionResult{}, repository.ErrTooManyResults)
+			},
+			expectedResponse: FlowerCollectionResponse{},
+			expectedError:    ErrTooManyFlowers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			result, err := service.GetAll(context.Background())
+
+			// Assert the error
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Assert the response
+			assert.Equal(t, len(tt.expectedResponse.Data), len(result.Data))
+			assert.Equal(t, tt.expectedResponse.Pagination, result.Pagination)
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlowerServiceImpl_GetAllPaginated(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	// Create test data
+	flowers := []model.Flower{
+		{
+			ID:          1,
+			Name:        "Rose",
+			Species:     "Rosa",
+			Color:       "Red",
+			Description: "A red rose",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		},
+	}
+
+	// Define pagination params
+	params := pagination.Params{
+		Page:       1,
+		Limit:      10,
+		TotalItems: 1,
+		TotalPages: 1,
+	}
+
+	// Define filters that should be passed through to the repository
+	filters := repository.Filters{"species": "Rosa"}
+
+	// Define test cases
+	tests := []struct {
+		name             string
+		mockSetup        func(mockRepo *MockFlowerRepository)
+		expectedResponse FlowerCollectionResponse
+		expectedError    error
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindAllPaginated", mock.Anything, params, filters).Return(repository.FlowerCollectionResult{
+					Data: flowers,
+					Pagination: &pagination.Params{
+						Page:       1,
+						Limit:      10,
+						TotalItems: 1,
+						TotalPages: 1,
+					},
+					CacheInfo: &repository.CacheInfo{
+						Status:  "miss",
+						Enabled: true,
+					},
+				}, nil)
+			},
+			expectedResponse: FlowerCollectionResponse{
+				Data: flowers,
+				Pagination: &pagination.Params{
+					Page:       1,
+					Limit:      10,
+					TotalItems: 1,
+					TotalPages: 1,
+				},
+				CacheInfo: &repository.CacheInfo{
+					Status:  "miss",
+					Enabled: true,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "RepositoryError",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindAllPaginated", mock.Anything, params, filters).Return(repository.FlowerCollectionResult{}, errors.New("database error"))
+			},
+			expectedResponse: FlowerCollectionResponse{},
+			expectedError:    errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			result, err := service.GetAllPaginated(context.Background(), params, filters)
+
+			// Assert the error
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Assert the response
+			assert.Equal(t, len(tt.expectedResponse.Data), len(result.Data))
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlowerServiceImpl_GetByID(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	// Create test data
+	flower := model.Flower{
+		ID:          1,
+		Name:        "Rose",
+		Species:     "Rosa",
+		Color:       "Red",
+		Description: "A red rose",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// Define test cases
+	tests := []struct {
+		name             string
+		flowerID         string
+		mockSetup        func(mockRepo *MockFlowerRepository)
+		expectedResponse FlowerResponse
+		expectedError    error
+	}{
+		{
+			name:     "Success",
+			flowerID: "1",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.FlowerResult{
+					Data: &flower,
+					CacheInfo: &repository.CacheInfo{
+						Status:  "miss",
+						Enabled: true,
+					},
+				}, nil)
+			},
+			expectedResponse: FlowerResponse{
+				Data: &flower,
+				CacheInfo: &repository.CacheInfo{
+					Status:  "miss",
+					Enabled: true,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "NotFound",
+			flowerID: "999",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(999)).Return(repository.FlowerResult{
+					Data:      nil,
+					CacheInfo: nil,
+				}, nil)
+			},
+			expectedResponse: FlowerResponse{},
+			expectedError:    ErrFlowerNotFound,
+		},
+		{
+			name:     "InvalidID",
+			flowerID: "invalid",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid ID
+			},
+			expectedResponse: FlowerResponse{},
+			expectedError:    ErrInvalidFlowerID,
+		},
+		{
+			name:     "EmptyID",
+			flowerID: "",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for empty ID
+			},
+			expectedResponse: FlowerResponse{},
+			expectedError:    ErrInvalidFlowerData,
+		},
+		{
+			name:     "RepositoryError",
+			flowerID: "1",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.FlowerResult{}, errors.New("database error"))
+			},
+			expectedResponse: FlowerResponse{},
+			expectedError:    errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			result, err := service.GetByID(context.Background(), tt.flowerID)
+
+			// Assert the error
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if tt.expectedError == ErrFlowerNotFound || tt.expectedError == ErrInvalidFlowerData || tt.expectedError == ErrInvalidFlowerID {
+					assert.Equal(t, tt.expectedError, err)
+				} else {
+					assert.Equal(t, tt.expectedError.Error(), err.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResponse.Data.ID, result.Data.ID)
+				assert.Equal(t, tt.expectedResponse.Data.Name, result.Data.Name)
+			}
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlowerServiceImpl_Create(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		flower        *model.Flower
+		mockSetup     func(mockRepo *MockFlowerRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			flower: &model.Flower{
+				Name:        "Rose",
+				Species:     "Rosa",
+				Color:       "Red",
+				Description: "A red rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Flower")).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "NilFlower",
+			flower: nil,
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for nil flower
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name: "EmptyName",
+			flower: &model.Flower{
+				Name:        "",
+				Species:     "Rosa",
+				Color:       "Red",
+				Description: "A red rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid flower
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name: "EmptySpecies",
+			flower: &model.Flower{
+				Name:        "Rose",
+				Species:     "",
+				Color:       "Red",
+				Description: "A red rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid flower
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name: "RepositoryError",
+			flower: &model.Flower{
+				Name:        "Rose",
+				Species:     "Rosa",
+				Color:       "Red",
+				Description: "A red rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Flower")).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			err := service.Create(context.Background(), tt.flower)
+
+			// Assert the error
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if tt.expectedError == ErrInvalidFlowerData {
+					assert.Equal(t, tt.expectedError, err)
+				} else {
+					assert.Equal(t, tt.expectedError.Error(), err.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlowerServiceImpl_Update(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	// Create test data
+	existingFlower := model.Flower{
+		ID:          1,
+		Name:        "Rose",
+		Species:     "Rosa",
+		Color:       "Red",
+		Description: "A red rose",
+		CreatedAt:   time.Now().Add(-24 * time.Hour), // Created yesterday
+		UpdatedAt:   time.Now().Add(-12 * time.Hour), // Updated 12 hours ago
+	}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		flowerID      string
+		flower        *model.Flower
+		mockSetup     func(mockRepo *MockFlowerRepository)
+		expectedError error
+	}{
+		{
+			name:     "Success",
+			flowerID: "1",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// Lock the row to check if the flower exists
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(&existingFlower, nil)
+
+				// Update the flower
+				mockRepo.On("UpdateTx", mock.Anything, mock.AnythingOfType("*model.Flower")).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "NotFound",
+			flowerID: "999",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// Flower not found
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(999)).Return(nil, nil)
+			},
+			expectedError: ErrFlowerNotFound,
+		},
+		{
+			name:     "EmptyID",
+			flowerID: "",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for empty ID
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name:     "InvalidID",
+			flowerID: "invalid",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid ID
+			},
+			expectedError: ErrInvalidFlowerID,
+		},
+		{
+			name:     "NilFlower",
+			flowerID: "1",
+			flower:   nil,
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for nil flower
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name:     "EmptyName",
+			flowerID: "1",
+			flower: &model.Flower{
+				Name:        "",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid flower
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name:     "EmptySpecies",
+			flowerID: "1",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid flower
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name:     "FindByIDError",
+			flowerID: "1",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// Error during the locking read
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(nil, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+		{
+			name:     "UpdateError",
+			flowerID: "1",
+			flower: &model.Flower{
+				Name:        "Rose Updated",
+				Species:     "Rosa",
+				Color:       "White",
+				Description: "An updated white rose",
+			},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// Locking read succeeds
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("FindByIDForUpdate", mock.Anything, uint64(1)).Return(&existingFlower, nil)
+
+				// Update fails
+				mockRepo.On("UpdateTx", mock.Anything, mock.AnythingOfType("*model.Flower")).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			err := service.Update(context.Background(), tt.flowerID, tt.flower)
+
+			// Assert the error
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if tt.expectedError == ErrFlowerNotFound || tt.expectedError == ErrInvalidFlowerData || tt.expectedError == ErrInvalidFlowerID {
+					assert.Equal(t, tt.expectedError, err)
+				} else {
+					assert.Equal(t, tt.expectedError.Error(), err.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlowerServiceImpl_Patch(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	existingFlower := model.Flower{
+		ID:          1,
+		Name:        "Rose",
+		Species:     "Rosa",
+		Color:       "Red",
+		Description: "A red rose",
+	}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		flowerID      string
+		fields        map[string]interface{}
+		mockSetup     func(mockRepo *MockFlowerRepository)
+		expectedError error
+		expectInvalid bool
+	}{
+		{
+			name:     "Success",
+			flowerID: "1",
+			fields:   map[string]interface{}{"color": "White"},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.FlowerResult{
+					Data: &existingFlower,
+				}, nil)
+
+				// Only the provided column is passed to the repository, converted to its model type
+				mockRepo.On("Patch", mock.Anything, uint64(1), map[string]interface{}{"color": "White"}).Return(nil)
+			},
+		},
+		{
+			name:     "NotFound",
+			flowerID: "999",
+			fields:   map[string]interface{}{"color": "White"},
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				mockRepo.On("FindByID", mock.Anything, uint64(999)).Return(repository.FlowerResult{
+					Data: nil,
+				}, nil)
+			},
+			expectedError: ErrFlowerNotFound,
+		},
+		{
+			name:          "EmptyFields",
+			flowerID:      "1",
+			fields:        map[string]interface{}{},
+			mockSetup:     func(mockRepo *MockFlowerRepository) {},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name:          "InvalidID",
+			flowerID:      "invalid",
+			fields:        map[string]interface{}{"color": "White"},
+			mockSetup:     func(mockRepo *MockFlowerRepository) {},
+			expectedError: ErrInvalidFlowerID,
+		},
+		{
+			name:          "UnknownField",
+			flowerID:      "1",
+			fields:        map[string]interface{}{"age": float64(3)},
+			mockSetup:     func(mockRepo *MockFlowerRepository) {},
+			expectInvalid: true,
+		},
+		{
+			name:          "ReadOnlyField",
+			flowerID:      "1",
+			fields:        map[string]interface{}{"id": float64(2)},
+			mockSetup:     func(mockRepo *MockFlowerRepository) {},
+			expectInvalid: true,
+		},
+		{
+			name:          "InvalidValue",
+			flowerID:      "1",
+			fields:        map[string]interface{}{"color": "X"},
+			mockSetup:     func(mockRepo *MockFlowerRepository) {},
+			expectInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			err := service.Patch(context.Background(), tt.flowerID, tt.fields)
+
+			// Assert the error
+			switch {
+			case tt.expectInvalid:
+				var validationErrors ValidationErrors
+				assert.ErrorAs(t, err, &validationErrors)
+			case tt.expectedError != nil:
+				assert.Equal(t, tt.expectedError, err)
+			default:
+				assert.NoError(t, err)
+			}
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFlowerServiceImpl_Delete(t *testing.T) {
+	// Create test logger
+	logger, _ := zap.NewDevelopment()
+
+	// Create test config
+	cfg := &config.Config{}
+
+	// Create test data
+	existingFlower := model.Flower{
+		ID:          1,
+		Name:        "Rose",
+		Species:     "Rosa",
+		Color:       "Red",
+		Description: "A red rose",
+	}
+
+	// Define test cases
+	tests := []struct {
+		name          string
+		flowerID      string
+		mockSetup     func(mockRepo *MockFlowerRepository)
+		expectedError error
+	}{
+		{
+			name:     "Success",
+			flowerID: "1",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// First call to FindByID to check if the flower exists
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.FlowerResult{
+					Data: &existingFlower,
+				}, nil)
+
+				// Second call to Delete to delete the flower
+				mockRepo.On("Delete", mock.Anything, uint64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "NotFound",
+			flowerID: "999",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// Flower not found
+				mockRepo.On("FindByID", mock.Anything, uint64(999)).Return(repository.FlowerResult{
+					Data: nil,
+				}, nil)
+			},
+			expectedError: ErrFlowerNotFound,
+		},
+		{
+			name:     "EmptyID",
+			flowerID: "",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for empty ID
+			},
+			expectedError: ErrInvalidFlowerData,
+		},
+		{
+			name:     "InvalidID",
+			flowerID: "invalid",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// No repository call expected for invalid ID
+			},
+			expectedError: ErrInvalidFlowerID,
+		},
+		{
+			name:     "FindByIDError",
+			flowerID: "1",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// Error during FindByID
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.FlowerResult{}, errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+		{
+			name:     "DeleteError",
+			flowerID: "1",
+			mockSetup: func(mockRepo *MockFlowerRepository) {
+				// First call to FindByID succeeds
+				mockRepo.On("FindByID", mock.Anything, uint64(1)).Return(repository.FlowerResult{
+					Data: &existingFlower,
+				}, nil)
+
+				// Second call to Delete fails
+				mockRepo.On("Delete", mock.Anything, uint64(1)).Return(errors.New("database error"))
+			},
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock repository
+			mockRepo := new(MockFlowerRepository)
+
+			// Setup the mock expectations
+			tt.mockSetup(mockRepo)
+
+			// Create service with mock repository
+			service := NewFlowerService(cfg, logger, mockRepo)
+
+			// Call the method being tested
+			err := service.Delete(context.Background(), tt.flowerID)
+
+			// Assert the error
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if tt.expectedError == ErrFlowerNotFound || tt.expectedError == ErrInvalidFlowerData || tt.expectedError == ErrInvalidFlowerID {
+					assert.Equal(t, tt.expectedError, err)
+				} else {
+					assert.Equal(t, tt.expectedError.Error(), err.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Verify that repository method was called
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
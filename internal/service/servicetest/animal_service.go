@@ -0,0 +1,74 @@
+// Package servicetest provides mock services for testing the packages that call them
+package servicetest
+
+import (
+	"context"
+
+	"github.com/linkeunid/go-api/internal/model"
+	"github.com/linkeunid/go-api/internal/repository"
+	"github.com/linkeunid/go-api/internal/service"
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAnimalService is a mock implementation of the service.AnimalService interface
+type MockAnimalService struct {
+	mock.Mock
+}
+
+func (m *MockAnimalService) GetAll(ctx context.Context) (service.AnimalCollectionResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(service.AnimalCollectionResponse), args.Error(1)
+}
+
+func (m *MockAnimalService) GetAllPaginated(ctx context.Context, params pagination.Params, filters repository.Filters) (service.AnimalCollectionResponse, error) {
+	args := m.Called(ctx, params, filters)
+	return args.Get(0).(service.AnimalCollectionResponse), args.Error(1)
+}
+
+func (m *MockAnimalService) GetByID(ctx context.Context, id string) (service.AnimalResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(service.AnimalResponse), args.Error(1)
+}
+
+func (m *MockAnimalService) Create(ctx context.Context, animal *model.Animal) error {
+	args := m.Called(ctx, animal)
+	return args.Error(0)
+}
+
+func (m *MockAnimalService) Update(ctx context.Context, id string, animal *model.Animal) error {
+	args := m.Called(ctx, id, animal)
+	return args.Error(0)
+}
+
+func (m *MockAnimalService) Patch(ctx context.Context, id string, fields map[string]interface{}) error {
+	args := m.Called(ctx, id, fields)
+	return args.Error(0)
+}
+
+func (m *MockAnimalService) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAnimalService) Import(ctx context.Context, animals []model.Animal) error {
+	args := m.Called(ctx, animals)
+	return args.Error(0)
+}
+
+func (m *MockAnimalService) Count(ctx context.Context, filters repository.Filters) (int64, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAnimalService) Export(ctx context.Context, sort, direction string, filters repository.Filters, fn func(batch []model.Animal) error) error {
+	args := m.Called(ctx, sort, direction, filters)
+	if batches, ok := args.Get(0).([][]model.Animal); ok {
+		for _, batch := range batches {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
@@ -0,0 +1,35 @@
+// Package version exposes build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/linkeunid/go-api/internal/version.version=v1.2.0 \
+//	  -X github.com/linkeunid/go-api/internal/version.commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/linkeunid/go-api/internal/version.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+//
+// `make build` sets all three
+package version
+
+import "runtime"
+
+// Build metadata, overridden with -ldflags "-X" at build time
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildTime = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version" xml:"version" example:"v1.2.0"`
+	Commit    string `json:"commit" xml:"commit" example:"4c8fa6c"`
+	BuildTime string `json:"buildTime" xml:"buildTime" example:"2025-05-01T12:00:00Z"`
+	GoVersion string `json:"goVersion" xml:"goVersion" example:"go1.24.2"`
+}
+
+// Get returns the metadata of the running build
+func Get() Info {
+	return Info{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+}
@@ -0,0 +1,4 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE `animals` ADD COLUMN `version` int unsigned NOT NULL DEFAULT 1 AFTER `description`;
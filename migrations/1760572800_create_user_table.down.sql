@@ -0,0 +1,3 @@
+-- Migration Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS `users`;
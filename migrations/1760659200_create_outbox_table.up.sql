@@ -0,0 +1,13 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `outbox` (
+    `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+    `topic` varchar(100) NOT NULL,
+    `event_type` varchar(50) NOT NULL,
+    `record_id` varchar(100) NOT NULL,
+    `payload` text,
+    `created_at` datetime(3) DEFAULT CURRENT_TIMESTAMP(3),
+    `dispatched_at` datetime(3) NULL DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_outbox_dispatched_at` (`dispatched_at`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+ALTER TABLE `users` ADD COLUMN `token_version` bigint unsigned NOT NULL DEFAULT 0 AFTER `scopes`;
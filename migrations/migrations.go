@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL migration files so they can be applied
+// without shipping the migrations directory next to the binary
+package migrations
+
+import "embed"
+
+// FS holds every *.sql migration in this directory, with the PostgreSQL
+// variants under postgres/
+//
+//go:embed *.sql postgres/*.sql
+var FS embed.FS
@@ -0,0 +1,18 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+CREATE TABLE IF NOT EXISTS "animals" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(255) NOT NULL,
+  "species" varchar(255) NOT NULL,
+  "age" bigint,
+  "description" varchar(255),
+  "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3),
+  "updated_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3)
+);
+
+CREATE INDEX IF NOT EXISTS "idx_animal_name" ON "animals" ("name");
+CREATE INDEX IF NOT EXISTS "idx_animal_species" ON "animals" ("species");
+CREATE INDEX IF NOT EXISTS "idx_animal_age" ON "animals" ("age");
+CREATE INDEX IF NOT EXISTS "idx_animal_created_at" ON "animals" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_animal_updated_at" ON "animals" ("updated_at");
@@ -0,0 +1,18 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS "flowers" (
+    "id" bigserial PRIMARY KEY,
+    "name" varchar(255) NOT NULL,
+    "species" varchar(255) NOT NULL,
+    "color" varchar(50) NOT NULL,
+    "description" varchar(1000),
+    "seasonal" boolean DEFAULT false,
+    "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3),
+    "updated_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3)
+);
+
+CREATE INDEX IF NOT EXISTS "idx_flower_name" ON "flowers" ("name");
+CREATE INDEX IF NOT EXISTS "idx_flower_species" ON "flowers" ("species");
+CREATE INDEX IF NOT EXISTS "idx_flower_color" ON "flowers" ("color");
+CREATE INDEX IF NOT EXISTS "idx_flower_created_at" ON "flowers" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_flower_updated_at" ON "flowers" ("updated_at");
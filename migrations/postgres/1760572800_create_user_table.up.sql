@@ -0,0 +1,13 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial PRIMARY KEY,
+    "email" varchar(255) NOT NULL,
+    "password_hash" varchar(255) NOT NULL,
+    "role" varchar(50) NOT NULL DEFAULT 'user',
+    "scopes" varchar(500) NOT NULL DEFAULT '',
+    "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3),
+    "updated_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_email" ON "users" ("email");
@@ -0,0 +1,13 @@
+-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS "outbox" (
+    "id" bigserial PRIMARY KEY,
+    "topic" varchar(100) NOT NULL,
+    "event_type" varchar(50) NOT NULL,
+    "record_id" varchar(100) NOT NULL,
+    "payload" text,
+    "created_at" timestamptz(3) DEFAULT CURRENT_TIMESTAMP(3),
+    "dispatched_at" timestamptz(3) NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_outbox_dispatched_at" ON "outbox" ("dispatched_at");
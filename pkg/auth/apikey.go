@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrAPIKeyInvalid is returned for API keys that don't match a configured key
+var ErrAPIKeyInvalid = errors.New("API key is invalid")
+
+// APIKey is the identity an API key authenticates as
+type APIKey struct {
+	UserID uint64
+	Role   string
+	Scopes []string
+}
+
+// APIKeyStore looks up the identity of an API key by its hash, as returned by HashAPIKey
+type APIKeyStore interface {
+	// Lookup returns the identity of the key hashed to hash, or ErrAPIKeyInvalid
+	Lookup(ctx context.Context, hash string) (APIKey, error)
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 digest keys are configured and looked up by,
+// so the keys themselves are never stored
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// StaticAPIKeys is an APIKeyStore holding a fixed set of keys
+type StaticAPIKeys map[string]APIKey
+
+// ParseAPIKeys builds a StaticAPIKeys from entries of the form <user_id>:<role>:<sha256 hex>,
+// optionally followed by :<scopes> with the scopes separated by spaces, as set in API_KEYS
+func ParseAPIKeys(entries []string) (StaticAPIKeys, error) {
+	keys := make(StaticAPIKeys, len(entries))
+	for i, entry := range entries {
+		// Scopes such as animals:write contain colons themselves, so they take the rest of the entry
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 4)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("API key %d: expected <user_id>:<role>:<sha256 hex>", i+1)
+		}
+
+		userID, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("API key %d: invalid user ID %q", i+1, parts[0])
+		}
+		if parts[1] == "" {
+			return nil, fmt.Errorf("API key %d: role is empty", i+1)
+		}
+		hash := strings.ToLower(parts[2])
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("API key %d: hash must be a hex-encoded SHA-256 digest", i+1)
+		}
+		if _, ok := keys[hash]; ok {
+			return nil, fmt.Errorf("API key %d: hash is configured more than once", i+1)
+		}
+
+		key := APIKey{UserID: userID, Role: parts[1]}
+		if len(parts) == 4 {
+			key.Scopes = strings.Fields(parts[3])
+		}
+		keys[hash] = key
+	}
+	return keys, nil
+}
+
+// Lookup implements APIKeyStore
+func (k StaticAPIKeys) Lookup(_ context.Context, hash string) (APIKey, error) {
+	key, ok := k[hash]
+	if !ok {
+		return APIKey{}, ErrAPIKeyInvalid
+	}
+	return key, nil
+}
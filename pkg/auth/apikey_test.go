@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIKeys(t *testing.T) {
+	hash := HashAPIKey("secret")
+
+	t.Run("Valid", func(t *testing.T) {
+		keys, err := ParseAPIKeys([]string{
+			"7:service:" + hash,
+			" 8:admin:" + strings.ToUpper(HashAPIKey("other")),
+			"9:service:" + HashAPIKey("scoped") + ":animals:read animals:write",
+		})
+		require.NoError(t, err)
+
+		key, err := keys.Lookup(context.Background(), HashAPIKey("secret"))
+		require.NoError(t, err)
+		assert.Equal(t, APIKey{UserID: 7, Role: "service"}, key)
+
+		key, err = keys.Lookup(context.Background(), HashAPIKey("other"))
+		require.NoError(t, err)
+		assert.Equal(t, APIKey{UserID: 8, Role: "admin"}, key)
+
+		key, err = keys.Lookup(context.Background(), HashAPIKey("scoped"))
+		require.NoError(t, err)
+		assert.Equal(t, APIKey{UserID: 9, Role: "service", Scopes: []string{"animals:read", "animals:write"}}, key)
+
+		_, err = keys.Lookup(context.Background(), HashAPIKey("guess"))
+		assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+	})
+
+	tests := []struct {
+		name    string
+		entry   string
+		message string
+	}{
+		{name: "MissingParts", entry: "7:" + hash, message: "expected <user_id>:<role>:<sha256 hex>"},
+		{name: "InvalidUserID", entry: "svc:service:" + hash, message: "invalid user ID"},
+		{name: "EmptyRole", entry: "7::" + hash, message: "role is empty"},
+		{name: "PlainKey", entry: "7:service:secret", message: "hex-encoded SHA-256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAPIKeys([]string{tt.entry})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.NotContains(t, err.Error(), hash, "errors shouldn't echo the configured hash")
+		})
+	}
+
+	t.Run("Duplicate", func(t *testing.T) {
+		_, err := ParseAPIKeys([]string{"7:service:" + hash, "8:service:" + hash})
+		assert.ErrorContains(t, err, "more than once")
+	})
+}
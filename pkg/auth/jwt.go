@@ -3,18 +3,23 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/util"
 )
 
 // Common JWT errors
 var (
 	ErrTokenExpired     = errors.New("token has expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
 	ErrTokenInvalid     = errors.New("token is invalid")
 	ErrTokenNotProvided = errors.New("token not provided")
 	ErrInvalidIssuer    = errors.New("token has invalid issuer")
+	ErrInvalidAudience  = errors.New("token has invalid audience")
 	ErrEmptySecret      = errors.New("JWT secret is empty")
 )
 
@@ -23,9 +28,20 @@ type Claims struct {
 	Username string `json:"username,omitempty"`
 	Role     string `json:"role,omitempty"`
 	Email    string `json:"email,omitempty"`
+	Scope    string `json:"scope,omitempty"`     // Space-separated scopes, as in RFC 8693
+	TokenUse string `json:"token_use,omitempty"` // TokenUseRefresh for refresh tokens, empty for access tokens
+	Version  uint64 `json:"ver,omitempty"`       // Token version of the user a refresh token was issued for
 	jwt.RegisteredClaims
 }
 
+// TokenUseRefresh marks tokens that can only be exchanged for new tokens, not used to authenticate requests
+const TokenUseRefresh = "refresh"
+
+// Scopes returns the scopes granted by the token
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
 // JWTService provides JWT operations
 type JWTService struct {
 	config *config.AuthConfig
@@ -38,27 +54,40 @@ func NewJWTService(cfg *config.AuthConfig) *JWTService {
 	}
 }
 
-// GenerateToken generates a new JWT token with the provided claims
-func (s *JWTService) GenerateToken(userID uint64, username, role, email string) (string, error) {
+// GenerateToken generates a new JWT token with the provided claims; scopes may be empty
+func (s *JWTService) GenerateToken(userID uint64, username, role, email string, scopes []string) (string, error) {
+	return s.sign(&Claims{
+		Username: username,
+		Role:     role,
+		Email:    email,
+		Scope:    strings.Join(scopes, " "),
+	}, userID, s.config.JWTExpiration)
+}
+
+// GenerateRefreshToken generates a long-lived token for userID that ValidateRefreshToken accepts
+// but authentication rejects, so it can only be exchanged for new tokens. version is the user's
+// token version, which the issuer bumps to revoke the token
+func (s *JWTService) GenerateRefreshToken(userID, version uint64) (string, error) {
+	return s.sign(&Claims{TokenUse: TokenUseRefresh, Version: version}, userID, s.config.RefreshExpiration)
+}
+
+// sign fills in the standard claims for userID and a token valid for expiration, and signs claims
+func (s *JWTService) sign(claims *Claims, userID uint64, expiration time.Duration) (string, error) {
 	if s.config.JWTSecret == "" {
 		return "", ErrEmptySecret
 	}
 
-	// Set the token expiration time
-	expirationTime := time.Now().Add(s.config.JWTExpiration)
-
-	// Create the JWT claims with standard and custom fields
-	claims := &Claims{
-		Username: username,
-		Role:     role,
-		Email:    email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			// Set standard claims
-			Issuer:    "linkeun-go-api",
-			Subject:   fmt.Sprintf("%d", userID),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
+	// Set standard claims
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        util.NewULID(),
+		Issuer:    "linkeun-go-api",
+		Subject:   fmt.Sprintf("%d", userID),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
+	}
+	if s.config.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.config.Audience}
 	}
 
 	// Create and sign the token with the secret key
@@ -71,8 +100,32 @@ func (s *JWTService) GenerateToken(userID uint64, username, role, email string)
 	return tokenString, nil
 }
 
-// ValidateToken validates the provided token and returns the claims
+// ValidateToken validates the provided access token and returns the claims
 func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenUse != "" {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// ValidateRefreshToken validates a token made by GenerateRefreshToken and returns the claims
+func (s *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	claims, err := s.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenUse != TokenUseRefresh {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// parse checks the signature, timing, issuer and audience of a token and returns its claims
+func (s *JWTService) parse(tokenString string) (*Claims, error) {
 	if tokenString == "" {
 		return nil, ErrTokenNotProvided
 	}
@@ -88,12 +141,16 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(s.config.JWTSecret), nil
-	})
+	}, jwt.WithLeeway(s.config.ClockSkew), jwt.WithIssuedAt())
 
 	// Handle parsing errors
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
 			return nil, ErrTokenExpired
+		case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+			// nbf or iat lies further in the future than the clock skew allows
+			return nil, ErrTokenNotYetValid
 		}
 		return nil, ErrTokenInvalid
 	}
@@ -113,6 +170,19 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 				return nil, ErrInvalidIssuer
 			}
 		}
+		// Check if the token is meant for this API (if configured)
+		if len(s.config.AllowedAudiences) > 0 {
+			audienceAllowed := false
+			for _, allowedAudience := range s.config.AllowedAudiences {
+				if slices.Contains(claims.Audience, allowedAudience) {
+					audienceAllowed = true
+					break
+				}
+			}
+			if !audienceAllowed {
+				return nil, ErrInvalidAudience
+			}
+		}
 		return claims, nil
 	}
 
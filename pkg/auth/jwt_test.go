@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signClaims signs claims with secret as another issuer's server would
+func signClaims(t *testing.T, secret string, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Role: "user", RegisteredClaims: claims}).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestValidateToken_Timing(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		skew        time.Duration
+		claims      jwt.RegisteredClaims
+		expectedErr error
+	}{
+		{
+			name:   "Valid",
+			claims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now), ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+		},
+		{
+			name:        "Expired",
+			claims:      jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute))},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name:   "ExpiredWithinSkew",
+			skew:   time.Minute,
+			claims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(-10 * time.Second))},
+		},
+		{
+			name:        "FutureNotBefore",
+			skew:        30 * time.Second,
+			claims:      jwt.RegisteredClaims{NotBefore: jwt.NewNumericDate(now.Add(time.Hour)), ExpiresAt: jwt.NewNumericDate(now.Add(2 * time.Hour))},
+			expectedErr: ErrTokenNotYetValid,
+		},
+		{
+			name:   "NotBeforeWithinSkew",
+			skew:   30 * time.Second,
+			claims: jwt.RegisteredClaims{NotBefore: jwt.NewNumericDate(now.Add(10 * time.Second)), ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+		},
+		{
+			name:        "FutureIssuedAt",
+			claims:      jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now.Add(time.Hour)), ExpiresAt: jwt.NewNumericDate(now.Add(2 * time.Hour))},
+			expectedErr: ErrTokenNotYetValid,
+		},
+		{
+			name:   "IssuedAtWithinSkew",
+			skew:   30 * time.Second,
+			claims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now.Add(10 * time.Second)), ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewJWTService(&config.AuthConfig{JWTSecret: "test-secret", ClockSkew: tt.skew})
+
+			claims, err := service.ValidateToken(signClaims(t, "test-secret", tt.claims))
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user", claims.Role)
+		})
+	}
+}
+
+func TestGenerateToken_Scopes(t *testing.T) {
+	service := NewJWTService(&config.AuthConfig{JWTSecret: "test-secret", JWTExpiration: time.Hour})
+
+	token, err := service.GenerateToken(7, "ann", "user", "ann@example.com", []string{"animals:read", "animals:write"})
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "animals:read animals:write", claims.Scope)
+	assert.Equal(t, []string{"animals:read", "animals:write"}, claims.Scopes())
+}
+
+func TestValidateToken_Audience(t *testing.T) {
+	tests := []struct {
+		name             string
+		audience         string
+		allowedAudiences []string
+		expectedErr      error
+	}{
+		{name: "NotChecked", audience: "other-service"},
+		{name: "NoAudienceNotChecked"},
+		{name: "Allowed", audience: "linkeun-go-api", allowedAudiences: []string{"admin-portal", "linkeun-go-api"}},
+		{name: "OtherService", audience: "other-service", allowedAudiences: []string{"linkeun-go-api"}, expectedErr: ErrInvalidAudience},
+		{name: "MissingAudience", allowedAudiences: []string{"linkeun-go-api"}, expectedErr: ErrInvalidAudience},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := NewJWTService(&config.AuthConfig{JWTSecret: "test-secret", JWTExpiration: time.Hour, Audience: tt.audience})
+			token, err := issuer.GenerateToken(7, "ann", "user", "ann@example.com", nil)
+			require.NoError(t, err)
+
+			validator := NewJWTService(&config.AuthConfig{JWTSecret: "test-secret", AllowedAudiences: tt.allowedAudiences})
+			claims, err := validator.ValidateToken(token)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.audience != "" {
+				assert.Equal(t, jwt.ClaimStrings{tt.audience}, claims.Audience)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoleRanker decides which roles may use a route that requires a given role
+type RoleRanker interface {
+	// AtLeast reports whether role is at or above required
+	AtLeast(role, required string) bool
+}
+
+// FlatRoles is a RoleRanker in which each role only satisfies itself
+type FlatRoles struct{}
+
+// AtLeast implements RoleRanker
+func (FlatRoles) AtLeast(role, required string) bool {
+	return role == required
+}
+
+// RoleHierarchy is a RoleRanker in which each role also satisfies the roles below it.
+// Roles outside the hierarchy only satisfy themselves
+type RoleHierarchy struct {
+	ranks map[string]int // Higher is more privileged
+}
+
+// NewRoleHierarchy creates a hierarchy from roles ordered from most to least privileged,
+// e.g. "admin", "editor", "user"
+func NewRoleHierarchy(roles ...string) (RoleHierarchy, error) {
+	ranks := make(map[string]int, len(roles))
+	for i, role := range roles {
+		role = strings.TrimSpace(role)
+		if role == "" {
+			return RoleHierarchy{}, fmt.Errorf("role %d of the hierarchy is empty", i+1)
+		}
+		if _, ok := ranks[role]; ok {
+			return RoleHierarchy{}, fmt.Errorf("role %q appears more than once in the hierarchy", role)
+		}
+		ranks[role] = len(roles) - i
+	}
+	return RoleHierarchy{ranks: ranks}, nil
+}
+
+// AtLeast implements RoleRanker
+func (h RoleHierarchy) AtLeast(role, required string) bool {
+	if role == required {
+		return true
+	}
+	rank, ok := h.ranks[role]
+	if !ok {
+		return false
+	}
+	requiredRank, ok := h.ranks[required]
+	return ok && rank >= requiredRank
+}
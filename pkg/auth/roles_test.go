@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatRoles(t *testing.T) {
+	assert.True(t, FlatRoles{}.AtLeast("admin", "admin"))
+	assert.False(t, FlatRoles{}.AtLeast("admin", "user"))
+}
+
+func TestRoleHierarchy(t *testing.T) {
+	hierarchy, err := NewRoleHierarchy("admin", " editor ", "user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{role: "admin", required: "user", expected: true},
+		{role: "admin", required: "editor", expected: true},
+		{role: "editor", required: "user", expected: true},
+		{role: "editor", required: "editor", expected: true},
+		{role: "user", required: "editor", expected: false},
+		{role: "editor", required: "admin", expected: false},
+		// Roles outside the hierarchy only satisfy themselves
+		{role: "auditor", required: "user", expected: false},
+		{role: "auditor", required: "auditor", expected: true},
+		{role: "admin", required: "auditor", expected: false},
+		{role: "", required: "user", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+">="+tt.required, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hierarchy.AtLeast(tt.role, tt.required))
+		})
+	}
+}
+
+func TestNewRoleHierarchy_Invalid(t *testing.T) {
+	_, err := NewRoleHierarchy("admin", "user", "admin")
+	assert.ErrorContains(t, err, `"admin" appears more than once`)
+
+	_, err = NewRoleHierarchy("admin", " ")
+	assert.ErrorContains(t, err, "role 2 of the hierarchy is empty")
+}
@@ -51,21 +51,65 @@ func GenerateHashKey(entity string, params map[string]interface{}) string {
 // Generic key generators for common patterns
 
 // GenerateListKey creates a key for paginated entity lists
-func GenerateListKey(entity string, page, limit int, sort, direction string) string {
+// Active filters, selected fields and preloaded associations are included so different filter
+// sets, field selections or includes never share a key; pass nil fields for full records and
+// nil includes for records without associations
+func GenerateListKey(entity string, page, limit int, sort, direction string, filters map[string]string, fields, includes []string) string {
 	params := map[string]interface{}{
 		"page":      page,
 		"limit":     limit,
 		"sort":      sort,
 		"direction": direction,
+		"fields":    strings.Join(fields, ","),
+		"include":   strings.Join(includes, ","),
+	}
+	for k, v := range filters {
+		params["filter."+k] = v
 	}
 	return GenerateKey(entity+":list", params)
 }
 
+// GenerateCountKey creates a key for the number of entities matching filters
+// It lives under the list namespace so whatever invalidates an entity's lists also drops its counts
+func GenerateCountKey(entity string, filters map[string]string) string {
+	params := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		params["filter."+k] = v
+	}
+	return GenerateKey(entity+":list:count", params)
+}
+
+// GenerateListPattern creates a wildcard pattern matching every list key of an entity
+// It mirrors the "<version>:<entity>:list:<params>" shape produced by GenerateListKey
+func GenerateListPattern(entity string) string {
+	return fmt.Sprintf("%s:%s:list:*", CurrentVersion, entity)
+}
+
+// GenerateEntityPattern creates a wildcard pattern matching every key of an entity: its items,
+// field selections and lists
+func GenerateEntityPattern(entity string) string {
+	return fmt.Sprintf("%s:%s:*", CurrentVersion, entity)
+}
+
 // GenerateItemKey creates a key for single entity items
 func GenerateItemKey(entity string, id interface{}) string {
 	return fmt.Sprintf("%s:%s:item:%v", CurrentVersion, entity, id)
 }
 
+// GenerateItemFieldsKey creates a key for a single entity item trimmed to the selected fields
+// It falls back to GenerateItemKey when no fields are selected
+func GenerateItemFieldsKey(entity string, id interface{}, fields []string) string {
+	if len(fields) == 0 {
+		return GenerateItemKey(entity, id)
+	}
+	return fmt.Sprintf("%s:fields=%s", GenerateItemKey(entity, id), strings.Join(fields, ","))
+}
+
+// GenerateItemFieldsPattern creates a wildcard pattern matching every field selection of an item
+func GenerateItemFieldsPattern(entity string, id interface{}) string {
+	return GenerateItemKey(entity, id) + ":fields=*"
+}
+
 // GenerateQueryKey creates a key for custom queries
 func GenerateQueryKey(entity string, query string) string {
 	// Use hash for query to avoid long keys
@@ -1,21 +1,25 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 // Init loads .env file only in development mode
 func init() {
-	// Check if APP_ENV is set and not 'development'
-	env := os.Getenv("APP_ENV")
-	if env != "" && env != "development" {
-		// Don't load .env in non-development environments
+	// Don't load .env in non-development environments
+	if !loadsEnvFile() {
 		return
 	}
 
@@ -31,148 +35,657 @@ func init() {
 	}
 }
 
+// loadsEnvFile reports whether the .env file is read, which is only in development mode,
+// i.e. when APP_ENV is unset or 'development'
+func loadsEnvFile() bool {
+	env := os.Getenv("APP_ENV")
+	return env == "" || env == "development"
+}
+
+// ReloadEnvFile reads the .env file again, when it is read at all, and sets the given keys from
+// it in the process environment, replacing their current values. Keys the file doesn't set, or
+// a missing file, leave the environment as it is
+func ReloadEnvFile(keys ...string) error {
+	if !loadsEnvFile() {
+		return nil
+	}
+
+	values, err := godotenv.Read()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			if err := os.Setenv(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Config represents application configuration
 type Config struct {
-	Environment string
-	Server      ServerConfig
-	Database    DatabaseConfig
-	Redis       RedisConfig
-	Logging     LoggingConfig
-	Auth        AuthConfig
+	Environment string            `yaml:"environment"`
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Migration   MigrationConfig   `yaml:"migration"`
+	Redis       RedisConfig       `yaml:"redis"`
+	Cache       CacheConfig       `yaml:"cache"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Maintenance MaintenanceConfig `yaml:"maintenance"`
+	Pagination  PaginationConfig  `yaml:"pagination"`
+	Validation  ValidationConfig  `yaml:"validation"`
+	Logging     LoggingConfig     `yaml:"logging"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Telemetry   TelemetryConfig   `yaml:"telemetry"`
+	GraphQL     GraphQLConfig     `yaml:"graphql"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	Service     ServiceConfig     `yaml:"service"`
+	Events      EventsConfig      `yaml:"events"`
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port            int
-	ReadTimeout     time.Duration
-	WriteTimeout    time.Duration
-	ShutdownTimeout time.Duration
+	Port            int           `yaml:"port"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	RequestTimeout  time.Duration `yaml:"request_timeout"` // Default time allowed to handle a request before responding with 504
+	MaxBodyBytes    int64         `yaml:"max_body_bytes"`  // Maximum allowed request body size in bytes
+	ResponseCase    string        `yaml:"response_case"`   // Naming of JSON response keys: "snake" or "camel"
+	BasePath        string        `yaml:"base_path"`       // Path the API routes are mounted under, e.g. /api/v1; "/" mounts them at the root
+	AllowedOrigins  []string      `yaml:"allowed_origins"` // Origins browsers may call the API and open WebSockets from; "*" allows any
 }
 
+// JSON response key naming styles
+const (
+	ResponseCaseSnake = "snake" // Keys as the models declare them, e.g. created_at
+	ResponseCaseCamel = "camel" // Keys rewritten in camelCase, e.g. createdAt
+)
+
+// Database driver identifiers
+const (
+	DBDriverMySQL    = "mysql"
+	DBDriverPostgres = "postgres"
+)
+
+// Primary key types for new resources
+const (
+	IDTypeInt  = "int"  // Auto-increment bigint
+	IDTypeULID = "ulid" // ULID stored as char(26)
+)
+
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	DSN             string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Driver             string        `yaml:"driver"`  // Database driver: "mysql" or "postgres"
+	IDType             string        `yaml:"id_type"` // Primary key type of scaffolded resources: "int" or "ulid"
+	DSN                string        `yaml:"dsn"`
+	MaxOpenConns       int           `yaml:"max_open_conns"`
+	MaxIdleConns       int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime    time.Duration `yaml:"conn_max_lifetime"`
+	ConnectRetries     int           `yaml:"connect_retries"`      // Connection attempts after the first before startup fails
+	ConnectBackoff     time.Duration `yaml:"connect_backoff"`      // Wait before the first retry; doubles after each failure
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"` // Queries taking longer are logged as warnings and counted; 0 disables it
+}
+
+// MigrationConfig holds the startup check of the database schema version
+type MigrationConfig struct {
+	Check  bool `yaml:"check"`  // Compare the schema_migrations version with the newest embedded migration at startup
+	Strict bool `yaml:"strict"` // Fail startup instead of logging a warning when the database is behind or dirty
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
-	Enabled      bool
-	Host         string
-	Port         int
-	Password     string
-	DB           int
-	CacheTTL     time.Duration
-	PaginatedTTL time.Duration
-	QueryCache   bool
-	KeyPrefix    string
-	PoolSize     int
+	Enabled          bool          `yaml:"enabled"`
+	Host             string        `yaml:"host"`
+	Port             int           `yaml:"port"`
+	Password         string        `yaml:"password"`
+	DB               int           `yaml:"db"`
+	CacheTTL         time.Duration `yaml:"cache_ttl"`
+	PaginatedTTL     time.Duration `yaml:"paginated_ttl"`
+	NegativeTTL      time.Duration `yaml:"negative_ttl"` // How long an empty query result is cached; 0 disables
+	CountTTL         time.Duration `yaml:"count_ttl"`    // How long a collection count is cached; 0 disables
+	QueryCache       bool          `yaml:"query_cache"`
+	KeyPrefix        string        `yaml:"key_prefix"`
+	PoolSize         int           `yaml:"pool_size"`
+	OpTimeout        time.Duration `yaml:"op_timeout"`        // Deadline of each Redis command; 0 leaves only the request's deadline
+	BreakerThreshold int           `yaml:"breaker_threshold"` // Consecutive Redis failures that stop cache calls; 0 disables
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`  // How long cache calls are skipped before Redis is probed again
+}
+
+// Cache backend identifiers
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendMemory = "memory"
+	CacheBackendNone   = "none"
+)
+
+// CacheConfig holds cache backend configuration
+// TTL and query caching settings are shared with RedisConfig
+type CacheConfig struct {
+	Backend               string        `yaml:"backend"`                 // Cache backend: "redis", "memory", or "none"
+	MemoryMaxItems        int           `yaml:"memory_max_items"`        // Maximum number of entries kept by the in-memory backend
+	MemoryCleanupInterval time.Duration `yaml:"memory_cleanup_interval"` // How often expired in-memory entries are purged
+	WarmOnStart           bool          `yaml:"warm_on_start"`           // Preload the first page of each collection into Redis at startup
+}
+
+// RateLimitConfig holds request rate limiting configuration
+type RateLimitConfig struct {
+	RPS     float64 `yaml:"rps"`      // Sustained requests per second allowed per client; 0 disables rate limiting
+	Burst   int     `yaml:"burst"`    // Maximum number of requests a client can make at once
+	IPRPS   float64 `yaml:"ip_rps"`   // Sustained requests per second allowed per IP address, shared by its clients; 0 disables it
+	IPBurst int     `yaml:"ip_burst"` // Maximum number of requests an IP address can make at once
+}
+
+// ServiceConfig holds configuration for the service layer
+type ServiceConfig struct {
+	OperationTimeout time.Duration `yaml:"operation_timeout"` // Longest a service call may take unless the caller's deadline is earlier; 0 disables it
+}
+
+// EventsConfig holds configuration for change events
+type EventsConfig struct {
+	OutboxEnabled      bool          `yaml:"outbox_enabled"`       // Store events in the outbox table with each write and publish them from there
+	OutboxPollInterval time.Duration `yaml:"outbox_poll_interval"` // How often the dispatcher looks for undelivered events
+	OutboxBatchSize    int           `yaml:"outbox_batch_size"`    // Most events published per poll
+	OutboxRetention    time.Duration `yaml:"outbox_retention"`     // How long delivered events are kept before they are deleted; 0 keeps them
+}
+
+// IdempotencyConfig holds configuration for replaying requests sent with an Idempotency-Key header
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl"` // How long a response is kept for replay
+}
+
+// MaintenanceConfig holds configuration for maintenance mode, which rejects writes while reads stay available
+type MaintenanceConfig struct {
+	Enabled    bool          `yaml:"enabled"`     // Enable maintenance mode at startup; it can also be toggled through PUT /admin/maintenance
+	Methods    []string      `yaml:"methods"`     // HTTP methods rejected with 503 while maintenance mode is enabled
+	RetryAfter time.Duration `yaml:"retry_after"` // Sent in the Retry-After header of rejected requests
+}
+
+// PaginationConfig holds page size limits for list endpoints
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"default_limit"` // Items per page when a request doesn't set a limit
+	MaxLimit     int `yaml:"max_limit"`     // Largest limit a request may ask for; list routes reject larger limits and GraphQL clamps them
+}
+
+// ValidationConfig holds request validation configuration
+type ValidationConfig struct {
+	AllowedSpecies []string `yaml:"allowed_species"` // Values accepted by the species rule; empty accepts any species
+	StrictTypes    bool     `yaml:"strict_types"`    // Reject numbers and booleans sent as JSON strings instead of converting them
+	StrictJSON     bool     `yaml:"strict_json"`     // Reject request bodies with fields the model doesn't have
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level          string
-	Format         string
-	OutputPath     string
-	FileOutputPath string // Path to log file if file logging is enabled
-	FileMaxSize    int    // Maximum size of log files in megabytes before rotation
-	FileMaxBackups int    // Maximum number of old log files to retain
-	FileMaxAge     int    // Maximum number of days to retain old log files
-	FileCompress   bool   // Whether to compress rotated log files
-	RotationType   string // Type of log rotation: "daily" or "size" (default: "daily")
+	Level          string   `yaml:"level"`
+	Format         string   `yaml:"format"`
+	OutputPath     string   `yaml:"output_path"`
+	FileOutputPath string   `yaml:"file_output_path"` // Path to log file if file logging is enabled
+	FileMaxSize    int      `yaml:"file_max_size"`    // Maximum size of log files in megabytes before rotation
+	FileMaxBackups int      `yaml:"file_max_backups"` // Maximum number of old log files to retain
+	FileMaxAge     int      `yaml:"file_max_age"`     // Maximum number of days to retain old log files
+	FileCompress   bool     `yaml:"file_compress"`    // Whether to compress rotated log files
+	RotationType   string   `yaml:"rotation_type"`    // Type of log rotation: "daily" or "size" (default: "daily")
+	RedactFields   []string `yaml:"redact_fields"`    // Field names whose values are masked in logs, matched as case-insensitive substrings
 }
 
+// DefaultRedactFields are the field names whose values are masked in logs unless LOG_REDACT_FIELDS is set
+var DefaultRedactFields = []string{"password", "secret", "token", "authorization"}
+
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
-	Enabled        bool          // Whether authentication is enabled
-	JWTSecret      string        // Secret key for JWT signing
-	JWTExpiration  time.Duration // JWT expiration time
-	AllowedIssuers []string      // Allowed JWT issuers
+	Enabled           bool          `yaml:"enabled"`            // Whether authentication is enabled
+	JWTSecret         string        `yaml:"jwt_secret"`         // Secret key for JWT signing
+	JWTExpiration     time.Duration `yaml:"jwt_expiration"`     // JWT expiration time
+	RefreshExpiration time.Duration `yaml:"refresh_expiration"` // Lifetime of refresh tokens issued at login
+	ClockSkew         time.Duration `yaml:"clock_skew"`         // Leeway for clock differences when checking a JWT's exp, nbf and iat
+	AllowedIssuers    []string      `yaml:"allowed_issuers"`    // Allowed JWT issuers
+	Audience          string        `yaml:"audience"`           // Audience set in generated JWTs; empty leaves aud out
+	AllowedAudiences  []string      `yaml:"allowed_audiences"`  // Audiences a JWT must name one of; empty skips the check
+	APIKeys           []string      `yaml:"api_keys"`           // API keys as <user_id>:<role>:<sha256 hex of the key>[:<scopes>]
+	RoleHierarchy     []string      `yaml:"role_hierarchy"`     // Roles from most to least privileged; empty matches roles exactly
+	LoginRPS          float64       `yaml:"login_rps"`          // Login attempts allowed per second for each client IP
+	LoginBurst        int           `yaml:"login_burst"`        // Login attempts a client IP may make at once
+}
+
+// TelemetryConfig holds OpenTelemetry tracing configuration
+type TelemetryConfig struct {
+	Enabled     bool    `yaml:"enabled"`      // Whether traces are recorded and exported
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP collector endpoint as host:port
+	Insecure    bool    `yaml:"insecure"`     // Export over plain HTTP instead of HTTPS
+	ServiceName string  `yaml:"service_name"` // Service name reported on every span
+	SampleRatio float64 `yaml:"sample_ratio"` // Fraction of new traces to sample, from 0 to 1
+}
+
+// GraphQLConfig holds configuration of the GraphQL endpoint
+type GraphQLConfig struct {
+	Enabled bool `yaml:"enabled"` // Serve the GraphQL API on <base path>/graphql
+}
+
+// MetricsConfig holds configuration of the Prometheus metrics endpoint
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"` // Serve Prometheus metrics on /metrics
+	Token   string `yaml:"token"`   // Bearer token scrapers must send; empty leaves the endpoint open
 }
 
-// LoadConfig loads application configuration from environment variables
+// EnvError describes an environment variable whose value could not be parsed
+type EnvError struct {
+	Key      string // Name of the environment variable
+	Value    string // The value that failed to parse
+	Expected string // The kind of value that was expected, e.g. "integer"
+	Default  any    // The value used instead
+}
+
+// Error implements the error interface
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("%s=%q is not a valid %s", e.Key, e.Value, e.Expected)
+}
+
+// LoadConfig loads application configuration from environment variables.
+// Malformed values are replaced by their defaults, with a warning on stderr for each
 func LoadConfig() *Config {
-	// Get current environment
-	env := getEnv("APP_ENV", "development")
+	p := &envParser{}
+	cfg := p.load(defaultConfig(getEnv("APP_ENV", "development")))
+	p.warn()
 
-	// Prepare DSN if not explicitly provided
-	dsn := getEnv("DSN", "")
-	if dsn == "" {
-		// Build DSN from individual DB_* environment variables
-		dbUser := getEnv("DB_USER", "root")
-		dbPassword := getEnv("DB_PASSWORD", "root")
-		dbHost := getEnv("DB_HOST", "localhost")
-
-		// Use environment-specific default port if not specified
-		var defaultDBPort int
-		if env == "production" {
-			// In production, prefer 3306 as default port
-			defaultDBPort = 3306
-			// For production Docker environments, prefer mysql hostname
-			if dbHost == "localhost" && os.Getenv("DOCKER_ENV") == "true" {
-				dbHost = "mysql"
-			}
-		} else {
-			// In development, prefer 3307 as default port
-			defaultDBPort = 3307
+	return cfg
+}
+
+// LoadConfigStrict loads application configuration like LoadConfig, but returns an error
+// listing every malformed value instead of falling back to defaults. Each wrapped error is an *EnvError
+func LoadConfigStrict() (*Config, error) {
+	p := &envParser{}
+	cfg := p.load(defaultConfig(getEnv("APP_ENV", "development")))
+
+	if len(p.errs) > 0 {
+		errs := make([]error, len(p.errs))
+		for i, err := range p.errs {
+			errs[i] = err
 		}
+		return nil, fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
 
-		dbPort := getEnvAsInt("DB_PORT", defaultDBPort)
-		dbName := getEnv("DB_NAME", "linkeun_go_api")
-		dbParams := getEnv("DB_PARAMS", "charset=utf8mb4&parseTime=True&loc=Local")
+	return cfg, nil
+}
 
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
-			dbUser, dbPassword, dbHost, dbPort, dbName, dbParams)
+// LoadConfigFromFile loads application configuration from a YAML or JSON file, then applies
+// environment variables on top so they override values from the file. Durations in the file
+// are written as strings like "30s". Malformed environment values are handled like LoadConfig
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Environment-specific defaults depend on the environment, which the file may set
+	var fileEnv struct {
+		Environment string `yaml:"environment"`
+	}
+	if err := yaml.Unmarshal(data, &fileEnv); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	env := fileEnv.Environment
+	if env == "" {
+		env = "development"
+	}
+
+	// JSON is a subset of YAML, so one decoder handles both formats
+	cfg := defaultConfig(getEnv("APP_ENV", env))
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	p := &envParser{}
+	cfg = p.load(cfg)
+	p.warn()
+
+	return cfg, nil
+}
+
+// envParser reads typed environment variables, recording the ones that fail to parse
+type envParser struct {
+	errs []*EnvError
+}
+
+// invalid records that key holds a value that isn't a valid expected, reporting each key once
+func (p *envParser) invalid(key, value, expected string, defaultValue any) {
+	for _, err := range p.errs {
+		if err.Key == key {
+			return
+		}
+	}
+	p.errs = append(p.errs, &EnvError{Key: key, Value: value, Expected: expected, Default: defaultValue})
+}
+
+// warn prints a warning on stderr for each malformed value
+func (p *envParser) warn() {
+	for _, err := range p.errs {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using default %v\n", err, err.Default)
+	}
+}
+
+// defaultConfig returns the configuration used for values that aren't set elsewhere
+func defaultConfig(env string) *Config {
+	// Development uses non-standard ports so local services don't clash with system ones
+	redisPort := 6380
+	logLevel := "info"
+	if env == "production" {
+		redisPort = 6379
+		logLevel = "error"
+	}
+
+	return &Config{
+		Environment: env,
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+			RequestTimeout:  30 * time.Second,
+			MaxBodyBytes:    1 << 20,
+			ResponseCase:    ResponseCaseSnake,
+			BasePath:        "/api/v1",
+			AllowedOrigins:  []string{"*"},
+		},
+		Database: DatabaseConfig{
+			Driver:             DBDriverMySQL,
+			IDType:             IDTypeInt,
+			MaxOpenConns:       25,
+			MaxIdleConns:       25,
+			ConnMaxLifetime:    5 * time.Minute,
+			ConnectRetries:     5,
+			ConnectBackoff:     time.Second,
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		Migration: MigrationConfig{
+			Check: true,
+		},
+		Redis: RedisConfig{
+			Host:             "localhost",
+			Port:             redisPort,
+			CacheTTL:         15 * time.Minute,
+			PaginatedTTL:     5 * time.Minute,
+			NegativeTTL:      30 * time.Second,
+			CountTTL:         30 * time.Second,
+			QueryCache:       true,
+			KeyPrefix:        "linkeun_api:",
+			PoolSize:         10,
+			OpTimeout:        100 * time.Millisecond,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		Cache: CacheConfig{
+			MemoryMaxItems:        10000,
+			MemoryCleanupInterval: time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			RPS:     10,
+			Burst:   20,
+			IPRPS:   50,
+			IPBurst: 100,
+		},
+		Service: ServiceConfig{
+			OperationTimeout: 5 * time.Second,
+		},
+		Events: EventsConfig{
+			OutboxEnabled:      false,
+			OutboxPollInterval: time.Second,
+			OutboxBatchSize:    100,
+			OutboxRetention:    24 * time.Hour,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+		Maintenance: MaintenanceConfig{
+			Methods:    []string{"POST", "PUT", "PATCH", "DELETE"},
+			RetryAfter: time.Minute,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: 10,
+			MaxLimit:     100,
+		},
+		Validation: ValidationConfig{
+			AllowedSpecies: []string{},
+		},
+		Logging: LoggingConfig{
+			Level:          logLevel,
+			Format:         "json",
+			OutputPath:     "stdout",
+			FileMaxSize:    100,
+			FileMaxBackups: 3,
+			FileMaxAge:     28,
+			FileCompress:   true,
+			RotationType:   "daily",
+			RedactFields:   slices.Clone(DefaultRedactFields),
+		},
+		Auth: AuthConfig{
+			JWTExpiration:     24 * time.Hour,
+			RefreshExpiration: 7 * 24 * time.Hour,
+			ClockSkew:         30 * time.Second,
+			LoginRPS:          0.2,
+			LoginBurst:        5,
+			AllowedIssuers:    []string{},
+		},
+		Telemetry: TelemetryConfig{
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "linkeun-go-api",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// load builds the configuration from environment variables, using the values in d
+// for variables that aren't set
+func (p *envParser) load(d *Config) *Config {
+	// Get current environment
+	env := getEnv("APP_ENV", d.Environment)
+
+	// Prepare DSN if not explicitly provided
+	dbDriver := p.getDBDriver(d.Database.Driver)
+	dsn := getEnv("DSN", d.Database.DSN)
+	if dsn == "" {
+		dsn = p.buildDSN(env, dbDriver)
 	}
 
+	redisEnabled := p.getEnvAsBool("REDIS_ENABLED", d.Redis.Enabled)
+
 	return &Config{
 		Environment: env,
 		Server: ServerConfig{
-			Port:            getEnvAsInt("PORT", 8080),
-			ReadTimeout:     getEnvAsDuration("SERVER_READ_TIMEOUT", 10*time.Second),
-			WriteTimeout:    getEnvAsDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
-			ShutdownTimeout: getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
+			Port:            p.getEnvAsInt("PORT", d.Server.Port),
+			ReadTimeout:     p.getEnvAsDuration("SERVER_READ_TIMEOUT", d.Server.ReadTimeout),
+			WriteTimeout:    p.getEnvAsDuration("SERVER_WRITE_TIMEOUT", d.Server.WriteTimeout),
+			ShutdownTimeout: p.getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", d.Server.ShutdownTimeout),
+			RequestTimeout:  p.getEnvAsDuration("SERVER_REQUEST_TIMEOUT", d.Server.RequestTimeout),
+			MaxBodyBytes:    p.getEnvAsInt64("SERVER_MAX_BODY_BYTES", d.Server.MaxBodyBytes),
+			ResponseCase:    strings.ToLower(getEnv("RESPONSE_CASE", d.Server.ResponseCase)),
+			BasePath:        normalizeBasePath(getEnv("SERVER_BASE_PATH", d.Server.BasePath)),
+			AllowedOrigins:  getEnvAsSlice("CORS_ALLOWED_ORIGINS", d.Server.AllowedOrigins, ","),
 		},
 		Database: DatabaseConfig{
-			DSN:             dsn,
-			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 25),
-			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+			Driver:             dbDriver,
+			IDType:             p.getIDType(d.Database.IDType),
+			DSN:                dsn,
+			MaxOpenConns:       p.getEnvAsInt("DB_MAX_OPEN_CONNS", d.Database.MaxOpenConns),
+			MaxIdleConns:       p.getEnvAsInt("DB_MAX_IDLE_CONNS", d.Database.MaxIdleConns),
+			ConnMaxLifetime:    p.getEnvAsDuration("DB_CONN_MAX_LIFETIME", d.Database.ConnMaxLifetime),
+			ConnectRetries:     p.getEnvAsInt("DB_CONNECT_RETRIES", d.Database.ConnectRetries),
+			ConnectBackoff:     p.getEnvAsDuration("DB_CONNECT_BACKOFF", d.Database.ConnectBackoff),
+			SlowQueryThreshold: p.getEnvAsDuration("DB_SLOW_QUERY_THRESHOLD", d.Database.SlowQueryThreshold),
+		},
+		Migration: MigrationConfig{
+			Check:  p.getEnvAsBool("MIGRATION_CHECK", d.Migration.Check),
+			Strict: p.getEnvAsBool("MIGRATION_STRICT", d.Migration.Strict),
 		},
 		Redis: RedisConfig{
-			Enabled:      getEnvAsBool("REDIS_ENABLED", false),
-			Host:         getEnv("REDIS_HOST", "localhost"),
-			Port:         getRedisPort(env),
-			Password:     getEnv("REDIS_PASSWORD", ""),
-			DB:           getEnvAsInt("REDIS_DB", 0),
-			CacheTTL:     getEnvAsDuration("REDIS_CACHE_TTL", 15*time.Minute),
-			PaginatedTTL: getEnvAsDuration("REDIS_PAGINATED_TTL", 5*time.Minute),
-			QueryCache:   getEnvAsBool("REDIS_QUERY_CACHING", true),
-			KeyPrefix:    getEnv("REDIS_KEY_PREFIX", "linkeun_api:"),
-			PoolSize:     getEnvAsInt("REDIS_POOL_SIZE", 10),
+			Enabled:          redisEnabled,
+			Host:             getEnv("REDIS_HOST", d.Redis.Host),
+			Port:             p.getEnvAsInt("REDIS_PORT", d.Redis.Port),
+			Password:         getEnv("REDIS_PASSWORD", d.Redis.Password),
+			DB:               p.getEnvAsInt("REDIS_DB", d.Redis.DB),
+			CacheTTL:         p.getEnvAsDuration("REDIS_CACHE_TTL", d.Redis.CacheTTL),
+			PaginatedTTL:     p.getEnvAsDuration("REDIS_PAGINATED_TTL", d.Redis.PaginatedTTL),
+			NegativeTTL:      p.getEnvAsDuration("REDIS_NEGATIVE_TTL", d.Redis.NegativeTTL),
+			CountTTL:         p.getEnvAsDuration("REDIS_COUNT_TTL", d.Redis.CountTTL),
+			QueryCache:       p.getEnvAsBool("REDIS_QUERY_CACHING", d.Redis.QueryCache),
+			KeyPrefix:        getEnv("REDIS_KEY_PREFIX", d.Redis.KeyPrefix),
+			PoolSize:         p.getEnvAsInt("REDIS_POOL_SIZE", d.Redis.PoolSize),
+			OpTimeout:        p.getEnvAsDuration("REDIS_OP_TIMEOUT", d.Redis.OpTimeout),
+			BreakerThreshold: p.getEnvAsInt("REDIS_BREAKER_THRESHOLD", d.Redis.BreakerThreshold),
+			BreakerCooldown:  p.getEnvAsDuration("REDIS_BREAKER_COOLDOWN", d.Redis.BreakerCooldown),
+		},
+		Cache: CacheConfig{
+			Backend:               getCacheBackend(d.Cache.Backend, redisEnabled),
+			MemoryMaxItems:        p.getEnvAsInt("CACHE_MEMORY_MAX_ITEMS", d.Cache.MemoryMaxItems),
+			MemoryCleanupInterval: p.getEnvAsDuration("CACHE_MEMORY_CLEANUP_INTERVAL", d.Cache.MemoryCleanupInterval),
+			WarmOnStart:           p.getEnvAsBool("CACHE_WARM_ON_START", d.Cache.WarmOnStart),
+		},
+		RateLimit: RateLimitConfig{
+			RPS:     p.getEnvAsFloat64("RATE_LIMIT_RPS", d.RateLimit.RPS),
+			Burst:   p.getEnvAsInt("RATE_LIMIT_BURST", d.RateLimit.Burst),
+			IPRPS:   p.getEnvAsFloat64("RATE_LIMIT_IP_RPS", d.RateLimit.IPRPS),
+			IPBurst: p.getEnvAsInt("RATE_LIMIT_IP_BURST", d.RateLimit.IPBurst),
+		},
+		Service: ServiceConfig{
+			OperationTimeout: p.getEnvAsDuration("SERVICE_OPERATION_TIMEOUT", d.Service.OperationTimeout),
+		},
+		Events: EventsConfig{
+			OutboxEnabled:      p.getEnvAsBool("EVENTS_OUTBOX_ENABLED", d.Events.OutboxEnabled),
+			OutboxPollInterval: p.getEnvAsDuration("EVENTS_OUTBOX_POLL_INTERVAL", d.Events.OutboxPollInterval),
+			OutboxBatchSize:    p.getEnvAsInt("EVENTS_OUTBOX_BATCH_SIZE", d.Events.OutboxBatchSize),
+			OutboxRetention:    p.getEnvAsDuration("EVENTS_OUTBOX_RETENTION", d.Events.OutboxRetention),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: p.getEnvAsDuration("IDEMPOTENCY_TTL", d.Idempotency.TTL),
+		},
+		Maintenance: MaintenanceConfig{
+			Enabled:    p.getEnvAsBool("MAINTENANCE_MODE", d.Maintenance.Enabled),
+			Methods:    getEnvAsSlice("MAINTENANCE_METHODS", d.Maintenance.Methods, ","),
+			RetryAfter: p.getEnvAsDuration("MAINTENANCE_RETRY_AFTER", d.Maintenance.RetryAfter),
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: p.getEnvAsInt("PAGINATION_DEFAULT_LIMIT", d.Pagination.DefaultLimit),
+			MaxLimit:     p.getEnvAsInt("PAGINATION_MAX_LIMIT", d.Pagination.MaxLimit),
+		},
+		Validation: ValidationConfig{
+			AllowedSpecies: getEnvAsSlice("VALIDATION_ALLOWED_SPECIES", d.Validation.AllowedSpecies, ","),
+			StrictTypes:    p.getEnvAsBool("VALIDATION_STRICT_TYPES", d.Validation.StrictTypes),
+			StrictJSON:     p.getEnvAsBool("STRICT_JSON", d.Validation.StrictJSON),
 		},
 		Logging: LoggingConfig{
-			Level:          getLogLevel(env),
-			Format:         getEnv("LOG_FORMAT", "json"),
-			OutputPath:     getEnv("LOG_OUTPUT_PATH", "stdout"),
-			FileOutputPath: getEnv("LOG_FILE_PATH", ""),
-			FileMaxSize:    getEnvAsInt("LOG_FILE_MAX_SIZE", 100),
-			FileMaxBackups: getEnvAsInt("LOG_FILE_MAX_BACKUPS", 3),
-			FileMaxAge:     getEnvAsInt("LOG_FILE_MAX_AGE", 28),
-			FileCompress:   getEnvAsBool("LOG_FILE_COMPRESS", true),
-			RotationType:   getEnv("LOG_ROTATION_TYPE", "daily"),
+			Level:          getEnv("LOG_LEVEL", d.Logging.Level),
+			Format:         getEnv("LOG_FORMAT", d.Logging.Format),
+			OutputPath:     getEnv("LOG_OUTPUT_PATH", d.Logging.OutputPath),
+			FileOutputPath: getEnv("LOG_FILE_PATH", d.Logging.FileOutputPath),
+			FileMaxSize:    p.getEnvAsInt("LOG_FILE_MAX_SIZE", d.Logging.FileMaxSize),
+			FileMaxBackups: p.getEnvAsInt("LOG_FILE_MAX_BACKUPS", d.Logging.FileMaxBackups),
+			FileMaxAge:     p.getEnvAsInt("LOG_FILE_MAX_AGE", d.Logging.FileMaxAge),
+			FileCompress:   p.getEnvAsBool("LOG_FILE_COMPRESS", d.Logging.FileCompress),
+			RotationType:   getEnv("LOG_ROTATION_TYPE", d.Logging.RotationType),
+			RedactFields:   getEnvAsSlice("LOG_REDACT_FIELDS", d.Logging.RedactFields, ","),
 		},
 		Auth: AuthConfig{
-			Enabled:        getEnvAsBool("AUTH_ENABLED", false),
-			JWTSecret:      getEnv("JWT_SECRET", ""),
-			JWTExpiration:  getEnvAsDuration("JWT_EXPIRATION", 24*time.Hour),
-			AllowedIssuers: getEnvAsSlice("JWT_ALLOWED_ISSUERS", []string{}, ","),
+			Enabled:           p.getEnvAsBool("AUTH_ENABLED", d.Auth.Enabled),
+			JWTSecret:         getEnv("JWT_SECRET", d.Auth.JWTSecret),
+			JWTExpiration:     p.getEnvAsDuration("JWT_EXPIRATION", d.Auth.JWTExpiration),
+			ClockSkew:         p.getEnvAsDuration("JWT_CLOCK_SKEW", d.Auth.ClockSkew),
+			RefreshExpiration: p.getEnvAsDuration("JWT_REFRESH_EXPIRATION", d.Auth.RefreshExpiration),
+			AllowedIssuers:    getEnvAsSlice("JWT_ALLOWED_ISSUERS", d.Auth.AllowedIssuers, ","),
+			Audience:          getEnv("JWT_AUDIENCE", d.Auth.Audience),
+			AllowedAudiences:  getEnvAsSlice("JWT_ALLOWED_AUDIENCES", d.Auth.AllowedAudiences, ","),
+			APIKeys:           getEnvAsSlice("API_KEYS", d.Auth.APIKeys, ","),
+			RoleHierarchy:     getEnvAsSlice("AUTH_ROLE_HIERARCHY", d.Auth.RoleHierarchy, ">"),
+			LoginRPS:          p.getEnvAsFloat64("AUTH_LOGIN_RATE_LIMIT_RPS", d.Auth.LoginRPS),
+			LoginBurst:        p.getEnvAsInt("AUTH_LOGIN_RATE_LIMIT_BURST", d.Auth.LoginBurst),
+		},
+		Telemetry: TelemetryConfig{
+			Enabled:     p.getEnvAsBool("OTEL_ENABLED", d.Telemetry.Enabled),
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", d.Telemetry.Endpoint),
+			Insecure:    p.getEnvAsBool("OTEL_EXPORTER_OTLP_INSECURE", d.Telemetry.Insecure),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", d.Telemetry.ServiceName),
+			SampleRatio: p.getEnvAsFloat64("OTEL_SAMPLE_RATIO", d.Telemetry.SampleRatio),
 		},
+		GraphQL: GraphQLConfig{
+			Enabled: p.getEnvAsBool("GRAPHQL_ENABLED", d.GraphQL.Enabled),
+		},
+		Metrics: MetricsConfig{
+			Enabled: p.getEnvAsBool("METRICS_ENABLED", d.Metrics.Enabled),
+			Token:   getEnv("METRICS_TOKEN", d.Metrics.Token),
+		},
+	}
+}
+
+// getDBDriver returns the configured database driver, falling back to MySQL for unknown drivers
+func (p *envParser) getDBDriver(defaultDriver string) string {
+	driver := strings.ToLower(getEnv("DB_DRIVER", defaultDriver))
+	switch driver {
+	case DBDriverPostgres, "postgresql", "pgsql":
+		return DBDriverPostgres
+	case DBDriverMySQL, "":
+		return DBDriverMySQL
+	default:
+		p.invalid("DB_DRIVER", driver, "database driver (mysql, postgres)", DBDriverMySQL)
+		return DBDriverMySQL
+	}
+}
+
+// getIDType returns the configured primary key type, falling back to integers for unknown types
+func (p *envParser) getIDType(defaultType string) string {
+	idType := strings.ToLower(getEnv("DB_ID_TYPE", defaultType))
+	switch idType {
+	case IDTypeInt, IDTypeULID:
+		return idType
+	case "":
+		return IDTypeInt
+	default:
+		p.invalid("DB_ID_TYPE", idType, "primary key type (int, ulid)", IDTypeInt)
+		return IDTypeInt
 	}
 }
 
+// buildDSN builds a DSN for the given driver from individual DB_* environment variables
+func (p *envParser) buildDSN(env, driver string) string {
+	dbUser := getEnv("DB_USER", "root")
+	dbPassword := getEnv("DB_PASSWORD", "root")
+	dbHost := getEnv("DB_HOST", "localhost")
+	dbName := getEnv("DB_NAME", "linkeun_go_api")
+
+	// For production Docker environments, prefer the database service hostname
+	if env == "production" && dbHost == "localhost" && os.Getenv("DOCKER_ENV") == "true" {
+		dbHost = driver
+	}
+
+	if driver == DBDriverPostgres {
+		dbPort := p.getEnvAsInt("DB_PORT", 5432)
+		dbParams := getEnv("DB_PARAMS", "sslmode=disable")
+
+		return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?%s",
+			dbUser, dbPassword, dbHost, dbPort, dbName, dbParams)
+	}
+
+	// Use environment-specific default port if not specified
+	// In production, prefer 3306 as default port; in development, prefer 3307
+	defaultDBPort := 3307
+	if env == "production" {
+		defaultDBPort = 3306
+	}
+
+	dbPort := p.getEnvAsInt("DB_PORT", defaultDBPort)
+	dbParams := getEnv("DB_PARAMS", "charset=utf8mb4&parseTime=True&loc=Local")
+
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
+		dbUser, dbPassword, dbHost, dbPort, dbName, dbParams)
+}
+
 // IsDevelopment returns true if the environment is development
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
@@ -196,23 +709,59 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
+func (p *envParser) getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+	if valueStr == "" {
+		return defaultValue
 	}
-	return defaultValue
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		p.invalid(key, valueStr, "integer", defaultValue)
+		return defaultValue
+	}
+	return value
 }
 
-func getEnvAsBool(key string, defaultValue bool) bool {
+func (p *envParser) getEnvAsInt64(key string, defaultValue int64) int64 {
 	valueStr := getEnv(key, "")
-	if value, err := strconv.ParseBool(valueStr); err == nil {
-		return value
+	if valueStr == "" {
+		return defaultValue
 	}
-	return defaultValue
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		p.invalid(key, valueStr, "integer", defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func (p *envParser) getEnvAsFloat64(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		p.invalid(key, valueStr, "number", defaultValue)
+		return defaultValue
+	}
+	return value
 }
 
-func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+func (p *envParser) getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		p.invalid(key, valueStr, "boolean", defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func (p *envParser) getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := getEnv(key, "")
 	if valueStr == "" {
 		return defaultValue
@@ -227,6 +776,7 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 		return time.Duration(i) * time.Second
 	}
 
+	p.invalid(key, valueStr, "duration", defaultValue)
 	return defaultValue
 }
 
@@ -249,20 +799,20 @@ func getEnvAsSlice(key string, defaultValue []string, separator string) []string
 	return result
 }
 
-// getRedisPort returns the default Redis port based on environment
-func getRedisPort(env string) int {
-	defaultPort := 6379 // Default for production
-	if env != "production" {
-		defaultPort = 6380 // Default for development
-	}
-	return getEnvAsInt("REDIS_PORT", defaultPort)
+// normalizeBasePath returns path with a leading slash and no trailing slash, e.g. "api/" becomes
+// "/api"; an empty path becomes "/"
+func normalizeBasePath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	return "/" + path
 }
 
-// getLogLevel returns the default log level based on environment
-func getLogLevel(env string) string {
-	defaultLevel := "info" // Default for development
-	if env == "production" {
-		defaultLevel = "error" // Default for production
+// getCacheBackend returns the cache backend, defaulting to Redis when Redis is enabled
+func getCacheBackend(defaultBackend string, redisEnabled bool) string {
+	if defaultBackend == "" {
+		defaultBackend = CacheBackendNone
+		if redisEnabled {
+			defaultBackend = CacheBackendRedis
+		}
 	}
-	return getEnv("LOG_LEVEL", defaultLevel)
+	return strings.ToLower(getEnv("CACHE_BACKEND", defaultBackend))
 }
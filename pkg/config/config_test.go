@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigStrict_ReportsMalformedValues(t *testing.T) {
+	t.Setenv("PORT", "80800x")
+	t.Setenv("SERVER_READ_TIMEOUT", "ten seconds")
+	t.Setenv("REDIS_ENABLED", "yes please")
+	t.Setenv("RATE_LIMIT_RPS", "fast")
+	t.Setenv("DB_DRIVER", "oracle")
+	t.Setenv("DB_ID_TYPE", "uuid")
+
+	cfg, err := LoadConfigStrict()
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+
+	assert.ErrorContains(t, err, `PORT="80800x" is not a valid integer`)
+	assert.ErrorContains(t, err, `SERVER_READ_TIMEOUT="ten seconds" is not a valid duration`)
+	assert.ErrorContains(t, err, `REDIS_ENABLED="yes please" is not a valid boolean`)
+	assert.ErrorContains(t, err, `RATE_LIMIT_RPS="fast" is not a valid number`)
+	assert.ErrorContains(t, err, `DB_DRIVER="oracle" is not a valid database driver`)
+	assert.ErrorContains(t, err, `DB_ID_TYPE="uuid" is not a valid primary key type`)
+
+	// Errors are reported in the order the variables are read
+	var envErr *EnvError
+	require.True(t, errors.As(err, &envErr))
+	assert.Equal(t, "DB_DRIVER", envErr.Key)
+	assert.Equal(t, DBDriverMySQL, envErr.Default)
+}
+
+func TestLoadConfigStrict_AcceptsValidValues(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("SERVER_READ_TIMEOUT", "15") // Bare numbers are seconds
+	t.Setenv("SERVER_WRITE_TIMEOUT", "1m")
+	t.Setenv("REDIS_ENABLED", "false")
+
+	cfg, err := LoadConfigStrict()
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, 15*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, time.Minute, cfg.Server.WriteTimeout)
+	assert.False(t, cfg.Redis.Enabled)
+}
+
+func TestLoadConfig_NormalizesBasePath(t *testing.T) {
+	tests := map[string]string{
+		"":             "/",
+		"/":            "/",
+		"gateway/api/": "/gateway/api",
+		" /api/v2 ":    "/api/v2",
+	}
+	for value, expected := range tests {
+		t.Setenv("SERVER_BASE_PATH", value)
+		assert.Equal(t, expected, LoadConfig().Server.BasePath, "SERVER_BASE_PATH=%q", value)
+	}
+}
+
+func TestLoadConfig_WarnsAndFallsBackToDefaults(t *testing.T) {
+	t.Setenv("PORT", "80800x")
+	t.Setenv("LOG_FILE_COMPRESS", "maybe")
+	t.Setenv("JWT_EXPIRATION", "1 day")
+
+	var cfg *Config
+	warnings := captureStderr(t, func() {
+		cfg = LoadConfig()
+	})
+
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.True(t, cfg.Logging.FileCompress)
+	assert.Equal(t, 24*time.Hour, cfg.Auth.JWTExpiration)
+
+	assert.Contains(t, warnings, `Warning: PORT="80800x" is not a valid integer, using default 8080`)
+	assert.Contains(t, warnings, `Warning: LOG_FILE_COMPRESS="maybe" is not a valid boolean, using default true`)
+	assert.Contains(t, warnings, `Warning: JWT_EXPIRATION="1 day" is not a valid duration, using default 24h0m0s`)
+}
+
+// captureStderr returns what fn writes to os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	fn()
+	require.NoError(t, w.Close())
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestLoadConfigFromFile_YAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 9000
+  read_timeout: 30s
+redis:
+  enabled: true
+  cache_ttl: 2m
+logging:
+  redact_fields: [password, api_key]
+`)
+
+	cfg, err := LoadConfigFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.WriteTimeout) // Unset values keep their defaults
+	assert.True(t, cfg.Redis.Enabled)
+	assert.Equal(t, 2*time.Minute, cfg.Redis.CacheTTL)
+	assert.Equal(t, CacheBackendRedis, cfg.Cache.Backend)
+	assert.Equal(t, []string{"password", "api_key"}, cfg.Logging.RedactFields)
+}
+
+func TestLoadConfigFromFile_JSON(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{
+		"environment": "production",
+		"server": {"port": 9001, "shutdown_timeout": "1m"},
+		"auth": {"enabled": true, "jwt_secret": "from-file"}
+	}`)
+
+	cfg, err := LoadConfigFromFile(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.IsProduction())
+	assert.Equal(t, 9001, cfg.Server.Port)
+	assert.Equal(t, time.Minute, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, "from-file", cfg.Auth.JWTSecret)
+	assert.Equal(t, "error", cfg.Logging.Level) // Production default
+}
+
+func TestLoadConfigFromFile_EnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 9000
+  read_timeout: 30s
+database:
+  dsn: file-dsn
+`)
+	t.Setenv("PORT", "9100")
+	t.Setenv("DSN", "env-dsn")
+
+	cfg, err := LoadConfigFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 9100, cfg.Server.Port)
+	assert.Equal(t, 30*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, "env-dsn", cfg.Database.DSN)
+}
+
+func TestLoadConfigFromFile_Errors(t *testing.T) {
+	_, err := LoadConfigFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read config file")
+
+	path := writeConfigFile(t, "unknown.yaml", "server:\n  prot: 9000\n")
+	_, err = LoadConfigFromFile(path)
+	assert.ErrorContains(t, err, "field prot not found")
+
+	path = writeConfigFile(t, "duration.yaml", "server:\n  read_timeout: soon\n")
+	_, err = LoadConfigFromFile(path)
+	assert.ErrorContains(t, err, "failed to parse config file")
+}
+
+// writeConfigFile writes content to a file named name in a temporary directory
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// placeholderJWTSecret is the JWT_SECRET shipped in .env.example, which must never reach production
+const placeholderJWTSecret = "your-secret-key-here-change-in-production"
+
+// Validate checks the configuration for values the application can't run with,
+// returning an error that lists every problem found. Production gets stricter checks
+// so that a missing secret fails at startup instead of on the first request
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(strings.HasPrefix(c.Server.BasePath, "/") && !strings.ContainsAny(c.Server.BasePath, "*{}? "),
+		"SERVER_BASE_PATH must be a path starting with / without wildcards or parameters, got %q", c.Server.BasePath)
+	check(c.Server.ResponseCase == ResponseCaseSnake || c.Server.ResponseCase == ResponseCaseCamel,
+		"RESPONSE_CASE must be %q or %q, got %q", ResponseCaseSnake, ResponseCaseCamel, c.Server.ResponseCase)
+
+	check(c.Database.Driver == DBDriverMySQL || c.Database.Driver == DBDriverPostgres,
+		"DB_DRIVER must be %q or %q, got %q", DBDriverMySQL, DBDriverPostgres, c.Database.Driver)
+	check(c.Database.DSN != "", "database DSN is empty, set DSN or the DB_* variables")
+	check(c.Migration.Check || !c.Migration.Strict, "MIGRATION_STRICT requires MIGRATION_CHECK to be enabled")
+
+	switch c.Cache.Backend {
+	case CacheBackendRedis:
+		check(c.Redis.Host != "", "REDIS_HOST is required when the Redis cache backend is enabled")
+		check(c.Redis.Port > 0 && c.Redis.Port <= 65535, "REDIS_PORT must be between 1 and 65535, got %d", c.Redis.Port)
+	case CacheBackendMemory, CacheBackendNone:
+	default:
+		check(false, "CACHE_BACKEND must be %q, %q or %q, got %q",
+			CacheBackendRedis, CacheBackendMemory, CacheBackendNone, c.Cache.Backend)
+	}
+
+	check(c.Pagination.DefaultLimit > 0, "PAGINATION_DEFAULT_LIMIT must be positive, got %d", c.Pagination.DefaultLimit)
+	check(c.Pagination.MaxLimit >= c.Pagination.DefaultLimit,
+		"PAGINATION_MAX_LIMIT must be at least PAGINATION_DEFAULT_LIMIT (%d), got %d", c.Pagination.DefaultLimit, c.Pagination.MaxLimit)
+
+	if c.Telemetry.Enabled {
+		check(c.Telemetry.Endpoint != "", "OTEL_EXPORTER_OTLP_ENDPOINT is required when OTEL_ENABLED is true")
+		check(c.Telemetry.SampleRatio >= 0 && c.Telemetry.SampleRatio <= 1,
+			"OTEL_SAMPLE_RATIO must be between 0 and 1, got %g", c.Telemetry.SampleRatio)
+	}
+
+	if c.Events.OutboxEnabled {
+		check(c.Events.OutboxPollInterval > 0, "EVENTS_OUTBOX_POLL_INTERVAL must be positive, got %s", c.Events.OutboxPollInterval)
+		check(c.Events.OutboxBatchSize > 0, "EVENTS_OUTBOX_BATCH_SIZE must be positive, got %d", c.Events.OutboxBatchSize)
+	}
+
+	if c.IsProduction() && c.Auth.Enabled {
+		check(c.Auth.JWTSecret != "", "JWT_SECRET is required in production when AUTH_ENABLED is true")
+		check(c.Auth.JWTSecret != placeholderJWTSecret, "JWT_SECRET is still the example value from .env.example")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
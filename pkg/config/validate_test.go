@@ -0,0 +1,145 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validConfig returns a configuration that passes Validate in production
+func validConfig() *Config {
+	return &Config{
+		Environment: "production",
+		Server:      ServerConfig{Port: 8080, ResponseCase: ResponseCaseSnake, BasePath: "/api/v1"},
+		Database:    DatabaseConfig{Driver: DBDriverMySQL, DSN: "user:pass@tcp(db:3306)/app"},
+		Redis:       RedisConfig{Enabled: true, Host: "redis", Port: 6379},
+		Cache:       CacheConfig{Backend: CacheBackendRedis},
+		Pagination:  PaginationConfig{DefaultLimit: 10, MaxLimit: 100},
+		Auth:        AuthConfig{Enabled: true, JWTSecret: "a-real-secret"},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{
+			name:   "valid production config",
+			modify: func(c *Config) {},
+		},
+		{
+			name:    "invalid port",
+			modify:  func(c *Config) { c.Server.Port = 0 },
+			wantErr: "PORT must be between 1 and 65535, got 0",
+		},
+		{
+			name:   "root base path",
+			modify: func(c *Config) { c.Server.BasePath = "/" },
+		},
+		{
+			name:    "base path with a wildcard",
+			modify:  func(c *Config) { c.Server.BasePath = "/api/*" },
+			wantErr: `SERVER_BASE_PATH must be a path starting with / without wildcards or parameters, got "/api/*"`,
+		},
+		{
+			name:    "unknown response case",
+			modify:  func(c *Config) { c.Server.ResponseCase = "kebab" },
+			wantErr: `RESPONSE_CASE must be "snake" or "camel", got "kebab"`,
+		},
+		{
+			name:    "unknown database driver",
+			modify:  func(c *Config) { c.Database.Driver = "oracle" },
+			wantErr: `DB_DRIVER must be "mysql" or "postgres", got "oracle"`,
+		},
+		{
+			name:    "missing DSN",
+			modify:  func(c *Config) { c.Database.DSN = "" },
+			wantErr: "database DSN is empty",
+		},
+		{
+			name:    "strict migration check without the check",
+			modify:  func(c *Config) { c.Migration = MigrationConfig{Check: false, Strict: true} },
+			wantErr: "MIGRATION_STRICT requires MIGRATION_CHECK",
+		},
+		{
+			name:    "Redis enabled without host",
+			modify:  func(c *Config) { c.Redis.Host = "" },
+			wantErr: "REDIS_HOST is required when the Redis cache backend is enabled",
+		},
+		{
+			name: "Redis host is ignored when the cache backend isn't Redis",
+			modify: func(c *Config) {
+				c.Cache.Backend = CacheBackendMemory
+				c.Redis.Host = ""
+			},
+		},
+		{
+			name:    "unknown cache backend",
+			modify:  func(c *Config) { c.Cache.Backend = "memcached" },
+			wantErr: `CACHE_BACKEND must be "redis", "memory" or "none", got "memcached"`,
+		},
+		{
+			name:    "non-positive default page limit",
+			modify:  func(c *Config) { c.Pagination.DefaultLimit = 0 },
+			wantErr: "PAGINATION_DEFAULT_LIMIT must be positive, got 0",
+		},
+		{
+			name:    "max page limit below the default",
+			modify:  func(c *Config) { c.Pagination.MaxLimit = 5 },
+			wantErr: "PAGINATION_MAX_LIMIT must be at least PAGINATION_DEFAULT_LIMIT (10), got 5",
+		},
+		{
+			name:    "production auth without JWT secret",
+			modify:  func(c *Config) { c.Auth.JWTSecret = "" },
+			wantErr: "JWT_SECRET is required in production when AUTH_ENABLED is true",
+		},
+		{
+			name:    "production auth with example JWT secret",
+			modify:  func(c *Config) { c.Auth.JWTSecret = placeholderJWTSecret },
+			wantErr: "JWT_SECRET is still the example value",
+		},
+		{
+			name: "development allows an empty JWT secret",
+			modify: func(c *Config) {
+				c.Environment = "development"
+				c.Auth.JWTSecret = ""
+			},
+		},
+		{
+			name: "production with auth disabled",
+			modify: func(c *Config) {
+				c.Auth.Enabled = false
+				c.Auth.JWTSecret = ""
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_Validate_ReportsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.DSN = ""
+	cfg.Redis.Host = ""
+	cfg.Auth.JWTSecret = ""
+
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "invalid configuration")
+	assert.ErrorContains(t, err, "database DSN is empty")
+	assert.ErrorContains(t, err, "REDIS_HOST is required")
+	assert.ErrorContains(t, err, "JWT_SECRET is required")
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CacheStats counts cache operations since the process started
+type CacheStats struct {
+	Hits     uint64  `json:"hits" xml:"hits"`
+	Misses   uint64  `json:"misses" xml:"misses"`
+	Sets     uint64  `json:"sets" xml:"sets"`
+	Deletes  uint64  `json:"deletes" xml:"deletes"`
+	HitRatio float64 `json:"hit_ratio" xml:"hit_ratio"` // Hits over lookups; 0 before the first lookup
+	// State of the Redis circuit breaker: closed, open or half_open; empty without a breaker
+	CircuitState string `json:"circuit_state,omitempty" xml:"circuit_state,omitempty" example:"closed"`
+}
+
+// CacheStatsProvider is implemented by caches that count their operations
+type CacheStatsProvider interface {
+	Stats() CacheStats
+}
+
+// cacheCounters are the operation counters kept by the cache implementations
+type cacheCounters struct {
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	sets    atomic.Uint64
+	deletes atomic.Uint64
+}
+
+// snapshot returns the current counts
+func (c *cacheCounters) snapshot() CacheStats {
+	stats := CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Sets:    c.sets.Load(),
+		Deletes: c.deletes.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// RegisterCacheMetrics exposes the counts of provider as Prometheus metrics
+func RegisterCacheMetrics(registerer prometheus.Registerer, provider CacheStatsProvider) error {
+	collectors := []prometheus.Collector{
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "cache_hits_total",
+			Help: "Cache lookups that found an entry.",
+		}, func() float64 { return float64(provider.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "cache_misses_total",
+			Help: "Cache lookups that found no entry.",
+		}, func() float64 { return float64(provider.Stats().Misses) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "cache_sets_total",
+			Help: "Entries written to the cache.",
+		}, func() float64 { return float64(provider.Stats().Sets) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "cache_deletes_total",
+			Help: "Cache invalidations, each of a key or a key pattern.",
+		}, func() float64 { return float64(provider.Stats().Deletes) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "cache_hit_ratio",
+			Help: "Fraction of cache lookups that found an entry since startup.",
+		}, func() float64 { return provider.Stats().HitRatio }),
+	}
+
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedCacheStats reports the same counts every time
+type fixedCacheStats CacheStats
+
+func (s fixedCacheStats) Stats() CacheStats {
+	return CacheStats(s)
+}
+
+func TestRegisterCacheMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	require.NoError(t, RegisterCacheMetrics(registry, fixedCacheStats{Hits: 3, Misses: 1, Sets: 2, Deletes: 4, HitRatio: 0.75}))
+
+	expected := map[string]float64{
+		"cache_hits_total":    3,
+		"cache_misses_total":  1,
+		"cache_sets_total":    2,
+		"cache_deletes_total": 4,
+		"cache_hit_ratio":     0.75,
+	}
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, len(expected))
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		value := metric.GetCounter().GetValue()
+		if family.GetName() == "cache_hit_ratio" {
+			value = metric.GetGauge().GetValue()
+		}
+		assert.Equal(t, expected[family.GetName()], value, family.GetName())
+	}
+
+	// Registering the same metrics twice is an error rather than a panic
+	assert.Error(t, RegisterCacheMetrics(registry, fixedCacheStats{}))
+	assert.Equal(t, 5, testutil.CollectAndCount(registry))
+}
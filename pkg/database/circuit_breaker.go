@@ -0,0 +1,101 @@
+package database
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrCacheUnavailable is returned by cache calls skipped while the circuit breaker is open
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
+// Circuit breaker states reported in CacheStats
+const (
+	CircuitClosed   = "closed"    // Cache calls go to Redis
+	CircuitOpen     = "open"      // Cache calls are skipped until the cooldown ends
+	CircuitHalfOpen = "half_open" // One call is probing whether Redis is back
+)
+
+// circuitBreaker stops calls to a backend after a run of consecutive failures, so requests
+// don't each wait for a timeout while it is down. After the cooldown one call is let through
+// as a probe: success closes the breaker and failure opens it for another cooldown
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newCircuitBreaker creates a closed circuit breaker that opens after threshold consecutive failures
+func newCircuitBreaker(threshold int, cooldown time.Duration, logger *zap.Logger) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a call may go ahead
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record counts the outcome of a call that allow let through
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if !b.openUntil.IsZero() {
+			b.logger.Info("Cache circuit breaker closed, resuming cache calls")
+		}
+		b.failures = 0
+		b.openUntil = time.Time{}
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.probing || b.failures >= b.threshold {
+		if !b.probing {
+			b.logger.Warn("Cache circuit breaker opened, skipping cache calls",
+				zap.Int("failures", b.failures),
+				zap.Duration("cooldown", b.cooldown))
+		}
+		b.openUntil = b.now().Add(b.cooldown)
+		b.probing = false
+	}
+}
+
+// state returns one of the Circuit* constants
+func (b *circuitBreaker) state() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.openUntil.IsZero():
+		return CircuitClosed
+	case b.probing || !b.now().Before(b.openUntil):
+		return CircuitHalfOpen
+	default:
+		return CircuitOpen
+	}
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newTestCircuitBreaker creates a circuit breaker whose clock only moves when the returned
+// function is called
+func newTestCircuitBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(threshold, cooldown, zap.NewNop())
+	breaker.now = func() time.Time { return now }
+	return breaker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("OpensAfterConsecutiveFailures", func(t *testing.T) {
+		breaker, _ := newTestCircuitBreaker(3, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			assert.True(t, breaker.allow())
+			breaker.record(true)
+		}
+		assert.Equal(t, CircuitClosed, breaker.state())
+
+		assert.True(t, breaker.allow())
+		breaker.record(true)
+		assert.Equal(t, CircuitOpen, breaker.state())
+		assert.False(t, breaker.allow(), "calls should be skipped while open")
+	})
+
+	t.Run("SuccessResetsFailures", func(t *testing.T) {
+		breaker, _ := newTestCircuitBreaker(2, time.Minute)
+
+		breaker.record(true)
+		breaker.record(false)
+		breaker.record(true)
+
+		assert.Equal(t, CircuitClosed, breaker.state(), "failures should have to be consecutive")
+	})
+
+	t.Run("ProbesOnceAfterCooldown", func(t *testing.T) {
+		breaker, advance := newTestCircuitBreaker(1, time.Minute)
+		breaker.record(true)
+
+		advance(59 * time.Second)
+		assert.False(t, breaker.allow())
+
+		advance(time.Second)
+		assert.Equal(t, CircuitHalfOpen, breaker.state())
+		assert.True(t, breaker.allow(), "the first call after the cooldown should probe")
+		assert.False(t, breaker.allow(), "only one call should probe at a time")
+
+		breaker.record(false)
+		assert.Equal(t, CircuitClosed, breaker.state())
+		assert.True(t, breaker.allow())
+	})
+
+	t.Run("FailedProbeReopens", func(t *testing.T) {
+		breaker, advance := newTestCircuitBreaker(3, time.Minute)
+		for i := 0; i < 3; i++ {
+			breaker.record(true)
+		}
+
+		advance(time.Minute)
+		assert.True(t, breaker.allow())
+		breaker.record(true)
+
+		assert.Equal(t, CircuitOpen, breaker.state(), "one failed probe should reopen the breaker")
+		advance(59 * time.Second)
+		assert.False(t, breaker.allow())
+	})
+
+	t.Run("LogsTransitions", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		breaker, advance := newTestCircuitBreaker(1, time.Minute)
+		breaker.logger = zap.New(core)
+
+		breaker.record(true)
+		advance(time.Minute)
+		breaker.allow()
+		breaker.record(false)
+
+		assert.Equal(t, 1, logs.FilterMessage("Cache circuit breaker opened, skipping cache calls").Len())
+		assert.Equal(t, 1, logs.FilterMessage("Cache circuit breaker closed, resuming cache calls").Len())
+	})
+}
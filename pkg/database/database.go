@@ -2,12 +2,16 @@ package database
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linkeunid/go-api/pkg/config"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
@@ -31,8 +35,36 @@ const (
 	ContextKeyCacheStatus ContextKey = "cache_status"
 	// ContextKeyCacheKey is the context key for cache key
 	ContextKeyCacheKey ContextKey = "cache_key"
+	// ContextKeyCustomCacheKey is the context key repositories use to supply their own cache key
+	ContextKeyCustomCacheKey ContextKey = "customCacheKey"
 )
 
+// cacheRecorderKey is the context key for the per-call cache status recorder
+type cacheRecorderKey struct{}
+
+// cacheRecorder holds the cache outcome of a CachedFind call
+type cacheRecorder struct {
+	mu     sync.Mutex
+	status CacheStatus
+	key    string
+}
+
+// WithCacheStatus returns a context in which CachedFind records its cache status and key.
+// Pass the same context to GetCacheStatus to read the outcome for that call only.
+func WithCacheStatus(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheRecorderKey{}, &cacheRecorder{status: CacheDisabled})
+}
+
+// recordCacheStatus stores the cache outcome in the context's recorder, if any
+func recordCacheStatus(ctx context.Context, status CacheStatus, key string) {
+	if rec, ok := ctx.Value(cacheRecorderKey{}).(*cacheRecorder); ok {
+		rec.mu.Lock()
+		rec.status = status
+		rec.key = key
+		rec.mu.Unlock()
+	}
+}
+
 // ErrRecordNotFound indicates a record was not found
 var ErrRecordNotFound = gorm.ErrRecordNotFound
 
@@ -42,6 +74,7 @@ type Database interface {
 	CachedFind(ctx context.Context, query *gorm.DB, dest interface{}) error
 	GetCacheManager() CacheManager
 	GetCacheStatus(ctx context.Context) (CacheStatus, string)
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
 	Close() error
 }
 
@@ -58,6 +91,13 @@ type Cache interface {
 	Delete(ctx context.Context, key string) error
 }
 
+// CacheFlusher is implemented by caches that report how many keys a delete removed
+type CacheFlusher interface {
+	// Flush removes key, or every key matching it when it contains a "*" wildcard, and
+	// returns how many keys were removed
+	Flush(ctx context.Context, key string) (int, error)
+}
+
 // Cacheable is the interface that models must implement to be cacheable
 type Cacheable interface {
 	CacheEnabled() bool
@@ -70,7 +110,8 @@ type gormDatabase struct {
 	cacheManager CacheManager
 	logger       *zap.Logger
 	config       *config.Config
-	cacheContext context.Context
+	// group collapses concurrent cache misses for the same key into one query
+	group singleflight.Group
 }
 
 // NewDatabase creates a new database instance
@@ -90,16 +131,18 @@ func (d *gormDatabase) GetDB() *gorm.DB {
 
 // CachedFind performs a find operation with caching
 func (d *gormDatabase) CachedFind(ctx context.Context, query *gorm.DB, dest interface{}) error {
-	// Create a new context to store cache status
-	cacheCtx := context.WithValue(context.Background(), ContextKeyCacheStatus, CacheDisabled)
-	cacheCtx = context.WithValue(cacheCtx, ContextKeyCacheKey, "")
+	// Trace the lookup, with any database query nested under it
+	ctx, span := tracer().Start(ctx, "CachedFind")
+	defer span.End()
+	query = query.WithContext(ctx)
 
-	// Store the context in a package-level variable to make it accessible
-	d.cacheContext = cacheCtx
+	// Reset the call-scoped cache status
+	recordCacheStatus(ctx, CacheDisabled, "")
 
 	// If caching is not enabled, just perform the query and mark as disabled
-	if d.cacheManager == nil || d.cacheManager.GetCache() == nil || !d.config.Redis.Enabled || !d.config.Redis.QueryCache {
+	if d.cacheManager == nil || d.cacheManager.GetCache() == nil || !d.config.Redis.QueryCache {
 		d.logger.Debug("Cache disabled")
+		span.SetAttributes(CacheStatusAttribute.String(string(CacheDisabled)))
 		return query.Find(dest).Error
 	}
 
@@ -116,7 +159,7 @@ func (d *gormDatabase) CachedFind(ctx context.Context, query *gorm.DB, dest inte
 
 	// If no key found yet, try using repository's custom key
 	if cacheKey == "" {
-		if customKey := ctx.Value(ContextKey("customCacheKey")); customKey != nil {
+		if customKey := ctx.Value(ContextKeyCustomCacheKey); customKey != nil {
 			if key, ok := customKey.(string); ok && key != "" {
 				cacheKey = key
 				d.logger.Debug("Using custom cache key", zap.String("key", cacheKey), zap.String("source", "customCacheKey"))
@@ -140,48 +183,93 @@ func (d *gormDatabase) CachedFind(ctx context.Context, query *gorm.DB, dest inte
 		d.logger.Debug("Generated cache key from query", zap.String("key", cacheKey), zap.String("source", "generated"))
 	}
 
-	d.cacheContext = context.WithValue(d.cacheContext, ContextKeyCacheKey, cacheKey)
-
 	// Try to get the item from cache first
 	err := d.cacheManager.GetCache().Get(ctx, cacheKey, dest)
 	if err == nil {
 		// Cache hit
-		d.cacheContext = context.WithValue(d.cacheContext, ContextKeyCacheStatus, CacheHit)
+		recordCacheStatus(ctx, CacheHit, cacheKey)
+		span.SetAttributes(CacheStatusAttribute.String(string(CacheHit)))
 		d.logger.Debug("Cache hit", zap.String("key", cacheKey))
 		return nil
 	}
 
 	// Cache miss
-	d.cacheContext = context.WithValue(d.cacheContext, ContextKeyCacheStatus, CacheMiss)
+	recordCacheStatus(ctx, CacheMiss, cacheKey)
+	span.SetAttributes(CacheStatusAttribute.String(string(CacheMiss)))
 	d.logger.Debug("Cache miss", zap.String("key", cacheKey))
 
-	// Perform database query
-	if err := query.Find(dest).Error; err != nil {
-		return err
-	}
+	// Only one goroutine per key queries the database; the rest wait for its result
+	v, err, _ := d.group.Do(cacheKey, func() (interface{}, error) {
+		loadCtx, cancel := d.sharedLoadContext(ctx)
+		defer cancel()
 
-	// Store result in cache
-	cacheTTL := d.config.Redis.CacheTTL
-	if cacheable, ok := dest.(Cacheable); ok && cacheable.CacheEnabled() {
-		cacheTTL = cacheable.CacheTTL()
+		// Perform database query
+		result := query.WithContext(loadCtx).Find(dest)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		// Store result in cache
+		cacheTTL := d.config.Redis.CacheTTL
+		if cacheable, ok := dest.(Cacheable); ok && cacheable.CacheEnabled() {
+			cacheTTL = cacheable.CacheTTL()
+		}
+
+		// An empty result, such as a lookup of a missing ID, is the not-found marker; it is kept
+		// only briefly so a row created later isn't hidden for long
+		if result.RowsAffected == 0 {
+			if d.config.Redis.NegativeTTL <= 0 {
+				return dest, nil
+			}
+			cacheTTL = d.config.Redis.NegativeTTL
+		}
+
+		// A skipped call was already reported when the circuit breaker opened
+		if err := d.cacheManager.GetCache().Set(loadCtx, cacheKey, dest, cacheTTL); err != nil && !errors.Is(err, ErrCacheUnavailable) {
+			d.logger.Warn("Failed to cache query result", zap.String("key", cacheKey), zap.Error(err))
+		}
+
+		return dest, nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := d.cacheManager.GetCache().Set(ctx, cacheKey, dest, cacheTTL); err != nil {
-		d.logger.Warn("Failed to cache query result", zap.String("key", cacheKey), zap.Error(err))
+	// Callers that shared another goroutine's query get their own copy of the result
+	if v != dest {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to copy shared query result: %w", err)
+		}
+		if err := json.Unmarshal(data, dest); err != nil {
+			return fmt.Errorf("failed to copy shared query result: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// GetCacheStatus returns the cache status and key for the current request
+// sharedLoadContext returns the context of a query shared by every caller waiting on the same
+// key. It keeps ctx's values and deadline but not its cancellation, so a client going away
+// doesn't fail the others; without a deadline the service operation timeout bounds it
+func (d *gormDatabase) sharedLoadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	if timeout := d.config.Service.OperationTimeout; timeout > 0 {
+		return context.WithTimeout(detached, timeout)
+	}
+	return context.WithCancel(detached)
+}
+
+// GetCacheStatus returns the cache status and key recorded in ctx by CachedFind
 func (d *gormDatabase) GetCacheStatus(ctx context.Context) (CacheStatus, string) {
-	// Use the stored context if available
-	if d.cacheContext != nil {
-		status, ok := d.cacheContext.Value(ContextKeyCacheStatus).(CacheStatus)
-		if ok {
-			key, _ := d.cacheContext.Value(ContextKeyCacheKey).(string)
-			return status, key
-		}
+	// Use the call-scoped recorder if available
+	if rec, ok := ctx.Value(cacheRecorderKey{}).(*cacheRecorder); ok {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		return rec.status, rec.key
 	}
 
 	// Fall back to passed context if needed
@@ -198,6 +286,12 @@ func (d *gormDatabase) GetCacheStatus(ctx context.Context) (CacheStatus, string)
 	return status, key
 }
 
+// Transaction runs fn inside a database transaction bound to ctx.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+func (d *gormDatabase) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return d.db.WithContext(ctx).Transaction(fn)
+}
+
 // GetCacheManager returns the cache manager
 func (d *gormDatabase) GetCacheManager() CacheManager {
 	return d.cacheManager
@@ -205,6 +299,11 @@ func (d *gormDatabase) GetCacheManager() CacheManager {
 
 // Close closes the database connection
 func (d *gormDatabase) Close() error {
+	// Stop background work owned by the cache backend
+	if closer, ok := d.cacheManager.(interface{ Close() }); ok {
+		closer.Close()
+	}
+
 	sqlDB, err := d.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get SQL DB: %w", err)
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type testRecord struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+// newDryRunDB opens a GORM connection that builds SQL without executing it.
+// onQuery is invoked for every query that would reach the database.
+func newDryRunDB(t *testing.T, onQuery func(*gorm.DB)) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:0)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:count", onQuery))
+	return db
+}
+
+func TestCachedFind_SingleflightCollapsesConcurrentMisses(t *testing.T) {
+	var queries int32
+	db := newDryRunDB(t, func(tx *gorm.DB) {
+		atomic.AddInt32(&queries, 1)
+		// Hold the query open so concurrent callers pile up behind it
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	cfg := &config.Config{Redis: config.RedisConfig{Enabled: true, QueryCache: true, CacheTTL: time.Minute}}
+	database := NewDatabase(cfg, zap.NewNop(), db, NewInMemoryCacheManager(cfg, zap.NewNop()))
+
+	ctx := context.WithValue(context.Background(), ContextKeyCacheKey, "v1:records:item:1")
+
+	const callers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var records []testRecord
+			errs <- database.CachedFind(ctx, db.Table("records"), &records)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&queries), "underlying query should run exactly once")
+}
+
+func TestCachedFind_CancelledCallerDoesNotFailWaiters(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+	started := make(chan struct{})
+	var once sync.Once
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:started", func(*gorm.DB) {
+		once.Do(func() { close(started) })
+	}))
+	// Only one query is expected; the slow result keeps the waiter behind the first caller
+	sqlMock.ExpectQuery("SELECT \\* FROM `records`").
+		WillDelayFor(100 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Fluffy"))
+
+	cfg := &config.Config{Redis: config.RedisConfig{QueryCache: true, CacheTTL: time.Minute}}
+	database := NewDatabase(cfg, zap.NewNop(), db, NewInMemoryCacheManager(cfg, zap.NewNop()))
+
+	base := context.WithValue(context.Background(), ContextKeyCacheKey, "v1:records:item:1")
+	firstCtx, cancelFirst := context.WithCancel(base)
+	defer cancelFirst()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var record testRecord
+		_ = database.CachedFind(firstCtx, db.Table("records").Where("id = ?", 1), &record)
+	}()
+
+	// Join the first caller's query once it is in flight, then let the first client go away
+	<-started
+	var record testRecord
+	waiterErr := make(chan error, 1)
+	go func() {
+		waiterErr <- database.CachedFind(base, db.Table("records").Where("id = ?", 1), &record)
+	}()
+	cancelFirst()
+
+	require.NoError(t, <-waiterErr)
+	assert.Equal(t, "Fluffy", record.Name)
+	wg.Wait()
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCachedFind_CacheStatusIsCallScoped(t *testing.T) {
+	db := newDryRunDB(t, func(tx *gorm.DB) {
+		// Keep the miss in flight while the hit completes
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	cfg := &config.Config{Redis: config.RedisConfig{Enabled: true, QueryCache: true, CacheTTL: time.Minute}}
+	cacheManager := NewInMemoryCacheManager(cfg, zap.NewNop())
+	database := NewDatabase(cfg, zap.NewNop(), db, cacheManager)
+
+	// Pre-populate the key for the request that should hit
+	require.NoError(t, cacheManager.Set(context.Background(), "v1:records:item:1", []testRecord{{ID: 1}}, time.Minute))
+
+	type outcome struct {
+		status CacheStatus
+		key    string
+	}
+
+	run := func(key string, started chan<- struct{}, wait <-chan struct{}) outcome {
+		ctx := WithCacheStatus(context.WithValue(context.Background(), ContextKeyCacheKey, key))
+		if wait != nil {
+			<-wait
+		}
+		if started != nil {
+			close(started)
+		}
+		var records []testRecord
+		assert.NoError(t, database.CachedFind(ctx, db.Table("records"), &records))
+		status, gotKey := database.GetCacheStatus(ctx)
+		return outcome{status: status, key: gotKey}
+	}
+
+	missStarted := make(chan struct{})
+	missResult := make(chan outcome, 1)
+	hitResult := make(chan outcome, 1)
+
+	// Start the miss first, then run the hit while the miss is still querying
+	go func() { missResult <- run("v1:records:item:2", missStarted, nil) }()
+	go func() { hitResult <- run("v1:records:item:1", nil, missStarted) }()
+
+	hit := <-hitResult
+	miss := <-missResult
+
+	assert.Equal(t, outcome{status: CacheHit, key: "v1:records:item:1"}, hit)
+	assert.Equal(t, outcome{status: CacheMiss, key: "v1:records:item:2"}, miss)
+}
+
+// ttlRecordingCache records the expiration each key was cached with
+type ttlRecordingCache struct {
+	*InMemoryCacheManager
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+}
+
+func (c *ttlRecordingCache) GetCache() Cache {
+	return c
+}
+
+func (c *ttlRecordingCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	c.ttls[key] = expiration
+	c.mu.Unlock()
+	return c.InMemoryCacheManager.Set(ctx, key, value, expiration)
+}
+
+func TestCachedFind_EmptyResultsUseNegativeTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		negativeTTL time.Duration
+		rows        *sqlmock.Rows
+		expectedTTL time.Duration
+		cached      bool
+	}{
+		{name: "Found", negativeTTL: 30 * time.Second, rows: sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Fluffy"), expectedTTL: time.Minute, cached: true},
+		{name: "NotFound", negativeTTL: 30 * time.Second, rows: sqlmock.NewRows([]string{"id", "name"}), expectedTTL: 30 * time.Second, cached: true},
+		{name: "NegativeCachingDisabled", negativeTTL: 0, rows: sqlmock.NewRows([]string{"id", "name"}), cached: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+
+			db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: gormlogger.Discard})
+			require.NoError(t, err)
+			sqlMock.ExpectQuery("SELECT \\* FROM `records`").WillReturnRows(tt.rows)
+
+			cfg := &config.Config{Redis: config.RedisConfig{QueryCache: true, CacheTTL: time.Minute, NegativeTTL: tt.negativeTTL}}
+			cacheManager := &ttlRecordingCache{InMemoryCacheManager: NewInMemoryCacheManager(cfg, zap.NewNop()), ttls: make(map[string]time.Duration)}
+			database := NewDatabase(cfg, zap.NewNop(), db, cacheManager)
+
+			ctx := context.WithValue(context.Background(), ContextKeyCacheKey, "v1:records:item:1")
+			var record testRecord
+			require.NoError(t, database.CachedFind(ctx, db.Table("records").Where("id = ?", 1), &record))
+
+			ttl, cached := cacheManager.ttls["v1:records:item:1"]
+			assert.Equal(t, tt.cached, cached)
+			assert.Equal(t, tt.expectedTTL, ttl)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"net"
+	"strconv"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/linkeunid/go-api/pkg/config"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Dialector returns the GORM dialector for the given driver, falling back to MySQL
+// for unknown drivers to match config.LoadConfig
+func Dialector(driver, dsn string) gorm.Dialector {
+	if driver == config.DBDriverPostgres {
+		return postgres.Open(dsn)
+	}
+	return mysql.Open(dsn)
+}
+
+// Open opens a GORM connection with dialector like gorm.Open, closing the connection pool when
+// the connection fails. gorm.Open leaves it open when only the ping fails, so every failed
+// attempt of a ConnectWithRetry loop would otherwise keep a pool around
+func Open(dialector gorm.Dialector, config *gorm.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, config)
+	if err != nil {
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				_ = sqlDB.Close()
+			}
+		}
+		return nil, err
+	}
+	return db, nil
+}
+
+// DSNAddress holds the parts of a DSN that identify the database without its credentials
+type DSNAddress struct {
+	Host string `json:"host" xml:"host" example:"localhost"`
+	Port int    `json:"port" xml:"port" example:"3306"`
+	Name string `json:"name" xml:"name" example:"linkeun_go_api"`
+}
+
+// ParseDSNAddress returns the host, port and database name of dsn for the given driver, so
+// they can be shown without the DSN's password. A MySQL socket path is returned as the host
+func ParseDSNAddress(driver, dsn string) (DSNAddress, error) {
+	if driver == config.DBDriverPostgres {
+		cfg, err := pgconn.ParseConfig(dsn)
+		if err != nil {
+			return DSNAddress{}, err
+		}
+		return DSNAddress{Host: cfg.Host, Port: int(cfg.Port), Name: cfg.Database}, nil
+	}
+
+	cfg, err := gomysql.ParseDSN(dsn)
+	if err != nil {
+		return DSNAddress{}, err
+	}
+	address := DSNAddress{Host: cfg.Addr, Name: cfg.DBName}
+	if host, port, err := net.SplitHostPort(cfg.Addr); err == nil {
+		address.Host = host
+		address.Port, _ = strconv.Atoi(port)
+	}
+	return address, nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestParseDSNAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		dsn      string
+		expected DSNAddress
+	}{
+		{name: "MySQLTCP", driver: config.DBDriverMySQL, dsn: "user:pass@tcp(localhost:3306)/linkeun_go_api?parseTime=true", expected: DSNAddress{Host: "localhost", Port: 3306, Name: "linkeun_go_api"}},
+		{name: "MySQLSocket", driver: config.DBDriverMySQL, dsn: "user:pass@unix(/var/run/mysqld/mysqld.sock)/linkeun_go_api", expected: DSNAddress{Host: "/var/run/mysqld/mysqld.sock", Name: "linkeun_go_api"}},
+		{name: "UnknownDriverIsMySQL", driver: "", dsn: "user:pass@tcp(db:3307)/app", expected: DSNAddress{Host: "db", Port: 3307, Name: "app"}},
+		{name: "PostgresURL", driver: config.DBDriverPostgres, dsn: "postgres://user:pass@db:5433/app", expected: DSNAddress{Host: "db", Port: 5433, Name: "app"}},
+		{name: "PostgresKeyValue", driver: config.DBDriverPostgres, dsn: "host=db port=5432 user=user password=pass dbname=app", expected: DSNAddress{Host: "db", Port: 5432, Name: "app"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			address, err := ParseDSNAddress(tc.driver, tc.dsn)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, address)
+		})
+	}
+
+	_, err := ParseDSNAddress(config.DBDriverMySQL, "not a dsn")
+	assert.Error(t, err)
+}
+
+func TestOpen_ClosesPoolWhenPingFails(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	sqlMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	sqlMock.ExpectClose()
+
+	db, err := Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: gormlogger.Discard})
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, db)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestOpen(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	sqlMock.ExpectPing()
+
+	db, err := Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{Logger: gormlogger.Discard})
+
+	require.NoError(t, err)
+	assert.NotNil(t, db)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
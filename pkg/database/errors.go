@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrDuplicateKey is returned when a write violates a unique index
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrTableNotFound is returned when a query names a table that doesn't exist
+var ErrTableNotFound = errors.New("table not found")
+
+const (
+	// mysqlErrDuplicateEntry is the MySQL error number for a duplicate entry on a unique index
+	mysqlErrDuplicateEntry = 1062
+	// mysqlErrNoSuchTable is the MySQL error number for a query on a missing table
+	mysqlErrNoSuchTable = 1146
+
+	// postgresUniqueViolation is the PostgreSQL SQLSTATE for a unique index violation
+	postgresUniqueViolation = "23505"
+	// postgresUndefinedTable is the PostgreSQL SQLSTATE for a query on a missing table
+	postgresUndefinedTable = "42P01"
+)
+
+// TranslateError maps driver-specific errors to the package's sentinel errors.
+// Errors it does not recognize are returned unchanged.
+func TranslateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrDuplicateEntry:
+			return fmt.Errorf("%w: %s", ErrDuplicateKey, mysqlErr.Message)
+		case mysqlErrNoSuchTable:
+			return fmt.Errorf("%w: %s", ErrTableNotFound, mysqlErr.Message)
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case postgresUniqueViolation:
+			return fmt.Errorf("%w: %s", ErrDuplicateKey, pgErr.Message)
+		case postgresUndefinedTable:
+			return fmt.Errorf("%w: %s", ErrTableNotFound, pgErr.Message)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Fluffy' for key 'idx_animal_name'"}
+
+	assert.ErrorIs(t, TranslateError(duplicate), ErrDuplicateKey)
+	assert.ErrorIs(t, TranslateError(fmt.Errorf("insert failed: %w", duplicate)), ErrDuplicateKey)
+
+	// Other errors are passed through untouched
+	other := &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+	assert.Equal(t, other, TranslateError(other))
+	assert.Nil(t, TranslateError(nil))
+
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, TranslateError(plain))
+
+	// PostgreSQL reports unique violations by SQLSTATE
+	pgDuplicate := &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"}
+	assert.ErrorIs(t, TranslateError(pgDuplicate), ErrDuplicateKey)
+
+	// Queries on missing tables are translated for both drivers
+	missing := &mysql.MySQLError{Number: 1146, Message: "Table 'app.schema_migrations' doesn't exist"}
+	assert.ErrorIs(t, TranslateError(missing), ErrTableNotFound)
+	pgMissing := &pgconn.PgError{Code: "42P01", Message: `relation "schema_migrations" does not exist`}
+	assert.ErrorIs(t, TranslateError(pgMissing), ErrTableNotFound)
+
+	pgOther := &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	assert.Equal(t, pgOther, TranslateError(pgOther))
+}
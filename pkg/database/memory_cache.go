@@ -0,0 +1,252 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/config"
+	"go.uber.org/zap"
+)
+
+// memoryCacheEntry is a single item stored in the in-memory cache
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// expired returns true if the entry's TTL has elapsed
+func (e *memoryCacheEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// InMemoryCacheManager implements the CacheManager interface using an in-process LRU cache with TTL
+type InMemoryCacheManager struct {
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List // front is most recently used
+	maxItems int
+	logger   *zap.Logger
+	config   *config.Config
+	stop     chan struct{}
+	stopOnce sync.Once
+	counters cacheCounters
+}
+
+// NewInMemoryCacheManager creates a new in-memory cache manager and starts its janitor
+func NewInMemoryCacheManager(cfg *config.Config, logger *zap.Logger) *InMemoryCacheManager {
+	m := &InMemoryCacheManager{
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		maxItems: cfg.Cache.MemoryMaxItems,
+		logger:   logger,
+		config:   cfg,
+		stop:     make(chan struct{}),
+	}
+
+	// Periodically purge expired entries so memory is reclaimed even for keys that are never read again
+	if cfg.Cache.MemoryCleanupInterval > 0 {
+		go m.janitor(cfg.Cache.MemoryCleanupInterval)
+	}
+
+	logger.Info("Initialized in-memory cache",
+		zap.Int("maxItems", m.maxItems),
+		zap.Duration("cleanupInterval", cfg.Cache.MemoryCleanupInterval),
+	)
+
+	return m
+}
+
+// GetCache returns the in-memory cache implementation
+func (m *InMemoryCacheManager) GetCache() Cache {
+	return m
+}
+
+// Stats returns the number of cache operations since startup
+func (m *InMemoryCacheManager) Stats() CacheStats {
+	return m.counters.snapshot()
+}
+
+// GetConfig returns the configuration used by this cache manager
+func (m *InMemoryCacheManager) GetConfig() *config.Config {
+	return m.config
+}
+
+// Get retrieves an item from cache
+func (m *InMemoryCacheManager) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	elem, ok := m.items[key]
+	if !ok {
+		m.mu.Unlock()
+		m.counters.misses.Add(1)
+		m.logger.Debug("Cache miss - key not found", zap.String("key", key))
+		return fmt.Errorf("key not found: %s", key)
+	}
+
+	entry := elem.Value.(*memoryCacheEntry)
+	if entry.expired(time.Now()) {
+		m.removeElement(elem)
+		m.mu.Unlock()
+		m.counters.misses.Add(1)
+		m.logger.Debug("Cache miss - key expired", zap.String("key", key))
+		return fmt.Errorf("key not found: %s", key)
+	}
+
+	// Mark as most recently used
+	m.order.MoveToFront(elem)
+	value := entry.value
+	m.mu.Unlock()
+
+	// Unmarshal JSON data so callers never share the cached value
+	if err := json.Unmarshal(value, dest); err != nil {
+		m.logger.Warn("Failed to unmarshal data from memory cache", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("failed to unmarshal data from memory cache: %w", err)
+	}
+
+	m.counters.hits.Add(1)
+	m.logger.Debug("Cache hit", zap.String("key", key))
+	return nil
+}
+
+// Set stores an item in cache
+func (m *InMemoryCacheManager) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	// Marshal value to JSON
+	data, err := json.Marshal(value)
+	if err != nil {
+		m.logger.Warn("Failed to marshal data for memory cache", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("failed to marshal data for memory cache: %w", err)
+	}
+
+	var expiresAt time.Time
+	if expiration > 0 {
+		expiresAt = time.Now().Add(expiration)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters.sets.Add(1)
+
+	if elem, ok := m.items[key]; ok {
+		entry := elem.Value.(*memoryCacheEntry)
+		entry.value = data
+		entry.expiresAt = expiresAt
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.items[key] = m.order.PushFront(&memoryCacheEntry{
+		key:       key,
+		value:     data,
+		expiresAt: expiresAt,
+	})
+
+	// Evict least recently used entries when over capacity
+	for m.maxItems > 0 && m.order.Len() > m.maxItems {
+		m.removeElement(m.order.Back())
+	}
+
+	m.logger.Debug("Successfully cached", zap.String("key", key), zap.Duration("ttl", expiration))
+	return nil
+}
+
+// Delete removes an item from cache
+func (m *InMemoryCacheManager) Delete(ctx context.Context, key string) error {
+	_, err := m.Flush(ctx, key)
+	return err
+}
+
+// Flush removes key, or every key matching it when it contains a wildcard, and returns
+// how many entries were removed
+func (m *InMemoryCacheManager) Flush(ctx context.Context, key string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters.deletes.Add(1)
+
+	// Check if the key contains a wildcard
+	if strings.Contains(key, "*") {
+		pattern := globToRegexp(key)
+
+		count := 0
+		for k, elem := range m.items {
+			if pattern.MatchString(k) {
+				m.removeElement(elem)
+				count++
+			}
+		}
+
+		m.logger.Debug("Successfully deleted keys with pattern",
+			zap.String("pattern", key),
+			zap.Int("count", count))
+		return count, nil
+	}
+
+	if elem, ok := m.items[key]; ok {
+		m.removeElement(elem)
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Close stops the janitor goroutine
+func (m *InMemoryCacheManager) Close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Len returns the number of entries currently held, including expired ones not yet purged
+func (m *InMemoryCacheManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// janitor purges expired entries on every tick until Close is called
+func (m *InMemoryCacheManager) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.deleteExpired()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// deleteExpired removes all expired entries
+func (m *InMemoryCacheManager) deleteExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, elem := range m.items {
+		if elem.Value.(*memoryCacheEntry).expired(now) {
+			m.removeElement(elem)
+		}
+	}
+}
+
+// removeElement removes an entry from both the index and the LRU list; callers must hold mu
+func (m *InMemoryCacheManager) removeElement(elem *list.Element) {
+	entry := elem.Value.(*memoryCacheEntry)
+	delete(m.items, entry.key)
+	m.order.Remove(elem)
+}
+
+// globToRegexp converts a Redis-style glob pattern ("*" and "?") into an anchored regular expression
+func globToRegexp(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.MustCompile("^" + quoted + "$")
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestMemoryCache(maxItems int) *InMemoryCacheManager {
+	cfg := &config.Config{Cache: config.CacheConfig{Backend: config.CacheBackendMemory, MemoryMaxItems: maxItems}}
+	return NewInMemoryCacheManager(cfg, zap.NewNop())
+}
+
+func TestInMemoryCache_SetGet(t *testing.T) {
+	cache := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	assert.NoError(t, cache.Set(ctx, "v1:animals:item:1", testRecord{ID: 1, Name: "Fluffy"}, time.Minute))
+
+	var got testRecord
+	assert.NoError(t, cache.Get(ctx, "v1:animals:item:1", &got))
+	assert.Equal(t, testRecord{ID: 1, Name: "Fluffy"}, got)
+
+	assert.Error(t, cache.Get(ctx, "v1:animals:item:2", &got))
+}
+
+func TestInMemoryCache_Expiration(t *testing.T) {
+	cache := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	assert.NoError(t, cache.Set(ctx, "short", testRecord{ID: 1}, 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	var got testRecord
+	assert.Error(t, cache.Get(ctx, "short", &got))
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestInMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newTestMemoryCache(2)
+	ctx := context.Background()
+
+	var got testRecord
+	assert.NoError(t, cache.Set(ctx, "a", testRecord{ID: 1}, time.Minute))
+	assert.NoError(t, cache.Set(ctx, "b", testRecord{ID: 2}, time.Minute))
+
+	// Touch "a" so "b" becomes the least recently used entry
+	assert.NoError(t, cache.Get(ctx, "a", &got))
+	assert.NoError(t, cache.Set(ctx, "c", testRecord{ID: 3}, time.Minute))
+
+	assert.NoError(t, cache.Get(ctx, "a", &got))
+	assert.Error(t, cache.Get(ctx, "b", &got))
+	assert.NoError(t, cache.Get(ctx, "c", &got))
+}
+
+func TestInMemoryCache_WildcardDelete(t *testing.T) {
+	cache := newTestMemoryCache(10)
+	ctx := context.Background()
+
+	assert.NoError(t, cache.Set(ctx, "v1:animals:list:limit=10:page=1", testRecord{}, time.Minute))
+	assert.NoError(t, cache.Set(ctx, "v1:animals:list:limit=10:page=2", testRecord{}, time.Minute))
+	assert.NoError(t, cache.Set(ctx, "v1:animals:item:1", testRecord{}, time.Minute))
+
+	assert.NoError(t, cache.Delete(ctx, "v1:animals:list*"))
+
+	var got testRecord
+	assert.Error(t, cache.Get(ctx, "v1:animals:list:limit=10:page=1", &got))
+	assert.Error(t, cache.Get(ctx, "v1:animals:list:limit=10:page=2", &got))
+	assert.NoError(t, cache.Get(ctx, "v1:animals:item:1", &got))
+}
+
+func TestInMemoryCache_Stats(t *testing.T) {
+	cache := newTestMemoryCache(10)
+	ctx := context.Background()
+	var value string
+
+	assert.Error(t, cache.Get(ctx, "missing", &value))
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute))
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	require.NoError(t, cache.Delete(ctx, "key"))
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Sets: 1, Deletes: 1, HitRatio: 0.5}, cache.Stats())
+}
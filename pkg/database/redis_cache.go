@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -14,9 +16,11 @@ import (
 
 // RedisCacheManager implements the CacheManager interface using Redis
 type RedisCacheManager struct {
-	client *redis.Client
-	logger *zap.Logger
-	config *config.Config
+	client   *redis.Client
+	logger   *zap.Logger
+	config   *config.Config
+	counters cacheCounters
+	breaker  *circuitBreaker // nil when REDIS_BREAKER_THRESHOLD is 0
 }
 
 // NewRedisCacheManager creates a new Redis cache manager
@@ -32,12 +36,16 @@ func NewRedisCacheManager(cfg *config.Config, logger *zap.Logger) (*RedisCacheMa
 	// Create Redis client
 	client := redis.NewClient(redisOpt)
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	_, err := client.Ping(ctx).Result()
+	// Test connection, retrying with the database connection settings while Redis starts up
+	err := ConnectWithRetry(context.Background(), cfg.Database.ConnectRetries, cfg.Database.ConnectBackoff,
+		LogRetries(logger, "redis"),
+		func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return client.Ping(ctx).Err()
+		})
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
@@ -47,11 +55,15 @@ func NewRedisCacheManager(cfg *config.Config, logger *zap.Logger) (*RedisCacheMa
 		zap.Int("db", cfg.Redis.DB),
 	)
 
-	return &RedisCacheManager{
+	manager := &RedisCacheManager{
 		client: client,
 		logger: logger,
 		config: cfg,
-	}, nil
+	}
+	if cfg.Redis.BreakerThreshold > 0 {
+		manager.breaker = newCircuitBreaker(cfg.Redis.BreakerThreshold, cfg.Redis.BreakerCooldown, logger)
+	}
+	return manager, nil
 }
 
 // GetCache returns the Redis cache implementation
@@ -59,6 +71,74 @@ func (r *RedisCacheManager) GetCache() Cache {
 	return r
 }
 
+// redisDeleteBatchSize is the SCAN count hint and the number of keys unlinked per command
+// by pattern deletes
+const redisDeleteBatchSize = 500
+
+// opContext bounds a single Redis command by REDIS_OP_TIMEOUT, so a slow Redis can't hold a
+// request until the server's own timeout
+func (r *RedisCacheManager) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.config.Redis.OpTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.config.Redis.OpTimeout)
+}
+
+// unlink removes keys matched by pattern without blocking Redis while their memory is freed
+func (r *RedisCacheManager) unlink(ctx context.Context, pattern string, keys []string) (int, error) {
+	opCtx, cancel := r.opContext(ctx)
+	defer cancel()
+
+	deleted, err := r.client.Unlink(opCtx, keys...).Result()
+	if err != nil {
+		r.logger.Warn("Failed to delete keys with pattern", zap.String("pattern", pattern), zap.Error(err))
+		return 0, fmt.Errorf("failed to delete from Redis: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// call runs op against Redis, unless the circuit breaker is open and the call is skipped
+// with ErrCacheUnavailable
+func (r *RedisCacheManager) call(op func() error) error {
+	if r.breaker == nil {
+		return op()
+	}
+	if !r.breaker.allow() {
+		return ErrCacheUnavailable
+	}
+
+	err := op()
+	r.breaker.record(isRedisFailure(err))
+	return err
+}
+
+// isRedisFailure reports whether err means Redis didn't answer, as opposed to a missing key
+// or a request that was canceled by its caller
+func isRedisFailure(err error) bool {
+	return err != nil && !errors.Is(err, redis.Nil) && !errors.Is(err, context.Canceled)
+}
+
+// isTimeout reports whether err is a command that ran out of time, which go-redis reports
+// either as the context's error or as a network timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// Stats returns the number of cache operations since startup and the circuit breaker state
+func (r *RedisCacheManager) Stats() CacheStats {
+	stats := r.counters.snapshot()
+	if r.breaker != nil {
+		stats.CircuitState = r.breaker.state()
+	}
+	return stats
+}
+
+// Client returns the underlying Redis client so other components can share the connection pool
+func (r *RedisCacheManager) Client() *redis.Client {
+	return r.client
+}
+
 // GetConfig returns the configuration used by this cache manager
 func (r *RedisCacheManager) GetConfig() *config.Config {
 	return r.config
@@ -72,12 +152,30 @@ func (r *RedisCacheManager) Get(ctx context.Context, key string, dest interface{
 	r.logger.Debug("Getting from Redis cache", zap.String("key", prefixedKey))
 
 	// Get value from Redis
-	val, err := r.client.Get(ctx, prefixedKey).Result()
+	var val string
+	err := r.call(func() (err error) {
+		opCtx, cancel := r.opContext(ctx)
+		defer cancel()
+		val, err = r.client.Get(opCtx, prefixedKey).Result()
+		return err
+	})
 	if err != nil {
 		if err == redis.Nil {
+			r.counters.misses.Add(1)
 			r.logger.Debug("Cache miss - key not found", zap.String("key", prefixedKey))
 			return fmt.Errorf("key not found: %s", key)
 		}
+		if errors.Is(err, ErrCacheUnavailable) {
+			return err
+		}
+		// A slow Redis is treated like a missing key, so the request falls back to the database
+		if isTimeout(err) {
+			r.counters.misses.Add(1)
+			r.logger.Warn("Redis read timed out, treating as a cache miss",
+				zap.String("key", prefixedKey),
+				zap.Duration("timeout", r.config.Redis.OpTimeout))
+			return fmt.Errorf("key not found: %s: %w", key, err)
+		}
 		r.logger.Warn("Redis error", zap.String("key", prefixedKey), zap.Error(err))
 		return err
 	}
@@ -88,6 +186,7 @@ func (r *RedisCacheManager) Get(ctx context.Context, key string, dest interface{
 		return fmt.Errorf("failed to unmarshal data from Redis: %w", err)
 	}
 
+	r.counters.hits.Add(1)
 	r.logger.Debug("Cache hit", zap.String("key", prefixedKey))
 	return nil
 }
@@ -107,53 +206,102 @@ func (r *RedisCacheManager) Set(ctx context.Context, key string, value interface
 	}
 
 	// Store in Redis
-	if err := r.client.Set(ctx, prefixedKey, jsonData, expiration).Err(); err != nil {
+	err = r.call(func() error {
+		opCtx, cancel := r.opContext(ctx)
+		defer cancel()
+		return r.client.Set(opCtx, prefixedKey, jsonData, expiration).Err()
+	})
+	if errors.Is(err, ErrCacheUnavailable) {
+		return err
+	}
+	if err != nil {
 		r.logger.Warn("Failed to store in Redis", zap.String("key", prefixedKey), zap.Error(err))
 		return fmt.Errorf("failed to store in Redis: %w", err)
 	}
 
+	r.counters.sets.Add(1)
 	r.logger.Debug("Successfully cached", zap.String("key", prefixedKey))
 	return nil
 }
 
 // Delete removes an item from cache
 func (r *RedisCacheManager) Delete(ctx context.Context, key string) error {
+	_, err := r.Flush(ctx, key)
+	return err
+}
+
+// Flush removes key, or every key matching it when it contains a wildcard, and returns
+// how many keys were removed
+func (r *RedisCacheManager) Flush(ctx context.Context, key string) (int, error) {
+	r.counters.deletes.Add(1)
+
+	var deleted int
+	err := r.call(func() (err error) {
+		deleted, err = r.flush(ctx, key)
+		return err
+	})
+	return deleted, err
+}
+
+// flush implements Flush without the circuit breaker
+func (r *RedisCacheManager) flush(ctx context.Context, key string) (int, error) {
 	// Add prefix to key
 	prefixedKey := r.config.Redis.KeyPrefix + key
 
 	// Check if the key contains a wildcard
 	if strings.Contains(key, "*") {
-		// Use pattern matching to delete multiple keys
+		// Walk the keyspace with SCAN rather than KEYS, which blocks Redis until it has
+		// matched every key, and unlink the matches in batches
 		r.logger.Debug("Deleting keys with pattern", zap.String("pattern", prefixedKey))
 
-		// Get matching keys
-		keys, err := r.client.Keys(ctx, prefixedKey).Result()
-		if err != nil {
-			r.logger.Warn("Failed to find keys matching pattern", zap.String("pattern", prefixedKey), zap.Error(err))
-			return fmt.Errorf("failed to find keys matching pattern: %w", err)
+		deleted := 0
+		scanCtx, cancel := r.opContext(ctx)
+		iter := r.client.Scan(scanCtx, 0, prefixedKey, redisDeleteBatchSize).Iterator()
+		cancel()
+		batch := make([]string, 0, redisDeleteBatchSize)
+		for r.nextKey(ctx, iter) {
+			batch = append(batch, iter.Val())
+			if len(batch) == redisDeleteBatchSize {
+				n, err := r.unlink(ctx, prefixedKey, batch)
+				deleted += n
+				if err != nil {
+					return deleted, err
+				}
+				batch = batch[:0]
+			}
 		}
-
-		if len(keys) == 0 {
-			// No keys to delete
-			return nil
+		if err := iter.Err(); err != nil {
+			r.logger.Warn("Failed to find keys matching pattern", zap.String("pattern", prefixedKey), zap.Error(err))
+			return deleted, fmt.Errorf("failed to find keys matching pattern: %w", err)
 		}
-
-		// Delete all matching keys
-		if err := r.client.Del(ctx, keys...).Err(); err != nil {
-			r.logger.Warn("Failed to delete keys with pattern", zap.String("pattern", prefixedKey), zap.Error(err))
-			return fmt.Errorf("failed to delete from Redis: %w", err)
+		if len(batch) > 0 {
+			n, err := r.unlink(ctx, prefixedKey, batch)
+			deleted += n
+			if err != nil {
+				return deleted, err
+			}
 		}
 
 		r.logger.Debug("Successfully deleted keys with pattern",
 			zap.String("pattern", prefixedKey),
-			zap.Int("count", len(keys)))
-		return nil
+			zap.Int("count", deleted))
+		return deleted, nil
 	}
 
 	// Delete single key from Redis
-	if err := r.client.Del(ctx, prefixedKey).Err(); err != nil {
-		return fmt.Errorf("failed to delete from Redis: %w", err)
+	opCtx, cancel := r.opContext(ctx)
+	defer cancel()
+	deleted, err := r.client.Del(opCtx, prefixedKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete from Redis: %w", err)
 	}
 
-	return nil
+	return int(deleted), nil
+}
+
+// nextKey advances iter, giving each SCAN page it fetches its own REDIS_OP_TIMEOUT
+func (r *RedisCacheManager) nextKey(ctx context.Context, iter *redis.ScanIterator) bool {
+	opCtx, cancel := r.opContext(ctx)
+	defer cancel()
+	return iter.Next(opCtx)
 }
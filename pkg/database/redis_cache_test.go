@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newTestRedisCache creates a Redis cache manager connected to an in-process Redis server
+func newTestRedisCache(t *testing.T) *RedisCacheManager {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	port, err := strconv.Atoi(server.Port())
+	require.NoError(t, err)
+
+	cfg := &config.Config{Redis: config.RedisConfig{Host: server.Host(), Port: port, KeyPrefix: "test:"}}
+	manager, err := NewRedisCacheManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Client().Close() })
+	return manager
+}
+
+func TestRedisCacheManager_Stats(t *testing.T) {
+	cache := newTestRedisCache(t)
+	ctx := context.Background()
+	var value testRecord
+
+	assert.Error(t, cache.Get(ctx, "records:1", &value))
+	assert.Equal(t, CacheStats{Misses: 1}, cache.Stats())
+
+	require.NoError(t, cache.Set(ctx, "records:1", testRecord{ID: 1}, time.Minute))
+	require.NoError(t, cache.Get(ctx, "records:1", &value))
+	require.NoError(t, cache.Get(ctx, "records:1", &value))
+	require.NoError(t, cache.Delete(ctx, "records:*"))
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Sets: 1, Deletes: 1, HitRatio: 2.0 / 3}, cache.Stats())
+}
+
+func TestRedisCacheManager_Flush(t *testing.T) {
+	cache := newTestRedisCache(t)
+	ctx := context.Background()
+	for _, key := range []string{"v1:animals:item:1", "v1:animals:list:page=1", "v1:flowers:item:1"} {
+		require.NoError(t, cache.Set(ctx, key, testRecord{ID: 1}, time.Minute))
+	}
+
+	deleted, err := cache.Flush(ctx, "v1:animals:*")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	deleted, err = cache.Flush(ctx, "v1:flowers:item:1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = cache.Flush(ctx, "v1:flowers:item:1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func TestRedisCacheManager_DeletePatternScansInBatches(t *testing.T) {
+	cache := newTestRedisCache(t)
+	ctx := context.Background()
+
+	// More keys than a single SCAN page or UNLINK batch
+	const keyCount = 2000
+	for i := 0; i < keyCount; i++ {
+		require.NoError(t, cache.Set(ctx, fmt.Sprintf("v1:animals:item:%d", i), testRecord{ID: uint64(i)}, time.Minute))
+	}
+	require.NoError(t, cache.Set(ctx, "v1:flowers:item:1", testRecord{ID: 1}, time.Minute))
+
+	deleted, err := cache.Flush(ctx, "v1:animals:*")
+	require.NoError(t, err)
+	assert.Equal(t, keyCount, deleted)
+
+	remaining, err := cache.Client().Keys(ctx, "test:*").Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test:v1:flowers:item:1"}, remaining)
+}
+
+func TestRedisCacheManager_CircuitBreaker(t *testing.T) {
+	server := miniredis.RunT(t)
+	port, err := strconv.Atoi(server.Port())
+	require.NoError(t, err)
+
+	cfg := &config.Config{Redis: config.RedisConfig{
+		Host:             server.Host(),
+		Port:             port,
+		KeyPrefix:        "test:",
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	}}
+	cache, err := NewRedisCacheManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cache.Client().Close() })
+
+	ctx := context.Background()
+	var value testRecord
+	require.NoError(t, cache.Set(ctx, "records:1", testRecord{ID: 1}, time.Minute))
+	assert.Equal(t, CircuitClosed, cache.Stats().CircuitState)
+
+	server.Close()
+	for i := 0; i < 2; i++ {
+		err := cache.Get(ctx, "records:1", &value)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCacheUnavailable, "calls before the threshold should reach Redis")
+	}
+
+	assert.Equal(t, CircuitOpen, cache.Stats().CircuitState)
+	assert.ErrorIs(t, cache.Get(ctx, "records:1", &value), ErrCacheUnavailable)
+	assert.ErrorIs(t, cache.Set(ctx, "records:1", testRecord{ID: 1}, time.Minute), ErrCacheUnavailable)
+	_, err = cache.Flush(ctx, "records:*")
+	assert.ErrorIs(t, err, ErrCacheUnavailable)
+}
+
+// newSlowRedisCache creates a Redis cache manager whose server accepts connections but never
+// answers, like a Redis that has stalled
+func newSlowRedisCache(t *testing.T, logger *zap.Logger) *RedisCacheManager {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				_ = conn.Close()
+			}
+			close(done)
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		<-done
+	})
+
+	// Built directly because NewRedisCacheManager would fail its startup ping
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	cfg := &config.Config{Redis: config.RedisConfig{KeyPrefix: "test:", OpTimeout: 50 * time.Millisecond}}
+	return &RedisCacheManager{client: client, logger: logger, config: cfg}
+}
+
+func TestRedisCacheManager_OpTimeout(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	cache := newSlowRedisCache(t, zap.New(core))
+	ctx := context.Background()
+
+	t.Run("ReadTimesOutAsMiss", func(t *testing.T) {
+		start := time.Now()
+		var value testRecord
+		err := cache.Get(ctx, "records:1", &value)
+
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second, "the read should give up at REDIS_OP_TIMEOUT")
+		assert.Equal(t, uint64(1), cache.Stats().Misses)
+		assert.Equal(t, 1, logs.FilterMessage("Redis read timed out, treating as a cache miss").Len())
+	})
+
+	t.Run("WritesTimeOut", func(t *testing.T) {
+		start := time.Now()
+		assert.Error(t, cache.Set(ctx, "records:1", testRecord{ID: 1}, time.Minute))
+		assert.Error(t, cache.Delete(ctx, "records:1"))
+		assert.Error(t, cache.Delete(ctx, "records:*"))
+		assert.Less(t, time.Since(start), 3*time.Second)
+	})
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxConnectBackoff caps the wait between connection attempts
+const maxConnectBackoff = 30 * time.Second
+
+// RetryFunc is called after each failed connection attempt that will be retried, with the
+// 1-based number of the failed attempt and how long ConnectWithRetry waits before the next
+type RetryFunc func(attempt int, wait time.Duration, err error)
+
+// ConnectWithRetry calls connect until it succeeds, retrying up to retries times after the
+// first failure. The wait starts at backoff and doubles after every failure, up to
+// maxConnectBackoff. The last error is returned once the retries run out or ctx is done
+func ConnectWithRetry(ctx context.Context, retries int, backoff time.Duration, onRetry RetryFunc, connect func() error) error {
+	wait := backoff
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil || attempt > retries {
+			return err
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		wait = nextBackoff(wait)
+	}
+}
+
+// nextBackoff returns the wait that follows wait
+func nextBackoff(wait time.Duration) time.Duration {
+	return min(wait*2, maxConnectBackoff)
+}
+
+// LogRetries returns a RetryFunc that logs each failed connection attempt to target
+func LogRetries(logger *zap.Logger, target string) RetryFunc {
+	return func(attempt int, wait time.Duration, err error) {
+		logger.Warn("Connection attempt failed, retrying",
+			zap.String("target", target),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", wait),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestConnectWithRetry(t *testing.T) {
+	errRefused := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		retries      int
+		failures     int
+		wantErr      error
+		wantAttempts int
+		wantWaits    []time.Duration
+	}{
+		{
+			name:         "SucceedsFirstTime",
+			retries:      3,
+			failures:     0,
+			wantAttempts: 1,
+		},
+		{
+			name:         "SucceedsAfterRetries",
+			retries:      3,
+			failures:     2,
+			wantAttempts: 3,
+			wantWaits:    []time.Duration{time.Millisecond, 2 * time.Millisecond},
+		},
+		{
+			name:         "GivesUpAfterRetries",
+			retries:      2,
+			failures:     5,
+			wantErr:      errRefused,
+			wantAttempts: 3,
+			wantWaits:    []time.Duration{time.Millisecond, 2 * time.Millisecond},
+		},
+		{
+			name:         "NoRetries",
+			retries:      0,
+			failures:     1,
+			wantErr:      errRefused,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			var waits []time.Duration
+			err := ConnectWithRetry(context.Background(), tt.retries, time.Millisecond,
+				func(attempt int, wait time.Duration, err error) {
+					assert.Equal(t, len(waits)+1, attempt)
+					waits = append(waits, wait)
+				},
+				func() error {
+					attempts++
+					if attempts <= tt.failures {
+						return errRefused
+					}
+					return nil
+				})
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+			assert.Equal(t, tt.wantWaits, waits)
+		})
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, nextBackoff(time.Second))
+	assert.Equal(t, maxConnectBackoff, nextBackoff(20*time.Second), "the wait shouldn't pass the cap")
+	assert.Equal(t, maxConnectBackoff, nextBackoff(maxConnectBackoff))
+}
+
+func TestConnectWithRetry_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := ConnectWithRetry(ctx, 5, time.Hour, nil, func() error {
+		attempts++
+		return errors.New("connection refused")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts, "a done context shouldn't wait for the backoff")
+}
+
+func TestLogRetries(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	LogRetries(zap.New(core), "redis")(2, time.Second, errors.New("connection refused"))
+
+	entries := logs.FilterMessage("Connection attempt failed, retrying").All()
+	if assert.Len(t, entries, 1) {
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "redis", fields["target"])
+		assert.Equal(t, int64(2), fields["attempt"])
+		assert.Equal(t, time.Second, fields["retry_in"])
+	}
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recordSpans installs a global tracer provider that keeps every finished span in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+// spanAttribute returns the value of the named attribute on span, if present
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestCachedFind_RecordsCacheStatusOnSpan(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db := newDryRunDB(t, func(tx *gorm.DB) {})
+	require.NoError(t, RegisterTracing(db))
+
+	cfg := &config.Config{Redis: config.RedisConfig{Enabled: true, QueryCache: true, CacheTTL: time.Minute, NegativeTTL: time.Minute}}
+	database := NewDatabase(cfg, zap.NewNop(), db, NewInMemoryCacheManager(cfg, zap.NewNop()))
+	ctx := context.WithValue(context.Background(), ContextKeyCacheKey, "v1:records:item:1")
+
+	var records []testRecord
+	require.NoError(t, database.CachedFind(ctx, db.Table("records"), &records))
+	require.NoError(t, database.CachedFind(ctx, db.Table("records"), &records))
+
+	var cachedFinds []sdktrace.ReadOnlySpan
+	var queries []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "CachedFind":
+			cachedFinds = append(cachedFinds, span)
+		case "db.query":
+			queries = append(queries, span)
+		}
+	}
+
+	require.Len(t, cachedFinds, 2)
+	status, ok := spanAttribute(cachedFinds[0], CacheStatusAttribute)
+	require.True(t, ok)
+	assert.Equal(t, string(CacheMiss), status.AsString())
+	status, ok = spanAttribute(cachedFinds[1], CacheStatusAttribute)
+	require.True(t, ok)
+	assert.Equal(t, string(CacheHit), status.AsString())
+
+	// Only the miss reaches the database, and its query is nested under the lookup
+	require.Len(t, queries, 1)
+	assert.Equal(t, cachedFinds[0].SpanContext().SpanID(), queries[0].Parent().SpanID())
+	statement, ok := spanAttribute(queries[0], "db.query.text")
+	require.True(t, ok)
+	assert.Contains(t, statement.AsString(), "SELECT * FROM `records`")
+}
+
+func TestCachedFind_RecordsDisabledCacheStatus(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db := newDryRunDB(t, func(tx *gorm.DB) {})
+	database := NewDatabase(&config.Config{}, zap.NewNop(), db, nil)
+
+	var records []testRecord
+	require.NoError(t, database.CachedFind(context.Background(), db.Table("records"), &records))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	status, ok := spanAttribute(spans[0], CacheStatusAttribute)
+	require.True(t, ok)
+	assert.Equal(t, string(CacheDisabled), status.AsString())
+}
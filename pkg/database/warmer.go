@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WarmFunc loads entries into the cache, usually by running a cached query
+type WarmFunc func(ctx context.Context) error
+
+// cacheWarmTask is a named WarmFunc
+type cacheWarmTask struct {
+	name string
+	warm WarmFunc
+}
+
+// CacheWarmer preloads frequently read cache entries at startup, so the first requests after a
+// deploy don't all miss the cache at once
+type CacheWarmer struct {
+	logger *zap.Logger
+	tasks  []cacheWarmTask
+}
+
+// NewCacheWarmer creates a cache warmer with nothing registered
+func NewCacheWarmer(logger *zap.Logger) *CacheWarmer {
+	return &CacheWarmer{logger: logger}
+}
+
+// Register adds a collection to warm; name is only used in logs
+func (w *CacheWarmer) Register(name string, warm WarmFunc) {
+	w.tasks = append(w.tasks, cacheWarmTask{name: name, warm: warm})
+}
+
+// Warm runs every registered WarmFunc in order and returns how long it took.
+// A failure is logged and doesn't stop the remaining collections from being warmed
+func (w *CacheWarmer) Warm(ctx context.Context) time.Duration {
+	start := time.Now()
+	warmed, failed := 0, 0
+	for _, task := range w.tasks {
+		if err := ctx.Err(); err != nil {
+			w.logger.Warn("Cache warming interrupted", zap.Error(err))
+			break
+		}
+		if err := task.warm(ctx); err != nil {
+			w.logger.Warn("Failed to warm cache", zap.String("collection", task.name), zap.Error(err))
+			failed++
+			continue
+		}
+		warmed++
+	}
+
+	elapsed := time.Since(start)
+	w.logger.Info("Cache warming finished",
+		zap.Int("collections", warmed),
+		zap.Int("failed", failed),
+		zap.Duration("duration", elapsed))
+	return elapsed
+}
+
+// Start runs Warm in a background goroutine so it doesn't delay startup.
+// The returned channel is closed once warming has finished
+func (w *CacheWarmer) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Warm(ctx)
+	}()
+	return done
+}
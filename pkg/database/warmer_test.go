@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCacheWarmer(t *testing.T) {
+	t.Run("WarmsEveryCollection", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		warmer := NewCacheWarmer(zap.New(core))
+
+		var warmed []string
+		warmer.Register("animals", func(ctx context.Context) error {
+			warmed = append(warmed, "animals")
+			return nil
+		})
+		warmer.Register("broken", func(ctx context.Context) error {
+			return errors.New("connection refused")
+		})
+		warmer.Register("flowers", func(ctx context.Context) error {
+			warmed = append(warmed, "flowers")
+			return nil
+		})
+
+		warmer.Warm(context.Background())
+
+		assert.Equal(t, []string{"animals", "flowers"}, warmed, "a failure shouldn't stop later collections")
+		assert.Equal(t, 1, logs.FilterMessage("Failed to warm cache").Len())
+		finished := logs.FilterMessage("Cache warming finished").All()
+		if assert.Len(t, finished, 1) {
+			fields := finished[0].ContextMap()
+			assert.Equal(t, int64(2), fields["collections"])
+			assert.Equal(t, int64(1), fields["failed"])
+			assert.Contains(t, fields, "duration")
+		}
+	})
+
+	t.Run("StopsWhenContextEnds", func(t *testing.T) {
+		warmer := NewCacheWarmer(zap.NewNop())
+		ctx, cancel := context.WithCancel(context.Background())
+
+		calls := 0
+		warmer.Register("first", func(ctx context.Context) error {
+			calls++
+			cancel()
+			return nil
+		})
+		warmer.Register("second", func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+
+		warmer.Warm(ctx)
+
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("StartRunsInBackground", func(t *testing.T) {
+		warmer := NewCacheWarmer(zap.NewNop())
+		release := make(chan struct{})
+		warmer.Register("slow", func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		// Start must return while warming is still blocked
+		done := warmer.Start(context.Background())
+		close(release)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("warming did not finish")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package events
+
+import "context"
+
+// Event types
+const (
+	TypeCreated = "created"
+	TypeUpdated = "updated"
+	TypeDeleted = "deleted"
+)
+
+// DefaultBuffer is the number of events a subscriber can fall behind by before it is dropped
+const DefaultBuffer = 64
+
+// Event describes a change to a record
+type Event struct {
+	Type string      `json:"type" example:"updated"`
+	ID   string      `json:"id" example:"1"`
+	Data interface{} `json:"data,omitempty"` // The record after the change, when it is known
+}
+
+// Publisher sends events to the subscribers of a topic
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event Event) error
+}
+
+// Subscriber hands out subscriptions to the events of a topic
+type Subscriber interface {
+	Subscribe(topic string) *Subscription
+}
+
+// Broker both publishes and subscribes
+type Broker interface {
+	Publisher
+	Subscriber
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Subscription receives the events of one topic until it is closed or dropped
+type Subscription struct {
+	events  chan Event
+	dropped bool // set, with the channel closed, when the subscriber fell too far behind
+	broker  *MemoryBroker
+	topic   string
+}
+
+// Events returns the channel events are delivered on. It is closed when the subscription is
+// closed, or dropped because it fell more than the broker's buffer behind
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped reports whether the subscription ended because its consumer was too slow.
+// It is only meaningful once Events is closed
+func (s *Subscription) Dropped() bool {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.dropped
+}
+
+// Close ends the subscription; it is safe to call more than once
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// MemoryBroker delivers events to subscribers in the same process
+type MemoryBroker struct {
+	buffer int
+
+	mu     sync.Mutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+// NewMemoryBroker creates a broker whose subscribers can fall buffer events behind
+func NewMemoryBroker(buffer int) *MemoryBroker {
+	if buffer < 1 {
+		buffer = DefaultBuffer
+	}
+	return &MemoryBroker{
+		buffer: buffer,
+		topics: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Publish delivers event to the current subscribers of topic. It never blocks: a subscriber
+// whose buffer is full is dropped so one slow consumer can't hold up the rest
+func (b *MemoryBroker) Publish(_ context.Context, topic string, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.topics[topic] {
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped = true
+			b.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe starts a subscription to topic
+func (b *MemoryBroker) Subscribe(topic string) *Subscription {
+	sub := &Subscription{
+		events: make(chan Event, b.buffer),
+		broker: b,
+		topic:  topic,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[*Subscription]struct{})
+	}
+	b.topics[topic][sub] = struct{}{}
+	return sub
+}
+
+// remove unregisters sub and closes its channel; b.mu must be held
+func (b *MemoryBroker) remove(sub *Subscription) {
+	subs := b.topics[sub.topic]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.topics, sub.topic)
+	}
+	close(sub.events)
+}
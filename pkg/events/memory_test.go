@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("DeliversToSubscribersOfTopic", func(t *testing.T) {
+		broker := NewMemoryBroker(4)
+		animals := broker.Subscribe("animals")
+		flowers := broker.Subscribe("flowers")
+
+		event := Event{Type: TypeCreated, ID: "1"}
+		assert.NoError(t, broker.Publish(ctx, "animals", event))
+
+		assert.Equal(t, event, <-animals.Events())
+		assert.Empty(t, flowers.Events(), "other topics shouldn't receive the event")
+	})
+
+	t.Run("DropsSlowSubscribers", func(t *testing.T) {
+		broker := NewMemoryBroker(2)
+		slow := broker.Subscribe("animals")
+		fast := broker.Subscribe("animals")
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, broker.Publish(ctx, "animals", Event{Type: TypeUpdated, ID: "1"}))
+			<-fast.Events()
+		}
+
+		// The buffered events are still delivered before the channel closes
+		received := 0
+		for range slow.Events() {
+			received++
+		}
+		assert.Equal(t, 2, received)
+		assert.True(t, slow.Dropped())
+		assert.False(t, fast.Dropped())
+	})
+
+	t.Run("CloseEndsSubscription", func(t *testing.T) {
+		broker := NewMemoryBroker(2)
+		sub := broker.Subscribe("animals")
+
+		sub.Close()
+		sub.Close()
+		assert.NoError(t, broker.Publish(ctx, "animals", Event{Type: TypeDeleted, ID: "1"}))
+
+		_, open := <-sub.Events()
+		assert.False(t, open)
+		assert.False(t, sub.Dropped())
+		assert.Empty(t, broker.topics)
+	})
+}
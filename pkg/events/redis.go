@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// RedisBroker publishes events through Redis pub/sub so subscribers on every instance receive
+// them. Each instance fans the events it receives out to its own subscribers
+type RedisBroker struct {
+	client *redis.Client
+	prefix string // Channel name prefix; the topic follows it
+	local  *MemoryBroker
+	logger *zap.Logger
+}
+
+// NewRedisBroker creates a broker publishing on the channels keyPrefix + "events:" + topic.
+// Call Start to begin receiving events
+func NewRedisBroker(client *redis.Client, keyPrefix string, buffer int, logger *zap.Logger) *RedisBroker {
+	return &RedisBroker{
+		client: client,
+		prefix: keyPrefix + "events:",
+		local:  NewMemoryBroker(buffer),
+		logger: logger,
+	}
+}
+
+// Start subscribes to every event channel and delivers what arrives to local subscribers
+// until ctx is done. go-redis reconnects the subscription if the connection drops
+func (b *RedisBroker) Start(ctx context.Context) error {
+	pubsub := b.client.PSubscribe(ctx, b.prefix+"*")
+	// Wait for the confirmation so events published after Start returns aren't missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event Event
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					b.logger.Warn("Ignoring malformed event", zap.String("channel", msg.Channel), zap.Error(err))
+					continue
+				}
+				_ = b.local.Publish(ctx, strings.TrimPrefix(msg.Channel, b.prefix), event)
+			}
+		}
+	}()
+	return nil
+}
+
+// Publish sends event to the subscribers of topic on every instance. If Redis can't be
+// reached the event still reaches this instance's subscribers
+func (b *RedisBroker) Publish(ctx context.Context, topic string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, b.prefix+topic, payload).Err(); err != nil {
+		_ = b.local.Publish(ctx, topic, event)
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe starts a subscription to topic on this instance
+func (b *RedisBroker) Subscribe(topic string) *Subscription {
+	return b.local.Subscribe(topic)
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedisBroker_DeliversAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// newInstance creates the broker of one API instance
+	newInstance := func() *RedisBroker {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		broker := NewRedisBroker(client, "test:", 4, zap.NewNop())
+		require.NoError(t, broker.Start(ctx))
+		return broker
+	}
+	publisher, receiver := newInstance(), newInstance()
+
+	local := publisher.Subscribe("animals")
+	remote := receiver.Subscribe("animals")
+	event := Event{Type: TypeDeleted, ID: "7"}
+	require.NoError(t, publisher.Publish(ctx, "animals", event))
+
+	for _, sub := range []*Subscription{local, remote} {
+		select {
+		case received := <-sub.Events():
+			assert.Equal(t, event, received)
+		case <-time.After(2 * time.Second):
+			t.Fatal("event wasn't delivered")
+		}
+	}
+}
+
+func TestRedisBroker_PublishFallsBackToLocalSubscribers(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	broker := NewRedisBroker(client, "test:", 4, zap.NewNop())
+	sub := broker.Subscribe("animals")
+
+	server.Close()
+	event := Event{Type: TypeCreated, ID: "1"}
+	assert.Error(t, broker.Publish(context.Background(), "animals", event))
+	assert.Equal(t, event, <-sub.Events())
+}
@@ -0,0 +1,234 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/response"
+)
+
+// Format is a supported export file format
+type Format string
+
+const (
+	// FormatJSON exports records as a JSON array
+	FormatJSON Format = "json"
+	// FormatCSV exports records as CSV with a header row
+	FormatCSV Format = "csv"
+)
+
+// ParseFormat returns the format named by s, defaulting to JSON when s is empty
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q, expected csv or json", s)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json"
+}
+
+// Encoder writes records to an export file batch by batch
+// Nothing is written until the first call to Write or Close
+type Encoder[T any] interface {
+	// Write appends a batch of records
+	Write(items []T) error
+	// Close finishes the file, writing an empty one if no records were written
+	Close() error
+}
+
+// NewEncoder creates an encoder for format that writes to w
+func NewEncoder[T any](w io.Writer, format Format) Encoder[T] {
+	if format == FormatCSV {
+		return &csvEncoder[T]{w: csv.NewWriter(w)}
+	}
+	return &jsonEncoder[T]{w: w}
+}
+
+// jsonEncoder streams records as the elements of a single JSON array, with keys in the case of
+// the API responses. CSV headers keep the json tag names
+type jsonEncoder[T any] struct {
+	w       io.Writer
+	started bool
+}
+
+// Write implements Encoder
+func (e *jsonEncoder[T]) Write(items []T) error {
+	for i := range items {
+		sep := ","
+		if !e.started {
+			sep = "["
+			e.started = true
+		}
+
+		data, err := response.MarshalJSON(items[i])
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, sep+"\n"); err != nil {
+			return err
+		}
+		if _, err := e.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Encoder
+func (e *jsonEncoder[T]) Close() error {
+	end := "\n]\n"
+	if !e.started {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// csvEncoder streams records as CSV rows, with a header row derived from the json tags of T
+type csvEncoder[T any] struct {
+	w       *csv.Writer
+	columns []column
+}
+
+// Write implements Encoder
+func (e *csvEncoder[T]) Write(items []T) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	for i := range items {
+		value := reflect.ValueOf(items[i])
+		for value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+
+		record := make([]string, len(e.columns))
+		for j, col := range e.columns {
+			cell, err := formatCell(value.FieldByIndex(col.index))
+			if err != nil {
+				return fmt.Errorf("failed to encode column %s: %w", col.name, err)
+			}
+			record[j] = cell
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Close implements Encoder
+func (e *csvEncoder[T]) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// writeHeader writes the header row once, before the first record
+func (e *csvEncoder[T]) writeHeader() error {
+	if e.columns != nil {
+		return nil
+	}
+
+	e.columns = columnsOf(reflect.TypeOf((*T)(nil)).Elem())
+	header := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		header[i] = col.name
+	}
+	return e.w.Write(header)
+}
+
+// column is an exported struct field and the name it is exported under
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf returns the exported fields of struct type t named by their json tags,
+// in declaration order. Fields tagged json:"-" are skipped and untagged fields keep their Go name
+func columnsOf(t reflect.Type) []column {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, column{name: name, index: field.Index})
+	}
+	return columns
+}
+
+// ColumnNames returns the names of the CSV columns of struct type t: its exported fields
+// named by their json tags, in declaration order
+func ColumnNames(t reflect.Type) []string {
+	columns := columnsOf(t)
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// formatCell renders a field value as a CSV cell
+func formatCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(time.RFC3339), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface()), nil
+	default:
+		// Nested values have no flat representation, so embed them as JSON
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
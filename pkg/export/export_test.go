@@ -0,0 +1,61 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportRecord struct {
+	ID       int               `json:"id"`
+	Name     *string           `json:"name,omitempty"`
+	Secret   string            `json:"-"`
+	Tags     map[string]string `json:"tags"`
+	Untagged bool
+	hidden   string
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	format, err = ParseFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestColumnNames(t *testing.T) {
+	assert.Equal(t, []string{"id", "name", "tags", "Untagged"}, ColumnNames(reflect.TypeOf(&exportRecord{})))
+}
+
+func TestCSVEncoder(t *testing.T) {
+	name := "Fluffy"
+	var buf bytes.Buffer
+	encoder := NewEncoder[exportRecord](&buf, FormatCSV)
+
+	require.NoError(t, encoder.Write([]exportRecord{
+		{ID: 1, Name: &name, Secret: "s", Tags: map[string]string{"a": "b"}, Untagged: true, hidden: "h"},
+		{ID: 2},
+	}))
+	require.NoError(t, encoder.Close())
+
+	assert.Equal(t, "id,name,tags,Untagged\n1,Fluffy,\"{\"\"a\"\":\"\"b\"\"}\",true\n2,,null,false\n", buf.String())
+}
+
+func TestJSONEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := NewEncoder[exportRecord](&buf, FormatJSON)
+
+	require.NoError(t, encoder.Write([]exportRecord{{ID: 1}}))
+	require.NoError(t, encoder.Write([]exportRecord{{ID: 2}}))
+	require.NoError(t, encoder.Close())
+
+	assert.JSONEq(t, `[{"id":1,"tags":null,"Untagged":false},{"id":2,"tags":null,"Untagged":false}]`, buf.String())
+}
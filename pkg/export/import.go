@@ -0,0 +1,166 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidHeader is returned by NewCSVDecoder when the header row is missing,
+// repeats a column or names a column the record type doesn't have
+var ErrInvalidHeader = errors.New("invalid CSV header")
+
+// RowError reports a CSV row that could not be decoded. Decoding can continue with the next row
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVDecoder reads records of type T from CSV written in the format produced by the CSV encoder:
+// a header row naming columns after the json tags of T, followed by one row per record
+type CSVDecoder[T any] struct {
+	r       *csv.Reader
+	columns []column
+}
+
+// NewCSVDecoder reads the header row from r and maps its columns to the fields of T.
+// The header may list any subset of the columns in any order
+func NewCSVDecoder[T any](r io.Reader) (*CSVDecoder[T], error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: file is empty", ErrInvalidHeader)
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+		}
+		return nil, err
+	}
+
+	known := make(map[string]column)
+	for _, col := range columnsOf(reflect.TypeOf((*T)(nil)).Elem()) {
+		known[col.name] = col
+	}
+
+	seen := make(map[string]bool, len(header))
+	columns := make([]column, len(header))
+	for i, name := range header {
+		// Spreadsheet tools often prefix the first cell with a byte order mark
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+
+		col, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected column %q", ErrInvalidHeader, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidHeader, name)
+		}
+		seen[name] = true
+		columns[i] = col
+	}
+
+	return &CSVDecoder[T]{r: reader, columns: columns}, nil
+}
+
+// Decode reads the next row. It returns io.EOF after the last row and a *RowError,
+// with the row's line number, if the row is malformed or a cell doesn't fit its field
+func (d *CSVDecoder[T]) Decode() (*T, error) {
+	record, err := d.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return nil, err
+	}
+
+	line := d.Line()
+	item := new(T)
+	value := reflect.ValueOf(item).Elem()
+	for i, col := range d.columns {
+		if err := parseCell(value.FieldByIndex(col.index), record[i]); err != nil {
+			return nil, &RowError{Line: line, Err: fmt.Errorf("column %s: %w", col.name, err)}
+		}
+	}
+
+	return item, nil
+}
+
+// Line returns the line number of the row returned by the last call to Decode
+func (d *CSVDecoder[T]) Line() int {
+	line, _ := d.r.FieldPos(0)
+	return line
+}
+
+// parseCell sets field from the text of a CSV cell, the inverse of formatCell.
+// Empty cells leave the field at its zero value
+func parseCell(field reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, cell)
+		if err != nil {
+			return fmt.Errorf("invalid time %q, expected RFC 3339", cell)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", cell)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", cell)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", cell)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", cell)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
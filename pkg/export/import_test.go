@@ -0,0 +1,77 @@
+package export
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type importRecord struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	Age       *int      `json:"age"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestCSVDecoder(t *testing.T) {
+	input := "\ufeffname,age,active,created_at\n" +
+		"Fluffy,3,true,2025-01-02T03:04:05Z\n" +
+		"Rex,old,false,\n" +
+		"Tiny\n" +
+		"\"Spot, the dog\",,,\n"
+
+	decoder, err := NewCSVDecoder[importRecord](strings.NewReader(input))
+	require.NoError(t, err)
+
+	item, err := decoder.Decode()
+	require.NoError(t, err)
+	age := 3
+	assert.Equal(t, importRecord{Name: "Fluffy", Age: &age, Active: true, CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}, *item)
+
+	// A bad cell or a short row fails only that row
+	_, err = decoder.Decode()
+	var rowErr *RowError
+	require.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 3, rowErr.Line)
+	assert.ErrorContains(t, err, "column age: invalid integer \"old\"")
+
+	_, err = decoder.Decode()
+	require.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 4, rowErr.Line)
+
+	item, err = decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, importRecord{Name: "Spot, the dog"}, *item)
+
+	_, err = decoder.Decode()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestCSVDecoder_InvalidHeader(t *testing.T) {
+	for _, input := range []string{"", "name,owner\n", "name,name\n"} {
+		_, err := NewCSVDecoder[importRecord](strings.NewReader(input))
+		assert.ErrorIs(t, err, ErrInvalidHeader, "header %q", input)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var buf strings.Builder
+	age := 7
+	records := []importRecord{{ID: 1, Name: "Fluffy", Age: &age, Active: true, CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}}
+
+	encoder := NewEncoder[importRecord](&buf, FormatCSV)
+	require.NoError(t, encoder.Write(records))
+	require.NoError(t, encoder.Close())
+
+	decoder, err := NewCSVDecoder[importRecord](strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	item, err := decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, records[0], *item)
+}
@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Job statuses
+const (
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// Job is the state of a background job
+type Job struct {
+	ID        string          `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Status    string          `json:"status" example:"running"`
+	Processed int             `json:"processed" example:"200"`
+	Total     int             `json:"total" example:"500"`
+	Result    json.RawMessage `json:"result,omitempty" swaggertype:"object"` // Set when the job is done
+	ErrorCode string          `json:"error_code,omitempty" example:"INTERNAL_ERROR"`
+	Error     string          `json:"error,omitempty" example:"Internal server error"` // Set when the job failed
+}
+
+// Finished reports whether the job has stopped running
+func (j *Job) Finished() bool {
+	return j.Status != StatusRunning
+}
+
+// Store keeps job state
+type Store interface {
+	// Save stores job under its ID for ttl, replacing any earlier state
+	Save(ctx context.Context, job Job, ttl time.Duration) error
+	// Get returns the job with id, or nil if there is none
+	Get(ctx context.Context, id string) (*Job, error)
+}
+
+// NewID returns a random job ID
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
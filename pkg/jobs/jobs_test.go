@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	stores := map[string]Store{
+		"Memory": NewMemoryStore(),
+		"Redis":  NewRedisStore(client, "test:"),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			missing, err := store.Get(ctx, "missing")
+			require.NoError(t, err)
+			assert.Nil(t, missing)
+
+			job := Job{ID: "1", Status: StatusRunning, Total: 10}
+			require.NoError(t, store.Save(ctx, job, time.Minute))
+
+			job.Status = StatusDone
+			job.Processed = 10
+			job.Result = json.RawMessage(`{"inserted":10}`)
+			require.NoError(t, store.Save(ctx, job, time.Minute))
+
+			stored, err := store.Get(ctx, "1")
+			require.NoError(t, err)
+			require.NotNil(t, stored)
+			assert.Equal(t, job, *stored)
+			assert.True(t, stored.Finished())
+		})
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, Job{ID: "old", Status: StatusDone}, time.Minute))
+	now = now.Add(2 * time.Minute)
+
+	job, err := store.Get(ctx, "old")
+	require.NoError(t, err)
+	assert.Nil(t, job, "expired jobs shouldn't be returned")
+
+	require.NoError(t, store.Save(ctx, Job{ID: "new", Status: StatusRunning}, time.Minute))
+	assert.NotContains(t, store.jobs, "old", "expired jobs should be removed")
+}
+
+func TestNewID(t *testing.T) {
+	first, err := NewID()
+	require.NoError(t, err)
+	second, err := NewID()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryJob is a job held by MemoryStore with its expiry
+type memoryJob struct {
+	job       Job
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store for a single instance of the API
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]memoryJob
+	now  func() time.Time
+}
+
+// NewMemoryStore creates an in-memory job store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: make(map[string]memoryJob),
+		now:  time.Now,
+	}
+}
+
+// Save implements Store; expired jobs are removed as new ones are saved
+func (s *MemoryStore) Save(_ context.Context, job Job, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, stored := range s.jobs {
+		if now.After(stored.expiresAt) {
+			delete(s.jobs, id)
+		}
+	}
+	s.jobs[job.ID] = memoryJob{job: job, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(_ context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.jobs[id]
+	if !ok || s.now().After(stored.expiresAt) {
+		return nil, nil
+	}
+	job := stored.job
+	return &job, nil
+}
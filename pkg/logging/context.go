@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// loggerKey is the context key for the request-scoped logger
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger, which FromContext returns
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by WithLogger, falling back to the
+// global logger (see zap.ReplaceGlobals) when there is none
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok && logger != nil {
+		return logger
+	}
+	return zap.L()
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// NewSlowQueryCounter creates the counter GormLogger increments for each slow query;
+// register it to export it as db_slow_queries_total
+func NewSlowQueryCounter() prometheus.Counter {
+	return prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "db_slow_queries_total",
+		Help: "Database queries that took longer than DB_SLOW_QUERY_THRESHOLD.",
+	})
+}
+
+// GormLogger is a GORM logger writing structured entries through zap, with the request-scoped
+// logger when the query's context carries one. Queries are logged with placeholders in place
+// of their parameters, so values such as password hashes never reach the logs; GORM fills in
+// the parameters itself for Raw(...).Scan, so avoid it for sensitive values
+type GormLogger struct {
+	logger        *zap.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration // Queries taking longer are logged as warnings; 0 disables it
+	slowQueries   prometheus.Counter
+}
+
+// NewGormLogger creates a GORM logger. At gormlogger.Info every query is logged at debug
+// level; slow queries are logged from gormlogger.Warn and failed ones from gormlogger.Error.
+// Slow queries increment slowQueries, when it isn't nil, whatever the level
+func NewGormLogger(logger *zap.Logger, level gormlogger.LogLevel, slowThreshold time.Duration, slowQueries prometheus.Counter) *GormLogger {
+	return &GormLogger{
+		logger:        logger,
+		level:         level,
+		slowThreshold: slowThreshold,
+		slowQueries:   slowQueries,
+	}
+}
+
+// LogMode implements gormlogger.Interface
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+// Info implements gormlogger.Interface
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.from(ctx).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn implements gormlogger.Interface
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.from(ctx).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error implements gormlogger.Interface
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.from(ctx).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace implements gormlogger.Interface
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	if slow && l.slowQueries != nil {
+		l.slowQueries.Inc()
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		l.from(ctx).Error("Database query failed", append(queryFields(fc, elapsed), zap.Error(err))...)
+	case slow && l.level >= gormlogger.Warn:
+		l.from(ctx).Warn("Slow database query",
+			append(queryFields(fc, elapsed), zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info:
+		l.from(ctx).Debug("Database query", queryFields(fc, elapsed)...)
+	}
+}
+
+// ParamsFilter implements gorm.ParamsFilter, dropping the parameters so logged queries keep
+// their placeholders
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, _ ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+// from returns the request-scoped logger stored in ctx, or the logger the GormLogger was created with
+func (l *GormLogger) from(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok && logger != nil {
+		return logger
+	}
+	return l.logger
+}
+
+// queryFields describes a query for a log entry
+func queryFields(fc func() (string, int64), elapsed time.Duration) []zap.Field {
+	sql, rows := fc()
+	fields := []zap.Field{zap.String("sql", sql), zap.Duration("duration", elapsed)}
+	// GORM reports -1 when the statement doesn't affect a row count
+	if rows >= 0 {
+		fields = append(fields, zap.Int64("rows", rows))
+	}
+	return fields
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger_Trace(t *testing.T) {
+	query := func() (string, int64) { return "SELECT * FROM `animals` WHERE id = ?", 1 }
+
+	tests := []struct {
+		name          string
+		level         gormlogger.LogLevel
+		elapsed       time.Duration
+		err           error
+		expectedLevel zapcore.Level
+		expectedMsg   string // Empty when nothing should be logged
+		expectedSlow  float64
+	}{
+		{name: "SlowQuery", level: gormlogger.Warn, elapsed: time.Second, expectedLevel: zap.WarnLevel, expectedMsg: "Slow database query", expectedSlow: 1},
+		{name: "FastQuery", level: gormlogger.Warn, elapsed: time.Millisecond},
+		{name: "FastQueryAtInfo", level: gormlogger.Info, elapsed: time.Millisecond, expectedLevel: zap.DebugLevel, expectedMsg: "Database query"},
+		{name: "SlowQueryCountedWhenSilent", level: gormlogger.Silent, elapsed: time.Second, expectedSlow: 1},
+		{name: "FailedQuery", level: gormlogger.Warn, elapsed: time.Millisecond, err: errors.New("deadlock"), expectedLevel: zap.ErrorLevel, expectedMsg: "Database query failed"},
+		{name: "RecordNotFound", level: gormlogger.Warn, elapsed: time.Millisecond, err: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+			slowQueries := NewSlowQueryCounter()
+			l := NewGormLogger(zap.New(core), tt.level, 200*time.Millisecond, slowQueries)
+
+			l.Trace(context.Background(), time.Now().Add(-tt.elapsed), query, tt.err)
+
+			assert.Equal(t, tt.expectedSlow, testutil.ToFloat64(slowQueries))
+			if tt.expectedMsg == "" {
+				assert.Zero(t, logs.Len())
+				return
+			}
+			require.Equal(t, 1, logs.Len())
+			entry := logs.All()[0]
+			assert.Equal(t, tt.expectedLevel, entry.Level)
+			assert.Equal(t, tt.expectedMsg, entry.Message)
+			assert.Equal(t, "SELECT * FROM `animals` WHERE id = ?", entry.ContextMap()["sql"])
+			assert.Equal(t, int64(1), entry.ContextMap()["rows"])
+		})
+	}
+}
+
+func TestGormLogger_MasksParameters(t *testing.T) {
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	core, logs := observer.New(zap.DebugLevel)
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		Logger: NewGormLogger(zap.New(core), gormlogger.Info, 0, nil),
+	})
+	require.NoError(t, err)
+
+	sqlMock.ExpectQuery("SELECT").WithArgs("ann@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var ids []int
+	require.NoError(t, db.Table("users").Where("email = ?", "ann@example.com").Pluck("id", &ids).Error)
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "SELECT `id` FROM `users` WHERE email = ?", logs.All()[0].ContextMap()["sql"])
+}
+
+func TestGormLogger_UsesRequestLogger(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	l := NewGormLogger(zap.NewNop(), gormlogger.Warn, 200*time.Millisecond, nil)
+
+	ctx := WithLogger(context.Background(), zap.New(core).With(zap.String("request_id", "abc")))
+	l.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 1", -1 }, nil)
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "abc", logs.All()[0].ContextMap()["request_id"])
+	assert.NotContains(t, logs.All()[0].ContextMap(), "rows")
+}
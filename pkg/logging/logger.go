@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/linkeunid/go-api/pkg/config"
 	"go.uber.org/zap"
@@ -23,32 +24,60 @@ const (
 
 // InitializeLogger creates and configures the logger based on configuration
 func InitializeLogger(cfg *config.Config) (*zap.Logger, error) {
-	// Determine rotation type from configuration
-	rotationType := RotationTypeDaily // default
+	return InitializeLoggerWithLevel(cfg, NewAtomicLevel(cfg))
+}
+
+// InitializeLoggerWithRotation creates a logger with specified rotation type
+func InitializeLoggerWithRotation(cfg *config.Config, rotationType LogRotationType) (*zap.Logger, error) {
+	return newLogger(cfg, rotationType, NewAtomicLevel(cfg))
+}
+
+// InitializeLoggerWithLevel creates a logger like InitializeLogger whose minimum level is
+// controlled by level, so it can be changed with level.SetLevel while the application runs
+func InitializeLoggerWithLevel(cfg *config.Config, level zap.AtomicLevel) (*zap.Logger, error) {
+	rotationType := RotationTypeDaily
 	if cfg.Logging.RotationType == "size" {
 		rotationType = RotationTypeSize
 	}
-	return InitializeLoggerWithRotation(cfg, rotationType)
+	return newLogger(cfg, rotationType, level)
 }
 
-// InitializeLoggerWithRotation creates a logger with specified rotation type
-func InitializeLoggerWithRotation(cfg *config.Config, rotationType LogRotationType) (*zap.Logger, error) {
-	zapConfig := zap.NewProductionConfig()
-	if cfg.IsDevelopment() {
-		zapConfig = zap.NewDevelopmentConfig()
+// NewAtomicLevel returns an adjustable level set to the configured log level.
+// Unknown levels fall back to debug in development and info elsewhere
+func NewAtomicLevel(cfg *config.Config) zap.AtomicLevel {
+	level, err := ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		level = zapcore.InfoLevel
+		if cfg.IsDevelopment() {
+			level = zapcore.DebugLevel
+		}
 	}
+	return zap.NewAtomicLevelAt(level)
+}
 
-	// Configure log level
-	switch cfg.Logging.Level {
+// ParseLevel parses one of the supported log level names: debug, info, warn or error
+func ParseLevel(name string) (zapcore.Level, error) {
+	switch strings.ToLower(name) {
 	case "debug":
-		zapConfig.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+		return zapcore.DebugLevel, nil
 	case "info":
-		zapConfig.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+		return zapcore.InfoLevel, nil
 	case "warn":
-		zapConfig.Level = zap.NewAtomicLevelAt(zapcore.WarnLevel)
+		return zapcore.WarnLevel, nil
 	case "error":
-		zapConfig.Level = zap.NewAtomicLevelAt(zapcore.ErrorLevel)
+		return zapcore.ErrorLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+	}
+}
+
+// newLogger builds the logger for the given rotation type and level
+func newLogger(cfg *config.Config, rotationType LogRotationType, level zap.AtomicLevel) (*zap.Logger, error) {
+	zapConfig := zap.NewProductionConfig()
+	if cfg.IsDevelopment() {
+		zapConfig = zap.NewDevelopmentConfig()
 	}
+	zapConfig.Level = level
 
 	// Check if file output is enabled
 	if cfg.Logging.FileOutputPath != "" {
@@ -91,7 +120,7 @@ func InitializeLoggerWithRotation(cfg *config.Config, rotationType LogRotationTy
 		}
 
 		// Create a core that writes to the file
-		fileCore := zapcore.NewCore(encoder, fileWriter, zapConfig.Level)
+		var core zapcore.Core = zapcore.NewCore(encoder, fileWriter, zapConfig.Level)
 
 		// If standard output is also requested, create a multi-writer core
 		if cfg.Logging.OutputPath == "stdout" || cfg.Logging.OutputPath == "stderr" {
@@ -106,15 +135,17 @@ func InitializeLoggerWithRotation(cfg *config.Config, rotationType LogRotationTy
 			stdCore := zapcore.NewCore(encoder, stdWriter, zapConfig.Level)
 
 			// Use a tee to write to both outputs
-			return zap.New(zapcore.NewTee(fileCore, stdCore), zap.AddCaller()), nil
+			core = zapcore.NewTee(core, stdCore)
 		}
 
-		// Only write to file
-		return zap.New(fileCore, zap.AddCaller()), nil
+		// Mask secrets before they reach any output
+		return zap.New(NewRedactingCore(core, cfg.Logging.RedactFields), zap.AddCaller()), nil
 	}
 
-	// Standard zap logger with no file output
-	logger, err := zapConfig.Build()
+	// Standard zap logger with no file output, masking secrets before they are written
+	logger, err := zapConfig.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return NewRedactingCore(core, cfg.Logging.RedactFields)
+	}))
 	if err != nil {
 		return nil, err
 	}
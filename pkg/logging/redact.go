@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/util"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedPlaceholder replaces values of denylisted fields that aren't strings
+const redactedPlaceholder = "******"
+
+// secretPattern finds one kind of secret inside a string and masks each match
+type secretPattern struct {
+	re   *regexp.Regexp
+	mask func(match string) string
+}
+
+// secretPatterns are applied in order to every logged string
+var secretPatterns = []secretPattern{
+	// URLs, which may carry user:password@ credentials or tokens in the query string
+	{regexp.MustCompile(`(?i)\b[a-z][a-z0-9+.-]*://[^\s"']+`), util.MaskURL},
+	// MySQL DSNs: user:password@tcp(host:port)/db
+	{regexp.MustCompile(`[^\s:/@"']+:[^\s@"']+@(?:tcp|unix)\(`), util.MaskDsn},
+	// Authorization header values
+	{regexp.MustCompile(`(?i)\bBearer\s+[A-Za-z0-9._~+/=-]+`), maskBearer},
+	// JWTs outside a Bearer header
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), util.MaskJWT},
+	// Email addresses
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), util.MaskEmail},
+}
+
+// maskBearer masks the token of a "Bearer <token>" value, keeping the scheme
+func maskBearer(match string) string {
+	scheme, token, _ := strings.Cut(match, " ")
+	return scheme + " " + util.MaskCredential(strings.TrimSpace(token))
+}
+
+// RedactingCore wraps a zapcore.Core and masks secrets before entries are written:
+// values of fields whose name contains a denylisted word are masked entirely, and
+// JWTs, DSN passwords, Bearer tokens, URL credentials and emails found in the message
+// or in any string or error field are masked with the util.Mask* helpers
+type RedactingCore struct {
+	zapcore.Core
+	deniedFields []string
+}
+
+// NewRedactingCore wraps core so that secrets are masked before they are logged.
+// deniedFields are matched case-insensitively against field names, so "token" also covers
+// "refresh_token"; nil uses config.DefaultRedactFields
+func NewRedactingCore(core zapcore.Core, deniedFields []string) *RedactingCore {
+	if deniedFields == nil {
+		deniedFields = config.DefaultRedactFields
+	}
+
+	denied := make([]string, 0, len(deniedFields))
+	for _, name := range deniedFields {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			denied = append(denied, name)
+		}
+	}
+
+	return &RedactingCore{Core: core, deniedFields: denied}
+}
+
+// With implements zapcore.Core, redacting fields added to the logger's context
+func (c *RedactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &RedactingCore{Core: c.Core.With(c.redactFields(fields)), deniedFields: c.deniedFields}
+}
+
+// Check implements zapcore.Core, routing enabled entries through this core's Write
+func (c *RedactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core, redacting the message and fields before passing them on
+func (c *RedactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = RedactString(ent.Message)
+	return c.Core.Write(ent, c.redactFields(fields))
+}
+
+// redactFields returns fields with secrets masked, copying the slice only if something changed
+func (c *RedactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, field := range fields {
+		masked, changed := c.redactField(field)
+		if !changed {
+			continue
+		}
+		if redacted == nil {
+			redacted = make([]zapcore.Field, len(fields))
+			copy(redacted, fields)
+		}
+		redacted[i] = masked
+	}
+
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// redactField masks a single field, reporting whether it was changed
+func (c *RedactingCore) redactField(field zapcore.Field) (zapcore.Field, bool) {
+	if c.isDenied(field.Key) {
+		switch field.Type {
+		case zapcore.StringType:
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: util.MaskCredential(field.String)}, true
+		case zapcore.SkipType:
+			return field, false
+		default:
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: redactedPlaceholder}, true
+		}
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		if masked := RedactString(field.String); masked != field.String {
+			field.String = masked
+			return field, true
+		}
+	case zapcore.ErrorType:
+		// Errors from drivers and HTTP clients often quote the DSN or URL they failed on
+		if err, ok := field.Interface.(error); ok && err != nil {
+			msg := err.Error()
+			if masked := RedactString(msg); masked != msg {
+				return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: masked}, true
+			}
+		}
+	}
+	return field, false
+}
+
+// isDenied reports whether a field name contains a denylisted word
+func (c *RedactingCore) isDenied(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range c.deniedFields {
+		if strings.Contains(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactString masks JWTs, DSN passwords, Bearer tokens, URL credentials and emails in s
+func RedactString(s string) string {
+	for _, p := range secretPatterns {
+		s = p.re.ReplaceAllStringFunc(s, p.mask)
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// identityHandler responds with the user ID and role the authentication middleware stored
+var identityHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = fmt.Fprintf(w, "%v/%v", r.Context().Value(KeyUserID), r.Context().Value(KeyUserRole))
+})
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	keys, err := auth.ParseAPIKeys([]string{"42:service:" + auth.HashAPIKey("s3cret")})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		apiKey         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "ValidKey", enabled: true, apiKey: "s3cret", expectedStatus: http.StatusOK, expectedBody: "42/service"},
+		{name: "InvalidKey", enabled: true, apiKey: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "MissingKey", enabled: true, expectedStatus: http.StatusUnauthorized},
+		{name: "AuthDisabled", enabled: false, expectedStatus: http.StatusOK, expectedBody: "<nil>/<nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAPIKeyMiddleware(keys, &config.AuthConfig{Enabled: tt.enabled}, zap.NewNop())
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			rr := httptest.NewRecorder()
+			m.Authenticate(identityHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAnyAuth(t *testing.T) {
+	cfg := &config.AuthConfig{Enabled: true, JWTSecret: "test-secret", JWTExpiration: time.Hour}
+	jwtService := auth.NewJWTService(cfg)
+	token, err := jwtService.GenerateToken(7, "ann", "admin", "ann@example.com", nil)
+	require.NoError(t, err)
+
+	keys, err := auth.ParseAPIKeys([]string{"42:service:" + auth.HashAPIKey("s3cret")})
+	require.NoError(t, err)
+
+	jwtAuth := NewAuthMiddleware(jwtService, cfg, nil, zap.NewNop())
+	authenticate := AnyAuth(NewAPIKeyMiddleware(keys, cfg, zap.NewNop()), jwtAuth)
+
+	tests := []struct {
+		name           string
+		header         string
+		value          string
+		requireRole    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "APIKey", header: APIKeyHeader, value: "s3cret", expectedStatus: http.StatusOK, expectedBody: "42/service"},
+		{name: "JWT", header: "Authorization", value: "Bearer " + token, expectedStatus: http.StatusOK, expectedBody: "7/admin"},
+		{name: "InvalidAPIKey", header: APIKeyHeader, value: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "NoCredentials", expectedStatus: http.StatusUnauthorized},
+		{name: "APIKeyRoleChecked", header: APIKeyHeader, value: "s3cret", requireRole: "admin", expectedStatus: http.StatusForbidden},
+		{name: "APIKeyRoleAllowed", header: APIKeyHeader, value: "s3cret", requireRole: "service", expectedStatus: http.StatusOK, expectedBody: "42/service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handler http.Handler = identityHandler
+			if tt.requireRole != "" {
+				handler = jwtAuth.RequireRole(tt.requireRole)(handler)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rr := httptest.NewRecorder()
+			authenticate(handler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/linkeunid/go-api/pkg/auth"
@@ -24,24 +25,76 @@ const (
 	KeyUserRole ContextKey = "user_role"
 	// KeyUserEmail is the context key for user email
 	KeyUserEmail ContextKey = "user_email"
+	// KeyUserScopes is the context key for the scopes granted to the user
+	KeyUserScopes ContextKey = "user_scopes"
+	// KeyTokenClaims is the context key for the *auth.Claims of a validated JWT
+	KeyTokenClaims ContextKey = "token_claims"
+	// KeyValidatedModel is the context key for the model decoded and validated by ValidationMiddleware
+	KeyValidatedModel ContextKey = "validated_model"
+	// KeyValidatedModels is the context key for the models decoded and validated from a JSON array
+	// by ArrayValidationMiddleware
+	KeyValidatedModels ContextKey = "validated_models"
+	// KeyValidatedQuery is the context key for the query parameters bound and validated by ValidateQuery
+	KeyValidatedQuery ContextKey = "validated_query"
 )
 
+// AuthProvider authenticates requests carrying one kind of credentials
+type AuthProvider interface {
+	// HasCredentials reports whether r carries the credentials the provider checks
+	HasCredentials(r *http.Request) bool
+	// Authenticate is a middleware that rejects requests whose credentials are missing or invalid
+	Authenticate(next http.Handler) http.Handler
+}
+
+// AnyAuth is a middleware that authenticates each request with the first provider whose
+// credentials it carries, so a client may use any of them. Requests carrying none are
+// passed to the last provider, which rejects them
+func AnyAuth(providers ...AuthProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		handlers := make([]http.Handler, len(providers))
+		for i, provider := range providers {
+			handlers[i] = provider.Authenticate(next)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i, provider := range providers {
+				if provider.HasCredentials(r) {
+					handlers[i].ServeHTTP(w, r)
+					return
+				}
+			}
+			handlers[len(handlers)-1].ServeHTTP(w, r)
+		})
+	}
+}
+
 // AuthMiddleware provides JWT authentication
 type AuthMiddleware struct {
 	jwtService *auth.JWTService
 	config     *config.AuthConfig
+	roles      auth.RoleRanker
 	logger     *zap.Logger
 }
 
-// NewAuthMiddleware creates a new authentication middleware
-func NewAuthMiddleware(jwtService *auth.JWTService, config *config.AuthConfig, logger *zap.Logger) *AuthMiddleware {
+// NewAuthMiddleware creates a new authentication middleware. roles decides which roles
+// RequireRole admits; nil admits only the exact roles listed
+func NewAuthMiddleware(jwtService *auth.JWTService, config *config.AuthConfig, roles auth.RoleRanker, logger *zap.Logger) *AuthMiddleware {
+	if roles == nil {
+		roles = auth.FlatRoles{}
+	}
 	return &AuthMiddleware{
 		jwtService: jwtService,
 		config:     config,
+		roles:      roles,
 		logger:     logger,
 	}
 }
 
+// HasCredentials implements AuthProvider
+func (am *AuthMiddleware) HasCredentials(r *http.Request) bool {
+	return r.Header.Get("Authorization") != ""
+}
+
 // Authenticate middleware for JWT authentication
 func (am *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -75,10 +128,14 @@ func (am *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			switch err {
 			case auth.ErrTokenExpired:
 				response.Unauthorized(w, r, "Token has expired")
+			case auth.ErrTokenNotYetValid:
+				response.Unauthorized(w, r, "Token is not valid yet; check the issuer's clock")
 			case auth.ErrTokenInvalid:
 				response.Unauthorized(w, r, "Invalid token")
 			case auth.ErrInvalidIssuer:
 				response.Unauthorized(w, r, "Invalid token issuer")
+			case auth.ErrInvalidAudience:
+				response.Unauthorized(w, r, "Token was not issued for this API")
 			default:
 				response.Unauthorized(w, r, "Authentication failed")
 			}
@@ -98,13 +155,19 @@ func (am *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, KeyUsername, claims.Username)
 		ctx = context.WithValue(ctx, KeyUserRole, claims.Role)
 		ctx = context.WithValue(ctx, KeyUserEmail, claims.Email)
+		ctx = context.WithValue(ctx, KeyUserScopes, claims.Scopes())
+		ctx = context.WithValue(ctx, KeyTokenClaims, claims)
+
+		// Let the request logger further up the chain see who made the request
+		recordUserID(ctx, userID)
 
 		// Pass the request with user information in context to the next handler
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// RequireRole middleware for role-based access control
+// RequireRole middleware for role-based access control; it admits users with any of roles
+// or, with a role hierarchy, a role ranked above one of them
 func (am *AuthMiddleware) RequireRole(roles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -121,10 +184,10 @@ func (am *AuthMiddleware) RequireRole(roles ...string) func(http.Handler) http.H
 				return
 			}
 
-			// Check if user has required role
+			// Check if user has one of the required roles, or a role above it
 			hasRole := false
 			for _, requiredRole := range roles {
-				if role == requiredRole {
+				if am.roles.AtLeast(role, requiredRole) {
 					hasRole = true
 					break
 				}
@@ -139,3 +202,95 @@ func (am *AuthMiddleware) RequireRole(roles ...string) func(http.Handler) http.H
 		})
 	}
 }
+
+// RequireScope middleware for scope-based access control; it admits users granted every one of scopes
+func (am *AuthMiddleware) RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return am.requireScopes(true, scopes)
+}
+
+// RequireAnyScope middleware for scope-based access control; it admits users granted at least one of scopes
+func (am *AuthMiddleware) RequireAnyScope(scopes ...string) func(http.Handler) http.Handler {
+	return am.requireScopes(false, scopes)
+}
+
+// requireScopes checks the user's scopes against scopes, requiring all of them or any one
+func (am *AuthMiddleware) requireScopes(all bool, scopes []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Skip scope check if auth is disabled
+			if !am.config.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			granted, _ := r.Context().Value(KeyUserScopes).([]string)
+			if !hasScopes(granted, scopes, all) {
+				response.Forbidden(w, r, "Insufficient scope")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HasScopes reports whether the user in ctx was granted every one of scopes, for checks that
+// can't run as middleware, such as GraphQL mutations
+func HasScopes(ctx context.Context, scopes ...string) bool {
+	granted, _ := ctx.Value(KeyUserScopes).([]string)
+	return hasScopes(granted, scopes, true)
+}
+
+// hasScopes reports whether granted holds all of required or, if all is false, any of them
+func hasScopes(granted, required []string, all bool) bool {
+	for _, scope := range required {
+		ok := slices.Contains(granted, scope)
+		if all && !ok {
+			return false
+		}
+		if !all && ok {
+			return true
+		}
+	}
+	return all
+}
+
+// OnWrites applies middlewares only to requests that may change data, leaving GET, HEAD and
+// OPTIONS requests untouched, so reads can stay public while writes require credentials
+func OnWrites(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		guarded := next
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			guarded = middlewares[i](guarded)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+			default:
+				guarded.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// WithoutCredentials applies middlewares only to requests carrying no credentials, neither an
+// Authorization nor an X-API-Key header. Requests that carry them are left to middleware run
+// after authentication, such as a RateLimit keyed by the authenticated user
+func WithoutCredentials(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		guarded := next
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			guarded = middlewares[i](guarded)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" || r.Header.Get(APIKeyHeader) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			guarded.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/linkeunid/go-api/pkg/auth"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRequireRole(t *testing.T) {
+	hierarchy, err := auth.NewRoleHierarchy("admin", "editor", "user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		roles          auth.RoleRanker
+		userRole       interface{}
+		required       []string
+		expectedStatus int
+	}{
+		{name: "FlatExactRole", userRole: "admin", required: []string{"admin"}, expectedStatus: http.StatusOK},
+		{name: "FlatHigherRole", userRole: "admin", required: []string{"user"}, expectedStatus: http.StatusForbidden},
+		{name: "FlatAnyListedRole", userRole: "user", required: []string{"admin", "user"}, expectedStatus: http.StatusOK},
+		{name: "HierarchyHigherRole", roles: hierarchy, userRole: "admin", required: []string{"user"}, expectedStatus: http.StatusOK},
+		{name: "HierarchySameRole", roles: hierarchy, userRole: "editor", required: []string{"editor"}, expectedStatus: http.StatusOK},
+		{name: "HierarchyLowerRole", roles: hierarchy, userRole: "user", required: []string{"editor"}, expectedStatus: http.StatusForbidden},
+		{name: "HierarchyUnknownRole", roles: hierarchy, userRole: "guest", required: []string{"user"}, expectedStatus: http.StatusForbidden},
+		{name: "HierarchyUnknownRequiredRole", roles: hierarchy, userRole: "admin", required: []string{"auditor"}, expectedStatus: http.StatusForbidden},
+		{name: "NoRole", roles: hierarchy, required: []string{"user"}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := NewAuthMiddleware(nil, &config.AuthConfig{Enabled: true}, tt.roles, zap.NewNop())
+			handler := am.RequireRole(tt.required...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.userRole != nil {
+				req = req.WithContext(context.WithValue(req.Context(), KeyUserRole, tt.userRole))
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		any            bool
+		granted        interface{}
+		required       []string
+		expectedStatus int
+	}{
+		{name: "AllGranted", granted: []string{"animals:read", "animals:write"}, required: []string{"animals:read", "animals:write"}, expectedStatus: http.StatusOK},
+		{name: "AllMissingOne", granted: []string{"animals:read"}, required: []string{"animals:read", "animals:write"}, expectedStatus: http.StatusForbidden},
+		{name: "AnyGrantedOne", any: true, granted: []string{"animals:read"}, required: []string{"animals:read", "animals:write"}, expectedStatus: http.StatusOK},
+		{name: "AnyGrantedNone", any: true, granted: []string{"flowers:write"}, required: []string{"animals:read", "animals:write"}, expectedStatus: http.StatusForbidden},
+		{name: "NoScopes", required: []string{"animals:write"}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := NewAuthMiddleware(nil, &config.AuthConfig{Enabled: true}, nil, zap.NewNop())
+			requireScope := am.RequireScope
+			if tt.any {
+				requireScope = am.RequireAnyScope
+			}
+			handler := requireScope(tt.required...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.granted != nil {
+				req = req.WithContext(context.WithValue(req.Context(), KeyUserScopes, tt.granted))
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestHasScopes(t *testing.T) {
+	ctx := context.WithValue(context.Background(), KeyUserScopes, []string{"animals:read", "animals:write"})
+
+	assert.True(t, HasScopes(ctx, "animals:write"))
+	assert.True(t, HasScopes(ctx, "animals:read", "animals:write"))
+	assert.False(t, HasScopes(ctx, "animals:write", "flowers:write"))
+	assert.False(t, HasScopes(context.Background(), "animals:write"))
+}
+
+func TestOnWrites(t *testing.T) {
+	cfg := &config.AuthConfig{Enabled: true, JWTSecret: "test-secret", JWTExpiration: time.Hour}
+	jwtService := auth.NewJWTService(cfg)
+	am := NewAuthMiddleware(jwtService, cfg, nil, zap.NewNop())
+	handler := OnWrites(am.Authenticate, am.RequireScope("animals:write"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	writer, err := jwtService.GenerateToken(7, "ann", "user", "ann@example.com", []string{"animals:read", "animals:write"})
+	require.NoError(t, err)
+	reader, err := jwtService.GenerateToken(8, "bob", "admin", "bob@example.com", []string{"animals:read"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		expectedStatus int
+	}{
+		{name: "AnonymousRead", method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "AnonymousWrite", method: http.MethodPost, expectedStatus: http.StatusUnauthorized},
+		{name: "WriteScope", method: http.MethodDelete, token: writer, expectedStatus: http.StatusOK},
+		{name: "ReadScopeOnly", method: http.MethodPut, token: reader, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestAuthenticate_FutureToken(t *testing.T) {
+	cfg := &config.AuthConfig{Enabled: true, JWTSecret: "test-secret", ClockSkew: 30 * time.Second}
+	future := time.Now().Add(time.Hour)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "7",
+		NotBefore: jwt.NewNumericDate(future),
+		ExpiresAt: jwt.NewNumericDate(future.Add(time.Hour)),
+	}}).SignedString([]byte(cfg.JWTSecret))
+	require.NoError(t, err)
+
+	am := NewAuthMiddleware(auth.NewJWTService(cfg), cfg, nil, zap.NewNop())
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	am.Authenticate(identityHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Token is not valid yet")
+}
+
+func TestWithoutCredentials(t *testing.T) {
+	reject := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	}
+	handler := WithoutCredentials(reject)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		header         string
+		expectedStatus int
+	}{
+		{name: "Anonymous", expectedStatus: http.StatusTeapot},
+		{name: "BearerToken", header: "Authorization", expectedStatus: http.StatusOK},
+		{name: "APIKey", header: APIKeyHeader, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, "secret")
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
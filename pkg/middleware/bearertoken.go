@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/linkeunid/go-api/pkg/response"
+)
+
+// RequireBearerToken only lets through requests whose Authorization header carries token as a
+// bearer token, for endpoints scraped by infrastructure rather than called by users. An empty
+// token lets every request through
+func RequireBearerToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				response.Unauthorized(w, r, "Invalid or missing bearer token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "NoTokenConfigured", token: "", authorization: "", expectedStatus: http.StatusOK},
+		{name: "Matching", token: "s3cret", authorization: "Bearer s3cret", expectedStatus: http.StatusOK},
+		{name: "Missing", token: "s3cret", authorization: "", expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong", token: "s3cret", authorization: "Bearer guess", expectedStatus: http.StatusUnauthorized},
+		{name: "NotBearer", token: "s3cret", authorization: "Basic s3cret", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireBearerToken(tt.token)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
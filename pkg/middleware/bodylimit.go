@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes is the default maximum request body size (1MB)
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// bodyBaseKey is the context key for the request body as it was before any limit was applied
+type bodyBaseKey struct{}
+
+// MaxBodyBytes is a middleware that limits the size of request bodies.
+// Like WithTimeout it can be applied globally and again on a sub-router or route:
+// an inner MaxBodyBytes replaces the outer limit instead of being capped by it,
+// so e.g. an upload route can accept larger bodies than the default
+func MaxBodyBytes(n int64) func(http.Handler) http.Handler {
+	if n <= 0 {
+		n = DefaultMaxBodyBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Limits are applied to the body before any earlier MaxBodyBytes
+			base, ok := r.Context().Value(bodyBaseKey{}).(io.ReadCloser)
+			if !ok {
+				base = r.Body
+				r = r.WithContext(context.WithValue(r.Context(), bodyBaseKey{}, base))
+			}
+
+			// Wrap the body so reads beyond the limit fail with *http.MaxBytesError
+			r.Body = http.MaxBytesReader(w, base, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxBodyBytes(t *testing.T) {
+	// readStatus reports 413 when the body exceeds the active limit
+	readStatus := func(w http.ResponseWriter, r *http.Request) {
+		var maxBytesErr *http.MaxBytesError
+		if _, err := io.ReadAll(r.Body); errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	r := chi.NewRouter()
+	r.Use(MaxBodyBytes(10))
+	r.Post("/", readStatus)
+	r.With(MaxBodyBytes(100)).Post("/upload", readStatus)
+	r.With(MaxBodyBytes(5)).Post("/tiny", readStatus)
+
+	tests := []struct {
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"/", strings.Repeat("a", 10), http.StatusOK},
+		{"/", strings.Repeat("a", 11), http.StatusRequestEntityTooLarge},
+		{"/upload", strings.Repeat("a", 50), http.StatusOK},
+		{"/upload", strings.Repeat("a", 101), http.StatusRequestEntityTooLarge},
+		{"/tiny", strings.Repeat("a", 6), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		assert.Equal(t, tt.expectedStatus, rr.Code, "%s with %d bytes", tt.path, len(tt.body))
+	}
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/linkeunid/go-api/pkg/response"
+	"go.uber.org/zap"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from an earlier request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is what is stored for an idempotency key: the hash of the first request's
+// body and, once that request has finished, the response to replay
+type IdempotencyRecord struct {
+	BodyHash  string      `json:"body_hash"`
+	Completed bool        `json:"completed"`
+	Status    int         `json:"status,omitempty"`
+	Header    http.Header `json:"header,omitempty"`
+	Body      []byte      `json:"body,omitempty"`
+}
+
+// IdempotencyStore keeps idempotency records
+type IdempotencyStore interface {
+	// Begin stores record under key unless the key is already in use, in which case
+	// the existing record is returned and nothing is stored
+	Begin(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (existing *IdempotencyRecord, err error)
+	// Complete replaces the record under key with the finished one
+	Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Abandon removes key so the request can be retried
+	Abandon(ctx context.Context, key string) error
+}
+
+// RedisIdempotencyStore is an IdempotencyStore backed by Redis, so keys are shared by all
+// instances of the API
+type RedisIdempotencyStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisIdempotencyStore creates a Redis-backed idempotency store
+func NewRedisIdempotencyStore(client *redis.Client, keyPrefix string) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{
+		client:    client,
+		keyPrefix: keyPrefix + "idempotency:",
+	}
+}
+
+// Begin implements IdempotencyStore
+func (s *RedisIdempotencyStore) Begin(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+
+	stored, err := s.client.SetNX(ctx, s.keyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	if stored {
+		return nil, nil
+	}
+
+	existing, err := s.client.Get(ctx, s.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The key expired between SETNX and GET; try again
+		return s.Begin(ctx, key, record, ttl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency record: %w", err)
+	}
+
+	var existingRecord IdempotencyRecord
+	if err := json.Unmarshal(existing, &existingRecord); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	return &existingRecord, nil
+}
+
+// Complete implements IdempotencyStore
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+
+	if err := s.client.Set(ctx, s.keyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Abandon implements IdempotencyStore
+func (s *RedisIdempotencyStore) Abandon(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.keyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to remove idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Idempotency is a middleware that makes requests carrying an Idempotency-Key header safe to retry.
+// The first response for a key is stored for ttl and replayed, with an Idempotent-Replayed header,
+// to later requests with the same key and body. Reusing a key with a different body, or while the
+// first request is still running, is rejected with 409 Conflict. Server errors are not stored so
+// the request can be retried. Keys are scoped to the client as identified for rate limiting.
+// With a nil store, or when the store fails, requests are passed through unchanged
+func Idempotency(store IdempotencyStore, ttl time.Duration, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if store == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				response.BadRequest(w, r, fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength), nil)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					response.PayloadTooLarge(w, r, fmt.Sprintf("Request body exceeds the %d byte limit", maxBytesErr.Limit))
+					return
+				}
+				response.BadRequest(w, r, "Failed to read request body", err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			sum := sha256.Sum256(body)
+			bodyHash := hex.EncodeToString(sum[:])
+			key := fmt.Sprintf("%s:%s %s:%s", rateLimitKey(r), r.Method, r.URL.Path, idempotencyKey)
+
+			existing, err := store.Begin(r.Context(), key, IdempotencyRecord{BodyHash: bodyHash}, ttl)
+			if err != nil {
+				logger.Warn("Idempotency store unavailable", zap.String("key", idempotencyKey), zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.BodyHash != bodyHash:
+					response.Error(w, r, http.StatusConflict, "IDEMPOTENCY_KEY_REUSED", IdempotencyKeyHeader+" was already used with a different request body")
+				case !existing.Completed:
+					response.Error(w, r, http.StatusConflict, "IDEMPOTENCY_KEY_IN_USE", "A request with this "+IdempotencyKeyHeader+" is still being processed")
+				default:
+					replayResponse(w, existing)
+				}
+				return
+			}
+
+			// Store operations after the handler must not fail because the request context ended
+			storeCtx := context.WithoutCancel(r.Context())
+			recorder := newIdempotencyRecorder(w)
+			completed := false
+			defer func() {
+				if !completed {
+					if err := store.Abandon(storeCtx, key); err != nil {
+						logger.Warn("Failed to release idempotency key", zap.String("key", idempotencyKey), zap.Error(err))
+					}
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+			if recorder.status == 0 {
+				recorder.WriteHeader(http.StatusOK)
+			}
+
+			if recorder.status >= http.StatusInternalServerError {
+				return
+			}
+
+			record := IdempotencyRecord{
+				BodyHash:  bodyHash,
+				Completed: true,
+				Status:    recorder.status,
+				Header:    recorder.header,
+				Body:      recorder.body.Bytes(),
+			}
+			if err := store.Complete(storeCtx, key, record, ttl); err != nil {
+				logger.Warn("Failed to store idempotent response", zap.String("key", idempotencyKey), zap.Error(err))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// replayResponse writes a stored response
+func replayResponse(w http.ResponseWriter, record *IdempotencyRecord) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	_, _ = w.Write(record.Body)
+}
+
+// idempotencyRecorder passes a response through while keeping a copy of it.
+// Only headers set by the handler are kept, not those earlier middleware set for this request
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	before http.Header
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+// newIdempotencyRecorder creates a recorder for w
+func newIdempotencyRecorder(w http.ResponseWriter) *idempotencyRecorder {
+	return &idempotencyRecorder{ResponseWriter: w, before: w.Header().Clone()}
+}
+
+// WriteHeader records the status and the headers the handler set
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if r.status != 0 {
+		return
+	}
+	r.status = status
+
+	r.header = make(http.Header)
+	for name, values := range r.ResponseWriter.Header() {
+		if before, ok := r.before[name]; ok && slices.Equal(before, values) {
+			continue
+		}
+		r.header[name] = append([]string(nil), values...)
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the body
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
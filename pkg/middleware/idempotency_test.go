@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryIdempotencyStore is an in-process IdempotencyStore for tests
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Begin(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[key]; ok {
+		return &existing, nil
+	}
+	s.records[key] = record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Abandon(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// failingIdempotencyStore simulates an unavailable store backend
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) Begin(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingIdempotencyStore) Abandon(ctx context.Context, key string) error {
+	return errors.New("connection refused")
+}
+
+func TestIdempotency_Middleware(t *testing.T) {
+	// createHandler counts its calls and responds like a create endpoint
+	createHandler := func(calls *int, status int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Location", "/animals/1")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		})
+	}
+
+	send := func(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/animals", strings.NewReader(body))
+		req.RemoteAddr = "192.0.2.1:1234"
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		rr.Header().Set("X-Request-ID", "set-by-earlier-middleware")
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("ReplaysFirstResponse", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		first := send(handler, "key-1", `{"name":"Fluffy"}`)
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+		replay := send(handler, "key-1", `{"name":"Fluffy"}`)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, replay.Code)
+		assert.Equal(t, `{"id":1}`, replay.Body.String())
+		assert.Equal(t, "/animals/1", replay.Header().Get("Location"))
+		assert.Equal(t, "application/json", replay.Header().Get("Content-Type"))
+		assert.Equal(t, "true", replay.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, []string{"set-by-earlier-middleware"}, replay.Header().Values("X-Request-ID"))
+	})
+
+	t.Run("DifferentBodyConflicts", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		send(handler, "key-1", `{"name":"Fluffy"}`)
+		rr := send(handler, "key-1", `{"name":"Rex"}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Contains(t, rr.Body.String(), "different request body")
+	})
+
+	t.Run("InProgressConflicts", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		calls := 0
+		var handler http.Handler
+		handler = Idempotency(store, time.Hour, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			// A retry arriving while the first request is still running
+			rr := send(handler, "key-1", `{}`)
+			assert.Equal(t, http.StatusConflict, rr.Code)
+			assert.Contains(t, rr.Body.String(), "still being processed")
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		assert.Equal(t, http.StatusCreated, send(handler, "key-1", `{}`).Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("DistinctKeysAndClients", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		send(handler, "key-1", `{}`)
+		send(handler, "key-2", `{}`)
+
+		req := httptest.NewRequest(http.MethodPost, "/animals", strings.NewReader(`{}`))
+		req.RemoteAddr = "198.51.100.7:1234"
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, 3, calls)
+		assert.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("ServerErrorsAreNotStored", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(createHandler(&calls, http.StatusInternalServerError))
+
+		send(handler, "key-1", `{}`)
+		rr := send(handler, "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+	})
+
+	t.Run("PanicReleasesKey", func(t *testing.T) {
+		store := newMemoryIdempotencyStore()
+		handler := Idempotency(store, time.Hour, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		assert.Panics(t, func() { send(handler, "key-1", `{}`) })
+		assert.Empty(t, store.records)
+	})
+
+	t.Run("WithoutKey", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		send(handler, "", `{}`)
+		send(handler, "", `{}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("KeyTooLong", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		rr := send(handler, strings.Repeat("k", 256), `{}`)
+
+		assert.Equal(t, 0, calls)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("BodyIsPassedOn", func(t *testing.T) {
+		var received []byte
+		handler := Idempotency(newMemoryIdempotencyStore(), time.Hour, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			received, err = io.ReadAll(r.Body)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		send(handler, "key-1", `{"name":"Fluffy"}`)
+		assert.Equal(t, `{"name":"Fluffy"}`, string(received))
+	})
+
+	t.Run("DisabledWithoutStore", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(nil, time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		send(handler, "key-1", `{}`)
+		send(handler, "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("StoreUnavailable", func(t *testing.T) {
+		calls := 0
+		handler := Idempotency(failingIdempotencyStore{}, time.Hour, zap.NewNop())(createHandler(&calls, http.StatusCreated))
+
+		rr := send(handler, "key-1", `{}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+	})
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// skipLogPaths lists endpoints polled by infrastructure that would only add noise to request logs
+var skipLogPaths = map[string]bool{
+	"/health":  true,
+	"/metrics": true,
+}
+
+// userIDHolderKey is the context key for the request logger's user ID holder
+type userIDHolderKey struct{}
+
+// withUserIDHolder returns a context through which downstream middleware can report the authenticated user
+func withUserIDHolder(ctx context.Context, holder *interface{}) context.Context {
+	return context.WithValue(ctx, userIDHolderKey{}, holder)
+}
+
+// recordUserID stores the authenticated user ID in the request logger's holder, if any
+func recordUserID(ctx context.Context, userID interface{}) {
+	if holder, ok := ctx.Value(userIDHolderKey{}).(*interface{}); ok {
+		*holder = userID
+	}
+}
+
+// ZapLogger is a middleware that logs each request as a structured zap entry.
+// 5xx responses are logged at error level, 4xx at warn level and everything else at info level.
+// Place it after chimiddleware.RequestID and TraceID so both IDs are available.
+func ZapLogger(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skipLogPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			// The authentication middleware runs further down the chain and stores the user ID
+			// on a derived request, so it reports the ID back through a shared holder
+			var userID interface{}
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				fields := []zap.Field{
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Int("status", status),
+					zap.Duration("duration", time.Since(start)),
+					zap.Int("bytes", ww.BytesWritten()),
+					zap.String("remote_addr", r.RemoteAddr),
+				}
+				if reqID := chimiddleware.GetReqID(r.Context()); reqID != "" {
+					fields = append(fields, zap.String("request_id", reqID))
+				}
+				if traceID := GetTraceID(r.Context()); traceID != "" {
+					fields = append(fields, zap.String("trace_id", traceID))
+				}
+				if userID != nil {
+					fields = append(fields, zap.Any("user_id", userID))
+				}
+
+				switch {
+				case status >= http.StatusInternalServerError:
+					logger.Error("Request completed", fields...)
+				case status >= http.StatusBadRequest:
+					logger.Warn("Request completed", fields...)
+				default:
+					logger.Info("Request completed", fields...)
+				}
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(withUserIDHolder(r.Context(), &userID)))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapLogger(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		status        int
+		expectedLevel zapcore.Level
+		expectLog     bool
+	}{
+		{name: "Success", path: "/api/v1/animals", status: http.StatusOK, expectedLevel: zapcore.InfoLevel, expectLog: true},
+		{name: "Redirect", path: "/api/v1/animals", status: http.StatusFound, expectedLevel: zapcore.InfoLevel, expectLog: true},
+		{name: "ClientError", path: "/api/v1/animals", status: http.StatusNotFound, expectedLevel: zapcore.WarnLevel, expectLog: true},
+		{name: "ServerError", path: "/api/v1/animals", status: http.StatusInternalServerError, expectedLevel: zapcore.ErrorLevel, expectLog: true},
+		{name: "SkipsHealth", path: "/health", status: http.StatusOK, expectLog: false},
+		{name: "SkipsMetrics", path: "/metrics", status: http.StatusOK, expectLog: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			// Simulate the authentication middleware running further down the chain
+			handler := chimiddleware.RequestID(ZapLogger(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), KeyUserID, uint64(7))
+				recordUserID(ctx, uint64(7))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("hello"))
+			})))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if !tt.expectLog {
+				assert.Equal(t, 0, logs.Len())
+				return
+			}
+
+			require.Equal(t, 1, logs.Len())
+			entry := logs.All()[0]
+			fields := entry.ContextMap()
+
+			assert.Equal(t, tt.expectedLevel, entry.Level)
+			assert.Equal(t, http.MethodGet, fields["method"])
+			assert.Equal(t, tt.path, fields["path"])
+			assert.Equal(t, int64(tt.status), fields["status"])
+			assert.Equal(t, int64(5), fields["bytes"])
+			assert.NotEmpty(t, fields["request_id"])
+			assert.Equal(t, uint64(7), fields["user_id"])
+			assert.Contains(t, fields, "duration")
+		})
+	}
+}
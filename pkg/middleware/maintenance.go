@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/linkeunid/go-api/pkg/response"
+	"go.uber.org/zap"
+)
+
+// MaintenanceStore holds whether maintenance mode is enabled
+type MaintenanceStore interface {
+	Enabled(ctx context.Context) (bool, error)
+	SetEnabled(ctx context.Context, enabled bool) error
+}
+
+// MemoryMaintenanceStore keeps the maintenance flag in process, so it only applies to this instance
+type MemoryMaintenanceStore struct {
+	enabled atomic.Bool
+}
+
+// NewMemoryMaintenanceStore creates an in-process maintenance store, initially disabled
+func NewMemoryMaintenanceStore() *MemoryMaintenanceStore {
+	return &MemoryMaintenanceStore{}
+}
+
+// Enabled implements MaintenanceStore
+func (s *MemoryMaintenanceStore) Enabled(ctx context.Context) (bool, error) {
+	return s.enabled.Load(), nil
+}
+
+// SetEnabled implements MaintenanceStore
+func (s *MemoryMaintenanceStore) SetEnabled(ctx context.Context, enabled bool) error {
+	s.enabled.Store(enabled)
+	return nil
+}
+
+// RedisMaintenanceStore keeps the maintenance flag in Redis, so it is shared by all instances of the API
+type RedisMaintenanceStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisMaintenanceStore creates a Redis-backed maintenance store
+func NewRedisMaintenanceStore(client *redis.Client, keyPrefix string) *RedisMaintenanceStore {
+	return &RedisMaintenanceStore{
+		client: client,
+		key:    keyPrefix + "maintenance",
+	}
+}
+
+// Enabled implements MaintenanceStore
+func (s *RedisMaintenanceStore) Enabled(ctx context.Context) (bool, error) {
+	err := s.client.Get(ctx, s.key).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read maintenance mode: %w", err)
+	}
+	return true, nil
+}
+
+// SetEnabled implements MaintenanceStore. The flag has no expiry: it stays set until disabled
+func (s *RedisMaintenanceStore) SetEnabled(ctx context.Context, enabled bool) error {
+	var err error
+	if enabled {
+		err = s.client.Set(ctx, s.key, "1", 0).Err()
+	} else {
+		err = s.client.Del(ctx, s.key).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store maintenance mode: %w", err)
+	}
+	return nil
+}
+
+// MaintenanceMode is a middleware that rejects requests using one of methods with 503 Service
+// Unavailable and a Retry-After header while the store reports maintenance mode enabled; other
+// methods, such as GET, are always served. The store is only consulted for the listed methods.
+// When the store fails, requests are passed through so an outage doesn't block every write
+func MaintenanceMode(store MaintenanceStore, methods []string, retryAfter time.Duration, logger *zap.Logger) func(http.Handler) http.Handler {
+	blocked := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		blocked[strings.ToUpper(strings.TrimSpace(method))] = true
+	}
+
+	// Retry-After is expressed in whole seconds, rounded up
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !blocked[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			enabled, err := store.Enabled(r.Context())
+			if err != nil {
+				logger.Warn("Maintenance store unavailable", zap.Error(err))
+			}
+			if !enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			response.ServiceUnavailable(w, r, "The API is in maintenance mode; changes are disabled until it ends")
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingMaintenanceStore simulates an unavailable maintenance store
+type failingMaintenanceStore struct{}
+
+func (failingMaintenanceStore) Enabled(ctx context.Context) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (failingMaintenanceStore) SetEnabled(ctx context.Context, enabled bool) error {
+	return errors.New("connection refused")
+}
+
+// okHandler answers every request with 200 OK
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestMaintenanceStores(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	stores := map[string]MaintenanceStore{
+		"Memory": NewMemoryMaintenanceStore(),
+		"Redis":  NewRedisMaintenanceStore(client, "test:"),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			enabled, err := store.Enabled(ctx)
+			require.NoError(t, err)
+			assert.False(t, enabled)
+
+			require.NoError(t, store.SetEnabled(ctx, true))
+			enabled, err = store.Enabled(ctx)
+			require.NoError(t, err)
+			assert.True(t, enabled)
+
+			require.NoError(t, store.SetEnabled(ctx, false))
+			enabled, err = store.Enabled(ctx)
+			require.NoError(t, err)
+			assert.False(t, enabled)
+		})
+	}
+
+	// Instances sharing the Redis server see each other's changes
+	other := NewRedisMaintenanceStore(client, "test:")
+	require.NoError(t, stores["Redis"].SetEnabled(context.Background(), true))
+	enabled, err := other.Enabled(context.Background())
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	tests := []struct {
+		name           string
+		methods        []string
+		method         string
+		enabled        bool
+		expectedStatus int
+	}{
+		{name: "WriteWhileEnabled", methods: []string{"POST", "PUT", "PATCH", "DELETE"}, method: http.MethodPost, enabled: true, expectedStatus: http.StatusServiceUnavailable},
+		{name: "DeleteWhileEnabled", methods: []string{"POST", "PUT", "PATCH", "DELETE"}, method: http.MethodDelete, enabled: true, expectedStatus: http.StatusServiceUnavailable},
+		{name: "ReadWhileEnabled", methods: []string{"POST", "PUT", "PATCH", "DELETE"}, method: http.MethodGet, enabled: true, expectedStatus: http.StatusOK},
+		{name: "WriteWhileDisabled", methods: []string{"POST", "PUT", "PATCH", "DELETE"}, method: http.MethodPost, enabled: false, expectedStatus: http.StatusOK},
+		{name: "MethodNotConfigured", methods: []string{"delete"}, method: http.MethodPost, enabled: true, expectedStatus: http.StatusOK},
+		{name: "ConfiguredMethodIsCaseInsensitive", methods: []string{" delete"}, method: http.MethodDelete, enabled: true, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewMemoryMaintenanceStore()
+			require.NoError(t, store.SetEnabled(context.Background(), tc.enabled))
+
+			handler := MaintenanceMode(store, tc.methods, 90*time.Second, zap.NewNop())(okHandler)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tc.method, "/animals", nil))
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "90", rr.Header().Get("Retry-After"))
+				assert.Contains(t, rr.Body.String(), `"error_code":"SERVICE_UNAVAILABLE"`)
+			}
+		})
+	}
+}
+
+func TestMaintenanceMode_StoreUnavailable(t *testing.T) {
+	handler := MaintenanceMode(failingMaintenanceStore{}, []string{"POST"}, time.Minute, zap.NewNop())(okHandler)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/animals", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
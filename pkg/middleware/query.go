@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/linkeunid/go-api/pkg/validator"
+)
+
+// ValidateQuery returns a per-route middleware that binds the query parameters into a copy of
+// target, a struct or pointer to one whose fields name their parameter in a schema tag, and
+// validates it. The copy keeps target's field values as defaults for parameters the request
+// doesn't send. Parameters that don't fit their field's type or fail validation are rejected
+// with 400 before the handler runs; the handler reads the bound copy with ValidatedQuery.
+// Like bodies, a target with a Validate method is validated by it instead of its tags. A
+// target with a BindQuery method is given the whole query once its fields are set, to bind
+// and check parameters that have no field of their own, such as open-ended filters
+//
+//	r.With(middleware.ValidateQuery(ListQuery{Direction: "asc"})).Get("/", handler)
+func ValidateQuery(target interface{}) func(http.Handler) http.Handler {
+	prototype := reflect.ValueOf(target)
+	for prototype.Kind() == reflect.Ptr {
+		prototype = prototype.Elem()
+	}
+	if prototype.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("middleware: ValidateQuery target must be a struct, got %T", target))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := reflect.New(prototype.Type())
+			query.Elem().Set(prototype)
+			if !HandleValidateQuery(w, r, query.Interface()) {
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), KeyValidatedQuery, query.Interface())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HandleValidateQuery binds and validates the query parameters into target, a pointer to a
+// struct, the way ValidateQuery does. It sends the 400 response and returns false if they're
+// invalid, for handlers that may be mounted without the middleware
+func HandleValidateQuery(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("middleware: HandleValidateQuery target must be a pointer to a struct, got %T", target))
+	}
+
+	validationErrors := bindQuery(r.URL.Query(), value.Elem())
+	if binder, ok := target.(interface {
+		BindQuery(url.Values) []validator.ValidationError
+	}); ok && len(validationErrors) == 0 {
+		validationErrors = binder.BindQuery(r.URL.Query())
+	}
+	if len(validationErrors) == 0 {
+		validationErrors = validateQuery(target)
+	}
+	if len(validationErrors) > 0 {
+		handleValidationError(w, r, validationErrors)
+		return false
+	}
+	return true
+}
+
+// ValidatedQuery returns the query parameters bound by ValidateQuery for this request.
+// The second result is false if the route was not wrapped with ValidateQuery for a T
+func ValidatedQuery[T any](r *http.Request) (*T, bool) {
+	query, ok := r.Context().Value(KeyValidatedQuery).(*T)
+	return query, ok && query != nil
+}
+
+// validateQuery validates bound query parameters with their Validate method or their tags
+func validateQuery(query interface{}) []validator.ValidationError {
+	if v, ok := query.(interface {
+		Validate() []validator.ValidationError
+	}); ok {
+		return v.Validate()
+	}
+	return validator.Validate(query)
+}
+
+// bindQuery sets the fields of the struct value that have a schema tag from the query parameter
+// it names, reporting parameters that can't be converted to their field's type. Slice fields
+// take every value of their parameter, split at commas; other fields take the first value.
+// Empty parameters, e.g. ?page=, are treated as absent
+func bindQuery(query url.Values, value reflect.Value) []validator.ValidationError {
+	var validationErrors []validator.ValidationError
+	for _, field := range reflect.VisibleFields(value.Type()) {
+		name := strings.SplitN(field.Tag.Get("schema"), ",", 2)[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		values := query[name]
+		if strings.TrimSpace(strings.Join(values, "")) == "" {
+			continue
+		}
+
+		target := value.FieldByIndex(field.Index)
+		if target.Kind() == reflect.Slice {
+			var parts []string
+			for _, v := range values {
+				for _, part := range strings.Split(v, ",") {
+					if part = strings.TrimSpace(part); part != "" {
+						parts = append(parts, part)
+					}
+				}
+			}
+			list := reflect.MakeSlice(target.Type(), len(parts), len(parts))
+			for i, part := range parts {
+				if err := setQueryValue(list.Index(i), part); err != nil {
+					validationErrors = append(validationErrors, queryTypeError(name, part, list.Index(i).Type()))
+				}
+			}
+			target.Set(list)
+			continue
+		}
+
+		if err := setQueryValue(target, values[0]); err != nil {
+			validationErrors = append(validationErrors, queryTypeError(name, values[0], target.Type()))
+		}
+	}
+	return validationErrors
+}
+
+// setQueryValue parses raw into a string, boolean or numeric value, allocating pointers as needed
+func setQueryValue(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Ptr {
+		elem := reflect.New(value.Type().Elem())
+		if err := setQueryValue(elem.Elem(), raw); err != nil {
+			return err
+		}
+		value.Set(elem)
+		return nil
+	}
+
+	raw = strings.TrimSpace(raw)
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported query parameter type %s", value.Type())
+	}
+	return nil
+}
+
+// queryTypeError reports a query parameter whose value doesn't fit its field's type
+func queryTypeError(name, raw string, fieldType reflect.Type) validator.ValidationError {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return validator.ValidationError{
+		Field: name,
+		Tag:   "type",
+		Value: raw,
+		Error: fmt.Sprintf("%s must be %s", name, describeType(fieldType)),
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/linkeunid/go-api/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testQuery exercises the binding of every supported field type
+type testQuery struct {
+	Page      *int     `json:"page" schema:"page" validate:"omitempty,min=1"`
+	Direction string   `json:"direction" schema:"direction" validate:"omitempty,oneof=asc desc"`
+	Ratio     float64  `json:"ratio" schema:"ratio"`
+	Active    *bool    `json:"active" schema:"active"`
+	IDs       []uint   `json:"ids" schema:"ids"`
+	Ignored   string   `json:"ignored"`
+	Tags      []string `json:"tags" schema:"tag"`
+}
+
+// sortQuery validates itself instead of through its tags
+type sortQuery struct {
+	Sort string `json:"sort" schema:"sort" validate:"required"`
+}
+
+func (q *sortQuery) Validate() []validator.ValidationError {
+	if q.Sort == "id" || q.Sort == "name" {
+		return nil
+	}
+	return []validator.ValidationError{{Field: "sort", Tag: "oneof", Value: q.Sort, Error: "sort must be one of id, name"}}
+}
+
+// filterQuery binds the parameters without a field as filters
+type filterQuery struct {
+	Sort    string `json:"sort" schema:"sort"`
+	Filters map[string]string
+}
+
+func (q *filterQuery) BindQuery(query url.Values) []validator.ValidationError {
+	q.Filters = make(map[string]string)
+	for key := range query {
+		if key == "sort" {
+			continue
+		}
+		if _, err := strconv.Atoi(query.Get(key)); err != nil {
+			return []validator.ValidationError{{Field: key, Tag: "numeric", Value: query.Get(key), Error: key + " must be an integer"}}
+		}
+		q.Filters[key] = query.Get(key)
+	}
+	return nil
+}
+
+func TestValidateQuery(t *testing.T) {
+	active, page := true, 2
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedQuery  testQuery
+		expectedField  string
+		expectedError  string
+	}{
+		{name: "Empty", query: "", expectedStatus: http.StatusOK, expectedQuery: testQuery{Direction: "asc"}},
+		{
+			name:           "AllTypes",
+			query:          "page=2&direction=desc&ratio=0.5&active=true&ids=1,2&ids=3&ignored=x&tag=a&tag=b,c&species=Cat",
+			expectedStatus: http.StatusOK,
+			expectedQuery:  testQuery{Page: &page, Direction: "desc", Ratio: 0.5, Active: &active, IDs: []uint{1, 2, 3}, Tags: []string{"a", "b", "c"}},
+		},
+		{name: "EmptyValueIsAbsent", query: "page=&direction=", expectedStatus: http.StatusOK, expectedQuery: testQuery{Direction: "asc"}},
+		{name: "NotAnInteger", query: "page=abc", expectedStatus: http.StatusBadRequest, expectedField: "page", expectedError: "page must be an integer"},
+		{name: "NegativeUint", query: "ids=1,-2", expectedStatus: http.StatusBadRequest, expectedField: "ids", expectedError: "ids must be a non-negative integer"},
+		{name: "NotABoolean", query: "active=maybe", expectedStatus: http.StatusBadRequest, expectedField: "active", expectedError: "active must be a boolean"},
+		{name: "BelowMinimum", query: "page=0", expectedStatus: http.StatusBadRequest, expectedField: "page", expectedError: "page must be 1 or greater"},
+		{name: "NotOneOf", query: "direction=up", expectedStatus: http.StatusBadRequest, expectedField: "direction", expectedError: "direction must be one of [asc desc]"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var bound *testQuery
+			handler := ValidateQuery(testQuery{Direction: "asc"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var ok bool
+				bound, ok = ValidatedQuery[testQuery](r)
+				require.True(t, ok)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil))
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.NotNil(t, bound)
+				assert.Equal(t, tc.expectedQuery, *bound)
+				return
+			}
+
+			assert.Nil(t, bound, "the handler must not run")
+			var resp struct {
+				Data []validator.ValidationError `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Len(t, resp.Data, 1)
+			assert.Equal(t, tc.expectedField, resp.Data[0].Field)
+			assert.Equal(t, tc.expectedError, resp.Data[0].Error)
+		})
+	}
+}
+
+func TestValidateQuery_ValidateMethod(t *testing.T) {
+	handler := ValidateQuery(&sortQuery{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, ok := ValidatedQuery[sortQuery](r)
+		require.True(t, ok)
+		_, _ = w.Write([]byte(query.Sort))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/?sort=name", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "name", rr.Body.String())
+
+	// The Validate method replaces the tags, so a missing sort isn't required
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/?sort=color", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "sort must be one of id, name")
+}
+
+func TestValidateQuery_BindQueryMethod(t *testing.T) {
+	handler := ValidateQuery(filterQuery{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, ok := ValidatedQuery[filterQuery](r)
+		require.True(t, ok)
+		assert.Equal(t, "age", query.Sort)
+		assert.Equal(t, map[string]string{"age_gte": "2"}, query.Filters)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/?sort=age&age_gte=2", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/?sort=age&age_gte=abc", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "age_gte must be an integer")
+}
+
+func TestValidateQuery_PanicsOnNonStruct(t *testing.T) {
+	assert.Panics(t, func() { ValidateQuery(42) })
+}
+
+func TestValidatedQuery_NotValidated(t *testing.T) {
+	_, ok := ValidatedQuery[testQuery](httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, ok)
+}
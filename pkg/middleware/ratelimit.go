@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/linkeunid/go-api/pkg/response"
+	"go.uber.org/zap"
+)
+
+// RateLimiter decides whether a client identified by key may make another request
+type RateLimiter interface {
+	// Allow consumes one token for key and reports whether the request is allowed.
+	// When it is not, retryAfter is how long the client should wait before retrying.
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// tokenBucket holds the state of a single client's bucket
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// MemoryRateLimiter is an in-process token bucket rate limiter
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	rps       float64
+	burst     int
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimiter creates a token bucket limiter refilling at rps tokens per second up to burst
+func NewMemoryRateLimiter(rps float64, burst int) *MemoryRateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &MemoryRateLimiter{
+		buckets:   make(map[string]*tokenBucket),
+		rps:       rps,
+		burst:     burst,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow implements RateLimiter
+func (l *MemoryRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.burst), lastSeen: now}
+		l.buckets[key] = bucket
+	}
+
+	// Refill tokens for the time elapsed since the last request
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(float64(l.burst), bucket.tokens+elapsed*l.rps)
+	bucket.lastSeen = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / l.rps * float64(time.Second))
+	return false, wait, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again; callers must hold mu
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.burst) / l.rps * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// redisTokenBucket atomically refills and consumes a token from a bucket stored as a hash.
+// It returns {allowed, retryAfterMillis}.
+var redisTokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, retry}
+`)
+
+// RedisRateLimiter is a token bucket rate limiter whose state is shared through Redis,
+// so limits apply across all instances of the API
+type RedisRateLimiter struct {
+	client    *redis.Client
+	keyPrefix string
+	rps       float64
+	burst     int
+}
+
+// NewRedisRateLimiter creates a Redis-backed token bucket limiter
+func NewRedisRateLimiter(client *redis.Client, keyPrefix string, rps float64, burst int) *RedisRateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RedisRateLimiter{
+		client:    client,
+		keyPrefix: keyPrefix,
+		rps:       rps,
+		burst:     burst,
+	}
+}
+
+// Allow implements RateLimiter
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	result, err := redisTokenBucket.Run(ctx, l.client,
+		[]string{l.keyPrefix + "ratelimit:" + key},
+		strconv.FormatFloat(l.rps, 'f', -1, 64), l.burst, now,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to evaluate rate limit: %w", err)
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// RateLimit is a middleware that rejects clients exceeding the limiter's rate with 429 Too Many Requests.
+// Clients are identified by the authenticated user ID when present, otherwise by their IP address;
+// place it after chimiddleware.RealIP so proxied requests are keyed correctly.
+// If the limiter fails, fallback (when non-nil) is consulted instead so requests are still limited.
+func RateLimit(limiter RateLimiter, fallback RateLimiter, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := rateLimitKey(r)
+
+			allowed, retryAfter, err := limiter.Allow(r.Context(), key)
+			if err != nil {
+				logger.Warn("Rate limiter unavailable", zap.String("key", key), zap.Error(err))
+				if fallback == nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+				allowed, retryAfter, _ = fallback.Allow(r.Context(), key)
+			}
+
+			if !allowed {
+				// Retry-After is expressed in whole seconds, rounded up
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				response.TooManyRequests(w, r, "Rate limit exceeded, retry later")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the client making the request
+func rateLimitKey(r *http.Request) string {
+	if userID := r.Context().Value(KeyUserID); userID != nil {
+		return fmt.Sprintf("user:%v", userID)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// failingLimiter simulates an unavailable limiter backend
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func TestMemoryRateLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	limiter := NewMemoryRateLimiter(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	// The burst is available immediately
+	for i := 0; i < 2; i++ {
+		allowed, _, err := limiter.Allow(ctx, "client")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	// The next request must wait for a token to refill
+	allowed, retryAfter, err := limiter.Allow(ctx, "client")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// Other clients have their own bucket
+	allowed, _, _ = limiter.Allow(ctx, "other")
+	assert.True(t, allowed)
+
+	// One token is refilled after a second
+	now = now.Add(time.Second)
+	allowed, _, _ = limiter.Allow(ctx, "client")
+	assert.True(t, allowed)
+}
+
+func TestRateLimit_Middleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("RejectsWithRetryAfter", func(t *testing.T) {
+		handler := RateLimit(NewMemoryRateLimiter(0.5, 1), nil, zap.NewNop())(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+
+		// A different IP is not affected
+		req.RemoteAddr = "10.0.0.2:1234"
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("KeysByUserID", func(t *testing.T) {
+		handler := RateLimit(NewMemoryRateLimiter(1, 1), nil, zap.NewNop())(next)
+
+		for _, addr := range []string{"10.0.0.1:1", "10.0.0.2:2"} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = addr
+			req = req.WithContext(context.WithValue(req.Context(), KeyUserID, 7))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if addr == "10.0.0.1:1" {
+				assert.Equal(t, http.StatusOK, rr.Code)
+			} else {
+				assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+			}
+		}
+	})
+
+	t.Run("FallsBackWhenLimiterFails", func(t *testing.T) {
+		handler := RateLimit(failingLimiter{}, NewMemoryRateLimiter(1, 1), zap.NewNop())(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Recoverer is a middleware that recovers from panics in later handlers, logs them at error level
+// with the stack trace and request ID, and responds with a JSON 500 in the usual response format.
+// The panic value isn't sent to the client. http.ErrAbortHandler is re-raised so the server aborts
+// the response as intended, and upgraded connections get no response since they aren't HTTP anymore
+func Recoverer(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+
+				fields := []zap.Field{
+					zap.String("panic", fmt.Sprint(rec)),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.ByteString("stack", debug.Stack()),
+				}
+				if reqID := chimiddleware.GetReqID(r.Context()); reqID != "" {
+					fields = append(fields, zap.String("request_id", reqID))
+				}
+				if traceID := GetTraceID(r.Context()); traceID != "" {
+					fields = append(fields, zap.String("trace_id", traceID))
+				}
+				logger.Error("Recovered from panic", fields...)
+
+				if r.Header.Get("Connection") != "Upgrade" {
+					response.InternalServerError(w, r, nil)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
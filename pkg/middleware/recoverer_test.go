@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoverer(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	handler := chimiddleware.RequestID(Recoverer(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map write")
+	})))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/animals", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, response.MediaTypeJSON, rr.Header().Get("Content-Type"))
+
+	var resp response.APIResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.False(t, resp.Success)
+	assert.Equal(t, response.CodeInternalError, resp.ErrorCode)
+	assert.NotEmpty(t, resp.RequestID)
+	assert.NotContains(t, rr.Body.String(), "nil map write", "the panic value must not reach the client")
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.ErrorLevel, entry.Level)
+	fields := entry.ContextMap()
+	assert.Equal(t, "nil map write", fields["panic"])
+	assert.Equal(t, resp.RequestID, fields["request_id"])
+	assert.Contains(t, fields["stack"], "recoverer.go")
+}
+
+func TestRecoverer_NoPanic(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	handler := Recoverer(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestRecoverer_ReraisesAbortHandler(t *testing.T) {
+	handler := Recoverer(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader is the response header carrying the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDResponseHeader is a middleware that returns the request ID to the client in the
+// X-Request-ID header. Place it after chimiddleware.RequestID, which generates the ID or
+// reuses the one sent by the client.
+func RequestIDResponseHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reqID := chimiddleware.GetReqID(r.Context()); reqID != "" {
+			w.Header().Set(RequestIDHeader, reqID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
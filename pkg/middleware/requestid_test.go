@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDResponseHeader(t *testing.T) {
+	handler := chimiddleware.RequestID(RequestIDResponseHeader(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.NotFound(w, r, "Animal not found")
+	})))
+
+	t.Run("ReturnsClientSuppliedID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "support-123")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, "support-123", rr.Header().Get(RequestIDHeader))
+
+		// Error payloads carry the same ID
+		var body response.APIResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "support-123", body.RequestID)
+	})
+
+	t.Run("ReturnsGeneratedID", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.NotEmpty(t, rr.Header().Get(RequestIDHeader))
+	})
+}
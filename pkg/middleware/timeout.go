@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/linkeunid/go-api/pkg/response"
+)
+
+// timeoutBaseKey is the context key for the request context as it was before any timeout was applied
+type timeoutBaseKey struct{}
+
+// WithTimeout is a middleware that bounds request handling to d.
+// It can be applied globally and again on any chi sub-router: an inner WithTimeout
+// replaces the outer deadline instead of being capped by it, so a route group can be
+// given a longer or shorter limit than the default. Client disconnects still cancel
+// the request. If the deadline passes before the handler writes a response, a
+// 504 Gateway Timeout is sent.
+func WithTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Deadlines are derived from the request context before any earlier WithTimeout
+			base, ok := r.Context().Value(timeoutBaseKey{}).(context.Context)
+			if !ok {
+				base = r.Context()
+			}
+
+			// Keep the values of the current context but drop the outer deadline
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), d)
+			defer cancel()
+
+			// Propagate client disconnects from the original request
+			stop := context.AfterFunc(base, cancel)
+			defer stop()
+
+			ctx = context.WithValue(ctx, timeoutBaseKey{}, base)
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			// Report the timeout if the handler gave up without responding
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && ww.Status() == 0 {
+				response.GatewayTimeout(w, r, "Request timed out")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("RespondsWithGatewayTimeout", func(t *testing.T) {
+		handler := WithTimeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	})
+
+	t.Run("KeepsHandlerResponse", func(t *testing.T) {
+		handler := WithTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+	})
+
+	t.Run("SubRouterOverridesOuterTimeout", func(t *testing.T) {
+		r := chi.NewRouter()
+		r.Use(WithTimeout(10 * time.Millisecond))
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), KeyUserID, 42)))
+			})
+		})
+		r.Route("/slow", func(r chi.Router) {
+			r.Use(WithTimeout(time.Second))
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				// Outlive the outer deadline; the inner one still has time left
+				time.Sleep(30 * time.Millisecond)
+				assert.NoError(t, r.Context().Err())
+				assert.Equal(t, 42, r.Context().Value(KeyUserID))
+				w.WriteHeader(http.StatusOK)
+			})
+		})
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/slow/", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("PropagatesClientCancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var handlerErr error
+		handler := WithTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cancel()
+			<-r.Context().Done()
+			handlerErr = r.Context().Err()
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+		assert.ErrorIs(t, handlerErr, context.Canceled)
+	})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/linkeunid/go-api/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// TraceIDHeader is the header carrying the trace ID in requests, responses and downstream calls
+const TraceIDHeader = "X-Trace-Id"
+
+// traceIDKey is the context key for the trace ID
+type traceIDKey struct{}
+
+// validTraceID limits client-supplied trace IDs to short tokens that are safe to log
+var validTraceID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// TraceID is a middleware that attaches a trace ID to the request, reusing the client's
+// X-Trace-Id header when it is well formed and generating one otherwise. It stores a child of
+// logger tagged with trace_id in the context for logging.FromContext, and echoes the ID in
+// the response so clients and downstream services can correlate their logs.
+func TraceID(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceID := r.Header.Get(TraceIDHeader)
+			if !validTraceID.MatchString(traceID) {
+				traceID = newTraceID()
+			}
+			w.Header().Set(TraceIDHeader, traceID)
+
+			ctx := context.WithValue(r.Context(), traceIDKey{}, traceID)
+			ctx = logging.WithLogger(ctx, logger.With(zap.String("trace_id", traceID)))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetTraceID returns the trace ID stored in ctx by TraceID, or an empty string.
+// Set it as the X-Trace-Id header on outgoing requests to propagate the trace
+func GetTraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// newTraceID returns a random 128-bit trace ID in hex
+func newTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkeunid/go-api/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTraceID(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		expectReused bool
+	}{
+		{name: "ReusesClientID", header: "4bf92f3577b34da6a3ce929d0e0e4736", expectReused: true},
+		{name: "GeneratesWhenMissing", header: "", expectReused: false},
+		{name: "ReplacesMalformedID", header: "bad id\nwith newline", expectReused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			var traceID string
+			handler := TraceID(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceID = GetTraceID(r.Context())
+				logging.FromContext(r.Context()).Info("handling request")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(TraceIDHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if tt.expectReused {
+				assert.Equal(t, tt.header, traceID)
+			} else {
+				assert.Len(t, traceID, 32)
+				assert.NotEqual(t, tt.header, traceID)
+			}
+			assert.Equal(t, traceID, rr.Header().Get(TraceIDHeader))
+
+			// Every line logged through the request-scoped logger carries the trace ID
+			require.Equal(t, 1, logs.Len())
+			assert.Equal(t, traceID, logs.All()[0].ContextMap()["trace_id"])
+		})
+	}
+}
+
+func TestTraceID_ZapLoggerIncludesTraceID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	handler := TraceID(logger)(ZapLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/animals", nil)
+	req.Header.Set(TraceIDHeader, "trace-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "trace-123", logs.All()[0].ContextMap()["trace_id"])
+}
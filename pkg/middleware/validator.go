@@ -1,62 +1,492 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	"github.com/linkeunid/go-api/pkg/response"
 	"github.com/linkeunid/go-api/pkg/validator"
 )
 
-// ValidationMiddleware is a middleware that validates the request body against a model
-func ValidationMiddleware(next http.Handler) http.Handler {
+// ValidationMiddleware is a per-route middleware that decodes the JSON request body into a new T,
+// validates it and stores a *T under KeyValidatedModel for the handler. Requests with a
+// non-JSON content type, a malformed body or invalid fields are rejected before the handler runs.
+// JSON arrays are rejected; use ArrayValidationMiddleware on routes that accept them.
+//
+//	r.With(middleware.ValidationMiddleware[model.Animal]).Post("/", handler)
+func ValidationMiddleware[T any](next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Only validate on POST, PUT, PATCH
-		if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
-			// Check if content type is application/json
-			contentType := r.Header.Get("Content-Type")
-			if contentType != "application/json" {
-				handleValidationError(w, r, []validator.ValidationError{
-					{
-						Field: "Content-Type",
-						Tag:   "required",
-						Error: "Content-Type must be application/json",
-					},
-				})
-				return
-			}
+		if !requireJSON(w, r) {
+			return
+		}
+
+		model := new(T)
+		if !HandleValidateRequest(w, r, model) {
+			return
+		}
 
-			// Store the validation data in the request context
-			r = r.WithContext(r.Context())
+		// Store the validated model in the request context
+		ctx := context.WithValue(r.Context(), KeyValidatedModel, model)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ArrayValidationMiddleware is like ValidationMiddleware but also accepts a JSON array of T.
+// An array is decoded into a []T stored under KeyValidatedModels, with every element validated;
+// errors name the element they belong to, e.g. "[2].name". Any other body is handled as by
+// ValidationMiddleware. The request is rejected if any element is invalid or the array is empty.
+//
+//	r.With(middleware.ArrayValidationMiddleware[model.Animal]).Post("/batch", handler)
+func ArrayValidationMiddleware[T any](next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requireJSON(w, r) {
+			return
+		}
 
-			// Call the next handler, which will validate the model specifically
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			validationErrors, err := decodeErrors(err, new(T))
+			respondInvalid(w, r, validationErrors, err)
+			return
 		}
-		next.ServeHTTP(w, r)
+
+		var ctx context.Context
+		if isJSONArray(data) {
+			models, validationErrors, err := validateJSONArray[T](data)
+			if err != nil {
+				respondInvalid(w, r, validationErrors, err)
+				return
+			}
+			ctx = context.WithValue(r.Context(), KeyValidatedModels, models)
+		} else {
+			model := new(T)
+			if validationErrors, err := validateJSON(data, model); err != nil {
+				respondInvalid(w, r, validationErrors, err)
+				return
+			}
+			ctx = context.WithValue(r.Context(), KeyValidatedModel, model)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// ValidateModel validates a model from the request body
-func ValidateModel(model interface{}, r *http.Request) []validator.ValidationError {
-	// Decode the request body
-	if err := json.NewDecoder(r.Body).Decode(model); err != nil {
-		return []validator.ValidationError{
+// requireJSON rejects requests whose content type isn't application/json, reporting whether
+// the request may proceed
+func requireJSON(w http.ResponseWriter, r *http.Request) bool {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		handleValidationError(w, r, []validator.ValidationError{
 			{
-				Field: "body",
-				Tag:   "json",
-				Error: "Invalid JSON format: " + err.Error(),
+				Field: "Content-Type",
+				Tag:   "required",
+				Error: "Content-Type must be application/json",
 			},
-		}
+		})
+		return false
+	}
+	return true
+}
+
+// Categories of request validation failures returned by ValidateModel
+var (
+	// ErrMalformedBody indicates the body could not be decoded as JSON (400)
+	ErrMalformedBody = errors.New("malformed request body")
+	// ErrBodyTooLarge indicates the body exceeded the MaxBodyBytes limit (413)
+	ErrBodyTooLarge = errors.New("request body too large")
+	// ErrValidationFailed indicates the body decoded but its fields are invalid (422)
+	ErrValidationFailed = errors.New("validation failed")
+)
+
+// strictTypes disables the conversion of numbers and booleans sent as JSON strings
+var strictTypes atomic.Bool
+
+// disallowUnknownFields rejects bodies with fields the model doesn't have
+var disallowUnknownFields atomic.Bool
+
+// SetStrictTypes configures whether ValidateModel rejects numbers and booleans sent as JSON strings,
+// e.g. "age": "3", instead of converting them to the field's type
+func SetStrictTypes(strict bool) {
+	strictTypes.Store(strict)
+}
+
+// SetDisallowUnknownFields configures whether ValidateModel rejects bodies with fields the model
+// doesn't have, such as a misspelled "nam", instead of ignoring them
+func SetDisallowUnknownFields(disallow bool) {
+	disallowUnknownFields.Store(disallow)
+}
+
+// ValidateModel decodes the request body into model and validates it.
+// The returned error is one of ErrMalformedBody, ErrBodyTooLarge or ErrValidationFailed
+// and the validation errors describe the failure.
+func ValidateModel(model interface{}, r *http.Request) ([]validator.ValidationError, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return decodeErrors(err, model)
+	}
+	return validateJSON(data, model)
+}
+
+// validateJSON decodes data into model and validates it, failing like ValidateModel
+func validateJSON(data []byte, model interface{}) ([]validator.ValidationError, error) {
+	if err := decodeJSON(data, model); err != nil {
+		return decodeErrors(err, model)
 	}
 
 	// If the model has a custom Validate method, use it
+	var validationErrors []validator.ValidationError
 	if v, ok := model.(interface {
 		Validate() []validator.ValidationError
 	}); ok {
-		return v.Validate()
+		validationErrors = v.Validate()
+	} else {
+		// Otherwise use the standard validator
+		validationErrors = validator.Validate(model)
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors, ErrValidationFailed
+	}
+	return nil, nil
+}
+
+// validateJSONArray decodes a JSON array into a []T and validates every element. The errors of
+// each element are prefixed with its index; if any element is malformed the whole body is
+// reported as ErrMalformedBody, otherwise invalid elements fail with ErrValidationFailed
+func validateJSONArray[T any](data []byte) ([]T, []validator.ValidationError, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		validationErrors, err := decodeErrors(err, new(T))
+		return nil, validationErrors, err
+	}
+	if len(elements) == 0 {
+		return nil, []validator.ValidationError{
+			{
+				Field: "body",
+				Tag:   "min",
+				Error: "Request body must contain at least one item",
+			},
+		}, ErrValidationFailed
+	}
+
+	models := make([]T, len(elements))
+	var allErrors []validator.ValidationError
+	var failure error
+	for i, element := range elements {
+		validationErrors, err := validateJSON(element, &models[i])
+		if err == nil {
+			continue
+		}
+		if !errors.Is(failure, ErrMalformedBody) {
+			failure = err
+		}
+		for _, validationError := range validationErrors {
+			validationError.Field = indexedField(i, validationError.Field)
+			allErrors = append(allErrors, validationError)
+		}
+	}
+	if failure != nil {
+		return nil, allErrors, failure
+	}
+	return models, nil, nil
+}
+
+// indexedField names field of the array element at index i, e.g. "[2].name";
+// errors about the element as a whole are named "[2]"
+func indexedField(i int, field string) string {
+	if field == "body" {
+		return fmt.Sprintf("[%d]", i)
+	}
+	return fmt.Sprintf("[%d].%s", i, field)
+}
+
+// isJSONArray reports whether data holds a JSON array, judging by its first non-space character
+func isJSONArray(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// decodeErrors describes an error reading or decoding a body for model as validation errors.
+// The returned error is ErrBodyTooLarge or ErrMalformedBody
+func decodeErrors(err error, model interface{}) ([]validator.ValidationError, error) {
+	// Report bodies rejected by MaxBodyBytes separately from malformed JSON
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return []validator.ValidationError{
+			{
+				Field: "body",
+				Tag:   "max_bytes",
+				Error: fmt.Sprintf("Request body too large: limit is %d bytes", maxBytesErr.Limit),
+			},
+		}, ErrBodyTooLarge
 	}
 
-	// Otherwise use the standard validator
-	return validator.Validate(model)
+	// Name the field the model doesn't have, suggesting the one that was probably meant
+	if field, ok := unknownField(err); ok {
+		message := field + " is not a known field"
+		if suggestion := closestJSONField(model, field); suggestion != "" {
+			message += fmt.Sprintf("; did you mean %s?", suggestion)
+		}
+		return []validator.ValidationError{
+			{
+				Field: field,
+				Tag:   "unknown",
+				Error: message,
+			},
+		}, ErrMalformedBody
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		// Name the field when a value has the wrong type
+		if typeErr.Field != "" {
+			return []validator.ValidationError{
+				{
+					Field: typeErr.Field,
+					Tag:   "type",
+					Value: typeErr.Value,
+					Error: fmt.Sprintf("%s must be %s", typeErr.Field, describeType(typeErr.Type)),
+				},
+			}, ErrMalformedBody
+		}
+		// The body as a whole has the wrong type, e.g. an array sent to a single-object route
+		return []validator.ValidationError{
+			{
+				Field: "body",
+				Tag:   "type",
+				Value: typeErr.Value,
+				Error: "Request body must be " + describeType(typeErr.Type),
+			},
+		}, ErrMalformedBody
+	}
+
+	return []validator.ValidationError{
+		{
+			Field: "body",
+			Tag:   "json",
+			Error: "Invalid JSON format: " + err.Error(),
+		},
+	}, ErrMalformedBody
+}
+
+// decodeJSON decodes a JSON body into model. Unless strict types are enabled, numbers and booleans
+// sent as strings to numeric or boolean fields of a struct model are converted and decoded again
+func decodeJSON(data []byte, model interface{}) error {
+	_, err := decodeCoercedJSON(data, model)
+	return err
+}
+
+// decodeCoercedJSON decodes a JSON body into model like decodeJSON and returns the body that was
+// decoded, with any converted values
+func decodeCoercedJSON(data []byte, model interface{}) ([]byte, error) {
+	err := newDecoder(data).Decode(model)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || strictTypes.Load() {
+		return data, err
+	}
+
+	coerced, ok := coerceStringValues(data, model)
+	if !ok {
+		return data, err
+	}
+	return coerced, newDecoder(coerced).Decode(model)
+}
+
+// newDecoder returns a JSON decoder for data that rejects unknown fields if configured to
+func newDecoder(data []byte) *json.Decoder {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if disallowUnknownFields.Load() {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder
+}
+
+// unknownFieldPrefix starts the error encoding/json returns for a field the model doesn't have
+const unknownFieldPrefix = "json: unknown field "
+
+// unknownField returns the field named by an unknown field decoding error
+func unknownField(err error) (string, bool) {
+	quoted, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix)
+	if !ok {
+		return "", false
+	}
+	field, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", false
+	}
+	return field, true
+}
+
+// closestJSONField returns the JSON name of model's field that is at most two edits away from
+// name, and fewer edits than name has characters, or "" if there is none
+func closestJSONField(model interface{}, name string) string {
+	modelType := reflect.TypeOf(model)
+	for modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return ""
+	}
+
+	closest, bestDistance := "", 3
+	for _, field := range reflect.VisibleFields(modelType) {
+		jsonName, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+		distance := editDistance(strings.ToLower(name), strings.ToLower(jsonName))
+		if distance < bestDistance && distance < len(name) {
+			closest, bestDistance = jsonName, distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// coerceStringValues rewrites the top-level string values of a JSON object that hold a number or
+// boolean for a numeric or boolean field of model, e.g. {"age": "3"} becomes {"age": 3}.
+// It reports false if there was nothing to convert
+func coerceStringValues(data []byte, model interface{}) ([]byte, bool) {
+	modelType := reflect.TypeOf(model)
+	for modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, false
+	}
+
+	changed := false
+	for key, raw := range object {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue
+		}
+		fieldType, ok := jsonFieldType(modelType, key)
+		if !ok {
+			continue
+		}
+		if literal, ok := coerceString(strings.TrimSpace(value), fieldType); ok {
+			object[key] = json.RawMessage(literal)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil, false
+	}
+
+	coerced, err := json.Marshal(object)
+	if err != nil {
+		return nil, false
+	}
+	return coerced, true
+}
+
+// jsonFieldType returns the type of the struct field a JSON object key decodes into,
+// matching names case-insensitively like encoding/json
+func jsonFieldType(structType reflect.Type, key string) (reflect.Type, bool) {
+	for _, field := range reflect.VisibleFields(structType) {
+		if name, ok := jsonFieldName(field); ok && strings.EqualFold(name, key) {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
+
+// jsonFieldName returns the name a struct field is decoded from, or false if encoding/json skips it
+// or, for an untagged embedded struct, decodes its fields in its place
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "") {
+		return "", false
+	}
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// coerceString returns the JSON literal for a string holding a value of a numeric or boolean type
+func coerceString(value string, fieldType reflect.Type) (string, bool) {
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		// Only plain JSON numbers are accepted, not forms such as "0x10" or "Inf"
+		if _, err := strconv.ParseFloat(value, 64); err != nil || !json.Valid([]byte(value)) {
+			return "", false
+		}
+		return value, true
+	case reflect.Bool:
+		if value == "true" || value == "false" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// describeType names a Go type the way a client sees it in JSON, e.g. "an integer"
+func describeType(t reflect.Type) string {
+	if t == nil {
+		return "a different type"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a " + t.String()
+	}
 }
 
 // handleValidationError responds with validation errors
@@ -64,12 +494,61 @@ func handleValidationError(w http.ResponseWriter, r *http.Request, errors []vali
 	response.ValidationError(w, r, errors)
 }
 
+// ValidatedModel returns the model stored by ValidationMiddleware[T] for this request.
+// The second result is false if the route was not wrapped with ValidationMiddleware for T.
+func ValidatedModel[T any](r *http.Request) (*T, bool) {
+	model, ok := r.Context().Value(KeyValidatedModel).(*T)
+	return model, ok && model != nil
+}
+
+// ValidatedModels returns the models stored by ArrayValidationMiddleware[T] when the body was
+// a JSON array. The second result is false if it wasn't; ValidatedModel then returns the object
+func ValidatedModels[T any](r *http.Request) ([]T, bool) {
+	models, ok := r.Context().Value(KeyValidatedModels).([]T)
+	return models, ok
+}
+
 // HandleValidateRequest validates a model and returns appropriate response
 func HandleValidateRequest(w http.ResponseWriter, r *http.Request, model interface{}) bool {
-	errors := ValidateModel(model, r)
-	if len(errors) > 0 {
-		handleValidationError(w, r, errors)
-		return false
+	validationErrors, err := ValidateModel(model, r)
+	if err == nil {
+		return true
+	}
+	respondInvalid(w, r, validationErrors, err)
+	return false
+}
+
+// HandleDecodeFields decodes a JSON object body into the fields of a partial update of model,
+// keyed by their JSON names, so omitted fields can be told apart from zero values. The body is
+// decoded into model first, so it fails like a body checked by ValidateModel: 413 when it's too
+// large and 400 when it's malformed, has a field model doesn't have or a value of the wrong
+// type. The fields hold the values as model received them, e.g. 3 for {"age": "3"} unless
+// strict types are enabled. It sends the response and returns false on failure
+func HandleDecodeFields(w http.ResponseWriter, r *http.Request, model interface{}) (map[string]interface{}, bool) {
+	var fields map[string]interface{}
+	data, err := io.ReadAll(r.Body)
+	if err == nil {
+		data, err = decodeCoercedJSON(data, model)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		validationErrors, err := decodeErrors(err, model)
+		respondInvalid(w, r, validationErrors, err)
+		return nil, false
+	}
+	return fields, true
+}
+
+// respondInvalid sends the response for a body that failed validation with err
+func respondInvalid(w http.ResponseWriter, r *http.Request, validationErrors []validator.ValidationError, err error) {
+	switch {
+	case errors.Is(err, ErrBodyTooLarge):
+		response.PayloadTooLarge(w, r, validationErrors[0].Error)
+	case errors.Is(err, ErrValidationFailed):
+		response.UnprocessableEntity(w, r, validationErrors)
+	default:
+		handleValidationError(w, r, validationErrors)
 	}
-	return true
 }
@@ -0,0 +1,384 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/linkeunid/go-api/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPayload is a minimal model used to exercise the validation middleware
+type testPayload struct {
+	Name string `json:"name" validate:"required,min=2"`
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedName   string
+	}{
+		{
+			name:           "Valid",
+			contentType:    "application/json",
+			body:           `{"name":"Fluffy"}`,
+			expectedStatus: http.StatusOK,
+			expectedName:   "Fluffy",
+		},
+		{
+			name:           "ValidWithCharset",
+			contentType:    "application/json; charset=utf-8",
+			body:           `{"name":"Fluffy"}`,
+			expectedStatus: http.StatusOK,
+			expectedName:   "Fluffy",
+		},
+		{
+			name:           "WrongContentType",
+			contentType:    "text/plain",
+			body:           `{"name":"Fluffy"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "MalformedJSON",
+			contentType:    "application/json",
+			body:           `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "FailsValidation",
+			contentType:    "application/json",
+			body:           `{"name":"F"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "Array",
+			contentType:    "application/json",
+			body:           `[{"name":"Fluffy"}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := ValidationMiddleware[testPayload](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+
+				payload, ok := ValidatedModel[testPayload](r)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedName, payload.Name)
+
+				// A different type is not returned
+				_, ok = ValidatedModel[struct{}](r)
+				assert.False(t, ok)
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+		})
+	}
+}
+
+func TestArrayValidationMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedNames  []string
+		expectedSingle string
+		expectedErrors []validator.ValidationError
+	}{
+		{
+			name:           "Array",
+			body:           ` [{"name":"Fluffy"},{"name":"Whiskers"}]`,
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"Fluffy", "Whiskers"},
+		},
+		{
+			name:           "Object",
+			body:           `{"name":"Fluffy"}`,
+			expectedStatus: http.StatusOK,
+			expectedSingle: "Fluffy",
+		},
+		{
+			name:           "InvalidElements",
+			body:           `[{"name":"Fluffy"},{"name":"F"},{}]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []validator.ValidationError{
+				{Field: "[1].name", Tag: "min", Value: "F", Error: "name must be at least 2 characters in length"},
+				{Field: "[2].name", Tag: "required", Error: "name is required"},
+			},
+		},
+		{
+			name:           "MalformedElement",
+			body:           `[{"name":"F"},{"name":3}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty",
+			body:           `[]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedErrors: []validator.ValidationError{
+				{Field: "body", Tag: "min", Error: "Request body must contain at least one item"},
+			},
+		},
+		{
+			name:           "MalformedArray",
+			body:           `[{"name":"Fluffy"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := ArrayValidationMiddleware[testPayload](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+
+				payloads, isArray := ValidatedModels[testPayload](r)
+				payload, isObject := ValidatedModel[testPayload](r)
+				assert.Equal(t, tt.expectedNames != nil, isArray)
+				assert.Equal(t, tt.expectedSingle != "", isObject)
+				for i, name := range tt.expectedNames {
+					assert.Equal(t, name, payloads[i].Name)
+				}
+				if isObject {
+					assert.Equal(t, tt.expectedSingle, payload.Name)
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+			if tt.expectedErrors != nil {
+				var resp struct {
+					Data []validator.ValidationError `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErrors, resp.Data)
+			}
+		})
+	}
+}
+
+// typedPayload has fields of the types that accept values sent as strings
+type typedPayload struct {
+	Name     string   `json:"name"`
+	Age      int      `json:"age" validate:"gte=0"`
+	Weight   *float64 `json:"weight"`
+	Seasonal bool     `json:"seasonal"`
+}
+
+func TestValidateModel_TypeCoercion(t *testing.T) {
+	weight := 4.5
+
+	tests := []struct {
+		name          string
+		body          string
+		strict        bool
+		expected      typedPayload
+		expectedError error
+		expectedField validator.ValidationError
+	}{
+		{
+			name:     "NumbersAsStrings",
+			body:     `{"name":"Fluffy","age":" 3 ","weight":"4.5","seasonal":"true"}`,
+			expected: typedPayload{Name: "Fluffy", Age: 3, Weight: &weight, Seasonal: true},
+		},
+		{
+			name:     "NativeTypes",
+			body:     `{"name":"42","age":3}`,
+			expected: typedPayload{Name: "42", Age: 3},
+		},
+		{
+			name:          "NotANumber",
+			body:          `{"age":"three"}`,
+			expectedError: ErrMalformedBody,
+			expectedField: validator.ValidationError{Field: "age", Tag: "type", Value: "string", Error: "age must be an integer"},
+		},
+		{
+			name:          "Fraction",
+			body:          `{"age":"3.5"}`,
+			expectedError: ErrMalformedBody,
+			expectedField: validator.ValidationError{Field: "age", Tag: "type", Value: "number 3.5", Error: "age must be an integer"},
+		},
+		{
+			name:          "Hexadecimal",
+			body:          `{"age":"0x10"}`,
+			expectedError: ErrMalformedBody,
+			expectedField: validator.ValidationError{Field: "age", Tag: "type", Value: "string", Error: "age must be an integer"},
+		},
+		{
+			name:          "WrongTypeForString",
+			body:          `{"name":["Fluffy"]}`,
+			expectedError: ErrMalformedBody,
+			expectedField: validator.ValidationError{Field: "name", Tag: "type", Value: "array", Error: "name must be a string"},
+		},
+		{
+			name:          "CoercedValueStillValidated",
+			body:          `{"age":"-1"}`,
+			expectedError: ErrValidationFailed,
+			expectedField: validator.ValidationError{Field: "age", Tag: "gte"},
+		},
+		{
+			name:          "Strict",
+			body:          `{"age":"3"}`,
+			strict:        true,
+			expectedError: ErrMalformedBody,
+			expectedField: validator.ValidationError{Field: "age", Tag: "type", Value: "string", Error: "age must be an integer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetStrictTypes(tt.strict)
+			defer SetStrictTypes(false)
+
+			var payload typedPayload
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			errs, err := ValidateModel(&payload, req)
+
+			if tt.expectedError == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, payload)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.expectedField.Field, errs[0].Field)
+			assert.Equal(t, tt.expectedField.Tag, errs[0].Tag)
+			if tt.expectedField.Error != "" {
+				assert.Equal(t, tt.expectedField, errs[0])
+			}
+		})
+	}
+}
+
+func TestValidateModel_UnknownFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		strict        bool
+		expectedError validator.ValidationError
+	}{
+		{
+			name:   "IgnoredByDefault",
+			body:   `{"name":"Fluffy","nam":"x"}`,
+			strict: false,
+		},
+		{
+			name:          "Misspelled",
+			body:          `{"nam":"Fluffy"}`,
+			strict:        true,
+			expectedError: validator.ValidationError{Field: "nam", Tag: "unknown", Error: "nam is not a known field; did you mean name?"},
+		},
+		{
+			name:          "MisspelledCase",
+			body:          `{"name":"Fluffy","Seasnal":true}`,
+			strict:        true,
+			expectedError: validator.ValidationError{Field: "Seasnal", Tag: "unknown", Error: "Seasnal is not a known field; did you mean seasonal?"},
+		},
+		{
+			name:          "Unknown",
+			body:          `{"name":"Fluffy","color":"red"}`,
+			strict:        true,
+			expectedError: validator.ValidationError{Field: "color", Tag: "unknown", Error: "color is not a known field"},
+		},
+		{
+			name:          "UnknownAfterCoercedValue",
+			body:          `{"age":"3","owner":"Sam"}`,
+			strict:        true,
+			expectedError: validator.ValidationError{Field: "owner", Tag: "unknown", Error: "owner is not a known field"},
+		},
+		{
+			name:   "KnownFieldsCaseInsensitive",
+			body:   `{"Name":"Fluffy","AGE":3}`,
+			strict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDisallowUnknownFields(tt.strict)
+			defer SetDisallowUnknownFields(false)
+
+			var payload typedPayload
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			errs, err := ValidateModel(&payload, req)
+
+			if tt.expectedError.Field == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "Fluffy", payload.Name)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrMalformedBody)
+			assert.Equal(t, []validator.ValidationError{tt.expectedError}, errs)
+		})
+	}
+}
+
+func TestHandleDecodeFields(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		limit          int64
+		expectedStatus int
+		expectedFields map[string]interface{}
+		expectedError  string
+	}{
+		{name: "OnlySentFields", body: `{"age":0}`, expectedStatus: http.StatusOK, expectedFields: map[string]interface{}{"age": float64(0)}},
+		{name: "CoercedString", body: `{"age":"3"}`, expectedStatus: http.StatusOK, expectedFields: map[string]interface{}{"age": float64(3)}},
+		{name: "WrongType", body: `{"age":"old"}`, expectedStatus: http.StatusBadRequest, expectedError: "age must be an integer"},
+		{name: "NotAnObject", body: `[{"age":1}]`, expectedStatus: http.StatusBadRequest, expectedError: "Request body must be an object"},
+		{name: "Malformed", body: `{"age":`, expectedStatus: http.StatusBadRequest},
+		{name: "TooLarge", body: `{"name":"a name longer than the limit"}`, limit: 8, expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields map[string]interface{}
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var ok bool
+				if fields, ok = HandleDecodeFields(w, r, &typedPayload{}); ok {
+					w.WriteHeader(http.StatusOK)
+				}
+			})
+			if tt.limit > 0 {
+				handler = MaxBodyBytes(tt.limit)(handler)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedFields, fields)
+			if tt.expectedError != "" {
+				assert.Contains(t, rr.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/linkeunid/go-api/pkg/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// UpHeader and DownHeader start the SQL of every migration file, including empty ones
+const (
+	UpHeader = `-- Migration Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+`
+	DownHeader = `-- Migration Down
+-- SQL in section 'Down' is executed when this migration is rolled back
+
+`
+)
+
+// requireMySQL returns an error for model-based generation, which relies on MySQL-only DDL
+func (m *Manager) requireMySQL() error {
+	if driver := m.db.Dialector.Name(); driver != config.DBDriverMySQL {
+		return fmt.Errorf("generating migrations from models is only supported for MySQL, write %s migrations by hand", driver)
+	}
+	return nil
+}
+
+// tableNamePattern matches the table names GenerateFromModel accepts as an override
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// GenerateFromModel returns the SQL creating and dropping the table of a GORM model. A
+// non-empty table overrides the model's table name, e.g. for a renamed or join table. The
+// table is created briefly to read MySQL's own CREATE TABLE statement for it, so it must not
+// exist yet
+func (m *Manager) GenerateFromModel(model interface{}, table string) (upSQL, downSQL string, err error) {
+	if err := m.requireMySQL(); err != nil {
+		return "", "", err
+	}
+	if table != "" && !tableNamePattern.MatchString(table) {
+		return "", "", fmt.Errorf("invalid table name %q", table)
+	}
+
+	createTableSQL, tableName, err := m.showCreateTable(model, table)
+	if err != nil {
+		return "", "", err
+	}
+
+	upSQL = UpHeader + createTableSQL
+	downSQL = DownHeader + fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", tableName)
+	return upSQL, downSQL, nil
+}
+
+// GenerateFromModels returns the SQL creating and dropping the tables of several GORM models
+// in one migration. Tables are created in the order given and dropped in reverse
+func (m *Manager) GenerateFromModels(models ...interface{}) (upSQL, downSQL string, err error) {
+	if err := m.requireMySQL(); err != nil {
+		return "", "", err
+	}
+	if len(models) == 0 {
+		return "", "", fmt.Errorf("no models to generate a migration from")
+	}
+
+	upStatements := make([]string, 0, len(models))
+	downStatements := make([]string, len(models))
+	for i, model := range models {
+		createTableSQL, tableName, err := m.showCreateTable(model, "")
+		if err != nil {
+			return "", "", err
+		}
+		upStatements = append(upStatements, createTableSQL)
+		downStatements[len(models)-1-i] = fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", tableName)
+	}
+
+	upSQL = UpHeader + strings.Join(upStatements, "\n\n")
+	downSQL = DownHeader + strings.Join(downStatements, "\n")
+	return upSQL, downSQL, nil
+}
+
+// showCreateTable creates the table of model, named table if not empty, reads its CREATE TABLE
+// statement and drops it again. The statement is returned as CREATE TABLE IF NOT EXISTS along
+// with the table name. Once created, the table is dropped whatever happens next, and the steps
+// run in a transaction that rolls back on error. MySQL commits DDL implicitly, so the drop is
+// what keeps the table from leaking
+func (m *Manager) showCreateTable(model interface{}, table string) (createTableSQL, tableName string, err error) {
+	stmt := &gorm.Statement{DB: m.db}
+	if err := stmt.ParseWithSpecialTableName(model, table); err != nil {
+		return "", "", fmt.Errorf("failed to parse model: %w", err)
+	}
+	tableName = stmt.Schema.Table
+
+	err = m.db.Transaction(func(tx *gorm.DB) (err error) {
+		if table != "" {
+			tx = tx.Table(table)
+		}
+		migrator := tx.Migrator()
+
+		// Create the table temporarily to get the CREATE TABLE statement
+		if err := migrator.CreateTable(model); err != nil {
+			return fmt.Errorf("failed to create table %s for DDL generation: %w", tableName, err)
+		}
+		defer func() {
+			if dropErr := migrator.DropTable(model); dropErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to clean up temporary table %s: %w", tableName, dropErr))
+			}
+		}()
+
+		var showTableName string
+		query := fmt.Sprintf("SHOW CREATE TABLE `%s`", tableName)
+		if err := tx.Raw(query).Row().Scan(&showTableName, &createTableSQL); err != nil {
+			return fmt.Errorf("failed to get CREATE TABLE SQL: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// Replace CREATE TABLE with CREATE TABLE IF NOT EXISTS
+	createTableSQL = strings.Replace(createTableSQL, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1)
+	return createTableSQL + ";", tableName, nil
+}
+
+// GenerateDiffFromModel generates ALTER TABLE statements that bring the live table in line
+// with a GORM model. Columns missing from the table are added and columns whose type or
+// nullability differs are modified. Columns that exist only in the table are dropped if
+// allowDestructive is true and reported in skipped otherwise. Empty SQL means there is no change.
+func (m *Manager) GenerateDiffFromModel(model interface{}, allowDestructive bool) (upSQL, downSQL string, skipped []string, err error) {
+	if err := m.requireMySQL(); err != nil {
+		return "", "", nil, err
+	}
+
+	stmt := &gorm.Statement{DB: m.db}
+	if err := stmt.Parse(model); err != nil {
+		return "", "", nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+
+	tableName := stmt.Schema.Table
+	migrator := m.db.Migrator()
+
+	if !migrator.HasTable(tableName) {
+		return "", "", nil, fmt.Errorf("table %s does not exist, generate it with GenerateFromModel", tableName)
+	}
+
+	columnTypes, err := migrator.ColumnTypes(tableName)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to read columns of %s: %w", tableName, err)
+	}
+
+	existing := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, ct := range columnTypes {
+		existing[ct.Name()] = ct
+	}
+
+	var upStatements, downStatements []string
+	modelColumns := make(map[string]bool)
+
+	for _, dbName := range stmt.Schema.DBNames {
+		field := stmt.Schema.FieldsByDBName[dbName]
+		modelColumns[dbName] = true
+
+		definition := fieldDefinition(m.db, field)
+		ct, exists := existing[dbName]
+
+		switch {
+		case !exists:
+			upStatements = append(upStatements, fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s;", tableName, dbName, definition))
+			downStatements = append(downStatements, fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`;", tableName, dbName))
+		case columnChanged(ct, m.db.Dialector.DataTypeOf(field), field.NotNull || field.PrimaryKey):
+			upStatements = append(upStatements, fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN `%s` %s;", tableName, dbName, definition))
+			downStatements = append(downStatements, fmt.Sprintf("ALTER TABLE `%s` MODIFY COLUMN `%s` %s;", tableName, dbName, columnDefinition(ct)))
+		}
+	}
+
+	// Columns that only exist in the table would be dropped; keep them unless explicitly allowed
+	for _, ct := range columnTypes {
+		if modelColumns[ct.Name()] {
+			continue
+		}
+
+		if !allowDestructive {
+			skipped = append(skipped, ct.Name())
+			continue
+		}
+
+		upStatements = append(upStatements, fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`;", tableName, ct.Name()))
+		downStatements = append(downStatements, fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s;", tableName, ct.Name(), columnDefinition(ct)))
+	}
+
+	if len(upStatements) == 0 {
+		return "", "", skipped, nil
+	}
+
+	// Roll back in reverse order of application
+	for i, j := 0, len(downStatements)-1; i < j; i, j = i+1, j-1 {
+		downStatements[i], downStatements[j] = downStatements[j], downStatements[i]
+	}
+
+	upSQL = UpHeader + strings.Join(upStatements, "\n")
+	downSQL = DownHeader + strings.Join(downStatements, "\n")
+	return upSQL, downSQL, skipped, nil
+}
+
+// fieldDefinition returns the column definition GORM would use for a model field
+func fieldDefinition(db *gorm.DB, field *schema.Field) string {
+	expr := db.Migrator().FullDataTypeOf(field)
+	definition := expr.SQL
+	for _, v := range expr.Vars {
+		definition = strings.Replace(definition, "?", fmt.Sprintf("%v", v), 1)
+	}
+	return definition
+}
+
+// columnChanged reports whether a live column differs from the model's type or nullability
+func columnChanged(ct gorm.ColumnType, dataType string, notNull bool) bool {
+	if columnType, ok := ct.ColumnType(); ok && normalizeColumnType(columnType) != normalizeColumnType(dataType) {
+		return true
+	}
+
+	if nullable, ok := ct.Nullable(); ok && nullable == notNull {
+		return true
+	}
+
+	return false
+}
+
+// columnTypeAliases maps the type names GORM generates to the ones MySQL reports for the column
+var columnTypeAliases = map[string]string{
+	"bool":    "tinyint(1)",
+	"boolean": "tinyint(1)",
+	"integer": "int",
+}
+
+// displayWidthPattern matches the display width MySQL before 8.0.19 reports for integer types
+var displayWidthPattern = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+// normalizeColumnType reduces a column type to the form MySQL reports it in, so equal types
+// compare equal. Attributes GORM appends to the type, such as AUTO_INCREMENT and NULL, are
+// dropped, and the display width of integers is ignored except for tinyint(1), MySQL's boolean
+func normalizeColumnType(columnType string) string {
+	columnType = strings.ToLower(strings.Join(strings.Fields(columnType), " "))
+	columnType = strings.ReplaceAll(columnType, ", ", ",")
+	for _, attribute := range []string{" auto_increment", " not null", " null"} {
+		columnType = strings.ReplaceAll(columnType, attribute, "")
+	}
+
+	if alias, ok := columnTypeAliases[columnType]; ok {
+		return alias
+	}
+	if !strings.HasPrefix(columnType, "tinyint(1)") {
+		columnType = displayWidthPattern.ReplaceAllString(columnType, "$1")
+	}
+	return columnType
+}
+
+// columnDefinition rebuilds the definition of a live column so it can be restored
+func columnDefinition(ct gorm.ColumnType) string {
+	definition, _ := ct.ColumnType()
+
+	if nullable, ok := ct.Nullable(); ok && !nullable {
+		definition += " NOT NULL"
+	}
+
+	if value, ok := ct.DefaultValue(); ok {
+		// Numbers and expressions such as CURRENT_TIMESTAMP(3) must stay unquoted
+		if _, err := strconv.ParseFloat(value, 64); err == nil || strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP") {
+			definition += " DEFAULT " + value
+		} else {
+			definition += fmt.Sprintf(" DEFAULT '%s'", strings.ReplaceAll(value, "'", "''"))
+		}
+	}
+
+	if autoIncrement, ok := ct.AutoIncrement(); ok && autoIncrement {
+		definition += " AUTO_INCREMENT"
+	}
+
+	return definition
+}
@@ -0,0 +1,303 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+)
+
+// widget is a model with a table of its own
+type widget struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"size:100;not null"`
+}
+
+const widgetCreateTable = "CREATE TABLE `widgets` (\n  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n  `name` varchar(100) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+
+// gadget is a second model with a table of its own
+type gadget struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+// expectTempTable expects table to be created, shown as createTable and dropped again in a
+// transaction
+func expectTempTable(sqlMock sqlmock.Sqlmock, table, createTable string) {
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("CREATE TABLE `" + table + "`").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery("SHOW CREATE TABLE `" + table + "`").
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow(table, createTable))
+	expectDropTable(sqlMock, table)
+	sqlMock.ExpectCommit()
+}
+
+// expectDropTable expects the statements of dropping table
+func expectDropTable(sqlMock sqlmock.Sqlmock, table string) {
+	sqlMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec("DROP TABLE IF EXISTS `" + table + "`").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 1").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestManager_GenerateFromModel(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	expectTempTable(sqlMock, "widgets", widgetCreateTable)
+
+	upSQL, downSQL, err := manager.GenerateFromModel(&widget{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, UpHeader+"CREATE TABLE IF NOT EXISTS `widgets` (\n  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n  `name` varchar(100) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;", upSQL)
+	assert.Equal(t, DownHeader+"DROP TABLE IF EXISTS `widgets`;", downSQL)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestManager_GenerateFromModel_ScanFailure(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("CREATE TABLE `widgets`").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery("SHOW CREATE TABLE `widgets`").
+		WillReturnRows(sqlmock.NewRows([]string{"Table"}).AddRow("widgets"))
+	expectDropTable(sqlMock, "widgets")
+	sqlMock.ExpectRollback()
+
+	_, _, err := manager.GenerateFromModel(&widget{}, "")
+	assert.ErrorContains(t, err, "failed to get CREATE TABLE SQL")
+	assert.NoError(t, sqlMock.ExpectationsWereMet(), "the temporary table must be dropped and the transaction rolled back")
+}
+
+func TestManager_GenerateFromModel_QueryFailure(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("CREATE TABLE `widgets`").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery("SHOW CREATE TABLE `widgets`").WillReturnError(errors.New("connection reset"))
+	expectDropTable(sqlMock, "widgets")
+	sqlMock.ExpectRollback()
+
+	_, _, err := manager.GenerateFromModel(&widget{}, "")
+	assert.ErrorContains(t, err, "connection reset")
+	assert.NoError(t, sqlMock.ExpectationsWereMet(), "the temporary table must be dropped")
+}
+
+func TestManager_GenerateFromModel_CleanupFailure(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("CREATE TABLE `widgets`").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery("SHOW CREATE TABLE `widgets`").
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).AddRow("widgets", widgetCreateTable))
+	sqlMock.ExpectExec("SET FOREIGN_KEY_CHECKS = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec("DROP TABLE IF EXISTS `widgets`").WillReturnError(errors.New("lock wait timeout"))
+	sqlMock.ExpectRollback()
+
+	_, _, err := manager.GenerateFromModel(&widget{}, "")
+	assert.ErrorContains(t, err, "failed to clean up temporary table widgets")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestManager_GenerateFromModel_CreateFailure(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	// A table that couldn't be created, e.g. because it already exists, isn't dropped
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("CREATE TABLE `widgets`").WillReturnError(errors.New("Table 'widgets' already exists"))
+	sqlMock.ExpectRollback()
+
+	_, _, err := manager.GenerateFromModel(&widget{}, "")
+	assert.ErrorContains(t, err, "failed to create table widgets")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestManager_GenerateFromModel_Table(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	// The table is created, shown and dropped under the given name
+	expectTempTable(sqlMock, "gizmos", strings.Replace(widgetCreateTable, "widgets", "gizmos", 1))
+
+	upSQL, downSQL, err := manager.GenerateFromModel(&widget{}, "gizmos")
+	require.NoError(t, err)
+	assert.Contains(t, upSQL, "CREATE TABLE IF NOT EXISTS `gizmos` (")
+	assert.NotContains(t, upSQL, "widgets")
+	assert.Equal(t, DownHeader+"DROP TABLE IF EXISTS `gizmos`;", downSQL)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+	_, _, err = manager.GenerateFromModel(&widget{}, "gizmos`; DROP TABLE users")
+	assert.ErrorContains(t, err, "invalid table name")
+}
+
+func TestManager_GenerateFromModels(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	gadgetCreateTable := "CREATE TABLE `gadgets` (\n  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+	expectTempTable(sqlMock, "widgets", widgetCreateTable)
+	expectTempTable(sqlMock, "gadgets", gadgetCreateTable)
+
+	upSQL, downSQL, err := manager.GenerateFromModels(&widget{}, &gadget{})
+	require.NoError(t, err)
+	assert.Equal(t, UpHeader+
+		strings.Replace(widgetCreateTable, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1)+";\n\n"+
+		strings.Replace(gadgetCreateTable, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1)+";", upSQL)
+	assert.Equal(t, DownHeader+"DROP TABLE IF EXISTS `gadgets`;\nDROP TABLE IF EXISTS `widgets`;", downSQL, "tables are dropped in reverse order")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+	_, _, err = manager.GenerateFromModels()
+	assert.ErrorContains(t, err, "no models")
+}
+
+func TestManager_GenerateRequiresMySQL(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+	manager := &Manager{db: db}
+
+	_, _, err = manager.GenerateFromModel(&widget{}, "")
+	assert.ErrorContains(t, err, "only supported for MySQL")
+	_, _, err = manager.GenerateFromModels(&widget{}, &gadget{})
+	assert.ErrorContains(t, err, "only supported for MySQL")
+	_, _, _, err = manager.GenerateDiffFromModel(&widget{}, false)
+	assert.ErrorContains(t, err, "only supported for MySQL")
+}
+
+func TestColumnChanged(t *testing.T) {
+	column := migrator.ColumnType{
+		ColumnTypeValue: sql.NullString{String: "varchar(100)", Valid: true},
+		NullableValue:   sql.NullBool{Bool: false, Valid: true},
+	}
+
+	assert.False(t, columnChanged(column, "VARCHAR(100)", true))
+	assert.True(t, columnChanged(column, "varchar(191)", true), "type changed")
+	assert.True(t, columnChanged(column, "varchar(100)", false), "nullability changed")
+
+	tests := []struct {
+		columnType string
+		dataType   string
+	}{
+		{columnType: "bigint unsigned", dataType: "bigint unsigned AUTO_INCREMENT"},
+		{columnType: "bigint(20) unsigned", dataType: "bigint unsigned"},
+		{columnType: "int(11)", dataType: "int"},
+		{columnType: "tinyint(1)", dataType: "boolean"},
+		{columnType: "datetime(3)", dataType: "datetime(3) NULL"},
+		{columnType: "decimal(10,2)", dataType: "decimal(10, 2)"},
+	}
+	for _, tc := range tests {
+		column := migrator.ColumnType{
+			ColumnTypeValue: sql.NullString{String: tc.columnType, Valid: true},
+			NullableValue:   sql.NullBool{Bool: false, Valid: true},
+		}
+		assert.False(t, columnChanged(column, tc.dataType, true), "%s should match %s", tc.columnType, tc.dataType)
+	}
+	column.ColumnTypeValue = sql.NullString{String: "tinyint(1)", Valid: true}
+	assert.True(t, columnChanged(column, "int", true), "integer type changed")
+}
+
+// gauge is a model covering the column types whose spelling differs between GORM and MySQL
+type gauge struct {
+	ID        uint    `gorm:"primaryKey"`
+	Name      string  `gorm:"size:100;not null"`
+	Enabled   bool    `gorm:"not null"`
+	Reading   float64 `gorm:"precision:10;scale:2"`
+	CreatedAt time.Time
+}
+
+func TestManager_GenerateDiffFromModel_Unchanged(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	manager := &Manager{db: db}
+
+	sqlMock.ExpectQuery("SELECT SCHEMA_NAME").WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("app"))
+	sqlMock.ExpectQuery("SELECT count\\(\\*\\) FROM information_schema.tables").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	sqlMock.ExpectQuery("SELECT SCHEMA_NAME").WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("app"))
+	sqlMock.ExpectQuery("SELECT \\* FROM `gauges` LIMIT").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "enabled", "reading", "created_at"}))
+	// The columns as MySQL reports them for the table GORM created from the model
+	sqlMock.ExpectQuery("SELECT column_name, .* FROM information_schema.columns").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"column_name", "column_default", "is_nullable", "data_type", "character_maximum_length", "column_type",
+			"column_key", "extra", "column_comment", "numeric_precision", "numeric_scale", "datetime_precision",
+		}).
+			AddRow("id", nil, false, "bigint", nil, "bigint unsigned", "PRI", "auto_increment", "", 20, 0, nil).
+			AddRow("name", nil, false, "varchar", 100, "varchar(100)", "", "", "", nil, nil, nil).
+			AddRow("enabled", nil, false, "tinyint", nil, "tinyint(1)", "", "", "", 3, 0, nil).
+			AddRow("reading", nil, true, "decimal", nil, "decimal(10,2)", "", "", "", 10, 2, nil).
+			AddRow("created_at", nil, true, "datetime", nil, "datetime(3)", "", "", "", nil, nil, 3))
+
+	upSQL, downSQL, skipped, err := manager.GenerateDiffFromModel(&gauge{}, false)
+	require.NoError(t, err)
+	assert.Empty(t, upSQL)
+	assert.Empty(t, downSQL)
+	assert.Empty(t, skipped)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestColumnDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   migrator.ColumnType
+		expected string
+	}{
+		{
+			name: "NullableString",
+			column: migrator.ColumnType{
+				ColumnTypeValue:    sql.NullString{String: "varchar(100)", Valid: true},
+				NullableValue:      sql.NullBool{Bool: true, Valid: true},
+				DefaultValueValue:  sql.NullString{String: "it's", Valid: true},
+				AutoIncrementValue: sql.NullBool{Valid: true},
+			},
+			expected: "varchar(100) DEFAULT 'it''s'",
+		},
+		{
+			name: "AutoIncrement",
+			column: migrator.ColumnType{
+				ColumnTypeValue:    sql.NullString{String: "bigint unsigned", Valid: true},
+				NullableValue:      sql.NullBool{Bool: false, Valid: true},
+				AutoIncrementValue: sql.NullBool{Bool: true, Valid: true},
+			},
+			expected: "bigint unsigned NOT NULL AUTO_INCREMENT",
+		},
+		{
+			name: "UnquotedDefaults",
+			column: migrator.ColumnType{
+				ColumnTypeValue:    sql.NullString{String: "datetime(3)", Valid: true},
+				NullableValue:      sql.NullBool{Bool: true, Valid: true},
+				DefaultValueValue:  sql.NullString{String: "CURRENT_TIMESTAMP(3)", Valid: true},
+				AutoIncrementValue: sql.NullBool{Valid: true},
+			},
+			expected: "datetime(3) DEFAULT CURRENT_TIMESTAMP(3)",
+		},
+		{
+			name: "NumericDefault",
+			column: migrator.ColumnType{
+				ColumnTypeValue:    sql.NullString{String: "int", Valid: true},
+				NullableValue:      sql.NullBool{Bool: true, Valid: true},
+				DefaultValueValue:  sql.NullString{String: "0", Valid: true},
+				AutoIncrementValue: sql.NullBool{Valid: true},
+			},
+			expected: "int DEFAULT 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, columnDefinition(tc.column))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	mysqldriver "github.com/golang-migrate/migrate/v4/database/mysql"
+	postgresdriver "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/linkeunid/go-api/pkg/config"
+	"gorm.io/gorm"
+)
+
+// ErrNoChange is returned by Up, Down and Steps when there is no migration to apply
+var ErrNoChange = migrate.ErrNoChange
+
+// Manager applies the migrations of one directory to a database and generates new migrations
+// from GORM models
+type Manager struct {
+	db      *gorm.DB
+	fsys    fs.FS
+	dir     string
+	migrate *migrate.Migrate
+}
+
+// NewManager returns a Manager applying the migrations in dir of fsys to db, which must use
+// the MySQL or PostgreSQL dialector. MySQL connections need multiStatements=true since each
+// migration file is sent as one statement. Closing the Manager leaves db open
+func NewManager(db *gorm.DB, fsys fs.FS, dir string) (*Manager, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL database connection: %w", err)
+	}
+
+	var driver migratedb.Driver
+	switch db.Dialector.Name() {
+	case config.DBDriverMySQL:
+		driver, err = mysqldriver.WithInstance(sqlDB, &mysqldriver.Config{MigrationsTable: Table})
+	case config.DBDriverPostgres:
+		driver, err = postgresdriver.WithInstance(sqlDB, &postgresdriver.Config{MigrationsTable: Table})
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", db.Dialector.Name())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	return newManager(db, driver, fsys, dir)
+}
+
+// newManager returns a Manager recording versions through driver, so tests can swap in
+// golang-migrate's in-memory stub
+func newManager(db *gorm.DB, driver migratedb.Driver, fsys fs.FS, dir string) (*Manager, error) {
+	source, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, db.Dialector.Name(), driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	return &Manager{db: db, fsys: fsys, dir: dir, migrate: m}, nil
+}
+
+// Up applies all pending migrations
+func (m *Manager) Up() error {
+	return m.migrate.Up()
+}
+
+// Down rolls back all applied migrations
+func (m *Manager) Down() error {
+	return m.migrate.Down()
+}
+
+// Steps applies n migrations, or rolls back -n migrations when n is negative
+func (m *Manager) Steps(n int) error {
+	return m.migrate.Steps(n)
+}
+
+// Version returns the applied version and whether the last migration failed part way. It is
+// 0 when no migration has been applied
+func (m *Manager) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Latest returns the highest version of the Manager's migrations
+func (m *Manager) Latest() (uint, error) {
+	return LatestVersion(m.fsys, m.dir)
+}
+
+// Force records version as applied and clears the dirty flag without running any migration.
+// A version of -1 records that no migration is applied
+func (m *Manager) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// HasTable reports whether the table of model exists
+func (m *Manager) HasTable(model interface{}) bool {
+	return m.db.Migrator().HasTable(model)
+}
+
+// Close releases the migration source and the connection reserved for migrations
+func (m *Manager) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	return errors.Join(sourceErr, dbErr)
+}
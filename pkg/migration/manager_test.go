@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMigrations holds three migrations, each recognizable by its SQL
+var testMigrations = fstest.MapFS{
+	"1746764390_create_animal_table.up.sql":   {Data: []byte("CREATE TABLE animal")},
+	"1746764390_create_animal_table.down.sql": {Data: []byte("DROP TABLE animal")},
+	"1746767356_create_flower_table.up.sql":   {Data: []byte("CREATE TABLE flower")},
+	"1746767356_create_flower_table.down.sql": {Data: []byte("DROP TABLE flower")},
+	"1760659200_create_outbox_table.up.sql":   {Data: []byte("CREATE TABLE outbox")},
+	"1760659200_create_outbox_table.down.sql": {Data: []byte("DROP TABLE outbox")},
+}
+
+// newTestManager returns a Manager applying testMigrations to golang-migrate's in-memory stub,
+// along with the stub to inspect what ran
+func newTestManager(t *testing.T) (*Manager, *stub.Stub) {
+	t.Helper()
+
+	db, _ := newTestDB(t)
+	driver, err := stub.WithInstance(nil, &stub.Config{})
+	require.NoError(t, err)
+
+	manager, err := newManager(db, driver, testMigrations, ".")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Close() })
+	return manager, driver.(*stub.Stub)
+}
+
+func TestManager_UpDown(t *testing.T) {
+	manager, driver := newTestManager(t)
+
+	version, dirty, err := manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version, "no migration applied yet")
+	assert.False(t, dirty)
+
+	require.NoError(t, manager.Up())
+	assert.Equal(t, []string{"CREATE TABLE animal", "CREATE TABLE flower", "CREATE TABLE outbox"}, driver.MigrationSequence)
+	version, _, err = manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1760659200), version)
+	assert.ErrorIs(t, manager.Up(), ErrNoChange)
+
+	require.NoError(t, manager.Down())
+	assert.Equal(t, []string{"DROP TABLE outbox", "DROP TABLE flower", "DROP TABLE animal"}, driver.MigrationSequence[3:])
+	version, _, err = manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+	assert.ErrorIs(t, manager.Down(), ErrNoChange)
+}
+
+func TestManager_Steps(t *testing.T) {
+	manager, driver := newTestManager(t)
+
+	require.NoError(t, manager.Steps(2))
+	version, _, err := manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1746767356), version)
+
+	require.NoError(t, manager.Steps(-1))
+	assert.Equal(t, "DROP TABLE flower", string(driver.LastRunMigration))
+	version, _, err = manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1746764390), version)
+
+	// Steps past the last migration apply nothing
+	assert.Error(t, manager.Steps(3))
+}
+
+func TestManager_Force(t *testing.T) {
+	manager, driver := newTestManager(t)
+	driver.CurrentVersion = 1746767356
+	driver.IsDirty = true
+
+	version, dirty, err := manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1746767356), version)
+	assert.True(t, dirty)
+
+	require.NoError(t, manager.Force(1746764390))
+	version, dirty, err = manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1746764390), version)
+	assert.False(t, dirty)
+	assert.Empty(t, driver.MigrationSequence, "forcing runs no migration")
+
+	require.NoError(t, manager.Force(-1))
+	version, _, err = manager.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+}
+
+func TestManager_Latest(t *testing.T) {
+	manager, _ := newTestManager(t)
+
+	latest, err := manager.Latest()
+	require.NoError(t, err)
+	assert.Equal(t, uint(1760659200), latest)
+}
+
+func TestNewManager_MissingDir(t *testing.T) {
+	db, _ := newTestDB(t)
+	driver, err := stub.WithInstance(nil, &stub.Config{})
+	require.NoError(t, err)
+
+	_, err = newManager(db, driver, testMigrations, "missing")
+	assert.ErrorContains(t, err, "failed to load migrations")
+}
@@ -0,0 +1,123 @@
+// Package migration applies and generates database migrations. Manager holds the logic behind
+// the migrate command so it can run programmatically, and the version helpers here back the
+// API's startup check: where migrations live for each driver, the newest version they define
+// and the version recorded in the database
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/linkeunid/go-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Table is the table golang-migrate records the applied version in
+const Table = "schema_migrations"
+
+// Path is the directory holding the migrations, relative to the repository root
+const Path = "migrations"
+
+// EmbeddedDir returns the directory of the driver's migrations relative to Path, which is also
+// their directory in migrations.FS. MySQL migrations live in Path itself and PostgreSQL ones in
+// its postgres subdirectory
+func EmbeddedDir(driver string) string {
+	if driver == config.DBDriverPostgres {
+		return config.DBDriverPostgres
+	}
+	return "."
+}
+
+// Dir returns the directory of the driver's migrations relative to the repository root
+func Dir(driver string) string {
+	return path.Join(Path, EmbeddedDir(driver))
+}
+
+// LatestVersion returns the highest version of the migrations in dir of fsys, or 0 if there are none
+func LatestVersion(fsys fs.FS, dir string) (uint, error) {
+	source, err := iofs.New(fsys, dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
+
+// Status compares the schema version recorded in the database with the newest migration
+type Status struct {
+	Current uint // Version recorded in Table; 0 when no migration has been applied
+	Latest  uint // Highest version of the available migrations
+	Dirty   bool // The last migration failed part way and the schema needs fixing by hand
+}
+
+// Behind reports whether migrations newer than the database's version are pending
+func (s Status) Behind() bool {
+	return s.Current < s.Latest
+}
+
+// Ahead reports whether the database was migrated past the newest available migration,
+// e.g. by a newer build
+func (s Status) Ahead() bool {
+	return s.Current > s.Latest
+}
+
+// CurrentVersion reads the version recorded in Table without changing anything, unlike
+// golang-migrate which creates the table when it opens the database. A missing table or an
+// empty one means no migration has been applied and is reported as version 0
+func CurrentVersion(ctx context.Context, db *gorm.DB) (uint, bool, error) {
+	var rows []struct {
+		Version int64
+		Dirty   bool
+	}
+	err := db.WithContext(ctx).Table(Table).Select("version", "dirty").Limit(1).Find(&rows).Error
+	if errors.Is(database.TranslateError(err), database.ErrTableNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read %s: %w", Table, err)
+	}
+	// golang-migrate records -1 while no migration is applied
+	if len(rows) == 0 || rows[0].Version < 0 {
+		return 0, false, nil
+	}
+	return uint(rows[0].Version), rows[0].Dirty, nil
+}
+
+// Check returns the Status of db against the migrations in dir of fsys
+func Check(ctx context.Context, db *gorm.DB, fsys fs.FS, dir string) (Status, error) {
+	latest, err := LatestVersion(fsys, dir)
+	if err != nil {
+		return Status{}, err
+	}
+
+	current, dirty, err := CurrentVersion(ctx, db)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{Current: current, Latest: latest, Dirty: dirty}, nil
+}
@@ -0,0 +1,150 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/linkeunid/go-api/migrations"
+	"github.com/linkeunid/go-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestDB returns a MySQL GORM connection backed by sqlmock
+func newTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+	return db, sqlMock
+}
+
+func TestDir(t *testing.T) {
+	assert.Equal(t, "migrations", Dir(config.DBDriverMySQL))
+	assert.Equal(t, "migrations/postgres", Dir(config.DBDriverPostgres))
+	assert.Equal(t, ".", EmbeddedDir(config.DBDriverMySQL))
+	assert.Equal(t, "postgres", EmbeddedDir(config.DBDriverPostgres))
+}
+
+func TestLatestVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"00000000_init.up.sql":                     {},
+		"00000000_init.down.sql":                   {},
+		"1746764390_create_animal_table.up.sql":    {},
+		"1760659200_create_outbox_table.up.sql":    {},
+		"1746767356_create_flower_table.up.sql":    {},
+		"postgres/1746764390_create_animal.up.sql": {},
+		"README.md": {},
+	}
+
+	latest, err := LatestVersion(fsys, ".")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1760659200), latest)
+
+	latest, err = LatestVersion(fsys, "postgres")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1746764390), latest)
+
+	// Directories without migrations have no version
+	latest, err = LatestVersion(fstest.MapFS{"README.md": {}}, ".")
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), latest)
+	latest, err = LatestVersion(fsys, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), latest)
+}
+
+func TestLatestVersion_Embedded(t *testing.T) {
+	// Every driver ships migrations with the binary
+	for _, driver := range []string{config.DBDriverMySQL, config.DBDriverPostgres} {
+		latest, err := LatestVersion(migrations.FS, EmbeddedDir(driver))
+		require.NoError(t, err, driver)
+		assert.NotZero(t, latest, driver)
+	}
+}
+
+func TestCurrentVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            *sqlmock.Rows
+		err             error
+		expectedVersion uint
+		expectedDirty   bool
+	}{
+		{name: "NoTable", err: &mysqldriver.MySQLError{Number: 1146, Message: "Table 'app.schema_migrations' doesn't exist"}},
+		{name: "EmptyTable", rows: sqlmock.NewRows([]string{"version", "dirty"})},
+		{name: "NilVersion", rows: sqlmock.NewRows([]string{"version", "dirty"}).AddRow(-1, false)},
+		{name: "Applied", rows: sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1760659200, false), expectedVersion: 1760659200},
+		{name: "Dirty", rows: sqlmock.NewRows([]string{"version", "dirty"}).AddRow(1760572800, true), expectedVersion: 1760572800, expectedDirty: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, sqlMock := newTestDB(t)
+			query := sqlMock.ExpectQuery("SELECT `version`,`dirty` FROM `schema_migrations` LIMIT ?")
+			if tc.err != nil {
+				query.WillReturnError(tc.err)
+			} else {
+				query.WillReturnRows(tc.rows)
+			}
+
+			version, dirty, err := CurrentVersion(context.Background(), db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, version)
+			assert.Equal(t, tc.expectedDirty, dirty)
+			assert.NoError(t, sqlMock.ExpectationsWereMet(), "the check must only read the table")
+		})
+	}
+}
+
+func TestCurrentVersion_Error(t *testing.T) {
+	db, sqlMock := newTestDB(t)
+	sqlMock.ExpectQuery("schema_migrations").WillReturnError(&mysqldriver.MySQLError{Number: 1142, Message: "SELECT command denied"})
+
+	_, _, err := CurrentVersion(context.Background(), db)
+	assert.ErrorContains(t, err, "failed to read schema_migrations")
+}
+
+func TestCheck(t *testing.T) {
+	fsys := fstest.MapFS{
+		"1746764390_create_animal_table.up.sql": {},
+		"1760659200_create_outbox_table.up.sql": {},
+	}
+
+	tests := []struct {
+		name           string
+		version        int
+		expectedBehind bool
+		expectedAhead  bool
+	}{
+		{name: "UpToDate", version: 1760659200},
+		{name: "Behind", version: 1746764390, expectedBehind: true},
+		{name: "Ahead", version: 1770000000, expectedAhead: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, sqlMock := newTestDB(t)
+			sqlMock.ExpectQuery("schema_migrations").WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(tc.version, false))
+
+			status, err := Check(context.Background(), db, fsys, ".")
+			require.NoError(t, err)
+			assert.Equal(t, Status{Current: uint(tc.version), Latest: 1760659200}, status)
+			assert.Equal(t, tc.expectedBehind, status.Behind())
+			assert.Equal(t, tc.expectedAhead, status.Ahead())
+		})
+	}
+}
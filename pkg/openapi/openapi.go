@@ -0,0 +1,440 @@
+// Package openapi converts the Swagger 2.0 document generated by swag into OpenAPI 3.0, for
+// tooling that no longer reads Swagger 2.0
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Version is the OpenAPI version of converted documents
+const Version = "3.0.3"
+
+// defaultMediaType is used for bodies when an operation and the document list no media types
+const defaultMediaType = "application/json"
+
+// schemaKeys are the parameter and header fields that describe a value's type in Swagger 2.0 and
+// move under schema in OpenAPI 3.0
+var schemaKeys = []string{
+	"type", "format", "items", "enum", "default", "pattern",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength", "minItems", "maxItems", "uniqueItems", "multipleOf",
+}
+
+// object is a JSON object of the document
+type object = map[string]interface{}
+
+// Convert returns the OpenAPI 3.0 equivalent of the Swagger 2.0 document swagger. Definitions
+// become component schemas, body and form parameters become request bodies and responses get
+// one content entry per produced media type. An API key security scheme sent in the
+// Authorization header, the way swag declares JWT bearer auth, becomes an HTTP bearer scheme
+// so UIs add the "Bearer " prefix themselves
+func Convert(swagger []byte) ([]byte, error) {
+	var doc object
+	if err := json.Unmarshal(swagger, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger document: %w", err)
+	}
+	if version, _ := doc["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("unsupported Swagger version %q", version)
+	}
+
+	rewriteRefs(doc)
+
+	out := object{
+		"openapi": Version,
+		"info":    doc["info"],
+		"servers": servers(doc),
+		"paths":   object{},
+	}
+	for _, key := range []string{"tags", "security", "externalDocs"} {
+		if value, ok := doc[key]; ok {
+			out[key] = value
+		}
+	}
+	copyExtensions(doc, out)
+
+	components := object{}
+	if definitions, ok := doc["definitions"].(object); ok {
+		for _, schema := range definitions {
+			convertSchema(schema)
+		}
+		components["schemas"] = definitions
+	}
+	if definitions, ok := doc["securityDefinitions"].(object); ok {
+		schemes := object{}
+		for name, definition := range definitions {
+			if definition, ok := definition.(object); ok {
+				schemes[name] = securityScheme(definition)
+			}
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	consumes := stringList(doc["consumes"])
+	produces := stringList(doc["produces"])
+	paths, _ := doc["paths"].(object)
+	for path, item := range paths {
+		item, ok := item.(object)
+		if !ok {
+			continue
+		}
+		converted := object{}
+		for key, value := range item {
+			switch key {
+			case "parameters":
+				if params, _ := splitParameters(value, nil); len(params) > 0 {
+					converted[key] = params
+				}
+			case "get", "put", "post", "delete", "options", "head", "patch":
+				if operation, ok := value.(object); ok {
+					converted[key] = convertOperation(operation, consumes, produces)
+				}
+			default:
+				converted[key] = value
+			}
+		}
+		out["paths"].(object)[path] = converted
+	}
+
+	return json.MarshalIndent(out, "", "    ")
+}
+
+// servers builds the server URLs from the document's schemes, host and base path
+func servers(doc object) []object {
+	host, _ := doc["host"].(string)
+	basePath, _ := doc["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			basePath = "/"
+		}
+		return []object{{"url": basePath}}
+	}
+
+	schemes := stringList(doc["schemes"])
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	list := make([]object, 0, len(schemes))
+	for _, scheme := range schemes {
+		list = append(list, object{"url": scheme + "://" + host + basePath})
+	}
+	return list
+}
+
+// convertOperation converts an operation, taking its body media types from consumes and its
+// response media types from produces unless it lists its own
+func convertOperation(operation object, consumes, produces []string) object {
+	if own := stringList(operation["consumes"]); len(own) > 0 {
+		consumes = own
+	}
+	if own := stringList(operation["produces"]); len(own) > 0 {
+		produces = own
+	}
+	if len(consumes) == 0 {
+		consumes = []string{defaultMediaType}
+	}
+	if len(produces) == 0 {
+		produces = []string{defaultMediaType}
+	}
+
+	out := object{}
+	for key, value := range operation {
+		switch key {
+		case "consumes", "produces", "schemes":
+		case "parameters":
+			params, body := splitParameters(value, consumes)
+			if len(params) > 0 {
+				out["parameters"] = params
+			}
+			if body != nil {
+				out["requestBody"] = body
+			}
+		case "responses":
+			responses := object{}
+			if list, ok := value.(object); ok {
+				for code, response := range list {
+					if response, ok := response.(object); ok {
+						responses[code] = convertResponse(response, produces)
+					}
+				}
+			}
+			out["responses"] = responses
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// splitParameters converts the path, query and header parameters of value and merges its body
+// and form parameters into a request body encoded as one of consumes
+func splitParameters(value interface{}, consumes []string) ([]object, object) {
+	list, _ := value.([]interface{})
+
+	var params []object
+	var body object
+	form := object{"type": "object", "properties": object{}}
+	var required []string
+	hasForm := false
+
+	for _, param := range list {
+		param, ok := param.(object)
+		if !ok {
+			continue
+		}
+		switch param["in"] {
+		case "body":
+			schema, _ := param["schema"].(object)
+			convertSchema(schema)
+			body = object{"content": content(consumes, schema)}
+			if description, ok := param["description"]; ok {
+				body["description"] = description
+			}
+			if isRequired, _ := param["required"].(bool); isRequired {
+				body["required"] = true
+			}
+		case "formData":
+			hasForm = true
+			name, _ := param["name"].(string)
+			property := moveSchemaKeys(param)
+			if description, ok := param["description"]; ok {
+				property["description"] = description
+			}
+			form["properties"].(object)[name] = property
+			if isRequired, _ := param["required"].(bool); isRequired {
+				required = append(required, name)
+			}
+		default:
+			params = append(params, convertParameter(param))
+		}
+	}
+
+	if hasForm && body == nil {
+		if len(required) > 0 {
+			form["required"] = required
+		}
+		mediaTypes := []string{"application/x-www-form-urlencoded"}
+		for _, mediaType := range consumes {
+			if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+				mediaTypes = []string{mediaType}
+				break
+			}
+		}
+		body = object{"content": content(mediaTypes, form)}
+		if len(required) > 0 {
+			body["required"] = true
+		}
+	}
+	return params, body
+}
+
+// convertParameter moves the type of a path, query or header parameter under schema
+func convertParameter(param object) object {
+	out := object{}
+	for key, value := range param {
+		switch key {
+		case "collectionFormat":
+			if value == "multi" {
+				out["explode"] = true
+			} else if value == "csv" {
+				out["explode"] = false
+			}
+		case "allowEmptyValue", "name", "in", "description", "required", "deprecated":
+			out[key] = value
+		default:
+			if strings.HasPrefix(key, "x-") {
+				out[key] = value
+			}
+		}
+	}
+	out["schema"] = moveSchemaKeys(param)
+	return out
+}
+
+// convertResponse moves a response's schema under one content entry per media type and the
+// types of its headers under schema
+func convertResponse(response object, produces []string) object {
+	out := object{"description": response["description"]}
+	if out["description"] == nil {
+		out["description"] = ""
+	}
+	if schema, ok := response["schema"].(object); ok {
+		convertSchema(schema)
+		out["content"] = content(produces, schema)
+	}
+	if headers, ok := response["headers"].(object); ok {
+		converted := object{}
+		for name, header := range headers {
+			if header, ok := header.(object); ok {
+				entry := object{"schema": moveSchemaKeys(header)}
+				if description, ok := header["description"]; ok {
+					entry["description"] = description
+				}
+				converted[name] = entry
+			}
+		}
+		out["headers"] = converted
+	}
+	copyExtensions(response, out)
+	return out
+}
+
+// content maps each media type to schema
+func content(mediaTypes []string, schema object) object {
+	out := object{}
+	for _, mediaType := range mediaTypes {
+		out[mediaType] = object{"schema": schema}
+	}
+	return out
+}
+
+// moveSchemaKeys returns a schema made of the type fields of a Swagger 2.0 parameter or header
+func moveSchemaKeys(value object) object {
+	schema := object{}
+	for _, key := range schemaKeys {
+		if v, ok := value[key]; ok {
+			schema[key] = v
+		}
+	}
+	convertSchema(schema)
+	return schema
+}
+
+// convertSchema rewrites the Swagger 2.0 specifics of schema and its subschemas in place:
+// x-nullable becomes nullable and files become binary strings
+func convertSchema(schema interface{}) {
+	switch schema := schema.(type) {
+	case object:
+		if nullable, ok := schema["x-nullable"]; ok {
+			schema["nullable"] = nullable
+			delete(schema, "x-nullable")
+		}
+		if schema["type"] == "file" {
+			schema["type"] = "string"
+			schema["format"] = "binary"
+		}
+		for _, value := range schema {
+			convertSchema(value)
+		}
+	case []interface{}:
+		for _, value := range schema {
+			convertSchema(value)
+		}
+	}
+}
+
+// securityScheme converts a security definition. API keys in the Authorization header become
+// bearer schemes, since that is how swag declares them
+func securityScheme(definition object) object {
+	out := object{}
+	if description, ok := definition["description"]; ok {
+		out["description"] = description
+	}
+
+	switch definition["type"] {
+	case "basic":
+		out["type"] = "http"
+		out["scheme"] = "basic"
+	case "apiKey":
+		name, _ := definition["name"].(string)
+		if definition["in"] == "header" && strings.EqualFold(name, "Authorization") {
+			out["type"] = "http"
+			out["scheme"] = "bearer"
+			out["bearerFormat"] = "JWT"
+		} else {
+			out["type"] = "apiKey"
+			out["in"] = definition["in"]
+			out["name"] = name
+		}
+	case "oauth2":
+		out["type"] = "oauth2"
+		flow := object{"scopes": definition["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = object{}
+		}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if value, ok := definition[key]; ok {
+				flow[key] = value
+			}
+		}
+		flowName, _ := definition["flow"].(string)
+		switch flowName {
+		case "accessCode":
+			flowName = "authorizationCode"
+		case "application":
+			flowName = "clientCredentials"
+		}
+		out["flows"] = object{flowName: flow}
+	default:
+		for key, value := range definition {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// rewriteRefs points every definition reference of value at the component schemas
+func rewriteRefs(value interface{}) {
+	switch value := value.(type) {
+	case object:
+		for key, v := range value {
+			if ref, ok := v.(string); ok && key == "$ref" {
+				value[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			rewriteRefs(v)
+		}
+	case []interface{}:
+		for _, v := range value {
+			rewriteRefs(v)
+		}
+	}
+}
+
+// copyExtensions copies the x- fields of from to to
+func copyExtensions(from, to object) {
+	for key, value := range from {
+		if strings.HasPrefix(key, "x-") {
+			to[key] = value
+		}
+	}
+}
+
+// stringList returns the strings of a JSON array
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Handler serves the OpenAPI 3.0 conversion of the Swagger document returned by readDoc. The
+// document is converted on the first request and reused afterwards
+func Handler(readDoc func() string) http.HandlerFunc {
+	var once sync.Once
+	var spec []byte
+	var err error
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			spec, err = Convert([]byte(readDoc()))
+		})
+		if err != nil {
+			http.Error(w, "Failed to convert API documentation", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	}
+}
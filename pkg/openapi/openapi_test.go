@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const swaggerDoc = `{
+	"swagger": "2.0",
+	"info": {"title": "Test API", "version": "1.0"},
+	"host": "localhost:8080",
+	"basePath": "/api/v1",
+	"schemes": ["http", "https"],
+	"paths": {
+		"/animals": {
+			"get": {
+				"produces": ["application/json", "application/xml"],
+				"parameters": [
+					{"type": "integer", "default": 1, "description": "Page number", "name": "page", "in": "query"},
+					{"type": "array", "items": {"type": "string"}, "collectionFormat": "csv", "name": "fields", "in": "query"}
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"schema": {"$ref": "#/definitions/model.Animal"},
+						"headers": {"X-Total-Count": {"type": "integer", "description": "Total records"}}
+					},
+					"400": {"description": "Bad Request"}
+				}
+			},
+			"post": {
+				"security": [{"BearerAuth": []}],
+				"consumes": ["application/json"],
+				"parameters": [
+					{"description": "Animal to create", "name": "animal", "in": "body", "required": true, "schema": {"$ref": "#/definitions/model.Animal"}}
+				],
+				"responses": {"201": {"description": "Created"}}
+			}
+		},
+		"/animals/import": {
+			"post": {
+				"consumes": ["multipart/form-data"],
+				"parameters": [
+					{"type": "file", "description": "CSV file", "name": "file", "in": "formData", "required": true}
+				],
+				"responses": {"200": {"description": "OK"}}
+			}
+		}
+	},
+	"definitions": {
+		"model.Animal": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "x-nullable": true},
+				"owner": {"$ref": "#/definitions/model.Owner"}
+			}
+		},
+		"model.Owner": {"type": "object"}
+	},
+	"securityDefinitions": {
+		"BearerAuth": {"type": "apiKey", "name": "Authorization", "in": "header", "description": "JWT"},
+		"ApiKeyAuth": {"type": "apiKey", "name": "X-API-Key", "in": "header"}
+	}
+}`
+
+// convertTestDoc converts swaggerDoc and decodes the result
+func convertTestDoc(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	out, err := Convert([]byte(swaggerDoc))
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	return doc
+}
+
+// lookup follows path through nested objects and arrays of doc
+func lookup(t *testing.T, doc interface{}, path ...interface{}) interface{} {
+	t.Helper()
+
+	for _, step := range path {
+		switch key := step.(type) {
+		case string:
+			object, ok := doc.(map[string]interface{})
+			require.True(t, ok, "expected an object at %q", key)
+			doc = object[key]
+		case int:
+			list, ok := doc.([]interface{})
+			require.True(t, ok, "expected an array at %d", key)
+			require.Less(t, key, len(list))
+			doc = list[key]
+		}
+	}
+	return doc
+}
+
+func TestConvert_Document(t *testing.T) {
+	doc := convertTestDoc(t)
+
+	assert.Equal(t, Version, doc["openapi"])
+	assert.Equal(t, "Test API", lookup(t, doc, "info", "title"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"url": "http://localhost:8080/api/v1"},
+		map[string]interface{}{"url": "https://localhost:8080/api/v1"},
+	}, doc["servers"])
+	assert.NotContains(t, doc, "definitions")
+	assert.NotContains(t, doc, "swagger")
+
+	// Definitions become component schemas and their references follow
+	assert.Equal(t, "#/components/schemas/model.Owner", lookup(t, doc, "components", "schemas", "model.Animal", "properties", "owner", "$ref"))
+	assert.Equal(t, true, lookup(t, doc, "components", "schemas", "model.Animal", "properties", "name", "nullable"))
+}
+
+func TestConvert_SecuritySchemes(t *testing.T) {
+	doc := convertTestDoc(t)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":         "http",
+		"scheme":       "bearer",
+		"bearerFormat": "JWT",
+		"description":  "JWT",
+	}, lookup(t, doc, "components", "securitySchemes", "BearerAuth"))
+	assert.Equal(t, map[string]interface{}{
+		"type": "apiKey",
+		"in":   "header",
+		"name": "X-API-Key",
+	}, lookup(t, doc, "components", "securitySchemes", "ApiKeyAuth"))
+	assert.Equal(t, []interface{}{map[string]interface{}{"BearerAuth": []interface{}{}}}, lookup(t, doc, "paths", "/animals", "post", "security"))
+}
+
+func TestConvert_Parameters(t *testing.T) {
+	doc := convertTestDoc(t)
+
+	assert.Equal(t, map[string]interface{}{
+		"name":        "page",
+		"in":          "query",
+		"description": "Page number",
+		"schema":      map[string]interface{}{"type": "integer", "default": float64(1)},
+	}, lookup(t, doc, "paths", "/animals", "get", "parameters", 0))
+	assert.Equal(t, map[string]interface{}{
+		"name":    "fields",
+		"in":      "query",
+		"explode": false,
+		"schema":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+	}, lookup(t, doc, "paths", "/animals", "get", "parameters", 1))
+}
+
+func TestConvert_RequestBodies(t *testing.T) {
+	doc := convertTestDoc(t)
+
+	create := lookup(t, doc, "paths", "/animals", "post").(map[string]interface{})
+	assert.NotContains(t, create, "parameters")
+	assert.NotContains(t, create, "consumes")
+	assert.Equal(t, map[string]interface{}{
+		"description": "Animal to create",
+		"required":    true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/model.Animal"},
+			},
+		},
+	}, create["requestBody"])
+
+	// Form parameters become the properties of a form body; files become binary strings
+	assert.Equal(t, map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"multipart/form-data": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"file"},
+					"properties": map[string]interface{}{
+						"file": map[string]interface{}{"type": "string", "format": "binary", "description": "CSV file"},
+					},
+				},
+			},
+		},
+	}, lookup(t, doc, "paths", "/animals/import", "post", "requestBody"))
+}
+
+func TestConvert_Responses(t *testing.T) {
+	doc := convertTestDoc(t)
+
+	ok := lookup(t, doc, "paths", "/animals", "get", "responses", "200").(map[string]interface{})
+	assert.Equal(t, "OK", ok["description"])
+	assert.NotContains(t, ok, "schema")
+	schema := map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/model.Animal"}}
+	assert.Equal(t, map[string]interface{}{"application/json": schema, "application/xml": schema}, ok["content"])
+	assert.Equal(t, map[string]interface{}{
+		"X-Total-Count": map[string]interface{}{"description": "Total records", "schema": map[string]interface{}{"type": "integer"}},
+	}, ok["headers"])
+
+	// Responses without a body have no content
+	assert.Equal(t, map[string]interface{}{"description": "Bad Request"}, lookup(t, doc, "paths", "/animals", "get", "responses", "400"))
+}
+
+func TestConvert_Errors(t *testing.T) {
+	_, err := Convert([]byte(`{"openapi":"3.0.0"}`))
+	assert.ErrorContains(t, err, "unsupported Swagger version")
+
+	_, err = Convert([]byte(`{`))
+	assert.ErrorContains(t, err, "failed to parse Swagger document")
+}
+
+func TestHandler(t *testing.T) {
+	reads := 0
+	handler := Handler(func() string {
+		reads++
+		return swaggerDoc
+	})
+
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `"openapi": "3.0.3"`)
+	}
+	assert.Equal(t, 1, reads, "the document is converted once")
+
+	rr := httptest.NewRecorder()
+	Handler(func() string { return "{}" }).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -1,51 +1,102 @@
 package pagination
 
 import (
-	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 )
 
-// DefaultLimit is the default number of items per page
+// DefaultLimit is the default number of items per page until SetLimits is called
 const DefaultLimit = 10
 
-// MaxLimit is the maximum number of items per page
+// MaxLimit is the maximum number of items per page until SetLimits is called
 const MaxLimit = 100
 
+// Page size limits applied by NewParams
+var (
+	limitsMu     sync.RWMutex
+	defaultLimit = DefaultLimit
+	maxLimit     = MaxLimit
+)
+
+// SetLimits configures the number of items per page used when a request doesn't set a limit,
+// and the largest limit a request may ask for. Non-positive values restore the built-in defaults,
+// and a default above the maximum is lowered to it
+func SetLimits(defaultSize, maxSize int) {
+	if defaultSize <= 0 {
+		defaultSize = DefaultLimit
+	}
+	if maxSize <= 0 {
+		maxSize = MaxLimit
+	}
+	if defaultSize > maxSize {
+		defaultSize = maxSize
+	}
+
+	limitsMu.Lock()
+	defaultLimit, maxLimit = defaultSize, maxSize
+	limitsMu.Unlock()
+}
+
+// Limits returns the configured default and maximum number of items per page
+func Limits() (defaultSize, maxSize int) {
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return defaultLimit, maxLimit
+}
+
 // Params represents pagination parameters
 type Params struct {
-	Page       int   `json:"page"`
-	Limit      int   `json:"limit"`
-	TotalItems int64 `json:"total_items"`
-	TotalPages int   `json:"total_pages"`
+	Page       int   `json:"page" xml:"page"`
+	Limit      int   `json:"limit" xml:"limit"`
+	TotalItems int64 `json:"total_items" xml:"total_items"`
+	TotalPages int   `json:"total_pages" xml:"total_pages"`
 }
 
 // PagedData represents a paginated data response
 type PagedData struct {
-	Items      interface{} `json:"items"`
-	Pagination Params      `json:"pagination"`
-	CacheInfo  interface{} `json:"cacheInfo,omitempty"`
+	Items      interface{} `json:"items" xml:"items>item"`
+	Pagination Params      `json:"pagination" xml:"pagination"`
+	Links      *Links      `json:"links,omitempty" xml:"links,omitempty"`
+	CacheInfo  interface{} `json:"cacheInfo,omitempty" xml:"cacheInfo,omitempty"`
+}
+
+// Links holds navigation URLs for a page of results
+// Next and Prev are omitted on the last and first page respectively
+type Links struct {
+	Self  string `json:"self" xml:"self"`
+	First string `json:"first" xml:"first"`
+	Last  string `json:"last" xml:"last"`
+	Next  string `json:"next,omitempty" xml:"next,omitempty"`
+	Prev  string `json:"prev,omitempty" xml:"prev,omitempty"`
 }
 
 // NewParams creates a new pagination parameters from HTTP request
 func NewParams(r *http.Request) Params {
 	query := r.URL.Query()
 
-	// Parse page number
-	page, err := strconv.Atoi(query.Get("page"))
-	if err != nil || page < 1 {
+	// Unparseable values become 0 and get the defaults
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	return ParamsFor(page, limit)
+}
+
+// ParamsFor creates pagination parameters for a page and limit from any source, applying the
+// same defaults and maximum as NewParams. A page or limit below 1 gets the default
+func ParamsFor(page, limit int) Params {
+	if page < 1 {
 		page = 1
 	}
 
-	// Parse items per page
-	limit, err := strconv.Atoi(query.Get("limit"))
-	if err != nil || limit < 1 {
-		limit = DefaultLimit
+	defaultSize, maxSize := Limits()
+	if limit < 1 {
+		limit = defaultSize
 	}
 
 	// Enforce maximum limit
-	if limit > MaxLimit {
-		limit = MaxLimit
+	if limit > maxSize {
+		limit = maxSize
 	}
 
 	return Params{
@@ -60,9 +111,21 @@ func (p Params) GetOffset() int {
 }
 
 // CalculatePages calculates total pages based on total items
+// An empty result, or a non-positive limit, has zero pages
 func (p *Params) CalculatePages(totalItems int64) {
 	p.TotalItems = totalItems
-	p.TotalPages = int(math.Ceil(float64(totalItems) / float64(p.Limit)))
+	p.TotalPages = 0
+	if totalItems > 0 && p.Limit > 0 {
+		p.TotalPages = int((totalItems + int64(p.Limit) - 1) / int64(p.Limit))
+	}
+}
+
+// SinglePage describes an unpaginated result of totalItems records as its only page, so it
+// carries the same metadata as a paginated one
+func SinglePage(totalItems int64) *Params {
+	p := &Params{Page: 1, Limit: int(totalItems)}
+	p.CalculatePages(totalItems)
+	return p
 }
 
 // HasPreviousPage returns true if there is a previous page
@@ -90,3 +153,32 @@ func (p Params) GetNextPage() int {
 	}
 	return p.Page + 1
 }
+
+// NewLinks builds navigation links for p from the request URL, keeping its path and
+// query parameters and replacing only the page. An empty result links to page 1 as its last page
+func NewLinks(u *url.URL, p Params) *Links {
+	lastPage := p.TotalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := &Links{
+		Self:  pageURL(u, p.Page),
+		First: pageURL(u, 1),
+		Last:  pageURL(u, lastPage),
+	}
+	if p.HasNextPage() {
+		links.Next = pageURL(u, p.GetNextPage())
+	}
+	if p.HasPreviousPage() {
+		links.Prev = pageURL(u, p.GetPreviousPage())
+	}
+	return links
+}
+
+// pageURL returns the path and query of u with the page parameter set to page
+func pageURL(u *url.URL, page int) string {
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	return u.Path + "?" + query.Encode()
+}
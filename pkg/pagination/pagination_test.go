@@ -0,0 +1,147 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParams_ConfiguredLimits(t *testing.T) {
+	SetLimits(20, 50)
+	t.Cleanup(func() { SetLimits(DefaultLimit, MaxLimit) })
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedLimit int
+	}{
+		{name: "NoLimit", query: "", expectedLimit: 20},
+		{name: "InvalidLimit", query: "limit=abc", expectedLimit: 20},
+		{name: "WithinMax", query: "limit=30", expectedLimit: 30},
+		{name: "AtMax", query: "limit=50", expectedLimit: 50},
+		{name: "AboveMax", query: "limit=500", expectedLimit: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParams(httptest.NewRequest("GET", "/animals?"+tt.query, nil))
+			assert.Equal(t, tt.expectedLimit, p.Limit)
+		})
+	}
+}
+
+func TestSetLimits(t *testing.T) {
+	t.Cleanup(func() { SetLimits(DefaultLimit, MaxLimit) })
+
+	// Non-positive values fall back to the built-in defaults
+	SetLimits(0, -1)
+	defaultSize, maxSize := Limits()
+	assert.Equal(t, DefaultLimit, defaultSize)
+	assert.Equal(t, MaxLimit, maxSize)
+
+	// The default never exceeds the maximum
+	SetLimits(80, 25)
+	defaultSize, maxSize = Limits()
+	assert.Equal(t, 25, defaultSize)
+	assert.Equal(t, 25, maxSize)
+}
+
+func TestCalculatePages(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		totalItems    int64
+		expectedPages int
+	}{
+		{name: "Empty", limit: 10, totalItems: 0, expectedPages: 0},
+		{name: "ZeroLimit", limit: 0, totalItems: 25, expectedPages: 0},
+		{name: "NegativeLimit", limit: -5, totalItems: 25, expectedPages: 0},
+		{name: "ExactMultiple", limit: 10, totalItems: 30, expectedPages: 3},
+		{name: "PartialLastPage", limit: 10, totalItems: 31, expectedPages: 4},
+		{name: "SinglePage", limit: 10, totalItems: 3, expectedPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Params{Page: 1, Limit: tt.limit}
+			p.CalculatePages(tt.totalItems)
+
+			assert.Equal(t, tt.totalItems, p.TotalItems)
+			assert.Equal(t, tt.expectedPages, p.TotalPages)
+		})
+	}
+}
+
+func TestSinglePage(t *testing.T) {
+	assert.Equal(t, &Params{Page: 1, Limit: 3, TotalItems: 3, TotalPages: 1}, SinglePage(3))
+	assert.Equal(t, &Params{Page: 1}, SinglePage(0), "an empty result should have no pages")
+	assert.False(t, SinglePage(3).HasNextPage())
+}
+
+func TestNewLinks(t *testing.T) {
+	u, err := url.Parse("/api/v1/animals?limit=10&page=2&species=cat")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		page       int
+		totalItems int64
+		expected   Links
+	}{
+		{
+			name:       "Empty",
+			page:       1,
+			totalItems: 0,
+			expected: Links{
+				Self:  "/api/v1/animals?limit=10&page=1&species=cat",
+				First: "/api/v1/animals?limit=10&page=1&species=cat",
+				Last:  "/api/v1/animals?limit=10&page=1&species=cat",
+			},
+		},
+		{
+			name:       "SinglePage",
+			page:       1,
+			totalItems: 7,
+			expected: Links{
+				Self:  "/api/v1/animals?limit=10&page=1&species=cat",
+				First: "/api/v1/animals?limit=10&page=1&species=cat",
+				Last:  "/api/v1/animals?limit=10&page=1&species=cat",
+			},
+		},
+		{
+			name:       "MiddlePage",
+			page:       2,
+			totalItems: 25,
+			expected: Links{
+				Self:  "/api/v1/animals?limit=10&page=2&species=cat",
+				First: "/api/v1/animals?limit=10&page=1&species=cat",
+				Last:  "/api/v1/animals?limit=10&page=3&species=cat",
+				Next:  "/api/v1/animals?limit=10&page=3&species=cat",
+				Prev:  "/api/v1/animals?limit=10&page=1&species=cat",
+			},
+		},
+		{
+			name:       "LastPage",
+			page:       3,
+			totalItems: 25,
+			expected: Links{
+				Self:  "/api/v1/animals?limit=10&page=3&species=cat",
+				First: "/api/v1/animals?limit=10&page=1&species=cat",
+				Last:  "/api/v1/animals?limit=10&page=3&species=cat",
+				Prev:  "/api/v1/animals?limit=10&page=2&species=cat",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Params{Page: tt.page, Limit: 10}
+			p.CalculatePages(tt.totalItems)
+
+			assert.Equal(t, tt.expected, *NewLinks(u, p))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package response
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GenerateETag creates a strong ETag for a resource from its ID and last update time
+func GenerateETag(id interface{}, updatedAt time.Time) string {
+	h := sha256.New()
+	h.Write([]byte(fmt.Sprintf("%v:%d", id, updatedAt.UnixNano())))
+	return fmt.Sprintf(`"%x"`, h.Sum(nil)[:16])
+}
+
+// ETagFor returns the ETag of the representation of a resource the request negotiates, given the
+// resource's etag. JSON keeps etag; JSON:API, XML and MessagePack each get their own, so a cache
+// never revalidates one format with another's ETag
+func ETagFor(r *http.Request, etag string) string {
+	mediaType := MediaTypeJSON
+	if WantsJSONAPI(r) {
+		mediaType = JSONAPIMediaType
+	} else if r != nil {
+		mediaType = negotiate(r.Header.Get("Accept"))
+	}
+	if etag == "" || mediaType == MediaTypeJSON || mediaType == "" {
+		return etag
+	}
+
+	h := sha256.Sum256([]byte(etag + ":" + mediaType))
+	return fmt.Sprintf(`"%x"`, h[:16])
+}
+
+// MatchesETag returns true if the request's If-None-Match header matches the given ETag
+func MatchesETag(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" || etag == "" {
+		return false
+	}
+
+	// If-None-Match may contain a list of ETags or a wildcard
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		// Weak comparison ignores the W/ prefix
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModified sends a 304 Not Modified response with an empty body
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// SuccessWithETag sends a successful response with data and an ETag header
+func SuccessWithETag(w http.ResponseWriter, r *http.Request, data interface{}, message string, etag string) {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	Success(w, r, data, message)
+}
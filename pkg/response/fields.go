@@ -0,0 +1,122 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Record is a JSON object trimmed to the fields a client selected. Values are kept as raw JSON
+// to encode exactly as they would in the full object
+type Record map[string]json.RawMessage
+
+// SelectFields encodes v as a JSON object and keeps only the given keys, so clients that
+// asked for a sparse fieldset don't receive the zero values of the fields they skipped
+func SelectFields(v interface{}, fields []string) (Record, error) {
+	all, err := encodeObject(v)
+	if err != nil {
+		return nil, err
+	}
+	return selectMembers(all, fields), nil
+}
+
+// MarshalXML encodes each member as a child element in key order, with JSON strings unquoted
+func (rec Record) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(rec))
+	for key := range rec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		text := string(rec[key])
+		var s string
+		if err := json.Unmarshal(rec[key], &s); err == nil {
+			text = s
+		} else if text == "null" {
+			text = ""
+		}
+		if err := e.EncodeElement(text, xml.StartElement{Name: xml.Name{Local: key}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// EncodeMsgpack encodes the record as a map of its decoded values
+func (rec Record) EncodeMsgpack(enc *msgpack.Encoder) error {
+	decoded := make(map[string]interface{}, len(rec))
+	for key, raw := range rec {
+		value, err := decodeJSONValue(raw)
+		if err != nil {
+			return err
+		}
+		decoded[key] = value
+	}
+	return enc.Encode(decoded)
+}
+
+// decodeJSONValue decodes raw JSON, keeping integers as int64 rather than float64
+// so large IDs survive re-encoding
+func decodeJSONValue(raw json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(value), nil
+}
+
+// normalizeNumbers replaces the json.Numbers in a decoded value with int64 or float64
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, member := range v {
+			v[key] = normalizeNumbers(member)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = normalizeNumbers(element)
+		}
+	}
+	return value
+}
+
+// encodeObject encodes v as a JSON object and returns its members
+func encodeObject(v interface{}) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// selectMembers returns the members of an encoded object named in fields
+func selectMembers(members map[string]json.RawMessage, fields []string) map[string]json.RawMessage {
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := members[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected
+}
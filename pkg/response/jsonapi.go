@@ -0,0 +1,120 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/linkeunid/go-api/pkg/validator"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents (https://jsonapi.org)
+const JSONAPIMediaType = "application/vnd.api+json"
+
+// JSONAPIDocument is a top-level JSON:API document. A document holds either Data or Errors, never both
+type JSONAPIDocument struct {
+	Data   interface{}            `json:"data,omitempty"`
+	Errors []JSONAPIError         `json:"errors,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+	Links  interface{}            `json:"links,omitempty"`
+}
+
+// JSONAPIResource is a JSON:API resource object
+type JSONAPIResource struct {
+	Type       string                     `json:"type"`
+	ID         string                     `json:"id"`
+	Attributes map[string]json.RawMessage `json:"attributes"`
+}
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	Status string `json:"status"`
+	Code   string `json:"code,omitempty"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// WantsJSONAPI reports whether the client asked for a JSON:API document in its Accept header.
+// Any other Accept value, including none, gets the default APIResponse envelope
+func WantsJSONAPI(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		// A quality of 0 means the client refuses the type
+		if err == nil && mediaType == JSONAPIMediaType && params["q"] != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// NewJSONAPIResource converts v, which must encode as a JSON object with an "id" member, into a
+// resource of the given type. Every other member becomes an attribute; when fields is non-empty
+// only the listed attributes are kept
+func NewJSONAPIResource(resourceType string, v interface{}, fields []string) (JSONAPIResource, error) {
+	attributes, err := encodeObject(v)
+	if err != nil {
+		return JSONAPIResource{}, err
+	}
+
+	rawID, ok := attributes["id"]
+	if !ok {
+		return JSONAPIResource{}, fmt.Errorf("%s resource has no id", resourceType)
+	}
+	delete(attributes, "id")
+
+	// JSON:API IDs are always strings
+	id := string(rawID)
+	if unquoted, err := strconv.Unquote(id); err == nil {
+		id = unquoted
+	}
+
+	if len(fields) > 0 {
+		attributes = selectMembers(attributes, fields)
+	}
+
+	return JSONAPIResource{Type: resourceType, ID: id, Attributes: attributes}, nil
+}
+
+// JSONAPI sends a JSON:API document with the given status code
+func JSONAPI(w http.ResponseWriter, r *http.Request, statusCode int, doc JSONAPIDocument) {
+	w.Header().Set("Content-Type", JSONAPIMediaType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// jsonAPIErrors converts an error response into JSON:API error objects, one per failed field
+// for validation errors
+func jsonAPIErrors(statusCode int, resp APIResponse) []JSONAPIError {
+	status := strconv.Itoa(statusCode)
+
+	if validationErrors, ok := resp.Data.([]validator.ValidationError); ok && len(validationErrors) > 0 {
+		errs := make([]JSONAPIError, 0, len(validationErrors))
+		for _, e := range validationErrors {
+			errs = append(errs, JSONAPIError{
+				Status: status,
+				Code:   resp.ErrorCode,
+				Title:  resp.Message,
+				Detail: e.Error,
+			})
+		}
+		return errs
+	}
+
+	return []JSONAPIError{{
+		Status: status,
+		Code:   resp.ErrorCode,
+		Title:  resp.Message,
+		Detail: resp.Error,
+	}}
+}
@@ -0,0 +1,136 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// camelCaseKeys rewrites the keys of JSON responses in camelCase
+var camelCaseKeys atomic.Bool
+
+// SetCamelCaseKeys configures whether JSON responses have their keys rewritten from the
+// snake_case the models declare to camelCase, e.g. created_at becomes createdAt. Only keys
+// change; values such as field names in validation errors are sent as they are
+func SetCamelCaseKeys(enabled bool) {
+	camelCaseKeys.Store(enabled)
+}
+
+// encodeJSON writes payload to buf as JSON, in camelCase when SetCamelCaseKeys is enabled
+func encodeJSON(buf *bytes.Buffer, payload interface{}) error {
+	if !camelCaseKeys.Load() {
+		return json.NewEncoder(buf).Encode(payload)
+	}
+
+	var encoded bytes.Buffer
+	if err := json.NewEncoder(&encoded).Encode(payload); err != nil {
+		return err
+	}
+	if err := camelizeJSON(buf, encoded.Bytes()); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of v, in camelCase when SetCamelCaseKeys is enabled, for
+// bodies written outside of Encode such as event streams and export files
+func MarshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// jsonContainer tracks an object or array camelizeJSON is inside of
+type jsonContainer struct {
+	object bool
+	tokens int // Keys and values written so far
+}
+
+// camelizeJSON copies the JSON document data to buf with every object key in camelCase,
+// at any depth. Keys keep their order and values are copied unchanged
+func camelizeJSON(buf *bytes.Buffer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var stack []jsonContainer
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		isKey := false
+		if delim, ok := tok.(json.Delim); !ok || delim == '{' || delim == '[' {
+			if n := len(stack); n > 0 {
+				top := &stack[n-1]
+				switch {
+				case top.object && top.tokens%2 == 1:
+					buf.WriteByte(':')
+				case top.tokens > 0:
+					buf.WriteByte(',')
+				}
+				isKey = top.object && top.tokens%2 == 0
+				top.tokens++
+			}
+		}
+
+		switch tok := tok.(type) {
+		case json.Delim:
+			buf.WriteRune(rune(tok))
+			if tok == '{' || tok == '[' {
+				stack = append(stack, jsonContainer{object: tok == '{'})
+			} else {
+				stack = stack[:len(stack)-1]
+			}
+		case string:
+			if isKey {
+				tok = camelCase(tok)
+			}
+			if err := writeJSONValue(buf, tok); err != nil {
+				return err
+			}
+		default:
+			if err := writeJSONValue(buf, tok); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeJSONValue writes a scalar token the way json.Encoder would, without the trailing newline
+func writeJSONValue(buf *bytes.Buffer, v interface{}) error {
+	var encoded bytes.Buffer
+	if err := json.NewEncoder(&encoded).Encode(v); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return nil
+}
+
+// camelCase converts a snake_case key to camelCase, e.g. error_code to errorCode. Keys without
+// underscores, including those already in camelCase, are returned unchanged
+func camelCase(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+
+	parts := strings.Split(key, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
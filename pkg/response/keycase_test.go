@@ -0,0 +1,119 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"name":             "name",
+		"created_at":       "createdAt",
+		"error_code":       "errorCode",
+		"is_cache_enabled": "isCacheEnabled",
+		"cacheInfo":        "cacheInfo",
+		"trailing_":        "trailing",
+	}
+	for key, expected := range tests {
+		assert.Equal(t, expected, camelCase(key), key)
+	}
+}
+
+func TestCamelizeJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Flat object",
+			input:    `{"first_name":"Fluffy","age":3}`,
+			expected: `{"firstName":"Fluffy","age":3}`,
+		},
+		{
+			name:     "Nested objects",
+			input:    `{"data":{"owner_info":{"last_name":"Doe","is_active":true}},"request_id":null}`,
+			expected: `{"data":{"ownerInfo":{"lastName":"Doe","isActive":true}},"requestId":null}`,
+		},
+		{
+			name:     "Arrays of objects",
+			input:    `{"total_items":2,"items":[{"created_at":"2025-01-01"},{"created_at":"2025-01-02","tag_list":["a_b",[1,{"x_y":2}]]}]}`,
+			expected: `{"totalItems":2,"items":[{"createdAt":"2025-01-01"},{"createdAt":"2025-01-02","tagList":["a_b",[1,{"xY":2}]]}]}`,
+		},
+		{
+			name:     "String values are kept",
+			input:    `{"field":"created_at","values":["snake_case"]}`,
+			expected: `{"field":"created_at","values":["snake_case"]}`,
+		},
+		{
+			name:     "Empty containers and large numbers",
+			input:    `{"empty_map":{},"empty_list":[],"big_id":18446744073709551615,"ratio":0.5}`,
+			expected: `{"emptyMap":{},"emptyList":[],"bigId":18446744073709551615,"ratio":0.5}`,
+		},
+		{
+			name:     "Top-level array",
+			input:    `[{"a_b":1},{"c_d":2}]`,
+			expected: `[{"aB":1},{"cD":2}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, camelizeJSON(&buf, []byte(tt.input)))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestEncode_CamelCaseKeys(t *testing.T) {
+	type owner struct {
+		FirstName string `json:"first_name"`
+	}
+	type pet struct {
+		PetName string  `json:"pet_name"`
+		Owners  []owner `json:"owners"`
+	}
+
+	SetCamelCaseKeys(true)
+	t.Cleanup(func() { SetCamelCaseKeys(false) })
+
+	rr := httptest.NewRecorder()
+	Success(rr, request(""), pet{PetName: "Fluffy", Owners: []owner{{FirstName: "Ann"}}}, "ok")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"data":{"petName":"Fluffy","owners":[{"firstName":"Ann"}]}`)
+
+	rr = httptest.NewRecorder()
+	Error(rr, request(""), http.StatusNotFound, CodeNotFound, "missing")
+	assert.Contains(t, rr.Body.String(), `"errorCode":"NOT_FOUND"`)
+}
+
+func TestEncode_SnakeCaseKeysByDefault(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Error(rr, request(""), http.StatusNotFound, CodeNotFound, "missing")
+
+	assert.Contains(t, rr.Body.String(), `"error_code":"NOT_FOUND"`)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	type pet struct {
+		PetName string `json:"pet_name"`
+	}
+
+	data, err := MarshalJSON(pet{PetName: "Fluffy"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"pet_name":"Fluffy"}`, string(data))
+
+	SetCamelCaseKeys(true)
+	t.Cleanup(func() { SetCamelCaseKeys(false) })
+
+	data, err = MarshalJSON(pet{PetName: "Fluffy"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"petName":"Fluffy"}`, string(data))
+}
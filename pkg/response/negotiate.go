@@ -0,0 +1,187 @@
+package response
+
+import (
+	"bytes"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Media types responses can be encoded as
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeXML     = "application/xml"
+	MediaTypeMsgpack = "application/msgpack"
+)
+
+// acceptedMediaTypes maps the media ranges a client may send in its Accept header to the
+// media type the response is encoded as. Wildcards get JSON, the default format
+var acceptedMediaTypes = map[string]string{
+	"*/*":                     MediaTypeJSON,
+	"application/*":           MediaTypeJSON,
+	MediaTypeJSON:             MediaTypeJSON,
+	JSONAPIMediaType:          MediaTypeJSON,
+	MediaTypeXML:              MediaTypeXML,
+	"text/xml":                MediaTypeXML,
+	MediaTypeMsgpack:          MediaTypeMsgpack,
+	"application/x-msgpack":   MediaTypeMsgpack,
+	"application/vnd.msgpack": MediaTypeMsgpack,
+}
+
+// negotiate returns the media type to encode a response as, picking the supported type the
+// Accept header gives the highest quality, with JSON winning ties. Clients whose preferred types
+// are all unsupported but that accept */* get JSON rather than a lower preference, so browsers,
+// which rank application/xml above */*, get JSON. It returns "" when nothing supported is
+// acceptable
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypeJSON
+	}
+
+	best, bestQuality, topQuality, wildcard := "", 0.0, 0.0, false
+	for _, accepted := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		topQuality = max(topQuality, quality)
+
+		mediaType, ok := acceptedMediaTypes[mediaRange]
+		if !ok || quality <= 0 {
+			continue
+		}
+		if mediaRange == "*/*" {
+			wildcard = true
+		}
+		if quality > bestQuality || (quality == bestQuality && mediaType == MediaTypeJSON) {
+			best, bestQuality = mediaType, quality
+		}
+	}
+
+	if bestQuality < topQuality && wildcard {
+		return MediaTypeJSON
+	}
+	return best
+}
+
+// Encode writes payload with the given status code in the format the request's Accept header
+// prefers: JSON by default, XML or MessagePack on request. Clients that accept none of them
+// get 406 Not Acceptable, except for errors, which are sent as JSON with their own status
+func Encode(w http.ResponseWriter, r *http.Request, statusCode int, payload interface{}) {
+	mediaType := MediaTypeJSON
+	if r != nil {
+		mediaType = negotiate(r.Header.Get("Accept"))
+	}
+	if mediaType == "" {
+		if statusCode < http.StatusBadRequest {
+			NotAcceptable(w, r)
+			return
+		}
+		mediaType = MediaTypeJSON
+	}
+
+	// Encode before writing the status so a failure can still be reported
+	var buf bytes.Buffer
+	var err error
+	switch mediaType {
+	case MediaTypeXML:
+		buf.WriteString(xml.Header)
+		err = xml.NewEncoder(&buf).Encode(payload)
+	case MediaTypeMsgpack:
+		// Reuse the JSON field names so every format has the same shape
+		encoder := msgpack.NewEncoder(&buf)
+		encoder.SetCustomStructTag("json")
+		err = encoder.Encode(payload)
+	default:
+		err = encodeJSON(&buf, payload)
+	}
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// NotAcceptable sends a 406 Not Acceptable response, as JSON since the client accepts
+// none of the supported formats
+func NotAcceptable(w http.ResponseWriter, r *http.Request) {
+	resp := APIResponse{
+		Success:   false,
+		Message:   "Not acceptable",
+		ErrorCode: CodeNotAcceptable,
+		Error:     "Supported media types are " + strings.Join([]string{MediaTypeJSON, MediaTypeXML, MediaTypeMsgpack}, ", "),
+		Timestamp: time.Now(),
+	}
+	if r != nil {
+		resp.RequestID = chimiddleware.GetReqID(r.Context())
+	}
+
+	var buf bytes.Buffer
+	_ = encodeJSON(&buf, resp)
+
+	w.Header().Set("Content-Type", MediaTypeJSON)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusNotAcceptable)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// MarshalXML encodes the response under a <response> root element. Map data, which
+// encoding/xml can't encode, is written as one child element per key
+func (resp APIResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	// plain has the same fields without this method, so encoding it doesn't recurse
+	type plain APIResponse
+	p := plain(resp)
+	if p.Data != nil {
+		p.Data = xmlValue{p.Data}
+	}
+
+	start.Name.Local = "response"
+	return e.EncodeElement(p, start)
+}
+
+// xmlValue encodes maps with string keys as one child element per key, in key order,
+// and any other value the way encoding/xml does
+type xmlValue struct {
+	v interface{}
+}
+
+// MarshalXML implements xml.Marshaler
+func (x xmlValue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	rv := reflect.ValueOf(x.v)
+	if _, ok := x.v.(xml.Marshaler); ok || rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return e.EncodeElement(x.v, start)
+	}
+
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		child := xml.StartElement{Name: xml.Name{Local: key.String()}}
+		if err := e.EncodeElement(xmlValue{rv.MapIndex(key).Interface()}, child); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
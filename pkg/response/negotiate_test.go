@@ -0,0 +1,198 @@
+package response
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type testItem struct {
+	ID   uint64 `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "NoHeader", accept: "", expected: MediaTypeJSON},
+		{name: "Wildcard", accept: "*/*", expected: MediaTypeJSON},
+		{name: "JSON", accept: "application/json", expected: MediaTypeJSON},
+		{name: "XML", accept: "application/xml", expected: MediaTypeXML},
+		{name: "TextXML", accept: "text/xml", expected: MediaTypeXML},
+		{name: "Msgpack", accept: "application/x-msgpack", expected: MediaTypeMsgpack},
+		{name: "HighestQualityWins", accept: "application/json;q=0.5, application/xml;q=0.9", expected: MediaTypeXML},
+		{name: "UnsupportedSkipped", accept: "text/html, application/msgpack;q=0.1", expected: MediaTypeMsgpack},
+		{name: "TieGoesToJSON", accept: "application/xml, application/json", expected: MediaTypeJSON},
+		{name: "WildcardTieGoesToJSON", accept: "application/msgpack, */*", expected: MediaTypeJSON},
+		{name: "WildcardBelowXML", accept: "application/xml, */*;q=0.1", expected: MediaTypeXML},
+		{name: "Browser", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", expected: MediaTypeJSON},
+		{name: "BrowserWithImages", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8", expected: MediaTypeJSON},
+		{name: "Unsupported", accept: "text/html", expected: ""},
+		{name: "Refused", accept: "application/json;q=0", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiate(tt.accept))
+		})
+	}
+}
+
+// request creates a GET request accepting the given media type
+func request(accept string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept", accept)
+	return req
+}
+
+func TestEncode_JSON(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Success(rr, request(""), testItem{ID: 1, Name: "Fluffy"}, "ok")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, MediaTypeJSON, rr.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+
+	var resp struct {
+		Success bool     `json:"success"`
+		Data    testItem `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, testItem{ID: 1, Name: "Fluffy"}, resp.Data)
+}
+
+func TestEncode_XML(t *testing.T) {
+	t.Run("Struct", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		paged := pagination.PagedData{Items: []testItem{{ID: 1, Name: "Fluffy"}, {ID: 2, Name: "Rex"}}}
+		Success(rr, request(MediaTypeXML), paged, "ok")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, MediaTypeXML, rr.Header().Get("Content-Type"))
+
+		var resp struct {
+			XMLName xml.Name   `xml:"response"`
+			Success bool       `xml:"success"`
+			Items   []testItem `xml:"data>items>item"`
+		}
+		require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.True(t, resp.Success)
+		assert.Equal(t, []testItem{{ID: 1, Name: "Fluffy"}, {ID: 2, Name: "Rex"}}, resp.Items)
+	})
+
+	t.Run("MapAndRecord", func(t *testing.T) {
+		record, err := SelectFields(testItem{ID: 1, Name: "Fluffy"}, []string{"name"})
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		Success(rr, request(MediaTypeXML), map[string]interface{}{"record": record, "count": 1}, "ok")
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp struct {
+			Count int    `xml:"data>count"`
+			Name  string `xml:"data>record>name"`
+		}
+		require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Count)
+		assert.Equal(t, "Fluffy", resp.Name)
+	})
+}
+
+func TestEncode_Msgpack(t *testing.T) {
+	record, err := SelectFields(testItem{ID: 1 << 60, Name: "Fluffy"}, []string{"id", "name"})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	NotFound(rr, request(MediaTypeMsgpack), "Item not found")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, MediaTypeMsgpack, rr.Header().Get("Content-Type"))
+
+	var errResp map[string]interface{}
+	require.NoError(t, msgpack.Unmarshal(rr.Body.Bytes(), &errResp))
+	assert.Equal(t, false, errResp["success"])
+	assert.Equal(t, "Item not found", errResp["message"])
+
+	rr = httptest.NewRecorder()
+	Success(rr, request(MediaTypeMsgpack), record, "ok")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Field names match the JSON encoding and large integers keep their precision
+	var resp struct {
+		Success bool     `msgpack:"success"`
+		Data    testItem `msgpack:"data"`
+	}
+	decoder := msgpack.NewDecoder(rr.Body)
+	decoder.SetCustomStructTag("json")
+	require.NoError(t, decoder.Decode(&resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, testItem{ID: 1 << 60, Name: "Fluffy"}, resp.Data)
+}
+
+func TestEncode_Browser(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Success(rr, request("text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"), testItem{ID: 1}, "ok")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, MediaTypeJSON, rr.Header().Get("Content-Type"))
+}
+
+func TestEncode_ErrorIgnoresUnsupportedAccept(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NotFound(rr, request("text/html"), "missing")
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, MediaTypeJSON, rr.Header().Get("Content-Type"))
+
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, CodeNotFound, resp.ErrorCode)
+}
+
+func TestEncode_NotAcceptable(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Success(rr, request("text/html"), testItem{ID: 1}, "ok")
+
+	assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+	assert.Equal(t, MediaTypeJSON, rr.Header().Get("Content-Type"))
+
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, MediaTypeMsgpack)
+}
+
+func TestETagFor(t *testing.T) {
+	etag := GenerateETag(1, time.Unix(0, 0))
+
+	assert.Equal(t, etag, ETagFor(request(""), etag))
+	assert.Equal(t, etag, ETagFor(request(MediaTypeJSON), etag))
+
+	jsonAPI := ETagFor(request(JSONAPIMediaType), etag)
+	xmlETag := ETagFor(request(MediaTypeXML), etag)
+	assert.NotEqual(t, etag, jsonAPI)
+	assert.NotEqual(t, etag, xmlETag)
+	assert.NotEqual(t, jsonAPI, xmlETag)
+	assert.Equal(t, jsonAPI, ETagFor(request(JSONAPIMediaType), etag), "the ETag of a representation must be stable")
+
+	assert.Empty(t, ETagFor(request(MediaTypeXML), ""))
+}
+
+func TestNotModified_VariesOnAccept(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NotModified(rr, request(""), `"abc"`)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+}
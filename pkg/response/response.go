@@ -1,38 +1,81 @@
 package response
 
 import (
-	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/linkeunid/go-api/pkg/validator"
 )
 
 // APIResponse represents a standardized API response format
 type APIResponse struct {
-	Success   bool        `json:"success"`
-	Message   string      `json:"message,omitempty"`
-	Data      interface{} `json:"data,omitempty"`
-	Error     string      `json:"error,omitempty"`
-	Timestamp time.Time   `json:"timestamp"`
+	Success   bool        `json:"success" xml:"success"`
+	Message   string      `json:"message,omitempty" xml:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty" xml:"data,omitempty"`
+	Error     string      `json:"error,omitempty" xml:"error,omitempty"`
+	ErrorCode string      `json:"error_code,omitempty" xml:"error_code,omitempty"` // Stable, machine-readable error type, e.g. ANIMAL_NOT_FOUND
+	RequestID string      `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	Timestamp time.Time   `json:"timestamp" xml:"timestamp"`
+}
+
+// Error codes sent by the helpers below; handlers may send more specific codes with Error
+const (
+	CodeBadRequest       = "BAD_REQUEST"
+	CodeNotFound         = "NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodePayloadTooLarge  = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited      = "RATE_LIMITED"
+	CodeInternalError    = "INTERNAL_ERROR"
+	CodeTimeout          = "TIMEOUT"
+	CodeUnauthorized     = "UNAUTHORIZED"
+	CodeForbidden        = "FORBIDDEN"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeNotAcceptable    = "NOT_ACCEPTABLE"
+	CodeUnavailable      = "SERVICE_UNAVAILABLE"
+	CodeTooManyResults   = "TOO_MANY_RESULTS" // An unpaginated list matched more records than it may return
+)
+
+// ValidationErrorResponse documents the body sent by ValidationError and UnprocessableEntity
+// Data holds one entry per field that failed validation
+type ValidationErrorResponse struct {
+	Success   bool                        `json:"success" example:"false"`
+	Message   string                      `json:"message" example:"Validation failed"`
+	Data      []validator.ValidationError `json:"data"`
+	Error     string                      `json:"error" example:"The provided data is invalid"`
+	ErrorCode string                      `json:"error_code" example:"VALIDATION_FAILED"`
+	RequestID string                      `json:"request_id,omitempty" example:"host/abcdef-000001"`
+	Timestamp time.Time                   `json:"timestamp"`
 }
 
 // sendResponse sends a JSON response with the provided status code and data
 func sendResponse(w http.ResponseWriter, r *http.Request, statusCode int, resp APIResponse) {
-	// Set content type and status code
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+	// JSON:API clients get errors in the spec's shape; successful resource responses are
+	// converted by the handlers, which know the resource type
+	if !resp.Success && WantsJSONAPI(r) {
+		doc := JSONAPIDocument{Errors: jsonAPIErrors(statusCode, resp)}
+		if reqID := chimiddleware.GetReqID(r.Context()); reqID != "" {
+			doc.Meta = map[string]interface{}{"request_id": reqID}
+		}
+		JSONAPI(w, r, statusCode, doc)
+		return
+	}
 
 	// Add timestamp if not set
 	if resp.Timestamp.IsZero() {
 		resp.Timestamp = time.Now()
 	}
 
-	// Encode response to JSON
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		// If encoding fails, send a plain text error
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	// Include the request ID in error responses so clients can quote it in support requests
+	if !resp.Success && resp.RequestID == "" && r != nil {
+		resp.RequestID = chimiddleware.GetReqID(r.Context())
 	}
+
+	// Encode response in the format the client asked for
+	Encode(w, r, statusCode, resp)
 }
 
 // Success sends a successful response with data
@@ -53,17 +96,31 @@ func Created(w http.ResponseWriter, r *http.Request, data interface{}, message s
 	})
 }
 
+// Accepted sends a response for work that has been started but not finished
+func Accepted(w http.ResponseWriter, r *http.Request, data interface{}, message string) {
+	sendResponse(w, r, http.StatusAccepted, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // NoContent sends a response with no content
 func NoContent(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// Paginated sends a paginated response
+// TotalCountHeader carries the number of records across every page of a paginated response
+const TotalCountHeader = "X-Total-Count"
+
+// Paginated sends a paginated response with navigation links built from the request URL
 func Paginated(w http.ResponseWriter, r *http.Request, items interface{}, params pagination.Params, message string) {
 	paginatedData := pagination.PagedData{
 		Items:      items,
 		Pagination: params,
+		Links:      pagination.NewLinks(r.URL, params),
 	}
+	SetPaginationHeaders(w, params, paginatedData.Links)
 
 	sendResponse(w, r, http.StatusOK, APIResponse{
 		Success: true,
@@ -72,6 +129,47 @@ func Paginated(w http.ResponseWriter, r *http.Request, items interface{}, params
 	})
 }
 
+// SetPaginationHeaders repeats the navigation links of a page in an RFC 8288 Link header, with
+// first, prev, next and last relations, and its total number of records in X-Total-Count, for
+// clients that read pagination from headers rather than the body
+func SetPaginationHeaders(w http.ResponseWriter, params pagination.Params, links *pagination.Links) {
+	relations := []struct{ rel, url string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	}
+
+	values := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		if relation.url != "" {
+			values = append(values, "<"+relation.url+`>; rel="`+relation.rel+`"`)
+		}
+	}
+	w.Header().Set("Link", strings.Join(values, ", "))
+	w.Header().Set(TotalCountHeader, strconv.FormatInt(params.TotalItems, 10))
+}
+
+// Error sends an error response with a specific error code, for clients to branch on
+func Error(w http.ResponseWriter, r *http.Request, statusCode int, code, message string) {
+	ErrorWithDetail(w, r, statusCode, code, message, nil)
+}
+
+// ErrorWithDetail sends an error response with a specific error code and the error as detail
+func ErrorWithDetail(w http.ResponseWriter, r *http.Request, statusCode int, code, message string, err error) {
+	errorMsg := ""
+	if err != nil {
+		errorMsg = err.Error()
+	}
+
+	sendResponse(w, r, statusCode, APIResponse{
+		Success:   false,
+		Message:   message,
+		Error:     errorMsg,
+		ErrorCode: code,
+	})
+}
+
 // BadRequest sends a bad request error response
 func BadRequest(w http.ResponseWriter, r *http.Request, message string, err error) {
 	errorMsg := ""
@@ -80,17 +178,54 @@ func BadRequest(w http.ResponseWriter, r *http.Request, message string, err erro
 	}
 
 	sendResponse(w, r, http.StatusBadRequest, APIResponse{
-		Success: false,
-		Message: message,
-		Error:   errorMsg,
+		Success:   false,
+		ErrorCode: CodeBadRequest,
+		Message:   message,
+		Error:     errorMsg,
 	})
 }
 
 // NotFound sends a not found error response
 func NotFound(w http.ResponseWriter, r *http.Request, message string) {
 	sendResponse(w, r, http.StatusNotFound, APIResponse{
-		Success: false,
-		Message: message,
+		Success:   false,
+		ErrorCode: CodeNotFound,
+		Message:   message,
+	})
+}
+
+// Conflict sends a conflict error response
+func Conflict(w http.ResponseWriter, r *http.Request, message string, err error) {
+	errorMsg := ""
+	if err != nil {
+		errorMsg = err.Error()
+	}
+
+	sendResponse(w, r, http.StatusConflict, APIResponse{
+		Success:   false,
+		ErrorCode: CodeConflict,
+		Message:   message,
+		Error:     errorMsg,
+	})
+}
+
+// PayloadTooLarge sends a request entity too large error response
+func PayloadTooLarge(w http.ResponseWriter, r *http.Request, message string) {
+	sendResponse(w, r, http.StatusRequestEntityTooLarge, APIResponse{
+		Success:   false,
+		ErrorCode: CodePayloadTooLarge,
+		Message:   "Request body too large",
+		Error:     message,
+	})
+}
+
+// TooManyRequests sends a rate limit exceeded error response
+func TooManyRequests(w http.ResponseWriter, r *http.Request, message string) {
+	sendResponse(w, r, http.StatusTooManyRequests, APIResponse{
+		Success:   false,
+		ErrorCode: CodeRateLimited,
+		Message:   "Too many requests",
+		Error:     message,
 	})
 }
 
@@ -102,34 +237,70 @@ func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
 	}
 
 	sendResponse(w, r, http.StatusInternalServerError, APIResponse{
-		Success: false,
-		Message: "An internal server error occurred",
-		Error:   errorMsg,
+		Success:   false,
+		ErrorCode: CodeInternalError,
+		Message:   "An internal server error occurred",
+		Error:     errorMsg,
+	})
+}
+
+// GatewayTimeout sends a gateway timeout error response
+func GatewayTimeout(w http.ResponseWriter, r *http.Request, message string) {
+	sendResponse(w, r, http.StatusGatewayTimeout, APIResponse{
+		Success:   false,
+		ErrorCode: CodeTimeout,
+		Message:   "Request timed out",
+		Error:     message,
+	})
+}
+
+// ServiceUnavailable sends a service unavailable error response
+func ServiceUnavailable(w http.ResponseWriter, r *http.Request, message string) {
+	sendResponse(w, r, http.StatusServiceUnavailable, APIResponse{
+		Success:   false,
+		ErrorCode: CodeUnavailable,
+		Message:   "Service unavailable",
+		Error:     message,
 	})
 }
 
 // Unauthorized sends an unauthorized error response
 func Unauthorized(w http.ResponseWriter, r *http.Request, message string) {
 	sendResponse(w, r, http.StatusUnauthorized, APIResponse{
-		Success: false,
-		Message: message,
+		Success:   false,
+		ErrorCode: CodeUnauthorized,
+		Message:   message,
 	})
 }
 
 // Forbidden sends a forbidden error response
 func Forbidden(w http.ResponseWriter, r *http.Request, message string) {
 	sendResponse(w, r, http.StatusForbidden, APIResponse{
-		Success: false,
-		Message: message,
+		Success:   false,
+		ErrorCode: CodeForbidden,
+		Message:   message,
+	})
+}
+
+// UnprocessableEntity sends a field-level validation error response for a
+// well-formed request whose values are invalid
+func UnprocessableEntity(w http.ResponseWriter, r *http.Request, errors interface{}) {
+	sendResponse(w, r, http.StatusUnprocessableEntity, APIResponse{
+		Success:   false,
+		ErrorCode: CodeValidationFailed,
+		Message:   "Validation failed",
+		Error:     "The provided data is invalid",
+		Data:      errors,
 	})
 }
 
 // ValidationError sends a validation error response
 func ValidationError(w http.ResponseWriter, r *http.Request, errors interface{}) {
 	sendResponse(w, r, http.StatusBadRequest, APIResponse{
-		Success: false,
-		Message: "Validation failed",
-		Error:   "The provided data is invalid",
-		Data:    errors,
+		Success:   false,
+		ErrorCode: CodeValidationFailed,
+		Message:   "Validation failed",
+		Error:     "The provided data is invalid",
+		Data:      errors,
 	})
 }
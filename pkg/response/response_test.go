@@ -0,0 +1,97 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkeunid/go-api/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	t.Run("Envelope", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		ErrorWithDetail(rr, request(""), http.StatusNotFound, "ANIMAL_NOT_FOUND", "Animal not found", errors.New("animal not found"))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		var resp APIResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.False(t, resp.Success)
+		assert.Equal(t, "ANIMAL_NOT_FOUND", resp.ErrorCode)
+		assert.Equal(t, "Animal not found", resp.Message)
+		assert.Equal(t, "animal not found", resp.Error)
+	})
+
+	t.Run("JSONAPI", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		Error(rr, request(JSONAPIMediaType), http.StatusNotFound, "ANIMAL_NOT_FOUND", "Animal not found")
+
+		var doc JSONAPIDocument
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+		require.Len(t, doc.Errors, 1)
+		assert.Equal(t, "ANIMAL_NOT_FOUND", doc.Errors[0].Code)
+		assert.Equal(t, "404", doc.Errors[0].Status)
+	})
+}
+
+func TestErrorHelpers_DefaultCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		send     func(w http.ResponseWriter, r *http.Request)
+		expected string
+	}{
+		{name: "BadRequest", send: func(w http.ResponseWriter, r *http.Request) { BadRequest(w, r, "bad", nil) }, expected: CodeBadRequest},
+		{name: "NotFound", send: func(w http.ResponseWriter, r *http.Request) { NotFound(w, r, "missing") }, expected: CodeNotFound},
+		{name: "Conflict", send: func(w http.ResponseWriter, r *http.Request) { Conflict(w, r, "taken", nil) }, expected: CodeConflict},
+		{name: "PayloadTooLarge", send: func(w http.ResponseWriter, r *http.Request) { PayloadTooLarge(w, r, "big") }, expected: CodePayloadTooLarge},
+		{name: "InternalServerError", send: func(w http.ResponseWriter, r *http.Request) { InternalServerError(w, r, errors.New("boom")) }, expected: CodeInternalError},
+		{name: "GatewayTimeout", send: func(w http.ResponseWriter, r *http.Request) { GatewayTimeout(w, r, "slow") }, expected: CodeTimeout},
+		{name: "ServiceUnavailable", send: func(w http.ResponseWriter, r *http.Request) { ServiceUnavailable(w, r, "down") }, expected: CodeUnavailable},
+		{name: "Unauthorized", send: func(w http.ResponseWriter, r *http.Request) { Unauthorized(w, r, "who") }, expected: CodeUnauthorized},
+		{name: "Forbidden", send: func(w http.ResponseWriter, r *http.Request) { Forbidden(w, r, "no") }, expected: CodeForbidden},
+		{name: "ValidationError", send: func(w http.ResponseWriter, r *http.Request) { ValidationError(w, r, nil) }, expected: CodeValidationFailed},
+		{name: "NotAcceptable", send: func(w http.ResponseWriter, r *http.Request) { Success(w, request("text/html"), nil, "ok") }, expected: CodeNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			tt.send(rr, request(""))
+
+			var resp APIResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expected, resp.ErrorCode)
+		})
+	}
+}
+
+func TestPaginated_Headers(t *testing.T) {
+	params := pagination.Params{Page: 3, Limit: 10}
+	params.CalculatePages(95)
+
+	rr := httptest.NewRecorder()
+	Paginated(rr, httptest.NewRequest(http.MethodGet, "/api/v1/animals?page=3&limit=10&sort=name", nil), []string{}, params, "ok")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "95", rr.Header().Get(TotalCountHeader))
+	assert.Equal(t, `</api/v1/animals?limit=10&page=1&sort=name>; rel="first", `+
+		`</api/v1/animals?limit=10&page=2&sort=name>; rel="prev", `+
+		`</api/v1/animals?limit=10&page=4&sort=name>; rel="next", `+
+		`</api/v1/animals?limit=10&page=10&sort=name>; rel="last"`, rr.Header().Get("Link"))
+}
+
+func TestPaginated_HeadersForEmptyResult(t *testing.T) {
+	params := pagination.Params{Page: 1, Limit: 10}
+	params.CalculatePages(0)
+
+	rr := httptest.NewRecorder()
+	Paginated(rr, httptest.NewRequest(http.MethodGet, "/api/v1/animals", nil), []string{}, params, "ok")
+
+	assert.Equal(t, "0", rr.Header().Get(TotalCountHeader))
+	assert.Equal(t, `</api/v1/animals?page=1>; rel="first", </api/v1/animals?page=1>; rel="last"`, rr.Header().Get("Link"))
+}
@@ -3,9 +3,7 @@ package seeder
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"reflect"
-	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/linkeunid/go-api/internal/model"
@@ -15,6 +13,7 @@ import (
 
 // AnimalSeeder seeds animal data
 type AnimalSeeder struct {
+	batchOptions
 	db     database.Database
 	logger *zap.Logger
 	count  int
@@ -43,11 +42,8 @@ type FakerAnimal struct {
 }
 
 // Custom faker providers
+// Providers draw from the package generator so SetRandomSeed makes them reproducible
 func init() {
-	// Create a random source using the current time
-	source := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(source)
-
 	// Register pet name provider
 	_ = faker.AddProvider("pet_name", func(v reflect.Value) (interface{}, error) {
 		petNames := []string{
@@ -56,7 +52,7 @@ func init() {
 			"Lola", "Oliver", "Stella", "Zeus", "Lily", "Duke", "Zoe", "Bentley",
 			"Sophie", "Toby", "Chloe", "Dexter", "Penny", "Gus", "Willow",
 		}
-		return petNames[r.Intn(len(petNames))], nil
+		return petNames[rng.Intn(len(petNames))], nil
 	})
 
 	// Register animal species provider
@@ -66,7 +62,7 @@ func init() {
 			"Turtle", "Snake", "Lizard", "Horse", "Cow", "Pig", "Sheep", "Goat",
 			"Chicken", "Duck", "Donkey", "Ferret", "Chinchilla",
 		}
-		return species[r.Intn(len(species))], nil
+		return species[rng.Intn(len(species))], nil
 	})
 
 	// Register animal description provider
@@ -85,10 +81,20 @@ func init() {
 			"Very social with other animals",
 			"Quiet and observant",
 		}
-		return descriptions[r.Intn(len(descriptions))], nil
+		return descriptions[rng.Intn(len(descriptions))], nil
 	})
 }
 
+// Truncate deletes all animals so the seeder can run on an empty table
+func (s *AnimalSeeder) Truncate(ctx context.Context) error {
+	if err := s.db.GetDB().WithContext(ctx).Where("1 = 1").Delete(&model.Animal{}).Error; err != nil {
+		return fmt.Errorf("failed to truncate animals: %w", err)
+	}
+
+	s.logger.Info("Truncated animals")
+	return nil
+}
+
 // Seed seeds animal data
 func (s *AnimalSeeder) Seed(ctx context.Context) error {
 	// Check if there are already animals in the database
@@ -118,7 +124,7 @@ func (s *AnimalSeeder) Seed(ctx context.Context) error {
 	}
 
 	// Insert in batches for better performance
-	batchSize := 100
+	batchSize := s.size()
 	for i := 0; i < len(animals); i += batchSize {
 		end := i + batchSize
 		if end > len(animals) {
@@ -130,6 +136,8 @@ func (s *AnimalSeeder) Seed(ctx context.Context) error {
 			tx.Rollback()
 			return fmt.Errorf("failed to seed animals batch %d: %w", i/batchSize, err)
 		}
+
+		s.reportBatch(i/batchSize, end, len(animals))
 	}
 
 	// Commit the transaction
@@ -143,10 +151,6 @@ func (s *AnimalSeeder) Seed(ctx context.Context) error {
 
 // generateAnimals creates a slice of random animal data using faker
 func (s *AnimalSeeder) generateAnimals(count int) ([]*model.Animal, error) {
-	// Create a random source
-	source := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(source)
-
 	// Configure faker
 	if err := faker.SetRandomStringLength(10); err != nil {
 		return nil, fmt.Errorf("failed to set random string length: %w", err)
@@ -161,13 +165,8 @@ func (s *AnimalSeeder) generateAnimals(count int) ([]*model.Animal, error) {
 			return nil, fmt.Errorf("failed to generate fake animal data: %w", err)
 		}
 
-		// Generate a creation time within the last year
-		createdAt := time.Now().Add(-time.Duration(r.Intn(365)) * 24 * time.Hour)
-
-		// Generate an update time between creation and now
-		updateDuration := time.Since(createdAt)
-		updateOffset := time.Duration(r.Int63n(int64(updateDuration)))
-		updatedAt := createdAt.Add(updateOffset)
+		// Generate a creation time within the last year and an update time after it
+		createdAt, updatedAt := randomTimestamps()
 
 		// Create animal model from fake data
 		animal := &model.Animal{
@@ -0,0 +1,52 @@
+package seeder
+
+// defaultBatchSize is the number of rows inserted per statement unless SetBatchSize is called
+const defaultBatchSize = 100
+
+// ProgressFunc is called while a seeder inserts rows with the number of rows inserted so far
+// and the total it will insert
+type ProgressFunc func(done, total int)
+
+// batchOptions controls how a seeder inserts its rows; it is embedded by each seeder
+type batchOptions struct {
+	batchSize     int
+	progressEvery int
+	progress      ProgressFunc
+}
+
+// SetBatchSize sets the number of rows inserted per statement
+func (o *batchOptions) SetBatchSize(n int) {
+	o.batchSize = n
+}
+
+// SetProgress registers fn to be called after every everyBatches inserted batches and
+// once more when the last batch is inserted
+func (o *batchOptions) SetProgress(everyBatches int, fn ProgressFunc) {
+	o.progressEvery = everyBatches
+	o.progress = fn
+}
+
+// size returns the configured batch size, falling back to defaultBatchSize
+func (o *batchOptions) size() int {
+	if o.batchSize <= 0 {
+		return defaultBatchSize
+	}
+	return o.batchSize
+}
+
+// reportBatch notifies the progress callback, if any, that batch (0-based) finished
+// and done of total rows have been inserted
+func (o *batchOptions) reportBatch(batch, done, total int) {
+	if o.progress == nil {
+		return
+	}
+
+	every := o.progressEvery
+	if every <= 0 {
+		every = 1
+	}
+
+	if (batch+1)%every == 0 || done == total {
+		o.progress(done, total)
+	}
+}
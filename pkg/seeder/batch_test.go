@@ -0,0 +1,32 @@
+package seeder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchOptions_ReportBatch(t *testing.T) {
+	var opts batchOptions
+	assert.Equal(t, defaultBatchSize, opts.size())
+
+	opts.SetBatchSize(250)
+	assert.Equal(t, 250, opts.size())
+
+	var reported []int
+	opts.SetProgress(2, func(done, total int) {
+		reported = append(reported, done)
+	})
+
+	// 1000 rows in batches of 250, reporting every 2nd batch and at the end
+	for batch, done := 0, 250; done <= 1000; batch, done = batch+1, done+250 {
+		opts.reportBatch(batch, done, 1000)
+	}
+	assert.Equal(t, []int{500, 1000}, reported)
+
+	reported = nil
+	for batch, done := 0, 250; done <= 750; batch, done = batch+1, done+250 {
+		opts.reportBatch(batch, done, 750)
+	}
+	assert.Equal(t, []int{500, 750}, reported)
+}
@@ -3,9 +3,7 @@ package seeder
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"reflect"
-	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/linkeunid/go-api/internal/model"
@@ -15,6 +13,7 @@ import (
 
 // FlowerSeeder seeds flower data
 type FlowerSeeder struct {
+	batchOptions
 	db     database.Database
 	logger *zap.Logger
 	count  int
@@ -44,11 +43,8 @@ type FakerFlower struct {
 }
 
 // Custom faker providers
+// Providers draw from the package generator so SetRandomSeed makes them reproducible
 func init() {
-	// Create a random source using the current time
-	source := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(source)
-
 	// Register flower name provider
 	_ = faker.AddProvider("flower_name", func(v reflect.Value) (interface{}, error) {
 		flowerNames := []string{
@@ -57,7 +53,7 @@ func init() {
 			"Hibiscus", "Magnolia", "Lavender", "Dahlia", "Hydrangea", "Jasmine",
 			"Bluebell", "Cherry Blossom", "Buttercup", "Forget-me-not", "Dandelion",
 		}
-		return flowerNames[r.Intn(len(flowerNames))], nil
+		return flowerNames[rng.Intn(len(flowerNames))], nil
 	})
 
 	// Register flower species provider
@@ -69,7 +65,7 @@ func init() {
 			"Hydrangea", "Jasminum", "Hyacinthoides", "Prunus", "Ranunculus",
 			"Myosotis", "Taraxacum",
 		}
-		return species[r.Intn(len(species))], nil
+		return species[rng.Intn(len(species))], nil
 	})
 
 	// Register flower color provider
@@ -79,7 +75,7 @@ func init() {
 			"Violet", "Indigo", "Cream", "Coral", "Lavender", "Maroon",
 			"Fuchsia", "Peach", "Magenta", "Crimson", "Lilac", "Gold", "Burgundy",
 		}
-		return colors[r.Intn(len(colors))], nil
+		return colors[rng.Intn(len(colors))], nil
 	})
 
 	// Register flower description provider
@@ -98,10 +94,20 @@ func init() {
 			"Rare variety with spectacular blooms",
 			"Native wildflower with ecological benefits",
 		}
-		return descriptions[r.Intn(len(descriptions))], nil
+		return descriptions[rng.Intn(len(descriptions))], nil
 	})
 }
 
+// Truncate deletes all flowers so the seeder can run on an empty table
+func (s *FlowerSeeder) Truncate(ctx context.Context) error {
+	if err := s.db.GetDB().WithContext(ctx).Where("1 = 1").Delete(&model.Flower{}).Error; err != nil {
+		return fmt.Errorf("failed to truncate flowers: %w", err)
+	}
+
+	s.logger.Info("Truncated flowers")
+	return nil
+}
+
 // Seed seeds flower data
 func (s *FlowerSeeder) Seed(ctx context.Context) error {
 	// Check if there are already flowers in the database
@@ -131,7 +137,7 @@ func (s *FlowerSeeder) Seed(ctx context.Context) error {
 	}
 
 	// Insert in batches for better performance
-	batchSize := 100
+	batchSize := s.size()
 	for i := 0; i < len(flowers); i += batchSize {
 		end := i + batchSize
 		if end > len(flowers) {
@@ -143,6 +149,8 @@ func (s *FlowerSeeder) Seed(ctx context.Context) error {
 			tx.Rollback()
 			return fmt.Errorf("failed to seed flowers batch %d: %w", i/batchSize, err)
 		}
+
+		s.reportBatch(i/batchSize, end, len(flowers))
 	}
 
 	// Commit the transaction
@@ -156,10 +164,6 @@ func (s *FlowerSeeder) Seed(ctx context.Context) error {
 
 // generateFlowers creates a slice of random flower data using faker
 func (s *FlowerSeeder) generateFlowers(count int) ([]*model.Flower, error) {
-	// Create a random source
-	source := rand.NewSource(time.Now().UnixNano())
-	r := rand.New(source)
-
 	// Configure faker
 	if err := faker.SetRandomStringLength(10); err != nil {
 		return nil, fmt.Errorf("failed to set random string length: %w", err)
@@ -174,13 +178,8 @@ func (s *FlowerSeeder) generateFlowers(count int) ([]*model.Flower, error) {
 			return nil, fmt.Errorf("failed to generate fake flower data: %w", err)
 		}
 
-		// Generate a creation time within the last year
-		createdAt := time.Now().Add(-time.Duration(r.Intn(365)) * 24 * time.Hour)
-
-		// Generate an update time between creation and now
-		updateDuration := time.Since(createdAt)
-		updateOffset := time.Duration(r.Int63n(int64(updateDuration)))
-		updatedAt := createdAt.Add(updateOffset)
+		// Generate a creation time within the last year and an update time after it
+		createdAt, updatedAt := randomTimestamps()
 
 		// Create flower model from fake data
 		flower := &model.Flower{
@@ -188,7 +187,7 @@ func (s *FlowerSeeder) generateFlowers(count int) ([]*model.Flower, error) {
 			Species:     fakeFlower.Species,
 			Color:       fakeFlower.Color,
 			Description: fakeFlower.Description,
-			Seasonal:    r.Intn(2) == 1,
+			Seasonal:    rng.Intn(2) == 1,
 			CreatedAt:   createdAt,
 			UpdatedAt:   updatedAt,
 		}
@@ -0,0 +1,56 @@
+package seeder
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+)
+
+// seededReferenceTime anchors generated timestamps when a seed is set, so the
+// same seed yields identical rows regardless of when seeding runs
+var seededReferenceTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	// rng drives every random choice made by the seeders and their faker providers
+	rng = newRand(time.Now().UnixNano())
+
+	// referenceTime is the "now" generated timestamps are relative to; zero means time.Now()
+	referenceTime time.Time
+)
+
+// newRand returns a concurrency-safe random generator for seed
+func newRand(seed int64) *rand.Rand {
+	return rand.New(faker.NewSafeSource(rand.NewSource(seed)))
+}
+
+// SetRandomSeed makes the seeders reproducible: the same seed produces the same data,
+// including faker-generated values and timestamps. Call it before running any seeder
+func SetRandomSeed(seed int64) {
+	rng = newRand(seed)
+	faker.SetRandomSource(faker.NewSafeSource(rand.NewSource(seed)))
+	referenceTime = seededReferenceTime
+}
+
+// now returns the time generated timestamps are relative to
+func now() time.Time {
+	if referenceTime.IsZero() {
+		return time.Now()
+	}
+	return referenceTime
+}
+
+// randomTimestamps returns a creation time within the year before now() and
+// an update time between creation and now()
+func randomTimestamps() (createdAt, updatedAt time.Time) {
+	current := now()
+	createdAt = current.Add(-time.Duration(rng.Intn(365)) * 24 * time.Hour)
+
+	// Guard against a zero range when the creation time is exactly now
+	if span := int64(current.Sub(createdAt)); span > 0 {
+		updatedAt = createdAt.Add(time.Duration(rng.Int63n(span)))
+	} else {
+		updatedAt = createdAt
+	}
+	return createdAt, updatedAt
+}
@@ -0,0 +1,41 @@
+package seeder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSetRandomSeed_ReproducesData(t *testing.T) {
+	animalSeeder := NewAnimalSeeder(nil, zap.NewNop(), 20)
+	flowerSeeder := NewFlowerSeeder(nil, zap.NewNop(), 20)
+
+	generate := func(seed int64) ([]interface{}, []interface{}) {
+		SetRandomSeed(seed)
+
+		animals, err := animalSeeder.generateAnimals(20)
+		require.NoError(t, err)
+		flowers, err := flowerSeeder.generateFlowers(20)
+		require.NoError(t, err)
+
+		a := make([]interface{}, len(animals))
+		for i, animal := range animals {
+			a[i] = *animal
+		}
+		f := make([]interface{}, len(flowers))
+		for i, flower := range flowers {
+			f[i] = *flower
+		}
+		return a, f
+	}
+
+	animals1, flowers1 := generate(42)
+	animals2, flowers2 := generate(42)
+	assert.Equal(t, animals1, animals2, "same seed should generate identical animals")
+	assert.Equal(t, flowers1, flowers2, "same seed should generate identical flowers")
+
+	animals3, _ := generate(43)
+	assert.NotEqual(t, animals1, animals3, "different seeds should generate different animals")
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet ULIDs are written in
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDLength is the length of a ULID in its canonical text form
+const ULIDLength = 26
+
+// NewULID returns a new ULID: a 48-bit millisecond timestamp followed by 80 random bits,
+// encoded as 26 Crockford base32 characters. ULIDs sort by creation time to the millisecond
+func NewULID() string {
+	return newULID(time.Now())
+}
+
+// newULID returns a ULID for the given time with random entropy
+func newULID(t time.Time) string {
+	var id [16]byte
+
+	ms := uint64(t.UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	_, _ = rand.Read(id[6:])
+
+	// 128 bits are written as 26 base32 digits, the first of which holds only the top 3 bits
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	out := make([]byte, ULIDLength)
+	for i := ULIDLength - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out)
+}
+
+// ParseULID validates a ULID and returns it in canonical upper case
+func ParseULID(s string) (string, error) {
+	if len(s) != ULIDLength {
+		return "", fmt.Errorf("invalid ULID %q: must be %d characters", s, ULIDLength)
+	}
+
+	s = strings.ToUpper(s)
+	for _, c := range s {
+		if !strings.ContainsRune(crockford, c) {
+			return "", fmt.Errorf("invalid ULID %q: %q is not a base32 character", s, c)
+		}
+	}
+
+	// The first character only holds 3 bits; anything larger overflows 128 bits
+	if s[0] > '7' {
+		return "", fmt.Errorf("invalid ULID %q: value out of range", s)
+	}
+
+	return s, nil
+}
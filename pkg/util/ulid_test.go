@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewULID(t *testing.T) {
+	id := NewULID()
+	require.Len(t, id, ULIDLength)
+
+	parsed, err := ParseULID(id)
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	assert.NotEqual(t, id, NewULID())
+
+	// The timestamp prefix makes ULIDs from later milliseconds sort after earlier ones
+	earlier := newULID(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	later := newULID(time.Date(2024, time.January, 1, 0, 0, 0, int(time.Millisecond), time.UTC))
+	assert.Less(t, earlier, later)
+
+	// The first 10 characters encode the timestamp; 1469918176385 is the example in the ULID spec
+	assert.Equal(t, "01ARYZ6S41", newULID(time.UnixMilli(1469918176385))[:10])
+}
+
+func TestParseULID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "Valid", input: "01ARZ3NDEKTSV4RRFFQ69G5FAV", expected: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{name: "Lowercase", input: "01arz3ndektsv4rrffq69g5fav", expected: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{name: "TooShort", input: "01ARZ3NDEK", wantErr: true},
+		{name: "Empty", input: "", wantErr: true},
+		{name: "InvalidCharacter", input: "01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+		{name: "Overflow", input: "81ARZ3NDEKTSV4RRFFQ69G5FAV", wantErr: true},
+		{name: "Integer", input: "42", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseULID(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
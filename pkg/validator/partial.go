@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidatePartial validates a subset of fields, keyed by JSON name, against the
+// validation rules declared on the struct s. It returns the provided values
+// converted to the Go types of the matching struct fields. Fields that are unknown
+// or listed in readOnly are rejected.
+func ValidatePartial(s interface{}, fields map[string]interface{}, readOnly ...string) (map[string]interface{}, []ValidationError) {
+	var errors []ValidationError
+	converted := make(map[string]interface{}, len(fields))
+
+	// Index struct fields by their JSON name
+	structFields := jsonFields(reflect.TypeOf(s))
+
+	blocked := make(map[string]bool, len(readOnly))
+	for _, name := range readOnly {
+		blocked[name] = true
+	}
+
+	// Visit fields in a stable order so errors are reported deterministically
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := fields[name]
+		field, ok := structFields[name]
+		if !ok || blocked[name] {
+			errors = append(errors, ValidationError{
+				Field: name,
+				Tag:   "unknown",
+				Value: fmt.Sprintf("%v", value),
+				Error: fmt.Sprintf("%s is not an updatable field", name),
+			})
+			continue
+		}
+
+		// Convert the raw JSON value into the field's type
+		typed := reflect.New(field.Type)
+		raw, err := json.Marshal(value)
+		if err == nil {
+			err = json.Unmarshal(raw, typed.Interface())
+		}
+		if err != nil {
+			errors = append(errors, ValidationError{
+				Field: name,
+				Tag:   "type",
+				Value: fmt.Sprintf("%v", value),
+				Error: fmt.Sprintf("%s must be of type %s", name, field.Type.Kind()),
+			})
+			continue
+		}
+
+		// Run the field's validation rules against the converted value
+		if tag := field.Tag.Get("validate"); tag != "" {
+			if fieldErrors := validate.validateField(name, typed.Elem().Interface(), tag); len(fieldErrors) > 0 {
+				errors = append(errors, fieldErrors...)
+				continue
+			}
+		}
+
+		converted[name] = typed.Elem().Interface()
+	}
+
+	return converted, errors
+}
+
+// jsonFields returns the exported struct fields of t indexed by JSON name
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := make(map[string]reflect.StructField)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	// Visible fields include those promoted from embedded structs such as model.WithULID
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || (f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "") {
+			continue
+		}
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+
+	return fields
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
@@ -15,17 +16,27 @@ import (
 
 // ValidationError represents a validation error
 type ValidationError struct {
-	Field string `json:"field"`
-	Tag   string `json:"tag"`
-	Value string `json:"value"`
-	Error string `json:"error"`
+	Field string `json:"field" xml:"field" example:"name"`
+	Tag   string `json:"tag" xml:"tag" example:"required"`
+	Value string `json:"value" xml:"value" example:""`
+	Error string `json:"error" xml:"error" example:"name is required"`
 }
 
+// Func is the signature of a custom validation function
+type Func = validator.Func
+
+// FieldLevel gives custom validation functions access to the field being validated
+type FieldLevel = validator.FieldLevel
+
 // Validator is a global validator instance
 type Validator struct {
 	validate *validator.Validate
 	trans    ut.Translator
 	once     sync.Once
+
+	// speciesMu guards allowedSpecies, which the species rule reads on every validation
+	speciesMu      sync.RWMutex
+	allowedSpecies map[string]bool
 }
 
 // New creates a new validator instance
@@ -50,9 +61,6 @@ func (v *Validator) init() {
 			return name
 		})
 
-		// Register custom validation functions
-		v.registerCustomValidations(validate)
-
 		// Set up the translator
 		english := en.New()
 		uni := ut.New(english, english)
@@ -62,43 +70,89 @@ func (v *Validator) init() {
 			fmt.Printf("Failed to register default translations: %v\n", err)
 		}
 
-		// Register custom error messages
-		v.registerCustomTranslations(validate, trans)
-
 		v.validate = validate
 		v.trans = trans
+
+		// Register custom validation functions and their messages
+		v.registerCustomValidations()
+
+		// Register custom error messages for built-in rules
+		v.registerCustomTranslations()
 	})
 }
 
-// registerCustomValidations registers custom validation functions
-func (v *Validator) registerCustomValidations(validate *validator.Validate) {
-	// Example: Register a custom validation for animal names
-	if err := validate.RegisterValidation("animalname", func(fl validator.FieldLevel) bool {
-		// Animal names should not contain numbers or special characters
-		name := fl.Field().String()
-		reg := regexp.MustCompile(`^[a-zA-Z\s-]+$`)
-		return reg.MatchString(name)
-	}); err != nil {
-		fmt.Printf("Failed to register animalname validation: %v\n", err)
-	}
+// RegisterValidation adds a custom validation rule under tag.
+// message is the error text shown when the rule fails: {0} is replaced with the field name
+// and {1} with the tag parameter, e.g. "{0} must be one of [{1}]".
+// Rules should be registered during startup, before the validator is used concurrently.
+func (v *Validator) RegisterValidation(tag string, fn Func, message string) error {
+	// Initialize the validator if not already done
+	v.init()
 
-	// Add more custom validations as needed
+	return v.registerRule(tag, fn, message)
 }
 
-// registerCustomTranslations registers custom error messages for validations
-func (v *Validator) registerCustomTranslations(validate *validator.Validate, trans ut.Translator) {
-	// Register custom error message for animalname validation
-	if err := validate.RegisterTranslation("animalname", trans, func(ut ut.Translator) error {
-		return ut.Add("animalname", "{0} must contain only letters, spaces, and hyphens", true)
+// registerRule registers a validation function and its message; the validator must be initialized
+func (v *Validator) registerRule(tag string, fn Func, message string) error {
+	if err := v.validate.RegisterValidation(tag, fn); err != nil {
+		return fmt.Errorf("failed to register %s validation: %w", tag, err)
+	}
+
+	if err := v.validate.RegisterTranslation(tag, v.trans, func(ut ut.Translator) error {
+		return ut.Add(tag, message, true)
 	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("animalname", fe.Field())
+		t, _ := ut.T(tag, fe.Field(), fe.Param())
 		return t
 	}); err != nil {
-		fmt.Printf("Failed to register animalname translation: %v\n", err)
+		return fmt.Errorf("failed to register %s translation: %w", tag, err)
+	}
+
+	return nil
+}
+
+// SetAllowedSpecies configures the values accepted by the species rule (case-insensitive).
+// An empty list accepts any species.
+func (v *Validator) SetAllowedSpecies(species []string) {
+	allowed := make(map[string]bool, len(species))
+	for _, s := range species {
+		if s = strings.TrimSpace(s); s != "" {
+			allowed[strings.ToLower(s)] = true
+		}
+	}
+
+	v.speciesMu.Lock()
+	v.allowedSpecies = allowed
+	v.speciesMu.Unlock()
+}
+
+// registerCustomValidations registers custom validation functions
+func (v *Validator) registerCustomValidations() {
+	rules := []struct {
+		tag     string
+		fn      Func
+		message string
+	}{
+		// Animal names should not contain numbers or special characters
+		{"animalname", isAnimalName, "{0} must contain only letters, spaces, and hyphens"},
+		// Case-insensitive variant of oneof, e.g. oneofci=red green blue
+		{"oneofci", isOneOfCaseInsensitive, "{0} must be one of [{1}]"},
+		// Only letters, digits and spaces
+		{"nospecial", hasNoSpecialCharacters, "{0} must not contain special characters"},
+		// Species must be one of the configured values
+		{"species", v.isAllowedSpecies, "{0} is not a supported species"},
+	}
+
+	for _, rule := range rules {
+		if err := v.registerRule(rule.tag, rule.fn, rule.message); err != nil {
+			fmt.Printf("%v\n", err)
+		}
 	}
+}
 
+// registerCustomTranslations registers custom error messages for built-in validations
+func (v *Validator) registerCustomTranslations() {
 	// Customize the required error message
-	if err := validate.RegisterTranslation("required", trans, func(ut ut.Translator) error {
+	if err := v.validate.RegisterTranslation("required", v.trans, func(ut ut.Translator) error {
 		return ut.Add("required", "{0} is required", true)
 	}, func(ut ut.Translator, fe validator.FieldError) string {
 		t, _ := ut.T("required", fe.Field())
@@ -110,6 +164,46 @@ func (v *Validator) registerCustomTranslations(validate *validator.Validate, tra
 	// Add more custom translations as needed
 }
 
+// animalNamePattern matches letters, spaces and hyphens
+var animalNamePattern = regexp.MustCompile(`^[a-zA-Z\s-]+$`)
+
+// isAnimalName implements the animalname rule
+func isAnimalName(fl validator.FieldLevel) bool {
+	return animalNamePattern.MatchString(fl.Field().String())
+}
+
+// isOneOfCaseInsensitive implements the oneofci rule
+func isOneOfCaseInsensitive(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	for _, option := range strings.Fields(fl.Param()) {
+		if strings.EqualFold(value, option) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNoSpecialCharacters implements the nospecial rule
+func hasNoSpecialCharacters(fl validator.FieldLevel) bool {
+	for _, r := range fl.Field().String() {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAllowedSpecies implements the species rule
+func (v *Validator) isAllowedSpecies(fl validator.FieldLevel) bool {
+	v.speciesMu.RLock()
+	defer v.speciesMu.RUnlock()
+
+	if len(v.allowedSpecies) == 0 {
+		return true
+	}
+	return v.allowedSpecies[strings.ToLower(strings.TrimSpace(fl.Field().String()))]
+}
+
 // ValidateStruct validates a struct and returns a list of validation errors
 func (v *Validator) ValidateStruct(s interface{}) []ValidationError {
 	var errors []ValidationError
@@ -142,6 +236,37 @@ func (v *Validator) ValidateVar(field interface{}, tag string) error {
 	return v.validate.Var(field, tag)
 }
 
+// validateField validates a single value and reports failures under the given field name
+func (v *Validator) validateField(name string, value interface{}, tag string) []ValidationError {
+	var errors []ValidationError
+
+	// Initialize the validator if not already done
+	v.init()
+
+	err := v.validate.Var(value, tag)
+	if err == nil {
+		return nil
+	}
+
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []ValidationError{{Field: name, Tag: tag, Value: fmt.Sprintf("%v", value), Error: err.Error()}}
+	}
+
+	for _, fe := range validationErrors {
+		// Var() has no field name, so prefix the translated message with ours
+		message := strings.TrimSpace(fe.Translate(v.trans))
+		errors = append(errors, ValidationError{
+			Field: name,
+			Tag:   fe.Tag(),
+			Value: fmt.Sprintf("%v", fe.Value()),
+			Error: name + " " + message,
+		})
+	}
+
+	return errors
+}
+
 // Global instance for convenience
 var validate = New()
 
@@ -150,6 +275,16 @@ func Validate(s interface{}) []ValidationError {
 	return validate.ValidateStruct(s)
 }
 
+// RegisterValidation adds a custom validation rule to the global validator instance
+func RegisterValidation(tag string, fn Func, message string) error {
+	return validate.RegisterValidation(tag, fn, message)
+}
+
+// SetAllowedSpecies configures the species rule of the global validator instance
+func SetAllowedSpecies(species []string) {
+	validate.SetAllowedSpecies(species)
+}
+
 // ValidateVar is a convenience function that uses the global validator instance
 func ValidateVar(field interface{}, tag string) error {
 	return validate.ValidateVar(field, tag)
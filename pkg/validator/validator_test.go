@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomValidations(t *testing.T) {
+	type payload struct {
+		Color   string `json:"color" validate:"omitempty,oneofci=red green"`
+		Code    string `json:"code" validate:"omitempty,nospecial"`
+		Species string `json:"species" validate:"omitempty,species"`
+	}
+
+	v := New()
+	v.SetAllowedSpecies([]string{"Cat", " dog "})
+
+	tests := []struct {
+		name          string
+		input         payload
+		expectedTag   string
+		expectedError string
+	}{
+		{name: "OneOfCaseInsensitive", input: payload{Color: "RED"}},
+		{name: "OneOfRejected", input: payload{Color: "blue"}, expectedTag: "oneofci", expectedError: "color must be one of [red green]"},
+		{name: "NoSpecialAllowsUnicode", input: payload{Code: "Café 42"}},
+		{name: "NoSpecialRejected", input: payload{Code: "drop;table"}, expectedTag: "nospecial", expectedError: "code must not contain special characters"},
+		{name: "SpeciesAllowed", input: payload{Species: "DOG"}},
+		{name: "SpeciesRejected", input: payload{Species: "Dragon"}, expectedTag: "species", expectedError: "species is not a supported species"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := v.ValidateStruct(tt.input)
+			if tt.expectedTag == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.expectedTag, errs[0].Tag)
+			assert.Equal(t, tt.expectedError, errs[0].Error)
+		})
+	}
+
+	// An empty species list accepts anything
+	v.SetAllowedSpecies(nil)
+	assert.Empty(t, v.ValidateStruct(payload{Species: "Dragon"}))
+}
+
+func TestRegisterValidation(t *testing.T) {
+	v := New()
+	require.NoError(t, v.RegisterValidation("uppercase", func(fl FieldLevel) bool {
+		value := fl.Field().String()
+		return value == strings.ToUpper(value)
+	}, "{0} must be upper case"))
+
+	type payload struct {
+		Code string `json:"code" validate:"uppercase"`
+	}
+
+	assert.Empty(t, v.ValidateStruct(payload{Code: "ABC"}))
+
+	errs := v.ValidateStruct(payload{Code: "abc"})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "code must be upper case", errs[0].Error)
+}
+
+func TestValidatePartial_EmbeddedFields(t *testing.T) {
+	type Key struct {
+		ID string `json:"id"`
+	}
+	type payload struct {
+		Key
+		Name string `json:"name" validate:"min=2"`
+	}
+
+	updates, errs := ValidatePartial(payload{}, map[string]interface{}{"name": "Fern"}, "id")
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"name": "Fern"}, updates)
+
+	// Promoted fields are known by their JSON name and the embedded struct itself is not a field
+	_, errs = ValidatePartial(payload{}, map[string]interface{}{"id": "x", "Key": "y"}, "id")
+	require.Len(t, errs, 2)
+	assert.Equal(t, "id is not an updatable field", errs[1].Error)
+	assert.Equal(t, "Key is not an updatable field", errs[0].Error)
+}
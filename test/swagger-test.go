@@ -89,7 +89,8 @@ func main() {
 
 	// Swagger UI
 	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"), // The URL points to API definition
+		httpSwagger.URL("/swagger/doc.json"),   // The URL points to API definition
+		httpSwagger.PersistAuthorization(true), // Keep the token entered in Authorize across reloads
 	))
 
 	// Simple route